@@ -3,10 +3,13 @@ package capture
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
+	"math/rand/v2"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"nectarcollector/config"
@@ -25,6 +28,7 @@ const (
 	StateWaitingForNATS // Paused, waiting for NATS connection
 	StateStopped
 	StateError
+	StatePaused // Paused via the API: port open and read, records discarded
 )
 
 // Buffer sizes for line reading - these are generous to handle any line length
@@ -67,11 +71,45 @@ func (s ChannelState) String() string {
 		return "stopped"
 	case StateError:
 		return "error"
+	case StatePaused:
+		return "paused"
 	default:
 		return "unknown"
 	}
 }
 
+// StateCode returns a stable number for the state, published next to the
+// name as state_code for time-series alerting. The mapping never changes
+// between versions; new states get new codes.
+//
+//	0 detecting         4 waiting_for_nats
+//	1 running           5 stopped
+//	2 no_signal         6 error
+//	3 reconnecting      7 paused
+//	                   -1 unknown
+func (s ChannelState) StateCode() int {
+	switch s {
+	case StateDetecting:
+		return 0
+	case StateRunning:
+		return 1
+	case StateNoSignal:
+		return 2
+	case StateReconnecting:
+		return 3
+	case StateWaitingForNATS:
+		return 4
+	case StateStopped:
+		return 5
+	case StateError:
+		return 6
+	case StatePaused:
+		return 7
+	default:
+		return -1
+	}
+}
+
 // ModemSignals represents the state of RS-232 modem control lines
 // These can indicate whether a device is physically connected
 type ModemSignals struct {
@@ -89,17 +127,43 @@ func (m ModemSignals) Connected() bool {
 
 // ChannelStats tracks statistics for a capture channel
 type ChannelStats struct {
-	BytesRead    int64
-	LinesRead    int64
-	Errors       int64
-	Reconnects   int64 // Total reconnection attempts
-	LastLineTime time.Time
-	DetectedBaud int
-	DetectedFlow bool
-	StartTime    time.Time
-	Signals      *ModemSignals `json:"signals,omitempty"` // RS-232 modem signals (nil if unavailable)
+	BytesRead         int64
+	LinesRead         int64
+	Errors            int64
+	ParityErrors      int64     // UART parity errors this session (usually wrong parity/data bits)
+	FramingErrors     int64     // UART framing errors this session (usually wrong baud/stop bits)
+	OverrunErrors     int64     // UART/tty overruns this session (data arriving faster than it's read)
+	Breaks            int64     // Break conditions this session (line held low, e.g. cable pulled)
+	Deduped           int64     // Identical consecutive lines suppressed by dedupe_window_ms/dedupe_count
+	RateLimited       int64     // Lines dropped for exceeding max_lines_per_sec
+	PausedDropped     int64     // Lines read and discarded while the channel was paused
+	OversizeLines     int64     // Lines longer than max_line_bytes (truncated or dropped per oversize_lines)
+	Reconnects        int64     // Total reconnection attempts
+	LastError         string    // Why the last session failed (cleared when the port opens)
+	LastErrorTime     time.Time // When LastError was recorded
+	SessionStart      time.Time // When the current session opened the port
+	TimeToFirstLineMs int64     // Open to first line this session (0 = no line yet)
+	SessionAgeSec     int64     // Seconds since SessionStart; with no first line, how long the feed has been silent
+	LastLineTime      time.Time
+	DetectedBaud      int
+	BaudSource        string // Where DetectedBaud came from: BaudSourceConfigured or BaudSourceDetected
+	DevicePath        string // Device node currently in use (differs from Device when remapped via device_by_id)
+	DetectedFlow      bool
+	DataBits          int    // Data bits in use (configured or detected; 0 = default 8)
+	Parity            string // Parity in use (configured or detected; "" = default none)
+	Stalled           bool   // No line for longer than stall_after_sec (always false when unset)
+	ExtraPubErrors    int64  // Failed publishes to extra_subjects (the write itself still succeeds)
+	LogDropped        int64  // Buffered records lost to a failed log write or flush (still published)
+	StartTime         time.Time
+	Signals           *ModemSignals `json:"signals,omitempty"` // RS-232 modem signals (nil if unavailable)
 }
 
+// Baud sources reported in ChannelStats.BaudSource
+const (
+	BaudSourceConfigured = "configured" // baud_rate from config was used as-is
+	BaudSourceDetected   = "detected"   // Autobaud detection chose the rate
+)
+
 // NATSChecker provides a way to check NATS connection status
 type NATSChecker interface {
 	IsConnected() bool
@@ -115,26 +179,87 @@ type Channel struct {
 	logConfig  *config.LoggingConfig
 
 	reader      *serial.ReaderWithStats
+	openReader  atomic.Pointer[serial.ReaderWithStats] // reader, published for Stop to force-close
 	dualWriter  *output.DualWriter
-	natsChecker NATSChecker // For checking NATS connection status
+	natsChecker NATSChecker      // For checking NATS connection status
+	timestamper *lineTimestamper // Header time from the data (nil = receive time)
+	headerFmt   output.HeaderFormat
+	deduper     *lineDeduper  // Suppresses repeated lines (nil = off)
+	redactor    *lineRedactor // Masks PII before output (nil = off)
+	limiter     *tokenBucket  // Enforces max_lines_per_sec (nil = unlimited)
+	limiting    bool          // Currently dropping lines; rate_limited event already fired
 
 	state      ChannelState
 	stateMutex sync.RWMutex
+	paused     atomic.Bool // Set by Pause: running shows as StatePaused and lines are discarded
 
 	stats               ChannelStats
 	consecutiveFailures int64 // For exponential backoff calculation, reset on success
-	garbledLineCount    int   // Consecutive lines with low ASCII validity
+	reconnectEvents     reconnectCoalescer
+	garbledLineCount    int  // Consecutive lines with low ASCII validity
+	deviceRemoved       bool // Device node was missing at last check (USB adapter unplugged)
+	awaitingFirstLine   bool // Session open, TimeToFirstLineMs not yet recorded
+	stalled             bool // Stalled at the last stall check; stalled event already fired
 	statsMutex          sync.RWMutex
+	lastRecord          string // Last line written, when record_preview is on
 
 	// Event callback (optional) - called on state changes, errors, etc.
 	// Set via SetEventCallback. If nil, events are silently ignored.
 	eventCallback output.EventCallback
 
+	// Re-detection requests from TriggerRedetect (buffered, size 1).
+	// forceDetect is only touched by the capture goroutine.
+	redetectCh  chan struct{}
+	forceDetect bool
+
+	// detect runs serial detection on a device (nil = serial.Detector).
+	// Replaced in tests, which have no real port to probe.
+	detect func(ctx context.Context, device string) (*serial.DetectionResult, error)
+
 	stopCh chan struct{}
 	wg     sync.WaitGroup
 	logger *slog.Logger
 }
 
+// portFIPSCode returns the port-specific FIPS code, or the app-level one
+func portFIPSCode(portCfg *config.PortConfig, appCfg *config.AppConfig) string {
+	if portCfg.FIPSCode != "" {
+		return portCfg.FIPSCode
+	}
+	return appCfg.FIPSCode
+}
+
+// logFilename expands the logging filename template for a port
+func logFilename(portCfg *config.PortConfig, appCfg *config.AppConfig, logCfg *config.LoggingConfig) string {
+	return logCfg.LogFilename(portFIPSCode(portCfg, appCfg), portCfg.SideDesignation, portCfg.County, appCfg.InstanceID)
+}
+
+// channelIdentifier returns a port's log identifier, FIPSCODE-A1 (e.g.,
+// 1429010002-A1). It keys the log file and the live SSE feed.
+func channelIdentifier(portCfg *config.PortConfig, appCfg *config.AppConfig) string {
+	return fmt.Sprintf("%s-%s", portFIPSCode(portCfg, appCfg), portCfg.SideDesignation)
+}
+
+// channelNaming returns the log identifier and NATS subject for a port.
+// The identifier is FIPSCODE-A1 (e.g., 1429010002-A1). Serial subjects use the
+// PEMA format {prefix}.{vendor}.{county}.{fips}, falling back to simpler forms
+// when vendor/county are not specified; HTTP subjects omit the county.
+func channelNaming(portCfg *config.PortConfig, appCfg *config.AppConfig, subjectPrefix string) (identifier, natsSubject string) {
+	fipsCode := portFIPSCode(portCfg, appCfg)
+
+	identifier = channelIdentifier(portCfg, appCfg)
+
+	switch {
+	case portCfg.Vendor != "" && portCfg.County != "" && !portCfg.IsHTTP():
+		natsSubject = fmt.Sprintf("%s.%s.%s.%s", subjectPrefix, portCfg.Vendor, portCfg.County, fipsCode)
+	case portCfg.Vendor != "":
+		natsSubject = fmt.Sprintf("%s.%s.%s", subjectPrefix, portCfg.Vendor, fipsCode)
+	default:
+		natsSubject = fmt.Sprintf("%s.%s", subjectPrefix, fipsCode)
+	}
+	return identifier, natsSubject
+}
+
 // NewChannel creates a new capture channel.
 // natsConn is required - serial capture is blocked when NATS is unavailable to prevent data loss.
 func NewChannel(
@@ -151,38 +276,42 @@ func NewChannel(
 		return nil, fmt.Errorf("NATS connection is required")
 	}
 
-	// Get FIPS code (port-specific or app-level)
-	fipsCode := portCfg.FIPSCode
-	if fipsCode == "" {
-		fipsCode = appCfg.FIPSCode
-	}
-
-	// Create identifier in format: FIPSCODE-A1 (e.g., 1429010002-A1)
-	identifier := fmt.Sprintf("%s-%s", fipsCode, portCfg.SideDesignation)
-
-	// Create NATS subject in PEMA format: ne.cdr.intrado.lancaster.3110900001
-	// Format: {prefix}.{vendor}.{county}.{fips}
-	// Falls back to simpler format if vendor/county not specified
-	var natsSubject string
-	if portCfg.Vendor != "" && portCfg.County != "" {
-		natsSubject = fmt.Sprintf("%s.%s.%s.%s", natsCfg.SubjectPrefix, portCfg.Vendor, portCfg.County, fipsCode)
-	} else if portCfg.Vendor != "" {
-		natsSubject = fmt.Sprintf("%s.%s.%s", natsCfg.SubjectPrefix, portCfg.Vendor, fipsCode)
-	} else {
-		natsSubject = fmt.Sprintf("%s.%s", natsCfg.SubjectPrefix, fipsCode)
-	}
+	identifier, natsSubject := channelNaming(portCfg, appCfg, natsCfg.SubjectPrefix)
 
 	// Build dual writer config
 	dwConfig := &output.DualWriterConfig{
-		Device:        portCfg.Device,
-		Identifier:    identifier,
-		LogBasePath:   logCfg.BasePath,
-		LogMaxSizeMB:  logCfg.MaxSizeMB,
-		LogMaxBackups: logCfg.MaxBackups,
-		LogCompress:   logCfg.Compress,
-		NATSConn:      natsConn,
-		NATSSubject:   natsSubject,
-		Logger:        logger,
+		Device:          portCfg.Source(),
+		Identifier:      identifier,
+		LogFilename:     logFilename(portCfg, appCfg, logCfg),
+		LogBasePath:     logCfg.BasePath,
+		LogMaxSizeMB:    logCfg.MaxSizeMB,
+		LogMaxBackups:   logCfg.MaxBackups,
+		LogCompress:     logCfg.Compress,
+		NATSConn:        natsConn,
+		NATSSubject:     natsSubject,
+		CompressPayload: portCfg.CompressPayload,
+		ExtraSubjects:   portCfg.ExtraSubjects,
+		Logger:          logger,
+
+		LogBufferSize:    logCfg.WriteBufferKB * 1024,
+		LogFlushInterval: logCfg.FlushInterval(),
+	}
+
+	timestamper, err := newLineTimestamper(portCfg)
+	if err != nil {
+		return nil, err
+	}
+	deduper, err := newLineDeduper(portCfg)
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := newLineRedactor(portCfg)
+	if err != nil {
+		return nil, err
+	}
+	headerFmt, err := output.NewHeaderFormat(appCfg.TimeZone, appCfg.TimestampPrecision)
+	if err != nil {
+		return nil, err
 	}
 
 	dualWriter, err := output.NewDualWriter(dwConfig)
@@ -192,6 +321,11 @@ func NewChannel(
 
 	return &Channel{
 		config:      portCfg,
+		timestamper: timestamper,
+		headerFmt:   headerFmt,
+		deduper:     deduper,
+		redactor:    redactor,
+		limiter:     newTokenBucket(portCfg.MaxLinesPerSec, time.Now()),
 		detection:   detectionCfg,
 		natsConfig:  natsCfg,
 		recovery:    recoveryCfg,
@@ -200,6 +334,7 @@ func NewChannel(
 		dualWriter:  dualWriter,
 		natsChecker: natsConn,
 		state:       StateDetecting,
+		redetectCh:  make(chan struct{}, 1),
 		stopCh:      make(chan struct{}),
 		logger:      logger,
 	}, nil
@@ -216,14 +351,108 @@ func (c *Channel) Start(ctx context.Context) error {
 	c.wg.Add(1)
 	go c.captureLoop(ctx)
 
+	if c.config.StallAfterSec > 0 {
+		c.wg.Add(1)
+		go c.stallWatchLoop(ctx)
+	}
+
 	return nil
 }
 
+// stallCheckInterval is the longest gap between stall checks
+const stallCheckInterval = 30 * time.Second
+
+// stallWatchLoop periodically checks for a stall so the stalled event fires
+// even when nothing is polling the stats
+func (c *Channel) stallWatchLoop(ctx context.Context) {
+	defer c.wg.Done()
+
+	interval := stallCheckInterval
+	if threshold := c.config.StallAfter(); threshold < interval {
+		interval = threshold
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.stopCh:
+			return
+		case now := <-ticker.C:
+			c.checkStall(now)
+		}
+	}
+}
+
+// stalledAt reports whether no line has arrived within stall_after_sec of
+// now, counting from channel start if there hasn't been one. Caller must
+// hold statsMutex.
+func (c *Channel) stalledAt(now time.Time) bool {
+	threshold := c.config.StallAfter()
+	if threshold <= 0 {
+		return false
+	}
+	last := c.stats.LastLineTime
+	if last.IsZero() {
+		last = c.stats.StartTime
+	}
+	return !last.IsZero() && now.Sub(last) > threshold
+}
+
+// checkStall fires the stalled event on the transition into stalled and
+// logs when lines resume
+func (c *Channel) checkStall(now time.Time) {
+	c.statsMutex.Lock()
+	stalled := c.stalledAt(now)
+	changed := stalled != c.stalled
+	c.stalled = stalled
+	last := c.stats.LastLineTime
+	c.statsMutex.Unlock()
+
+	if !changed {
+		return
+	}
+	if !stalled {
+		c.logger.Info("Channel no longer stalled", "device", c.config.Device)
+		return
+	}
+
+	silence := "since start"
+	if !last.IsZero() {
+		silence = "for " + now.Sub(last).Round(time.Second).String()
+	}
+	c.logger.Warn("Channel stalled, no lines "+silence, "device", c.config.Device, "stall_after_sec", c.config.StallAfterSec)
+	if c.eventCallback != nil {
+		c.eventCallback(output.Event{
+			Type:    output.EventStalled,
+			Channel: c.config.SideDesignation,
+			Device:  c.config.Device,
+			Message: "No lines " + silence,
+			Details: map[string]any{
+				"stall_after_sec": c.config.StallAfterSec,
+				"last_line_time":  last,
+			},
+		})
+	}
+}
+
 // Stop stops the capture channel
 func (c *Channel) Stop() {
 	c.logger.Info("Stopping capture channel", "device", c.config.Device)
 	close(c.stopCh)
-	c.wg.Wait()
+	if !c.waitStopped(c.stopGrace()) {
+		// Stuck in a Read (or a driver that ignores the read timeout):
+		// close the port under it so the loop sees an error and exits
+		c.logger.Warn("Capture loop did not stop in time, force-closing port",
+			"device", c.config.Source(), "grace", c.stopGrace())
+		if r := c.openReader.Load(); r != nil {
+			r.Abort()
+		}
+		c.wg.Wait()
+	}
+	c.reconnectEvents.Disarm()
 
 	if c.reader != nil {
 		c.reader.Close()
@@ -237,6 +466,33 @@ func (c *Channel) Stop() {
 	c.logger.Info("Capture channel stopped", "device", c.config.Device)
 }
 
+// stopGrace is how long Stop waits for the capture goroutines before
+// force-closing the port
+func (c *Channel) stopGrace() time.Duration {
+	if c.recovery == nil {
+		return config.DefaultStopGraceMs * time.Millisecond
+	}
+	return c.recovery.StopGrace()
+}
+
+// waitStopped waits up to timeout for the capture goroutines to exit
+func (c *Channel) waitStopped(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
 // captureLoop is the main loop for the capture channel
 func (c *Channel) captureLoop(ctx context.Context) {
 	defer c.wg.Done()
@@ -248,8 +504,30 @@ func (c *Channel) captureLoop(ctx context.Context) {
 		case <-c.stopCh:
 			return
 		default:
-			if err := c.runCaptureSession(ctx); err != nil {
+			err := c.runCaptureSession(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case <-c.stopCh:
+				// A force-closed port fails the read; that's not a session error
+				return
+			default:
+			}
+			if errors.Is(err, errRedetect) {
+				c.logger.Info("Re-detection requested, restarting session", "device", c.config.Device)
+				c.forceDetect = true
+				continue
+			}
+			if errors.Is(err, errSessionExpired) {
+				// Planned reopen: not a failure, so no backoff or reconnect count
+				c.logger.Info("Max session duration reached, reopening port",
+					"device", c.config.Source(), "max_session_duration", c.maxSessionDuration())
+				continue
+			}
+			if err != nil {
 				c.logger.Error("Capture session failed", "device", c.config.Device, "error", err)
+				c.recordSessionError(err)
 				c.setState(StateReconnecting)
 				c.handleReconnect(ctx)
 			}
@@ -257,8 +535,216 @@ func (c *Channel) captureLoop(ctx context.Context) {
 	}
 }
 
+// recordOversizeLine counts a line that exceeded max_line_bytes
+func (c *Channel) recordOversizeLine() {
+	c.statsMutex.Lock()
+	c.stats.OversizeLines++
+	c.statsMutex.Unlock()
+
+	policy := c.config.OversizeLines
+	if policy == "" {
+		policy = config.OversizeTruncate
+	}
+	c.logger.Warn("Oversized line", "device", c.config.Device, "policy", policy)
+}
+
+// recordSessionError retains why a session failed so the API can show it
+func (c *Channel) recordSessionError(err error) {
+	c.statsMutex.Lock()
+	c.stats.LastError = err.Error()
+	c.stats.LastErrorTime = time.Now()
+	c.statsMutex.Unlock()
+}
+
+// sessionOpened resets failure tracking once the port is open and starts
+// timing the first line of the session
+func (c *Channel) sessionOpened() {
+	c.statsMutex.Lock()
+	c.consecutiveFailures = 0
+	c.garbledLineCount = 0
+	c.stats.LastError = ""
+	c.stats.LastErrorTime = time.Time{}
+	c.stats.SessionStart = time.Now()
+	c.stats.TimeToFirstLineMs = 0
+	c.awaitingFirstLine = true
+	c.statsMutex.Unlock()
+
+	// After a reconnect storm, report recovery once the session holds
+	c.reconnectEvents.ArmRecovery(c.reconnectEventInterval(), c.emitRecovered)
+}
+
+// reconnectEventInterval is the minimum spacing of reconnect events
+func (c *Channel) reconnectEventInterval() time.Duration {
+	if c.recovery == nil {
+		return 0
+	}
+	return c.recovery.ReconnectEventInterval()
+}
+
+// emitRecovered fires the single recovered event that ends a reconnect storm
+func (c *Channel) emitRecovered(attempts int64, duration time.Duration) {
+	c.logger.Info("Channel recovered after reconnects",
+		"device", c.config.Device,
+		"attempts", attempts,
+		"duration", duration.Round(time.Second))
+
+	if c.eventCallback != nil {
+		c.eventCallback(output.Event{
+			Type:    output.EventRecovered,
+			Channel: c.config.SideDesignation,
+			Device:  c.config.Device,
+			Message: fmt.Sprintf("Recovered after %d reconnection attempts", attempts),
+			Details: map[string]any{
+				"attempts":     attempts,
+				"duration_sec": int64(duration.Seconds()),
+			},
+		})
+	}
+}
+
+// recordFirstLine measures open-to-first-line latency once per session
+func (c *Channel) recordFirstLine(now time.Time) {
+	c.statsMutex.Lock()
+	defer c.statsMutex.Unlock()
+	if !c.awaitingFirstLine {
+		return
+	}
+	c.awaitingFirstLine = false
+	c.stats.TimeToFirstLineMs = max(now.Sub(c.stats.SessionStart).Milliseconds(), 1)
+}
+
+// errRedetect is returned by the read loops when TriggerRedetect is called
+var errRedetect = fmt.Errorf("re-detection requested")
+
+// errSessionExpired is returned by the read loops when a session reaches
+// max_session_duration_sec, so the port is closed and reopened cleanly
+var errSessionExpired = fmt.Errorf("max session duration reached")
+
+// maxSessionDuration is how long a session may run before a proactive reopen
+// (0 = unlimited). The port setting overrides the recovery default.
+func (c *Channel) maxSessionDuration() time.Duration {
+	if d := c.config.MaxSessionDuration(); d > 0 {
+		return d
+	}
+	if c.recovery == nil {
+		return 0
+	}
+	return c.recovery.MaxSessionDuration()
+}
+
+// sessionExpired reports whether a session that started at start has run for
+// its maximum duration
+func (c *Channel) sessionExpired(start, now time.Time) bool {
+	limit := c.maxSessionDuration()
+	return limit > 0 && now.Sub(start) >= limit
+}
+
+// Pause stops the channel writing records while leaving its port open.
+// Lines keep being read and are counted in PausedDropped.
+func (c *Channel) Pause() {
+	c.paused.Store(true)
+	if c.State() == StateRunning {
+		c.setState(StatePaused)
+	}
+}
+
+// Resume undoes Pause
+func (c *Channel) Resume() {
+	c.paused.Store(false)
+	if c.State() == StatePaused {
+		c.setState(StateRunning)
+	}
+}
+
+// Paused reports whether the channel has been paused
+func (c *Channel) Paused() bool {
+	return c.paused.Load()
+}
+
+// TriggerRedetect asks the channel to abandon its current session and re-run
+// detection, ignoring configured baud_rate/flow control for that session only.
+// Safe to call from any goroutine; repeated calls before the channel reacts
+// collapse into one.
+func (c *Channel) TriggerRedetect() {
+	select {
+	case c.redetectCh <- struct{}{}:
+	default:
+	}
+}
+
+// redetectRequested reports whether re-detection was requested, either by a
+// session that was interrupted or by a trigger that arrived between sessions
+func (c *Channel) redetectRequested() bool {
+	forced := c.forceDetect
+	c.forceDetect = false
+	select {
+	case <-c.redetectCh:
+		forced = true
+	default:
+	}
+	return forced
+}
+
+// runDetection probes the device for baud rate, flow control and (optionally) framing
+func (c *Channel) runDetection(ctx context.Context, device string) (*serial.DetectionResult, error) {
+	if c.detect != nil {
+		return c.detect(ctx, device)
+	}
+	detector := serial.NewDetector(
+		device,
+		c.detection.BaudRates,
+		c.detection.DetectionTimeout(),
+		c.detection.MinBytesForValid,
+		c.logger,
+	)
+	if c.detection.DetectFraming {
+		detector.SetFramings(detectFramings(c.config))
+	}
+	detector.SetModemOutputs(c.config.AssertDTR, c.config.AssertRTS)
+	return detector.Detect(ctx)
+}
+
+// detectFramings narrows the framing sweep to combinations consistent with
+// the port's explicit data_bits/parity, so detection never overrides them.
+// If none of the defaults match, the configured framing is the only candidate.
+func detectFramings(portCfg *config.PortConfig) []serial.Framing {
+	var framings []serial.Framing
+	for _, f := range serial.DefaultFramings {
+		if (portCfg.DataBits == 0 || portCfg.DataBits == f.DataBits) &&
+			(portCfg.Parity == "" || portCfg.Parity == f.Parity) {
+			framings = append(framings, f)
+		}
+	}
+	if len(framings) == 0 {
+		f := serial.Framing{DataBits: portCfg.DataBits, Parity: portCfg.Parity}
+		if f.DataBits == 0 {
+			f.DataBits = 8
+		}
+		if f.Parity == "" {
+			f.Parity = "none"
+		}
+		framings = append(framings, f)
+	}
+	return framings
+}
+
 // runCaptureSession runs a single capture session (detect + read)
 func (c *Channel) runCaptureSession(ctx context.Context) error {
+	if c.config.IsTCP() {
+		return c.runTCPSession(ctx)
+	}
+	if c.config.IsFile() {
+		return c.runReplaySession(ctx)
+	}
+
+	// Phase 0: Find the device node - it may have been unplugged or renamed
+	device, err := serial.ResolveDevicePath(c.config.Device, c.config.DeviceByID)
+	if err != nil {
+		c.markDeviceRemoved()
+		return err
+	}
+	c.markDevicePresent(device)
+
 	// Phase 1: Detection (if needed)
 	baudRate := c.config.BaudRate
 	useFlowControl := false
@@ -266,28 +752,50 @@ func (c *Channel) runCaptureSession(ctx context.Context) error {
 		useFlowControl = *c.config.UseFlowControl
 	}
 
-	needsDetection := baudRate == 0 || c.config.UseFlowControl == nil
+	// An explicit flow_control setting means there's no pinout to detect
+	flowConfigured := c.config.UseFlowControl != nil || c.config.FlowControl != ""
+	if c.config.FlowControl != "" {
+		useFlowControl = c.config.FlowControl == serial.FlowControlHardware
+	}
+
+	// A re-detect request overrides configured settings for this session
+	if c.redetectRequested() {
+		baudRate = 0
+		flowConfigured = false
+	}
+
+	needsDetection := baudRate == 0 || !flowConfigured
+
+	// Framing comes from config; detection only fills in what's left unset
+	dataBits := c.config.DataBits
+	parity := c.config.Parity
 
 	if needsDetection {
 		c.setState(StateDetecting)
 		c.logger.Info("Running detection", "device", c.config.Device)
 
-		detector := serial.NewDetector(
-			c.config.Device,
-			c.detection.BaudRates,
-			c.detection.DetectionTimeout(),
-			c.detection.MinBytesForValid,
-			c.logger,
-		)
-
-		result, err := detector.Detect()
+		result, err := c.runDetection(ctx, device)
+		if ctx.Err() != nil {
+			// Shutting down mid-sweep - not a detection failure
+			return ctx.Err()
+		}
 		if err != nil {
 			c.setState(StateError)
 			return fmt.Errorf("detection failed: %w", err)
 		}
 
 		baudRate = result.BaudRate
-		useFlowControl = result.UseFlowControl
+		if !flowConfigured {
+			useFlowControl = result.UseFlowControl
+		}
+		if c.detection.DetectFraming {
+			if dataBits == 0 {
+				dataBits = result.DataBits
+			}
+			if parity == "" {
+				parity = result.Parity
+			}
+		}
 
 		c.statsMutex.Lock()
 		c.stats.DetectedBaud = baudRate
@@ -297,6 +805,8 @@ func (c *Channel) runCaptureSession(ctx context.Context) error {
 		c.logger.Info("Detection complete",
 			"device", c.config.Device,
 			"baud", baudRate,
+			"data_bits", result.DataBits,
+			"parity", result.Parity,
 			"flow_control", useFlowControl)
 
 		// Fire baud detection event
@@ -308,6 +818,8 @@ func (c *Channel) runCaptureSession(ctx context.Context) error {
 				Message: fmt.Sprintf("Baud rate auto-detected: %d", baudRate),
 				Details: map[string]any{
 					"baud_rate":    baudRate,
+					"data_bits":    result.DataBits,
+					"parity":       result.Parity,
 					"flow_control": useFlowControl,
 				},
 			})
@@ -316,20 +828,31 @@ func (c *Channel) runCaptureSession(ctx context.Context) error {
 
 	// Phase 2: Open port
 	// Always record the baud rate being used (whether configured or detected)
+	baudSource := BaudSourceConfigured
+	if needsDetection {
+		// Detection picks the baud even when only the pinout was unknown
+		baudSource = BaudSourceDetected
+	}
 	c.statsMutex.Lock()
 	c.stats.DetectedBaud = baudRate
+	c.stats.BaudSource = baudSource
 	c.stats.DetectedFlow = useFlowControl
+	c.stats.DataBits = dataBits
+	c.stats.Parity = parity
 	c.statsMutex.Unlock()
 
 	// Build serial config from port configuration
 	serialConfig := serial.SerialConfig{
 		BaudRate:       baudRate,
-		DataBits:       c.config.DataBits,
-		Parity:         c.config.Parity,
+		DataBits:       dataBits,
+		Parity:         parity,
 		StopBits:       c.config.StopBits,
 		UseFlowControl: useFlowControl,
+		FlowControl:    c.config.FlowControl,
+		AssertDTR:      c.config.AssertDTR,
+		AssertRTS:      c.config.AssertRTS,
 	}
-	reader, err := serial.NewRealReaderWithConfig(c.config.Device, serialConfig)
+	reader, err := serial.NewRealReaderWithConfig(device, serialConfig)
 	if err != nil {
 		return fmt.Errorf("failed to open port: %w", err)
 	}
@@ -337,7 +860,9 @@ func (c *Channel) runCaptureSession(ctx context.Context) error {
 	// Use defer immediately after successful open to prevent file descriptor leaks
 	// This ensures cleanup even if panic occurs between here and explicit close
 	c.reader = serial.NewReaderWithStats(reader)
+	c.openReader.Store(c.reader)
 	defer func() {
+		c.openReader.Store(nil)
 		c.reader.Close()
 		c.reader = nil
 	}()
@@ -357,35 +882,155 @@ func (c *Channel) runCaptureSession(ctx context.Context) error {
 
 	// Switch to shorter read timeout for production reads
 	// This allows faster shutdown response (500ms vs 5s)
-	if err := c.reader.SetReadTimeout(serial.DefaultReadTimeout); err != nil {
+	if err := c.reader.SetReadTimeout(c.readTimeout()); err != nil {
 		c.logger.Warn("Failed to set production read timeout", "device", c.config.Device, "error", err)
 		// Non-fatal - continue with detection timeout
 	}
 
-	// Reset failure counters on successful connection
+	c.sessionOpened()
+
+	// Phase 3: Read loop
+	if c.config.IdleGapMs > 0 {
+		return c.readIdleGapLoop(ctx, device)
+	}
+	return c.readLoop(ctx, device)
+}
+
+// runTCPSession connects to a terminal server and runs the read loop on the
+// socket. There's nothing to detect or poll: the terminal server owns the
+// line settings and a dropped connection surfaces as a read error.
+func (c *Channel) runTCPSession(ctx context.Context) error {
+	tcpReader, err := serial.NewTCPReader(c.config.Address, serial.TCPDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	c.reader = serial.NewReaderWithStats(tcpReader)
+	c.openReader.Store(c.reader)
+	defer func() {
+		c.openReader.Store(nil)
+		c.reader.Close()
+		c.reader = nil
+	}()
+
+	c.logger.Info("Connected to TCP source", "address", c.config.Address)
+	c.setState(StateRunning)
+
+	if err := c.reader.SetReadTimeout(c.readTimeout()); err != nil {
+		c.logger.Warn("Failed to set read timeout", "address", c.config.Address, "error", err)
+	}
+
+	c.sessionOpened()
+
+	// No device node to watch
+	if c.config.IdleGapMs > 0 {
+		return c.readIdleGapLoop(ctx, "")
+	}
+	return c.readLoop(ctx, "")
+}
+
+// readTimeout is the per-read timeout for production reads. With idle-gap
+// framing the timeout is the tick that notices a gap, so it can't be longer
+// than the gap itself.
+func (c *Channel) readTimeout() time.Duration {
+	readTimeout := serial.DefaultReadTimeout
+	if gap := c.config.IdleGap(); gap > 0 && gap < readTimeout {
+		readTimeout = gap
+	}
+	return readTimeout
+}
+
+// devicePollInterval is how often the read loop checks the device node still
+// exists (and, with use_modem_signal_state, the modem signals)
+const devicePollInterval = 2 * time.Second
+
+// modemSilenceThreshold is how long a port must go without data before
+// dropped modem signals move it to no_signal
+const modemSilenceThreshold = 5 * time.Second
+
+// errDeviceRemoved is returned when the device node disappears mid-session
+var errDeviceRemoved = fmt.Errorf("device removed")
+
+// markDeviceRemoved records that the device node is missing, firing a
+// device_removed event on the first check after it disappears
+func (c *Channel) markDeviceRemoved() {
 	c.statsMutex.Lock()
-	c.consecutiveFailures = 0
-	c.garbledLineCount = 0
+	wasRemoved := c.deviceRemoved
+	c.deviceRemoved = true
+	path := c.stats.DevicePath
 	c.statsMutex.Unlock()
 
-	// Phase 3: Read loop
-	return c.readLoop(ctx)
+	if wasRemoved {
+		return
+	}
+
+	c.logger.Warn("Device removed", "device", c.config.Device, "path", path)
+	if c.eventCallback != nil {
+		c.eventCallback(output.Event{
+			Type:    output.EventDeviceRemoved,
+			Channel: c.config.SideDesignation,
+			Device:  c.config.Device,
+			Message: "Serial device removed - adapter may be unplugged",
+			Details: map[string]any{"path": path},
+		})
+	}
+}
+
+// markDevicePresent records the device node in use, firing a device_added
+// event if the device was previously missing
+func (c *Channel) markDevicePresent(path string) {
+	c.statsMutex.Lock()
+	wasRemoved := c.deviceRemoved
+	oldPath := c.stats.DevicePath
+	c.deviceRemoved = false
+	c.stats.DevicePath = path
+	c.statsMutex.Unlock()
+
+	if oldPath != "" && oldPath != path {
+		c.logger.Info("Device remapped", "device", c.config.Device, "old_path", oldPath, "new_path", path)
+	}
+
+	if !wasRemoved {
+		return
+	}
+
+	c.logger.Info("Device reappeared", "device", c.config.Device, "path", path)
+	if c.eventCallback != nil {
+		c.eventCallback(output.Event{
+			Type:    output.EventDeviceAdded,
+			Channel: c.config.SideDesignation,
+			Device:  c.config.Device,
+			Message: "Serial device reappeared at " + path,
+			Details: map[string]any{
+				"path":     path,
+				"old_path": oldPath,
+			},
+		})
+	}
 }
 
 // natsCheckInterval is how often we check NATS status when waiting for reconnection
 const natsCheckInterval = 500 * time.Millisecond
 
-// readLoop reads lines from the serial port and writes them.
+// readLoop reads lines from the serial port and writes them. device is the
+// node to watch for removal ("" for a TCP source).
 // CRITICAL: This loop blocks when NATS is disconnected to prevent data loss.
 // The sending device's buffer holds data until we're ready to receive again.
-func (c *Channel) readLoop(ctx context.Context) error {
+func (c *Channel) readLoop(ctx context.Context, device string) error {
+	sessionStart := time.Now()
+	lastDeviceCheck := sessionStart
+
 	// Outer loop allows scanner recreation on "no data" errors
 	for {
 		scanner := bufio.NewScanner(c.reader)
 
-		// Increase buffer size for long lines (like Scannex, handle any line length)
-		buf := make([]byte, InitialLineBufferSize)
-		scanner.Buffer(buf, MaxLineBufferSize)
+		// Increase buffer size for long lines (like Scannex, handle any line length).
+		// Lines beyond max_line_bytes are truncated or dropped by the splitter
+		// rather than killing the scanner with ErrTooLong.
+		splitter := newLineSplitter(c.config, c.recordOversizeLine)
+		buf := make([]byte, min(InitialLineBufferSize, splitter.BufferSize()))
+		scanner.Buffer(buf, splitter.BufferSize())
+		scanner.Split(splitter.Split)
 
 		shouldRecreateScanner := false
 
@@ -396,10 +1041,26 @@ func (c *Channel) readLoop(ctx context.Context) error {
 				return nil
 			case <-c.stopCh:
 				return nil
+			case <-c.redetectCh:
+				return errRedetect
 			default:
 				// Continue
 			}
 
+			if c.sessionExpired(sessionStart, time.Now()) {
+				return errSessionExpired
+			}
+
+			// Notice an unplugged adapter even if the driver keeps returning timeouts
+			if time.Since(lastDeviceCheck) >= devicePollInterval {
+				lastDeviceCheck = time.Now()
+				if device != "" && !serial.DevicePresent(device) {
+					c.markDeviceRemoved()
+					return errDeviceRemoved
+				}
+				c.checkModemSignal(lastDeviceCheck)
+			}
+
 			// Block if NATS is disconnected - don't read serial data we can't deliver
 			if !c.waitForNATS(ctx) {
 				// Context cancelled or stop requested during wait
@@ -454,10 +1115,46 @@ func (c *Channel) readLoop(ctx context.Context) error {
 	}
 }
 
+// checkModemSignal moves a silent running port to no_signal once DCD and DSR
+// have both dropped (use_modem_signal_state). processLine moves it back to
+// running when data resumes.
+func (c *Channel) checkModemSignal(now time.Time) {
+	if !c.config.UseModemSignalState || c.State() != StateRunning {
+		return
+	}
+
+	c.statsMutex.RLock()
+	lastData := c.stats.LastLineTime
+	if c.stats.SessionStart.After(lastData) {
+		lastData = c.stats.SessionStart
+	}
+	c.statsMutex.RUnlock()
+
+	if now.Sub(lastData) < modemSilenceThreshold {
+		return
+	}
+
+	modem, err := c.reader.GetModemStatus()
+	if err != nil || modem == nil || modem.DCD || modem.DSR {
+		return
+	}
+
+	c.logger.Warn("Modem signals dropped and no data, cable may be disconnected",
+		"device", c.config.Device, "silent_for", now.Sub(lastData).Round(time.Second))
+	c.setState(StateNoSignal)
+}
+
 // waitForNATS blocks until NATS is connected or shutdown is requested.
 // Returns true if NATS is connected and we should continue reading.
 // Returns false if shutdown was requested and we should exit.
 func (c *Channel) waitForNATS(ctx context.Context) bool {
+	// A stopping channel must not start (or resume) reading
+	select {
+	case <-c.stopCh:
+		return false
+	default:
+	}
+
 	if c.natsChecker.IsConnected() {
 		return true
 	}
@@ -568,6 +1265,38 @@ func (c *Channel) processLine(line string) {
 		c.logger.Info("Signal detected, now receiving data", "device", c.config.Device)
 	}
 
+	c.recordFirstLine(time.Now())
+
+	// Paused: keep draining the port so the device doesn't back up, but
+	// write nothing
+	if c.paused.Load() {
+		c.reader.LineRead()
+		c.statsMutex.Lock()
+		c.stats.PausedDropped++
+		c.stats.LastLineTime = time.Now()
+		c.statsMutex.Unlock()
+		return
+	}
+
+	// Drop lines beyond max_lines_per_sec (a faulted port can spew garbage)
+	if !c.allowLine(time.Now()) {
+		c.reader.LineRead()
+		c.statsMutex.Lock()
+		c.stats.RateLimited++
+		c.statsMutex.Unlock()
+		return
+	}
+
+	// Drop repeats of the previous line (misbehaving devices resend CDRs)
+	if c.deduper.Suppress(line, time.Now()) {
+		c.reader.LineRead()
+		c.statsMutex.Lock()
+		c.stats.Deduped++
+		c.stats.LastLineTime = time.Now()
+		c.statsMutex.Unlock()
+		return
+	}
+
 	// Get FIPS code (port-specific or app-level)
 	fipsCode := c.config.FIPSCode
 	if fipsCode == "" {
@@ -575,23 +1304,115 @@ func (c *Channel) processLine(line string) {
 	}
 
 	// Build header
-	header := output.BuildHeader(fipsCode, c.config.SideDesignation, time.Now().UTC())
+	header := c.headerFmt.Build(fipsCode, c.config.SideDesignation, c.timestamper.Timestamp(line, time.Now().UTC()))
 
-	// Write to both log and NATS
-	fullLine := header + line
+	// Write to both log and NATS; both only ever see the redacted form
+	redacted := c.redactor.Redact(line)
+	fullLine := output.AppendChecksum(header+redacted, c.config.AppendChecksum, []byte(redacted))
 	if err := c.dualWriter.WriteLine(fullLine); err != nil {
 		c.logger.Warn("Write error", "device", c.config.Device, "error", err)
 		c.reader.IncrementErrors()
 	}
 
+	// The original goes only to the restricted subject, if configured
+	if c.config.UnredactedSubject != "" && redacted != line {
+		original := output.AppendChecksum(header+line, c.config.AppendChecksum, []byte(line))
+		publishUnredacted(c.dualWriter, c.config, original, c.logger)
+	}
+
 	// Update stats
 	c.reader.LineRead()
 
 	c.statsMutex.Lock()
 	c.stats.LastLineTime = time.Now()
+	if c.config.RecordPreview {
+		c.lastRecord = recordPreview(redacted)
+	}
 	c.statsMutex.Unlock()
 }
 
+// extraPublishErrors returns the writer's failed extra_subjects publishes (0 without a writer)
+func extraPublishErrors(dw *output.DualWriter) int64 {
+	if dw == nil {
+		return 0
+	}
+	return dw.ExtraPublishErrors()
+}
+
+// logDropped returns the writer's buffered records lost to log failures (0 without a writer)
+func logDropped(dw *output.DualWriter) int64 {
+	if dw == nil {
+		return 0
+	}
+	return dw.DroppedLogRecords()
+}
+
+// allowLine applies the max_lines_per_sec limit, firing a rate_limited event
+// once when the channel starts dropping lines. The event re-arms once the
+// bucket has refilled, i.e. the line rate stayed under the limit for a second.
+func (c *Channel) allowLine(now time.Time) bool {
+	if c.limiter == nil {
+		return true
+	}
+
+	if c.limiter.Allow(now) {
+		if c.limiting && c.limiter.Full() {
+			c.limiting = false
+		}
+		return true
+	}
+
+	if !c.limiting {
+		c.limiting = true
+		c.logger.Warn("Line rate limit exceeded, dropping lines",
+			"device", c.config.Device, "max_lines_per_sec", c.config.MaxLinesPerSec)
+		if c.eventCallback != nil {
+			c.eventCallback(output.Event{
+				Type:    output.EventRateLimited,
+				Channel: c.config.SideDesignation,
+				Device:  c.config.Device,
+				Message: fmt.Sprintf("Line rate exceeded %d/s - dropping excess lines", c.config.MaxLinesPerSec),
+				Details: map[string]any{"max_lines_per_sec": c.config.MaxLinesPerSec},
+			})
+		}
+	}
+	return false
+}
+
+// reconnectDelay returns the wait before the next reconnect attempt:
+// exponential backoff on consecutive failures, then jitter, capped at
+// max_reconnect_delay_sec
+func (c *Channel) reconnectDelay(failures int64) time.Duration {
+	delay := c.recovery.ReconnectDelay()
+	maxDelay := c.recovery.MaxReconnectDelay()
+	if c.recovery.ExponentialBackoff && failures > 1 {
+		// Cap the exponent to avoid overflow with very large failure counts
+		exponent := math.Min(float64(failures-1), 30)
+		multiplier := math.Pow(2, exponent)
+		calculatedDelay := time.Duration(float64(delay) * multiplier)
+		if calculatedDelay > maxDelay {
+			delay = maxDelay
+		} else {
+			delay = calculatedDelay
+		}
+	}
+
+	delay = withJitter(delay, c.recovery.ReconnectJitter())
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// withJitter spreads d uniformly over ±frac of itself so collectors that
+// lost the same server don't all retry at the same instant
+func withJitter(d time.Duration, frac float64) time.Duration {
+	if frac <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + frac*(2*rand.Float64()-1)))
+}
+
 // handleReconnect waits before attempting reconnection, using exponential backoff.
 // It tracks consecutive failures and increases delay accordingly.
 func (c *Channel) handleReconnect(ctx context.Context) {
@@ -602,34 +1423,27 @@ func (c *Channel) handleReconnect(ctx context.Context) {
 	reconnects := c.stats.Reconnects
 	c.statsMutex.Unlock()
 
-	// Fire reconnect event
-	if c.eventCallback != nil {
+	// Fire reconnect event, coalesced so a flapping cable doesn't flood the
+	// events stream: "attempts" counts the attempts this event stands for
+	if emit, attempts := c.reconnectEvents.Attempt(time.Now(), c.reconnectEventInterval()); emit && c.eventCallback != nil {
+		message := fmt.Sprintf("Reconnection attempt %d", reconnects)
+		if attempts > 1 {
+			message = fmt.Sprintf("Reconnection attempt %d (%d attempts since last report)", reconnects, attempts)
+		}
 		c.eventCallback(output.Event{
 			Type:    output.EventReconnect,
 			Channel: c.config.SideDesignation,
 			Device:  c.config.Device,
-			Message: fmt.Sprintf("Reconnection attempt %d", reconnects),
+			Message: message,
 			Details: map[string]any{
 				"attempt":              reconnects,
+				"attempts":             attempts,
 				"consecutive_failures": failures,
 			},
 		})
 	}
 
-	// Calculate delay with exponential backoff based on consecutive failures
-	delay := c.recovery.ReconnectDelay()
-	if c.recovery.ExponentialBackoff && failures > 1 {
-		maxDelay := c.recovery.MaxReconnectDelay()
-		// Cap the exponent to avoid overflow with very large failure counts
-		exponent := math.Min(float64(failures-1), 30)
-		multiplier := math.Pow(2, exponent)
-		calculatedDelay := time.Duration(float64(delay) * multiplier)
-		if calculatedDelay > maxDelay {
-			delay = maxDelay
-		} else {
-			delay = calculatedDelay
-		}
-	}
+	delay := c.reconnectDelay(failures)
 
 	c.logger.Info("Waiting before reconnection attempt",
 		"device", c.config.Device,
@@ -656,6 +1470,11 @@ func (c *Channel) SetEventCallback(cb output.EventCallback) {
 
 // setState updates the channel state and fires an event if callback is set
 func (c *Channel) setState(state ChannelState) {
+	// A paused channel that (re)opens its port stays paused
+	if state == StateRunning && c.paused.Load() {
+		state = StatePaused
+	}
+
 	c.stateMutex.Lock()
 	oldState := c.state
 	c.state = state
@@ -702,12 +1521,26 @@ func (c *Channel) State() ChannelState {
 	return c.state
 }
 
+// LastRecord returns the truncated last line written ("" unless
+// record_preview is on)
+func (c *Channel) LastRecord() string {
+	c.statsMutex.RLock()
+	defer c.statsMutex.RUnlock()
+	return c.lastRecord
+}
+
 // Stats returns current statistics
 func (c *Channel) Stats() ChannelStats {
 	c.statsMutex.RLock()
 	defer c.statsMutex.RUnlock()
 
 	stats := c.stats
+	if !stats.SessionStart.IsZero() {
+		stats.SessionAgeSec = int64(time.Since(stats.SessionStart).Seconds())
+	}
+	stats.Stalled = c.stalledAt(time.Now())
+	stats.ExtraPubErrors = extraPublishErrors(c.dualWriter)
+	stats.LogDropped = logDropped(c.dualWriter)
 
 	// Get reader stats if available
 	if c.reader != nil {
@@ -715,6 +1548,12 @@ func (c *Channel) Stats() ChannelStats {
 		stats.BytesRead = bytesRead
 		stats.LinesRead = linesRead
 		stats.Errors = errors
+		if lineErrs, ok := c.reader.LineErrors(); ok {
+			stats.ParityErrors = lineErrs.Parity
+			stats.FramingErrors = lineErrs.Framing
+			stats.OverrunErrors = lineErrs.Overrun
+			stats.Breaks = lineErrs.Break
+		}
 
 		// Get modem signals to show connection status
 		if modem, err := c.reader.GetModemStatus(); err == nil && modem != nil {
@@ -725,9 +1564,10 @@ func (c *Channel) Stats() ChannelStats {
 				RI:  modem.RI,
 			}
 		}
-	} else {
+	} else if c.config.IsSerial() {
 		// Reader not open - try to probe modem signals by briefly opening port
-		// This allows showing cable status even during detection/reconnection
+		// This allows showing cable status even during detection/reconnection.
+		// TCP and file sources have no modem lines to probe.
 		stats.Signals = c.probeModemSignals()
 	}
 
@@ -737,7 +1577,15 @@ func (c *Channel) Stats() ChannelStats {
 // probeModemSignals briefly opens the port to check RS-232 signal levels
 // This is safe to call even when the port is being used by detection
 func (c *Channel) probeModemSignals() *ModemSignals {
-	reader, err := serial.NewRealReader(c.config.Device, 9600, false)
+	device := c.stats.DevicePath
+	if device == "" {
+		device = c.config.Device
+	}
+
+	probeConfig := serial.DefaultSerialConfig(9600, false)
+	probeConfig.AssertDTR = c.config.AssertDTR
+	probeConfig.AssertRTS = c.config.AssertRTS
+	reader, err := serial.NewRealReaderWithConfig(device, probeConfig)
 	if err != nil {
 		return nil
 	}
@@ -756,9 +1604,10 @@ func (c *Channel) probeModemSignals() *ModemSignals {
 	}
 }
 
-// Device returns the device path
+// Device returns the device path (host:port for a TCP source, the replay
+// file for a file source)
 func (c *Channel) Device() string {
-	return c.config.Device
+	return c.config.Source()
 }
 
 // SideDesignation returns the A-designation (A1-A16)
@@ -766,6 +1615,14 @@ func (c *Channel) SideDesignation() string {
 	return c.config.SideDesignation
 }
 
+// LogPath returns the channel's log file path ("" if not open)
+func (c *Channel) LogPath() string {
+	if c.dualWriter == nil {
+		return ""
+	}
+	return c.dualWriter.LogPath()
+}
+
 // FIPSCode returns the FIPS code for this channel (port-specific or app-level)
 func (c *Channel) FIPSCode() string {
 	if c.config.FIPSCode != "" {
@@ -773,3 +1630,8 @@ func (c *Channel) FIPSCode() string {
 	}
 	return c.appConfig.FIPSCode
 }
+
+// Identifier returns the channel's log identifier (FIPSCODE-A1)
+func (c *Channel) Identifier() string {
+	return channelIdentifier(c.config, c.appConfig)
+}
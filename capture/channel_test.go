@@ -1,10 +1,22 @@
 package capture
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"nectarcollector/config"
+	"nectarcollector/output"
 	"nectarcollector/serial"
 )
 
@@ -20,6 +32,7 @@ func TestChannelStateString(t *testing.T) {
 		{StateWaitingForNATS, "waiting_for_nats"},
 		{StateStopped, "stopped"},
 		{StateError, "error"},
+		{StatePaused, "paused"},
 		{ChannelState(99), "unknown"},
 	}
 
@@ -33,6 +46,32 @@ func TestChannelStateString(t *testing.T) {
 	}
 }
 
+func TestChannelStateCode(t *testing.T) {
+	// These codes are published for alerting and must never change
+	tests := []struct {
+		state ChannelState
+		want  int
+	}{
+		{StateDetecting, 0},
+		{StateRunning, 1},
+		{StateNoSignal, 2},
+		{StateReconnecting, 3},
+		{StateWaitingForNATS, 4},
+		{StateStopped, 5},
+		{StateError, 6},
+		{StatePaused, 7},
+		{ChannelState(99), -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state.String(), func(t *testing.T) {
+			if got := tt.state.StateCode(); got != tt.want {
+				t.Errorf("%s.StateCode() = %d, want %d", tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestChannelStatsDefaults(t *testing.T) {
 	stats := ChannelStats{}
 
@@ -157,3 +196,779 @@ func TestMockNATSChecker(t *testing.T) {
 		t.Error("MockNATSChecker.IsConnected() should return false")
 	}
 }
+
+func TestChannelLastError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &Channel{
+		config:     &config.PortConfig{Device: filepath.Join(t.TempDir(), "ttyMISSING"), SideDesignation: "A1"},
+		recovery:   &config.RecoveryConfig{ReconnectDelaySec: 1},
+		redetectCh: make(chan struct{}, 1),
+		stopCh:     make(chan struct{}),
+		logger:     logger,
+	}
+
+	// Opening a missing device fails the session and records why
+	ctx, cancel := context.WithCancel(context.Background())
+	c.wg.Add(1)
+	go c.captureLoop(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.statsMutex.RLock()
+		lastErr, lastErrTime := c.stats.LastError, c.stats.LastErrorTime
+		c.statsMutex.RUnlock()
+		if lastErr != "" {
+			if !strings.Contains(lastErr, "ttyMISSING") {
+				t.Errorf("LastError = %q, want it to name the device", lastErr)
+			}
+			if lastErrTime.IsZero() {
+				t.Error("LastErrorTime not set")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("LastError not set after failed open")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	c.wg.Wait()
+
+	// A successful open clears it
+	c.sessionOpened()
+	c.statsMutex.RLock()
+	defer c.statsMutex.RUnlock()
+	if c.stats.LastError != "" || !c.stats.LastErrorTime.IsZero() {
+		t.Errorf("LastError = %q at %v after open, want cleared", c.stats.LastError, c.stats.LastErrorTime)
+	}
+}
+
+func TestChannelTimeToFirstLine(t *testing.T) {
+	dir := t.TempDir()
+	device := filepath.Join(dir, "ttyTEST")
+	if err := os.WriteFile(device, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       device,
+		Identifier:   "1429010002-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	// The feed stays silent for 80ms after open, then sends two lines
+	reader := &scriptedReader{steps: []readStep{
+		{delay: 80 * time.Millisecond, data: "CALL 001\n"},
+		{delay: 200 * time.Millisecond, data: "CALL 002\n"},
+	}}
+	c := &Channel{
+		config:      &config.PortConfig{Device: device, SideDesignation: "A1"},
+		appConfig:   &config.AppConfig{FIPSCode: "1429010002"},
+		reader:      serial.NewReaderWithStats(reader),
+		dualWriter:  writer,
+		natsChecker: &MockNATSChecker{connected: true},
+		stopCh:      make(chan struct{}),
+		logger:      logger,
+	}
+
+	c.sessionOpened()
+	if err := c.readLoop(context.Background(), device); err != nil {
+		t.Fatalf("readLoop() error = %v", err)
+	}
+
+	c.statsMutex.RLock()
+	got := c.stats.TimeToFirstLineMs
+	c.statsMutex.RUnlock()
+
+	// Only the first line counts, not the later one
+	if got < 80 || got >= 250 {
+		t.Errorf("TimeToFirstLineMs = %d, want ~80", got)
+	}
+
+	// A new session resets the measurement
+	c.sessionOpened()
+	c.statsMutex.RLock()
+	defer c.statsMutex.RUnlock()
+	if c.stats.TimeToFirstLineMs != 0 || !c.awaitingFirstLine {
+		t.Errorf("after reopen TimeToFirstLineMs = %d, want 0 until a line arrives", c.stats.TimeToFirstLineMs)
+	}
+}
+
+// uartReader is a scriptedReader on a UART that counts line errors
+type uartReader struct {
+	scriptedReader
+	counts serial.LineErrors
+}
+
+func (u *uartReader) LineErrors() (serial.LineErrors, error) { return u.counts, nil }
+
+func TestChannelStatsLineErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &Channel{
+		config: &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1"},
+		reader: serial.NewReaderWithStats(&uartReader{counts: serial.LineErrors{Parity: 12, Framing: 3, Overrun: 1, Break: 2}}),
+		state:  StateRunning,
+		logger: logger,
+	}
+
+	stats := c.Stats()
+	if stats.ParityErrors != 12 || stats.FramingErrors != 3 || stats.OverrunErrors != 1 || stats.Breaks != 2 {
+		t.Errorf("Stats() line errors = %d/%d/%d/%d, want 12/3/1/2",
+			stats.ParityErrors, stats.FramingErrors, stats.OverrunErrors, stats.Breaks)
+	}
+
+	manager := NewManager(&config.Config{}, "", logger)
+	manager.channels = append(manager.channels, c)
+	got := manager.getHealthStats().Channels[0]
+	if got.ParityErrors != 12 || got.FramingErrors != 3 || got.OverrunErrors != 1 || got.Breaks != 2 {
+		t.Errorf("health line errors = %d/%d/%d/%d, want 12/3/1/2",
+			got.ParityErrors, got.FramingErrors, got.OverrunErrors, got.Breaks)
+	}
+
+	// Sources without a UART leave them zero
+	c.reader = serial.NewReaderWithStats(&scriptedReader{})
+	if stats := c.Stats(); stats.ParityErrors != 0 || stats.Breaks != 0 {
+		t.Errorf("Stats() without a UART = %+v, want no line errors", stats)
+	}
+}
+
+func TestChannelStatsSilentSession(t *testing.T) {
+	c := &Channel{
+		config: &config.PortConfig{Device: "/dev/ttyTEST"},
+		reader: serial.NewReaderWithStats(&scriptedReader{}),
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	// Port opened three minutes ago and nothing has arrived
+	c.sessionOpened()
+	c.statsMutex.Lock()
+	c.stats.SessionStart = time.Now().Add(-3 * time.Minute)
+	c.statsMutex.Unlock()
+
+	stats := c.Stats()
+	if stats.TimeToFirstLineMs != 0 {
+		t.Errorf("TimeToFirstLineMs = %d, want 0 with no line", stats.TimeToFirstLineMs)
+	}
+	if stats.SessionAgeSec < 180 || stats.SessionAgeSec > 185 {
+		t.Errorf("SessionAgeSec = %d, want ~180", stats.SessionAgeSec)
+	}
+}
+
+// modemReader is a scriptedReader whose modem signals can be changed mid-test
+type modemReader struct {
+	scriptedReader
+	status serial.ModemStatus
+}
+
+func (m *modemReader) GetModemStatus() (*serial.ModemStatus, error) {
+	status := m.status
+	return &status, nil
+}
+
+func TestChannelModemSignalState(t *testing.T) {
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       filepath.Join(dir, "ttyTEST"),
+		Identifier:   "1429010002-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	newChannel := func(enabled bool, reader *modemReader) (*Channel, *[]output.Event) {
+		var events []output.Event
+		c := &Channel{
+			config:        &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1", UseModemSignalState: enabled},
+			appConfig:     &config.AppConfig{FIPSCode: "1429010002"},
+			reader:        serial.NewReaderWithStats(reader),
+			dualWriter:    writer,
+			state:         StateRunning,
+			eventCallback: func(e output.Event) { events = append(events, e) },
+			logger:        logger,
+		}
+		c.sessionOpened()
+		return c, &events
+	}
+
+	t.Run("signals drop and data stops", func(t *testing.T) {
+		reader := &modemReader{status: serial.ModemStatus{DCD: true, DSR: true}}
+		c, events := newChannel(true, reader)
+		start := time.Now()
+
+		// Signals up: silence alone isn't no_signal
+		c.checkModemSignal(start.Add(time.Minute))
+		if c.State() != StateRunning {
+			t.Fatalf("state = %s with signals asserted, want running", c.State())
+		}
+
+		// Signals drop, but data only just stopped
+		reader.status = serial.ModemStatus{}
+		c.checkModemSignal(start.Add(time.Second))
+		if c.State() != StateRunning {
+			t.Fatalf("state = %s before silence threshold, want running", c.State())
+		}
+
+		c.checkModemSignal(start.Add(modemSilenceThreshold + time.Second))
+		if c.State() != StateNoSignal {
+			t.Fatalf("state = %s, want no_signal", c.State())
+		}
+
+		// Data resumes
+		reader.status = serial.ModemStatus{DCD: true, DSR: true}
+		c.processLine("CALL 001")
+		if c.State() != StateRunning {
+			t.Errorf("state = %s after data, want running", c.State())
+		}
+
+		var types []string
+		for _, e := range *events {
+			types = append(types, e.Type)
+		}
+		got := strings.Join(types, ",")
+		want := strings.Join([]string{output.EventStateChange, output.EventSignalLost, output.EventStateChange, output.EventSignalDetected}, ",")
+		if got != want {
+			t.Errorf("events = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("recent data keeps running", func(t *testing.T) {
+		reader := &modemReader{}
+		c, _ := newChannel(true, reader)
+		c.processLine("CALL 001")
+
+		c.checkModemSignal(time.Now().Add(time.Second))
+		if c.State() != StateRunning {
+			t.Errorf("state = %s with recent data, want running", c.State())
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		c, _ := newChannel(false, &modemReader{})
+		c.checkModemSignal(time.Now().Add(time.Hour))
+		if c.State() != StateRunning {
+			t.Errorf("state = %s with use_modem_signal_state off, want running", c.State())
+		}
+	})
+}
+
+func TestChannelBaudSource(t *testing.T) {
+	noFlow := false
+	tests := []struct {
+		name       string
+		baudRate   int
+		wantBaud   int
+		wantSource string
+		wantDetect bool
+	}{
+		{"configured baud and flow", 9600, 9600, BaudSourceConfigured, false},
+		{"auto-detect", 0, 19200, BaudSourceDetected, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A regular file passes the presence check but can't be opened as
+			// a serial port, so the session ends right after recording stats
+			device := filepath.Join(t.TempDir(), "ttyTEST")
+			if err := os.WriteFile(device, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			detected := false
+			c := &Channel{
+				config:     &config.PortConfig{Device: device, SideDesignation: "A1", BaudRate: tt.baudRate, UseFlowControl: &noFlow},
+				detection:  &config.DetectionConfig{},
+				redetectCh: make(chan struct{}, 1),
+				logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+				detect: func(context.Context, string) (*serial.DetectionResult, error) {
+					detected = true
+					return &serial.DetectionResult{BaudRate: 19200}, nil
+				},
+			}
+
+			if err := c.runCaptureSession(context.Background()); err == nil {
+				t.Fatal("runCaptureSession() should fail to open a regular file")
+			}
+			if detected != tt.wantDetect {
+				t.Errorf("detection ran = %v, want %v", detected, tt.wantDetect)
+			}
+
+			stats := c.Stats()
+			if stats.DetectedBaud != tt.wantBaud {
+				t.Errorf("DetectedBaud = %d, want %d", stats.DetectedBaud, tt.wantBaud)
+			}
+			if stats.BaudSource != tt.wantSource {
+				t.Errorf("BaudSource = %q, want %q", stats.BaudSource, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestChannelDetectionCancelled(t *testing.T) {
+	device := filepath.Join(t.TempDir(), "ttyTEST")
+	if err := os.WriteFile(device, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Channel{
+		config:     &config.PortConfig{Device: device, SideDesignation: "A1"},
+		detection:  &config.DetectionConfig{},
+		redetectCh: make(chan struct{}, 1),
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		detect: func(ctx context.Context, _ string) (*serial.DetectionResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	if err := c.runCaptureSession(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("runCaptureSession() error = %v, want context.Canceled", err)
+	}
+	if c.State() == StateError {
+		t.Error("cancelled detection should not put the channel in error state")
+	}
+}
+
+func TestChannelTCPSource(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// Terminal server sends two CDR lines, then hangs up
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte("CALL 001 IN 5551234\r\nCALL 002 OUT 5559876\r\n"))
+		time.Sleep(50 * time.Millisecond)
+		conn.Close()
+	}()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+	portCfg := &config.PortConfig{Type: config.PortTypeTCP, Address: ln.Addr().String(), SideDesignation: "A1"}
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       portCfg.Source(),
+		Identifier:   "1429010002-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	c := &Channel{
+		config:      portCfg,
+		appConfig:   &config.AppConfig{FIPSCode: "1429010002"},
+		dualWriter:  writer,
+		natsChecker: &MockNATSChecker{connected: true},
+		redetectCh:  make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+		logger:      logger,
+	}
+
+	// The session runs until the remote closes, which is a reconnectable error
+	err = c.runCaptureSession(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "closed by remote") {
+		t.Errorf("runCaptureSession() error = %v, want remote close", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "1429010002-A1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"[1429010002][A1]", "CALL 001 IN 5551234", "CALL 002 OUT 5559876"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("log missing %q: %q", want, data)
+		}
+	}
+	if c.Device() != portCfg.Address {
+		t.Errorf("Device() = %q, want %q", c.Device(), portCfg.Address)
+	}
+}
+
+func TestChannelStallThreshold(t *testing.T) {
+	var events []output.Event
+	start := time.Date(2025, 12, 3, 8, 0, 0, 0, time.UTC)
+	c := &Channel{
+		config:        &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1", StallAfterSec: 3600},
+		eventCallback: func(e output.Event) { events = append(events, e) },
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	c.stats.StartTime = start
+
+	// Quiet, but under the threshold
+	c.checkStall(start.Add(59 * time.Minute))
+	if len(events) != 0 {
+		t.Fatalf("stalled event fired under the threshold: %+v", events)
+	}
+
+	// Crossing fires once, staying stalled doesn't fire again
+	c.checkStall(start.Add(61 * time.Minute))
+	c.checkStall(start.Add(90 * time.Minute))
+	if len(events) != 1 || events[0].Type != output.EventStalled {
+		t.Fatalf("events = %+v, want one stalled event", events)
+	}
+
+	// A line clears the stall; silence past the threshold again fires again
+	c.stats.LastLineTime = start.Add(2 * time.Hour)
+	c.checkStall(start.Add(2*time.Hour + time.Minute))
+	if c.stalled {
+		t.Error("still stalled after a line arrived")
+	}
+	c.checkStall(start.Add(3*time.Hour + time.Minute))
+	if len(events) != 2 {
+		t.Errorf("got %d events after second stall, want 2", len(events))
+	}
+
+	// Stats reflect the stall as of now
+	c.stats.LastLineTime = time.Now().Add(-2 * time.Hour)
+	if !c.Stats().Stalled {
+		t.Error("Stats().Stalled = false two hours after the last line")
+	}
+	c.stats.LastLineTime = time.Now()
+	if c.Stats().Stalled {
+		t.Error("Stats().Stalled = true right after a line")
+	}
+
+	// Threshold unset: never stalled
+	c.config.StallAfterSec = 0
+	c.stats.LastLineTime = time.Now().Add(-48 * time.Hour)
+	if c.Stats().Stalled {
+		t.Error("Stats().Stalled = true with stall_after_sec unset")
+	}
+}
+
+func TestChannelMaxSessionDuration(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// Terminal server that never hangs up: each session only ends when the
+	// channel closes it. Lines keep the read loop turning.
+	accepted := make(chan time.Time, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- time.Now()
+			go func() {
+				defer conn.Close()
+				for {
+					if _, err := conn.Write([]byte("CALL 001 IN\r\n")); err != nil {
+						return
+					}
+					time.Sleep(20 * time.Millisecond)
+				}
+			}()
+		}
+	}()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	portCfg := &config.PortConfig{
+		Type:                  config.PortTypeTCP,
+		Address:               ln.Addr().String(),
+		SideDesignation:       "A1",
+		MaxSessionDurationSec: 1,
+	}
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       portCfg.Source(),
+		Identifier:   "1429010002-A1",
+		LogBasePath:  t.TempDir(),
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	c := &Channel{
+		config: portCfg,
+		// A long reconnect delay would show up as a late second session
+		recovery:    &config.RecoveryConfig{ReconnectDelaySec: 30, MaxReconnectDelaySec: 30, MaxSessionDurationSec: 3600},
+		appConfig:   &config.AppConfig{FIPSCode: "1429010002"},
+		dualWriter:  writer,
+		natsChecker: &MockNATSChecker{connected: true},
+		redetectCh:  make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+		logger:      logger,
+	}
+	if got := c.maxSessionDuration(); got != time.Second {
+		t.Fatalf("maxSessionDuration() = %v, want the port's 1s over recovery's", got)
+	}
+
+	c.wg.Add(1)
+	go c.captureLoop(context.Background())
+	defer func() {
+		close(c.stopCh)
+		c.wg.Wait()
+	}()
+
+	var first, second time.Time
+	for i, at := range []*time.Time{&first, &second} {
+		select {
+		case *at = <-accepted:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("session %d never connected", i+1)
+		}
+	}
+
+	// The first session ends near the limit and the reopen is immediate
+	if gap := second.Sub(first); gap < time.Second || gap > 2*time.Second {
+		t.Errorf("second session opened %v after the first, want ~1s", gap)
+	}
+
+	stats := c.Stats()
+	if stats.Reconnects != 0 {
+		t.Errorf("Reconnects = %d, want 0 (a planned reopen isn't a failure)", stats.Reconnects)
+	}
+	if stats.LastError != "" {
+		t.Errorf("LastError = %q, want none", stats.LastError)
+	}
+	c.statsMutex.RLock()
+	failures := c.consecutiveFailures
+	c.statsMutex.RUnlock()
+	if failures != 0 {
+		t.Errorf("consecutiveFailures = %d, want 0", failures)
+	}
+}
+
+// blockingReader blocks in Read until it is aborted, like a serial driver
+// that ignores the read timeout
+type blockingReader struct {
+	scriptedReader
+	aborted chan struct{}
+	once    sync.Once
+}
+
+func (b *blockingReader) Read(p []byte) (int, error) {
+	<-b.aborted
+	return 0, errors.New("port closed")
+}
+
+func (b *blockingReader) Abort() error {
+	b.once.Do(func() { close(b.aborted) })
+	return nil
+}
+
+func TestChannelStopDuringNATSOutage(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &Channel{
+		config:      &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1"},
+		reader:      serial.NewReaderWithStats(&scriptedReader{}),
+		natsChecker: &MockNATSChecker{connected: false},
+		stopCh:      make(chan struct{}),
+		logger:      logger,
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.readLoop(context.Background(), "")
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.State() != StateWaitingForNATS {
+		if time.Now().After(deadline) {
+			t.Fatalf("state = %s, want %s", c.State(), StateWaitingForNATS)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	start := time.Now()
+	c.Stop()
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Stop() took %v while waiting for NATS, want prompt return", elapsed)
+	}
+
+	// Once stopped, waitForNATS must not block even if NATS is down
+	if c.waitForNATS(context.Background()) {
+		t.Error("waitForNATS() = true after Stop")
+	}
+}
+
+func TestChannelStopForceClosesBlockedRead(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reader := &blockingReader{aborted: make(chan struct{})}
+	c := &Channel{
+		config:      &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1"},
+		recovery:    &config.RecoveryConfig{StopGraceMs: 50},
+		reader:      serial.NewReaderWithStats(reader),
+		natsChecker: &MockNATSChecker{connected: true},
+		stopCh:      make(chan struct{}),
+		logger:      logger,
+	}
+	c.openReader.Store(c.reader)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.readLoop(context.Background(), "")
+	}()
+	time.Sleep(20 * time.Millisecond) // let the loop block in Read
+
+	start := time.Now()
+	c.Stop()
+	elapsed := time.Since(start)
+
+	select {
+	case <-reader.aborted:
+	default:
+		t.Fatal("Stop() did not abort the blocked read")
+	}
+	if elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Stop() took %v, want about the 50ms grace", elapsed)
+	}
+}
+
+func TestReconnectDelayJitter(t *testing.T) {
+	c := &Channel{recovery: &config.RecoveryConfig{
+		ReconnectDelaySec:    10,
+		MaxReconnectDelaySec: 60,
+		ExponentialBackoff:   true,
+		ReconnectJitterPct:   20,
+	}}
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 200; i++ {
+		d := c.reconnectDelay(1)
+		if d < 8*time.Second || d > 12*time.Second {
+			t.Fatalf("reconnectDelay(1) = %s, want within 10s ±20%%", d)
+		}
+		seen[d] = true
+
+		// Backoff past the cap: jitter never pushes it over max_reconnect_delay_sec
+		if d := c.reconnectDelay(5); d < 48*time.Second || d > 60*time.Second {
+			t.Fatalf("reconnectDelay(5) = %s, want within 48s..60s", d)
+		}
+	}
+	if len(seen) < 2 {
+		t.Error("reconnectDelay() returned the same delay every time with jitter on")
+	}
+
+	// Off: deterministic
+	c.recovery.ReconnectJitterPct = 0
+	for i := 0; i < 10; i++ {
+		if d := c.reconnectDelay(2); d != 20*time.Second {
+			t.Fatalf("reconnectDelay(2) without jitter = %s, want 20s", d)
+		}
+	}
+}
+
+func TestChannelPauseResume(t *testing.T) {
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       "/dev/ttyTEST",
+		Identifier:   "1429010002-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	var events []output.Event
+	c := &Channel{
+		config:        &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1"},
+		appConfig:     &config.AppConfig{FIPSCode: "1429010002"},
+		reader:        serial.NewReaderWithStats(&scriptedReader{}),
+		dualWriter:    writer,
+		eventCallback: func(e output.Event) { events = append(events, e) },
+		logger:        logger,
+	}
+	c.setState(StateRunning)
+
+	c.Pause()
+	if c.State() != StatePaused || !c.Paused() {
+		t.Fatalf("after Pause state = %s, want paused", c.State())
+	}
+	c.processLine("CALL 001")
+	c.processLine("CALL 002")
+
+	// A session reopening the port while paused stays paused
+	c.setState(StateReconnecting)
+	c.setState(StateRunning)
+	if c.State() != StatePaused {
+		t.Errorf("reopened while paused: state = %s, want paused", c.State())
+	}
+
+	c.Resume()
+	if c.State() != StateRunning || c.Paused() {
+		t.Fatalf("after Resume state = %s, want running", c.State())
+	}
+	c.processLine("CALL 003")
+
+	stats := c.Stats()
+	if stats.PausedDropped != 2 {
+		t.Errorf("PausedDropped = %d, want 2", stats.PausedDropped)
+	}
+	if stats.LinesRead != 3 {
+		t.Errorf("LinesRead = %d, want 3 (paused lines are still read)", stats.LinesRead)
+	}
+
+	data, _ := os.ReadFile(writer.LogPath())
+	if strings.Contains(string(data), "CALL 001") || !strings.Contains(string(data), "CALL 003") {
+		t.Errorf("log = %q, want only the line written after resume", data)
+	}
+
+	var transitions []string
+	for _, e := range events {
+		if e.Type == output.EventStateChange {
+			transitions = append(transitions, e.Message)
+		}
+	}
+	if len(transitions) == 0 || transitions[0] != "detecting -> running" || !slices.Contains(transitions, "running -> paused") || transitions[len(transitions)-1] != "paused -> running" {
+		t.Errorf("state changes = %v, want running -> paused ... paused -> running", transitions)
+	}
+}
+
+func TestDetectFramings(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataBits int
+		parity   string
+		want     []serial.Framing
+	}{
+		{"nothing set sweeps all", 0, "", serial.DefaultFramings},
+		{"data bits narrows", 7, "", []serial.Framing{{DataBits: 7, Parity: "even"}, {DataBits: 7, Parity: "odd"}}},
+		{"parity narrows", 0, "none", []serial.Framing{{DataBits: 8, Parity: "none"}}},
+		{"both set", 7, "odd", []serial.Framing{{DataBits: 7, Parity: "odd"}}},
+		{"unlisted framing kept as configured", 8, "even", []serial.Framing{{DataBits: 8, Parity: "even"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectFramings(&config.PortConfig{DataBits: tt.dataBits, Parity: tt.parity})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("detectFramings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
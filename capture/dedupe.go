@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"nectarcollector/config"
+)
+
+// lineDeduper suppresses identical consecutive lines. The first occurrence is
+// always written; repeats are dropped until the window (time since that first
+// occurrence, or number of repeats) runs out, at which point the next repeat
+// is written and starts a new window.
+type lineDeduper struct {
+	window     time.Duration
+	maxRepeats int
+	exempt     *regexp.Regexp // Lines the operator wants kept even if repeated
+
+	last    string
+	lastAt  time.Time
+	repeats int
+	active  bool
+}
+
+// newLineDeduper builds a deduper from the port config.
+// Returns nil (no deduplication) if neither window nor count is set.
+func newLineDeduper(portCfg *config.PortConfig) (*lineDeduper, error) {
+	if portCfg.DedupeWindowMs <= 0 && portCfg.DedupeCount <= 0 {
+		return nil, nil
+	}
+
+	d := &lineDeduper{
+		window:     portCfg.DedupeWindow(),
+		maxRepeats: portCfg.DedupeCount,
+	}
+	if portCfg.DedupeExempt != "" {
+		re, err := regexp.Compile(portCfg.DedupeExempt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedupe_exempt: %w", err)
+		}
+		d.exempt = re
+	}
+	return d, nil
+}
+
+// Suppress reports whether line repeats the previous line within the window
+// and should be dropped. Safe to call on nil receiver.
+func (d *lineDeduper) Suppress(line string, now time.Time) bool {
+	if d == nil {
+		return false
+	}
+
+	if d.exempt != nil && d.exempt.MatchString(line) {
+		d.active = false
+		return false
+	}
+
+	if d.active && line == d.last && d.withinWindow(now) {
+		d.repeats++
+		return true
+	}
+
+	d.last = line
+	d.lastAt = now
+	d.repeats = 0
+	d.active = true
+	return false
+}
+
+// withinWindow reports whether another repeat may still be suppressed
+func (d *lineDeduper) withinWindow(now time.Time) bool {
+	if d.window > 0 && now.Sub(d.lastAt) >= d.window {
+		return false
+	}
+	if d.maxRepeats > 0 && d.repeats >= d.maxRepeats {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package capture
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+	"nectarcollector/serial"
+)
+
+func TestLineDeduper(t *testing.T) {
+	start := time.Date(2025, 12, 3, 15, 0, 0, 0, time.UTC)
+	ms := func(n int) time.Time { return start.Add(time.Duration(n) * time.Millisecond) }
+
+	type feed struct {
+		line string
+		at   time.Time
+		want bool // suppressed
+	}
+
+	tests := []struct {
+		name    string
+		portCfg config.PortConfig
+		feeds   []feed
+	}{
+		{
+			name:    "suppressed within window, released after",
+			portCfg: config.PortConfig{DedupeWindowMs: 1000},
+			feeds: []feed{
+				{"CDR 1", ms(0), false},
+				{"CDR 1", ms(100), true},
+				{"CDR 1", ms(999), true},
+				{"CDR 1", ms(1000), false}, // Window expired: written, new window starts
+				{"CDR 1", ms(1500), true},
+				{"CDR 2", ms(1600), false},
+				{"CDR 1", ms(1700), false}, // Not consecutive
+			},
+		},
+		{
+			name:    "count window",
+			portCfg: config.PortConfig{DedupeCount: 2},
+			feeds: []feed{
+				{"CDR 1", ms(0), false},
+				{"CDR 1", ms(1), true},
+				{"CDR 1", ms(2), true},
+				{"CDR 1", ms(3), false},
+				{"CDR 1", ms(4), true},
+			},
+		},
+		{
+			name:    "exempt keepalives are never deduped",
+			portCfg: config.PortConfig{DedupeWindowMs: 1000, DedupeExempt: `^KEEPALIVE`},
+			feeds: []feed{
+				{"KEEPALIVE", ms(0), false},
+				{"KEEPALIVE", ms(10), false},
+				{"CDR 1", ms(20), false},
+				{"CDR 1", ms(30), true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := newLineDeduper(&tt.portCfg)
+			if err != nil {
+				t.Fatalf("newLineDeduper() error = %v", err)
+			}
+			for i, f := range tt.feeds {
+				if got := d.Suppress(f.line, f.at); got != f.want {
+					t.Errorf("feed %d (%q): Suppress() = %v, want %v", i, f.line, got, f.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLineDeduperDisabled(t *testing.T) {
+	d, err := newLineDeduper(&config.PortConfig{})
+	if err != nil || d != nil {
+		t.Fatalf("newLineDeduper() = %v, %v; want nil, nil", d, err)
+	}
+	if d.Suppress("CDR 1", time.Now()) || d.Suppress("CDR 1", time.Now()) {
+		t.Error("nil deduper should never suppress")
+	}
+}
+
+func TestProcessLineDedupe(t *testing.T) {
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       filepath.Join(dir, "ttyTEST"),
+		Identifier:   "1429010002-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	portCfg := &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1", DedupeCount: 100}
+	deduper, err := newLineDeduper(portCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Channel{
+		config:     portCfg,
+		appConfig:  &config.AppConfig{FIPSCode: "1429010002"},
+		reader:     serial.NewReaderWithStats(&scriptedReader{}),
+		dualWriter: writer,
+		deduper:    deduper,
+		logger:     logger,
+	}
+
+	for _, line := range []string{"CDR 1", "CDR 1", "CDR 1", "CDR 2"} {
+		c.processLine(line)
+	}
+	writer.Close()
+
+	c.statsMutex.RLock()
+	deduped := c.stats.Deduped
+	c.statsMutex.RUnlock()
+	if deduped != 2 {
+		t.Errorf("Deduped = %d, want 2", deduped)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "1429010002-A1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "] CDR 1") || !strings.HasSuffix(lines[1], "] CDR 2") {
+		t.Errorf("log = %q, want CDR 1 then CDR 2", lines)
+	}
+}
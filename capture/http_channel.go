@@ -1,10 +1,22 @@
 package capture
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
+	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,9 +25,36 @@ import (
 	"nectarcollector/output"
 )
 
-// MaxHTTPBodySize is the maximum size of an HTTP POST body (50MB)
+// MaxHTTPBodySize is the default maximum size of an HTTP POST body (50MB)
 const MaxHTTPBodySize = 50 * 1024 * 1024
 
+// DefaultHMACHeader carries the body signature when hmac_header isn't set
+const DefaultHMACHeader = "X-Signature"
+
+// bodySizeBuckets are the upper bounds of the request body size histogram.
+// Bodies larger than the last bound are counted in the overflow bucket.
+var bodySizeBuckets = [...]struct {
+	limit int
+	label string
+}{
+	{1 << 10, "<=1KB"},
+	{4 << 10, "<=4KB"},
+	{16 << 10, "<=16KB"},
+	{64 << 10, "<=64KB"},
+	{256 << 10, "<=256KB"},
+	{1 << 20, "<=1MB"},
+}
+
+// bodySizeOverflowLabel names the bucket for bodies over the largest bound
+const bodySizeOverflowLabel = ">1MB"
+
+// HTTP channel states. A channel on a dedicated listen_port is bind_failed
+// until its server manages to bind (e.g., the port is held by another process).
+const (
+	HTTPStateRunning    = "running"
+	HTTPStateBindFailed = "bind_failed"
+)
+
 // HTTPChannel handles CDR capture from HTTP POST requests
 type HTTPChannel struct {
 	config    config.PortConfig
@@ -24,21 +63,41 @@ type HTTPChannel struct {
 
 	dualWriter *output.DualWriter
 
+	// Source IP restrictions, parsed from AllowedCIDRs/TrustedProxies
+	allowedNets    []*net.IPNet
+	trustedProxies []*net.IPNet
+
+	timestamper *lineTimestamper // Header time from the body (nil = receive time)
+	redactor    *lineRedactor    // Masks PII before output (nil = off)
+	redactorErr error            // Set when redaction_rules failed to compile; requests are rejected
+	headerFmt   output.HeaderFormat
+
+	inflight chan struct{} // Concurrency slots from MaxConcurrentRequests (nil = unlimited)
+
 	// Stats
-	statsMutex   sync.RWMutex
-	stats        HTTPChannelStats
-	bytesRead    atomic.Int64
-	requestCount atomic.Int64
-	errorCount   atomic.Int64
+	statsMutex       sync.RWMutex
+	stats            HTTPChannelStats
+	lastRecord       string // Last body captured, when record_preview is on
+	bindErr          error  // Why the listen port couldn't be bound (nil = listening)
+	bytesRead        atomic.Int64
+	requestCount     atomic.Int64
+	errorCount       atomic.Int64
+	rejectedOverload atomic.Int64
+	sizeCounts       [len(bodySizeBuckets) + 1]atomic.Int64 // One per bodySizeBuckets entry plus overflow
 }
 
 // HTTPChannelStats tracks statistics for an HTTP capture channel
 type HTTPChannelStats struct {
-	BytesRead       int64     `json:"bytes_read"`
-	RequestCount    int64     `json:"requests"`
-	Errors          int64     `json:"errors"`
-	LastRequestTime time.Time `json:"last_request_time"`
-	StartTime       time.Time `json:"start_time"`
+	BytesRead        int64            `json:"bytes_read"`
+	RequestCount     int64            `json:"requests"`
+	Errors           int64            `json:"errors"`
+	LastRequestTime  time.Time        `json:"last_request_time"`
+	StartTime        time.Time        `json:"start_time"`
+	SizeBuckets      map[string]int64 `json:"size_buckets"`      // Captured body sizes by bucket, e.g. "<=4KB"
+	RejectedOverload int64            `json:"rejected_overload"` // Requests answered 503 at max_concurrent_requests
+	BindError        string           `json:"bind_error,omitempty"`
+	ExtraPubErrors   int64            `json:"extra_publish_errors"` // Failed publishes to extra_subjects
+	LogDropped       int64            `json:"log_dropped"`          // Buffered records lost to log write failures
 }
 
 // NewHTTPChannel creates a new HTTP capture channel
@@ -48,7 +107,7 @@ func NewHTTPChannel(
 	dualWriter *output.DualWriter,
 	logger *slog.Logger,
 ) *HTTPChannel {
-	return &HTTPChannel{
+	h := &HTTPChannel{
 		config:     portCfg,
 		appConfig:  appCfg,
 		dualWriter: dualWriter,
@@ -57,37 +116,154 @@ func NewHTTPChannel(
 			StartTime: time.Now(),
 		},
 	}
+
+	// CIDRs are checked at config load, so a parse failure here means the
+	// config bypassed validation - fail closed rather than accept everything
+	var err error
+	if h.allowedNets, err = config.ParseCIDRs(portCfg.AllowedCIDRs); err != nil {
+		h.logger.Error("Invalid allowed_cidrs, rejecting all requests", "error", err)
+		h.allowedNets = []*net.IPNet{}
+	}
+	if h.trustedProxies, err = config.ParseCIDRs(portCfg.TrustedProxies); err != nil {
+		h.logger.Error("Invalid trusted_proxies, ignoring X-Forwarded-For", "error", err)
+		h.trustedProxies = nil
+	}
+	if h.timestamper, err = newLineTimestamper(&portCfg); err != nil {
+		h.logger.Error("Invalid timestamp settings, using receive time", "error", err)
+	}
+	if h.redactor, err = newLineRedactor(&portCfg); err != nil {
+		// Patterns are checked at config load; fail closed rather than log PII
+		h.logger.Error("Invalid redaction_rules, rejecting all requests", "error", err)
+		h.redactorErr = err
+	}
+	h.headerFmt = newHeaderFormat(&appCfg, h.logger)
+	if portCfg.MaxConcurrentRequests > 0 {
+		h.inflight = make(chan struct{}, portCfg.MaxConcurrentRequests)
+	}
+
+	return h
 }
 
-// ServeHTTP handles incoming HTTP POST requests
+// ServeHTTP handles incoming HTTP requests (POST unless allowed_methods says otherwise)
 func (h *HTTPChannel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Only accept POST
-	if r.Method != http.MethodPost {
+	// Shed load before doing any work so a retry storm can't pile up goroutines
+	if h.inflight != nil {
+		select {
+		case h.inflight <- struct{}{}:
+			defer func() { <-h.inflight }()
+		default:
+			h.rejectedOverload.Add(1)
+			h.logger.Warn("Rejected request over max_concurrent_requests",
+				"remote_addr", r.RemoteAddr, "limit", cap(h.inflight))
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	// Only accept configured methods (POST by default)
+	if !h.methodAllowed(r.Method) {
 		h.errorCount.Add(1)
+		w.Header().Set("Allow", strings.Join(h.config.HTTPMethods(), ", "))
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	// Restrict source addresses if an allowlist is configured
+	if !h.allowedSource(r) {
+		h.errorCount.Add(1)
+		h.logger.Warn("Rejected request from disallowed address",
+			"remote_addr", r.RemoteAddr,
+			"x_forwarded_for", r.Header.Get("X-Forwarded-For"))
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Require the shared secret if one is configured
+	if !h.authorized(r) {
+		h.errorCount.Add(1)
+		h.logger.Warn("Rejected unauthorized request", "remote_addr", r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="NectarCollector"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Reject media types this endpoint doesn't expect (e.g., browser form posts)
+	if !h.contentTypeAllowed(r) {
+		h.errorCount.Add(1)
+		h.logger.Warn("Rejected request with disallowed content type",
+			"content_type", r.Header.Get("Content-Type"),
+			"remote_addr", r.RemoteAddr)
+		http.Error(w, "Unsupported media type", http.StatusUnsupportedMediaType)
+		return
+	}
+
 	// Limit body size
-	r.Body = http.MaxBytesReader(w, r.Body, MaxHTTPBodySize)
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes())
 
 	// Read body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		h.errorCount.Add(1)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.logger.Warn("Request body too large", "limit", maxBytesErr.Limit)
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		h.logger.Warn("Failed to read request body", "error", err)
 		http.Error(w, "Failed to read body", http.StatusBadRequest)
 		return
 	}
 
+	// Query-string pollers deliver the CDR in the URL rather than a body
+	if len(body) == 0 && r.Method == http.MethodGet {
+		body = []byte(r.URL.RawQuery)
+	}
+
 	if len(body) == 0 {
 		h.errorCount.Add(1)
 		http.Error(w, "Empty body", http.StatusBadRequest)
 		return
 	}
 
-	// Build the record with headers
-	record := h.buildRecord(r, body)
+	// Verify the body signature before anything is written
+	if !h.validSignature(r, body) {
+		h.errorCount.Add(1)
+		h.logger.Warn("Rejected request with invalid signature", "remote_addr", r.RemoteAddr)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// Decompress after the signature check - senders sign the bytes they send
+	if encoding := r.Header.Get("Content-Encoding"); encoding != "" {
+		body, err = h.decodeBody(encoding, body)
+		if err != nil {
+			h.errorCount.Add(1)
+			h.logger.Warn("Failed to decode request body", "content_encoding", encoding, "error", err)
+			switch {
+			case errors.Is(err, errUnsupportedEncoding):
+				http.Error(w, "Unsupported content encoding", http.StatusUnsupportedMediaType)
+			case errors.Is(err, errDecodedTooLarge):
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			default:
+				http.Error(w, "Invalid compressed body", http.StatusBadRequest)
+			}
+			return
+		}
+	}
+
+	if h.redactorErr != nil {
+		h.errorCount.Add(1)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Build the record with headers; the log and primary subject only ever
+	// see the redacted form
+	original := h.buildRecord(r, body)
+	record := h.redactor.Redact(original)
+	redactedBody := h.redactor.Redact(string(body))
 
 	// Get FIPS code
 	fipsCode := h.config.FIPSCode
@@ -96,8 +272,8 @@ func (h *HTTPChannel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build header and write
-	header := output.BuildHeader(fipsCode, h.config.SideDesignation, time.Now().UTC())
-	fullRecord := header + record
+	header := h.headerFmt.Build(fipsCode, h.config.SideDesignation, h.timestamper.Timestamp(string(body), time.Now().UTC()))
+	fullRecord := output.AppendChecksum(header+record, h.config.AppendChecksum, []byte(redactedBody))
 
 	if err := h.dualWriter.WriteLine(fullRecord); err != nil {
 		h.errorCount.Add(1)
@@ -106,21 +282,240 @@ func (h *HTTPChannel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The original goes only to the restricted subject, if configured
+	if h.config.UnredactedSubject != "" && record != original {
+		publishUnredacted(h.dualWriter, &h.config, output.AppendChecksum(header+original, h.config.AppendChecksum, body), h.logger)
+	}
+
 	// Update stats
 	h.bytesRead.Add(int64(len(body)))
 	h.requestCount.Add(1)
+	h.recordBodySize(len(body))
 	h.statsMutex.Lock()
 	h.stats.LastRequestTime = time.Now()
+	if h.config.RecordPreview {
+		h.lastRecord = recordPreview(redactedBody)
+	}
 	h.statsMutex.Unlock()
 
 	h.logger.Debug("Captured HTTP POST",
 		"content_length", len(body),
 		"content_type", r.Header.Get("Content-Type"))
 
-	// Success response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"ok"}`))
+	h.writeSuccess(w)
+}
+
+// writeSuccess sends the acknowledgment for a captured record. Some CPE
+// retry until they see a specific status or body, so both are configurable.
+func (h *HTTPChannel) writeSuccess(w http.ResponseWriter) {
+	status := h.config.ResponseStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := h.config.ResponseBody
+	contentType := h.config.ResponseContentType
+	if body == "" && contentType == "" {
+		body = `{"status":"ok"}`
+		contentType = "application/json"
+	}
+	if status == http.StatusNoContent || status == http.StatusResetContent {
+		body = ""
+	}
+
+	if contentType != "" && body != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	if body != "" {
+		w.Write([]byte(body))
+	}
+}
+
+// recordBodySize counts a captured body in its size bucket
+func (h *HTTPChannel) recordBodySize(n int) {
+	for i, b := range bodySizeBuckets {
+		if n <= b.limit {
+			h.sizeCounts[i].Add(1)
+			return
+		}
+	}
+	h.sizeCounts[len(bodySizeBuckets)].Add(1)
+}
+
+// sizeBuckets snapshots the body size histogram, including empty buckets
+// so consumers always see the same keys
+func (h *HTTPChannel) sizeBuckets() map[string]int64 {
+	buckets := make(map[string]int64, len(bodySizeBuckets)+1)
+	for i, b := range bodySizeBuckets {
+		buckets[b.label] = h.sizeCounts[i].Load()
+	}
+	buckets[bodySizeOverflowLabel] = h.sizeCounts[len(bodySizeBuckets)].Load()
+	return buckets
+}
+
+// methodAllowed reports whether this endpoint accepts the request method
+func (h *HTTPChannel) methodAllowed(method string) bool {
+	for _, allowed := range h.config.HTTPMethods() {
+		if method == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// contentTypeAllowed checks the request media type against AllowedContentTypes,
+// ignoring parameters such as charset. No allowlist means any type (or none)
+// is accepted; with an allowlist a missing Content-Type is rejected.
+func (h *HTTPChannel) contentTypeAllowed(r *http.Request) bool {
+	if len(h.config.AllowedContentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	for _, allowed := range h.config.AllowedContentTypes {
+		if allowedType, _, err := mime.ParseMediaType(allowed); err == nil && allowedType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// maxBodyBytes returns the body size limit for this endpoint
+func (h *HTTPChannel) maxBodyBytes() int64 {
+	if h.config.MaxBodyBytes > 0 {
+		return h.config.MaxBodyBytes
+	}
+	return MaxHTTPBodySize
+}
+
+// errUnsupportedEncoding is returned for a Content-Encoding other than gzip or deflate
+var errUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// errDecodedTooLarge is returned when a compressed body expands past max_body_bytes
+var errDecodedTooLarge = errors.New("decompressed body too large")
+
+// decodeBody undoes a gzip or deflate Content-Encoding. The decompressed
+// size is held to max_body_bytes so a small zip bomb can't exhaust memory.
+func (h *HTTPChannel) decodeBody(encoding string, body []byte) ([]byte, error) {
+	var zr io.ReadCloser
+	var err error
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		zr, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		// HTTP deflate is zlib-wrapped, but some senders send raw deflate
+		zr, err = zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			zr, err = flate.NewReader(bytes.NewReader(body)), nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedEncoding, encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	limit := h.maxBodyBytes()
+	decoded, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decoded)) > limit {
+		return nil, fmt.Errorf("%w (limit %d bytes)", errDecodedTooLarge, limit)
+	}
+	return decoded, nil
+}
+
+// allowedSource checks the client address against AllowedCIDRs.
+// No allowlist configured means any source is accepted.
+func (h *HTTPChannel) allowedSource(r *http.Request) bool {
+	if len(h.config.AllowedCIDRs) == 0 {
+		return true
+	}
+	ip := h.clientIP(r)
+	if ip == nil {
+		return false
+	}
+	return ipInNets(ip, h.allowedNets)
+}
+
+// clientIP returns the address of the client that sent the request.
+// X-Forwarded-For is only honored when the direct peer is a trusted proxy;
+// the rightmost entry that isn't itself a trusted proxy is the client, since
+// anything further left could have been supplied by the client.
+func (h *HTTPChannel) clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ipInNets(ip, h.trustedProxies) {
+		return ip
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// Malformed entry - don't trust anything to the left of it
+			return ip
+		}
+		if !ipInNets(hop, h.trustedProxies) {
+			return hop
+		}
+		ip = hop
+	}
+	return ip
+}
+
+// ipInNets reports whether ip falls in any of nets
+func ipInNets(ip net.IP, nets []*net.IPNet) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized checks the bearer token against the configured AuthToken.
+// An empty AuthToken leaves the endpoint open.
+func (h *HTTPChannel) authorized(r *http.Request) bool {
+	if h.config.AuthToken == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.config.AuthToken)) == 1
+}
+
+// validSignature checks the hex HMAC-SHA256 of body against the signature
+// header. A "sha256=" prefix is accepted. An empty HMACSecret skips the check.
+func (h *HTTPChannel) validSignature(r *http.Request, body []byte) bool {
+	if h.config.HMACSecret == "" {
+		return true
+	}
+
+	headerName := h.config.HMACHeader
+	if headerName == "" {
+		headerName = DefaultHMACHeader
+	}
+	signature := strings.TrimPrefix(r.Header.Get(headerName), "sha256=")
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.config.HMACSecret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
 }
 
 // buildRecord constructs the full record with headers and body
@@ -132,6 +527,10 @@ func (h *HTTPChannel) buildRecord(r *http.Request, body []byte) string {
 
 	// Add headers
 	for name, values := range r.Header {
+		// Don't write the shared secret to the log
+		if name == "Authorization" && h.config.AuthToken != "" {
+			continue
+		}
 		for _, value := range values {
 			record += fmt.Sprintf("%s: %s\n", name, value)
 		}
@@ -149,17 +548,62 @@ func (h *HTTPChannel) buildRecord(r *http.Request, body []byte) string {
 	return record
 }
 
+// SetBindError records that the server for this channel's listen port
+// couldn't bind; nil clears it once the server is listening
+func (h *HTTPChannel) SetBindError(err error) {
+	h.statsMutex.Lock()
+	defer h.statsMutex.Unlock()
+	h.bindErr = err
+}
+
+// State returns HTTPStateRunning, or HTTPStateBindFailed while the channel's
+// listen port is unbound
+func (h *HTTPChannel) State() string {
+	h.statsMutex.RLock()
+	defer h.statsMutex.RUnlock()
+	if h.bindErr != nil {
+		return HTTPStateBindFailed
+	}
+	return HTTPStateRunning
+}
+
+// StateCode maps State onto the serial channel state codes: running, or
+// error while the listen port is unbound
+func (h *HTTPChannel) StateCode() int {
+	if h.State() == HTTPStateBindFailed {
+		return StateError.StateCode()
+	}
+	return StateRunning.StateCode()
+}
+
+// LastRecord returns the truncated last body captured ("" unless
+// record_preview is on)
+func (h *HTTPChannel) LastRecord() string {
+	h.statsMutex.RLock()
+	defer h.statsMutex.RUnlock()
+	return h.lastRecord
+}
+
 // GetStats returns current channel statistics
 func (h *HTTPChannel) GetStats() HTTPChannelStats {
 	h.statsMutex.RLock()
 	defer h.statsMutex.RUnlock()
 
+	var bindError string
+	if h.bindErr != nil {
+		bindError = h.bindErr.Error()
+	}
 	return HTTPChannelStats{
-		BytesRead:       h.bytesRead.Load(),
-		RequestCount:    h.requestCount.Load(),
-		Errors:          h.errorCount.Load(),
-		LastRequestTime: h.stats.LastRequestTime,
-		StartTime:       h.stats.StartTime,
+		BytesRead:        h.bytesRead.Load(),
+		RequestCount:     h.requestCount.Load(),
+		Errors:           h.errorCount.Load(),
+		LastRequestTime:  h.stats.LastRequestTime,
+		StartTime:        h.stats.StartTime,
+		SizeBuckets:      h.sizeBuckets(),
+		RejectedOverload: h.rejectedOverload.Load(),
+		BindError:        bindError,
+		ExtraPubErrors:   extraPublishErrors(h.dualWriter),
+		LogDropped:       logDropped(h.dualWriter),
 	}
 }
 
@@ -178,6 +622,19 @@ func (h *HTTPChannel) SideDesignation() string {
 	return h.config.SideDesignation
 }
 
+// Identifier returns the channel's log identifier (FIPSCODE-A1)
+func (h *HTTPChannel) Identifier() string {
+	return channelIdentifier(&h.config, &h.appConfig)
+}
+
+// LogPath returns the channel's log file path ("" if not open)
+func (h *HTTPChannel) LogPath() string {
+	if h.dualWriter == nil {
+		return ""
+	}
+	return h.dualWriter.LogPath()
+}
+
 // Stop closes the HTTP channel's dual writer
 func (h *HTTPChannel) Stop() error {
 	h.logger.Info("Stopping HTTP channel", "path", h.config.Path)
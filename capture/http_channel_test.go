@@ -2,15 +2,25 @@ package capture
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"nectarcollector/config"
+	"nectarcollector/output"
 )
 
 // mockDualWriter implements a minimal writer for testing
@@ -227,3 +237,703 @@ func TestMaxHTTPBodySize(t *testing.T) {
 		t.Errorf("MaxHTTPBodySize = %d, want %d (50MB)", MaxHTTPBodySize, expected)
 	}
 }
+
+// newTestHTTPWriter creates a DualWriter logging to a temp dir and returns
+// it along with the log file path
+func newTestHTTPWriter(t *testing.T) (*output.DualWriter, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       "/test",
+		Identifier:   "1234567890-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { writer.Close() })
+	return writer, filepath.Join(dir, "1234567890-A1.log")
+}
+
+func TestHTTPChannelAuthToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		authHeader string
+		wantStatus int
+	}{
+		{"correct token", "Bearer s3cret", http.StatusOK},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"missing token", "", http.StatusUnauthorized},
+		{"basic auth instead of bearer", "Basic czNjcmV0", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, logPath := newTestHTTPWriter(t)
+			portCfg := config.PortConfig{
+				Type:            "http",
+				Path:            "/test",
+				SideDesignation: "A1",
+				FIPSCode:        "1234567890",
+				AuthToken:       "s3cret",
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest("POST", "/test", strings.NewReader("CALL 001 IN"))
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			ch.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			stats := ch.GetStats()
+			data, _ := os.ReadFile(logPath)
+			if tt.wantStatus == http.StatusOK {
+				if stats.Errors != 0 || stats.RequestCount != 1 {
+					t.Errorf("Errors = %d, RequestCount = %d, want 0, 1", stats.Errors, stats.RequestCount)
+				}
+				if !strings.Contains(string(data), "CALL 001 IN") {
+					t.Errorf("log missing record: %q", data)
+				}
+				if strings.Contains(string(data), "s3cret") {
+					t.Error("log should not contain the auth token")
+				}
+			} else {
+				if stats.Errors != 1 || stats.RequestCount != 0 {
+					t.Errorf("Errors = %d, RequestCount = %d, want 1, 0", stats.Errors, stats.RequestCount)
+				}
+				if w.Header().Get("WWW-Authenticate") == "" {
+					t.Error("401 response should include WWW-Authenticate")
+				}
+				if len(data) != 0 {
+					t.Errorf("rejected request should not be logged: %q", data)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPChannelNoAuthTokenIsOpen(t *testing.T) {
+	writer, _ := newTestHTTPWriter(t)
+	portCfg := config.PortConfig{
+		Type:            "http",
+		Path:            "/test",
+		SideDesignation: "A1",
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+	req := httptest.NewRequest("POST", "/test", strings.NewReader("CALL 001 IN"))
+	w := httptest.NewRecorder()
+
+	ch.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestHTTPChannelHMACSignature(t *testing.T) {
+	const secret = "vendor-secret"
+	body := "CALL 001 IN"
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	validSig := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name       string
+		header     string
+		signature  string
+		body       string
+		wantStatus int
+	}{
+		{"valid signature", "X-Signature", validSig, body, http.StatusOK},
+		{"valid signature with prefix", "X-Signature", "sha256=" + validSig, body, http.StatusOK},
+		{"tampered body", "X-Signature", validSig, "CALL 999 IN", http.StatusUnauthorized},
+		{"tampered signature", "X-Signature", strings.Repeat("0", len(validSig)), body, http.StatusUnauthorized},
+		{"missing signature", "", "", body, http.StatusUnauthorized},
+		{"custom header", "X-Vendor-Sig", validSig, body, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, logPath := newTestHTTPWriter(t)
+			portCfg := config.PortConfig{
+				Type:            "http",
+				Path:            "/test",
+				SideDesignation: "A1",
+				FIPSCode:        "1234567890",
+				HMACSecret:      secret,
+			}
+			if tt.header == "X-Vendor-Sig" {
+				portCfg.HMACHeader = tt.header
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest("POST", "/test", strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.signature)
+			}
+			w := httptest.NewRecorder()
+
+			ch.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			data, _ := os.ReadFile(logPath)
+			if tt.wantStatus == http.StatusOK {
+				if !strings.Contains(string(data), tt.body) {
+					t.Errorf("log missing record: %q", data)
+				}
+			} else {
+				if len(data) != 0 {
+					t.Errorf("rejected request should not be logged: %q", data)
+				}
+				if ch.GetStats().Errors != 1 {
+					t.Errorf("Errors = %d, want 1", ch.GetStats().Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPChannelAllowedCIDRs(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		wantStatus int
+	}{
+		{"allowed address", "10.1.2.3:5000", "", http.StatusOK},
+		{"denied address", "192.168.1.5:5000", "", http.StatusForbidden},
+		{"allowed ipv6 address", "[fd00::5]:5000", "", http.StatusOK},
+		{"xff from untrusted peer is ignored", "192.168.1.5:5000", "10.1.2.3", http.StatusForbidden},
+		{"xff via trusted proxy allowed", "172.16.0.1:5000", "10.1.2.3", http.StatusOK},
+		{"xff via trusted proxy denied", "172.16.0.1:5000", "192.168.1.5", http.StatusForbidden},
+		{"spoofed leftmost xff is ignored", "172.16.0.1:5000", "10.1.2.3, 192.168.1.5", http.StatusForbidden},
+		{"chained trusted proxies", "172.16.0.1:5000", "10.1.2.3, 172.16.0.2", http.StatusOK},
+		{"trusted proxy without xff", "172.16.0.1:5000", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, _ := newTestHTTPWriter(t)
+			portCfg := config.PortConfig{
+				Type:            "http",
+				Path:            "/test",
+				SideDesignation: "A1",
+				AllowedCIDRs:    []string{"10.0.0.0/8", "fd00::/8"},
+				TrustedProxies:  []string{"172.16.0.0/24"},
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest("POST", "/test", strings.NewReader("CALL 001 IN"))
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			w := httptest.NewRecorder()
+
+			ch.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusForbidden && ch.GetStats().Errors != 1 {
+				t.Errorf("Errors = %d, want 1", ch.GetStats().Errors)
+			}
+		})
+	}
+}
+
+func TestHTTPChannelMaxBodyBytes(t *testing.T) {
+	writer, logPath := newTestHTTPWriter(t)
+	portCfg := config.PortConfig{
+		Type:            "http",
+		Path:            "/test",
+		SideDesignation: "A1",
+		MaxBodyBytes:    64,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+	// At the limit is accepted
+	req := httptest.NewRequest("POST", "/test", strings.NewReader(strings.Repeat("a", 64)))
+	w := httptest.NewRecorder()
+	ch.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("body at limit: status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Over the limit is rejected and not written
+	req = httptest.NewRequest("POST", "/test", strings.NewReader(strings.Repeat("b", 65)))
+	w = httptest.NewRecorder()
+	ch.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("body over limit: status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	if ch.GetStats().Errors != 1 {
+		t.Errorf("Errors = %d, want 1", ch.GetStats().Errors)
+	}
+	data, _ := os.ReadFile(logPath)
+	if strings.Contains(string(data), "bbb") {
+		t.Error("oversized body should not be logged")
+	}
+}
+
+func TestHTTPChannelMaxBodyBytesDefault(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test"}, config.AppConfig{}, nil, logger)
+	if got := ch.maxBodyBytes(); got != MaxHTTPBodySize {
+		t.Errorf("maxBodyBytes() = %d, want %d", got, MaxHTTPBodySize)
+	}
+}
+
+func TestHTTPChannelAllowedMethods(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantRecord string
+	}{
+		{"put with body", "PUT", "/test", "CALL 001 IN", http.StatusOK, "CALL 001 IN"},
+		{"get with query", "GET", "/test?ani=4025551212&trunk=3", "", http.StatusOK, "ani=4025551212&trunk=3"},
+		{"get without query", "GET", "/test", "", http.StatusBadRequest, ""},
+		{"post not listed", "POST", "/test", "CALL 001 IN", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, logPath := newTestHTTPWriter(t)
+			portCfg := config.PortConfig{
+				Type:            "http",
+				Path:            "/test",
+				SideDesignation: "A1",
+				AllowedMethods:  []string{"PUT", "GET"},
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			ch.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && w.Header().Get("Allow") != "PUT, GET" {
+				t.Errorf("Allow = %q, want %q", w.Header().Get("Allow"), "PUT, GET")
+			}
+
+			data, _ := os.ReadFile(logPath)
+			if tt.wantRecord != "" {
+				if !strings.Contains(string(data), tt.method+" "+tt.target) {
+					t.Errorf("log missing request line: %q", data)
+				}
+				if !strings.HasSuffix(string(data), "\n\n"+tt.wantRecord+"\n") {
+					t.Errorf("log record body = %q, want %q", data, tt.wantRecord)
+				}
+			} else if len(data) != 0 {
+				t.Errorf("rejected request should not be logged: %q", data)
+			}
+		})
+	}
+}
+
+func TestHTTPChannelAllowedContentTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantStatus  int
+	}{
+		{"allowed type", "application/xml", http.StatusOK},
+		{"allowed type with charset", "application/xml; charset=utf-8", http.StatusOK},
+		{"allowed type different case", "Text/XML", http.StatusOK},
+		{"form post rejected", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"missing content type", "", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, logPath := newTestHTTPWriter(t)
+			portCfg := config.PortConfig{
+				Type:                "http",
+				Path:                "/test",
+				SideDesignation:     "A1",
+				AllowedContentTypes: []string{"application/xml", "text/xml"},
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest("POST", "/test", strings.NewReader("<cdr>CALL 001</cdr>"))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			ch.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			stats := ch.GetStats()
+			data, _ := os.ReadFile(logPath)
+			if tt.wantStatus == http.StatusOK {
+				if stats.Errors != 0 || !strings.Contains(string(data), "CALL 001") {
+					t.Errorf("Errors = %d, log = %q, want record captured", stats.Errors, data)
+				}
+			} else {
+				if stats.Errors != 1 {
+					t.Errorf("Errors = %d, want 1", stats.Errors)
+				}
+				if len(data) != 0 {
+					t.Errorf("rejected request should not be logged: %q", data)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPChannelNoContentTypeAllowlist(t *testing.T) {
+	writer, _ := newTestHTTPWriter(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test", SideDesignation: "A1"}, config.AppConfig{}, writer, logger)
+
+	req := httptest.NewRequest("POST", "/test", strings.NewReader("a=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	ch.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d with no allowlist", w.Code, http.StatusOK)
+	}
+}
+
+func TestHTTPChannelSuccessResponse(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		body            string
+		contentType     string
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		{"default", 0, "", "", http.StatusOK, `{"status":"ok"}`, "application/json"},
+		{"custom xml ack", http.StatusAccepted, "<ack result=\"0\"/>", "application/xml", http.StatusAccepted, "<ack result=\"0\"/>", "application/xml"},
+		{"status only", http.StatusAccepted, "", "", http.StatusAccepted, `{"status":"ok"}`, "application/json"},
+		{"no content", http.StatusNoContent, "", "", http.StatusNoContent, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, _ := newTestHTTPWriter(t)
+			portCfg := config.PortConfig{
+				Type:                "http",
+				Path:                "/test",
+				SideDesignation:     "A1",
+				ResponseStatus:      tt.status,
+				ResponseBody:        tt.body,
+				ResponseContentType: tt.contentType,
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest("POST", "/test", strings.NewReader("CALL 001"))
+			w := httptest.NewRecorder()
+			ch.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestHTTPChannelSizeBuckets(t *testing.T) {
+	writer, _ := newTestHTTPWriter(t)
+	portCfg := config.PortConfig{Type: "http", Path: "/test", SideDesignation: "A1"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+	sizes := []int{10, 1024, 1025, 5000, 70 << 10}
+	for _, n := range sizes {
+		req := httptest.NewRequest("POST", "/test", strings.NewReader(strings.Repeat("x", n)))
+		w := httptest.NewRecorder()
+		ch.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("size %d: status = %d, want %d", n, w.Code, http.StatusOK)
+		}
+	}
+	// Rejected requests aren't counted
+	ch.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/test", strings.NewReader("")))
+	// The test writer's 1MB log limit rejects larger records, so count one directly
+	ch.recordBodySize(2 << 20)
+
+	want := map[string]int64{
+		"<=1KB":   2,
+		"<=4KB":   1,
+		"<=16KB":  1,
+		"<=64KB":  0,
+		"<=256KB": 1,
+		"<=1MB":   0,
+		">1MB":    1,
+	}
+	got := ch.GetStats().SizeBuckets
+	if len(got) != len(want) {
+		t.Errorf("SizeBuckets has %d keys, want %d: %v", len(got), len(want), got)
+	}
+	for label, count := range want {
+		if got[label] != count {
+			t.Errorf("SizeBuckets[%q] = %d, want %d", label, got[label], count)
+		}
+	}
+}
+
+func TestHTTPChannelCompressedBody(t *testing.T) {
+	const cdr = "<CDR><Call>001</Call><ANI>5551234</ANI></CDR>"
+
+	compress := func(newWriter func(io.Writer) io.WriteCloser) []byte {
+		var buf bytes.Buffer
+		zw := newWriter(&buf)
+		zw.Write([]byte(cdr))
+		zw.Close()
+		return buf.Bytes()
+	}
+	rawDeflate := func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	}
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"gzip", "gzip", compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })},
+		{"deflate", "deflate", compress(func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) })},
+		{"raw deflate", "deflate", compress(rawDeflate)},
+		{"identity", "identity", []byte(cdr)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, logPath := newTestHTTPWriter(t)
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test", SideDesignation: "A1"}, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest("POST", "/test", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Encoding", tt.encoding)
+			w := httptest.NewRecorder()
+			ch.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+
+			data, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "\n\n"+cdr) {
+				t.Errorf("log = %q, want the decompressed body", data)
+			}
+			if got := ch.GetStats().BytesRead; got != int64(len(cdr)) {
+				t.Errorf("BytesRead = %d, want decompressed size %d", got, len(cdr))
+			}
+		})
+	}
+}
+
+func TestHTTPChannelCompressedBodyRejected(t *testing.T) {
+	// 1KB of zeros compresses to a few bytes: well under the 64-byte wire
+	// limit, far over it once expanded
+	var bomb bytes.Buffer
+	zw := gzip.NewWriter(&bomb)
+	zw.Write(make([]byte, 1024))
+	zw.Close()
+
+	tests := []struct {
+		name       string
+		encoding   string
+		body       []byte
+		wantStatus int
+	}{
+		{"expands past limit", "gzip", bomb.Bytes(), http.StatusRequestEntityTooLarge},
+		{"not actually gzip", "gzip", []byte("plain text"), http.StatusBadRequest},
+		{"unsupported encoding", "br", []byte("whatever"), http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.body) > 64 {
+				t.Fatalf("test body is %d bytes, must fit the wire limit", len(tt.body))
+			}
+			writer, logPath := newTestHTTPWriter(t)
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ch := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test", SideDesignation: "A1", MaxBodyBytes: 64}, config.AppConfig{}, writer, logger)
+
+			req := httptest.NewRequest("POST", "/test", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Encoding", tt.encoding)
+			w := httptest.NewRecorder()
+			ch.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if ch.GetStats().Errors != 1 {
+				t.Errorf("Errors = %d, want 1", ch.GetStats().Errors)
+			}
+			if data, _ := os.ReadFile(logPath); len(data) != 0 {
+				t.Errorf("rejected body was logged: %q", data)
+			}
+		})
+	}
+}
+
+func TestHTTPChannelMaxConcurrentRequests(t *testing.T) {
+	writer, _ := newTestHTTPWriter(t)
+	portCfg := config.PortConfig{
+		Type:                  "http",
+		Path:                  "/test",
+		SideDesignation:       "A1",
+		MaxConcurrentRequests: 2,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+
+	// Hold two requests in flight by blocking their body reads
+	var pipes []*io.PipeWriter
+	done := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		pr, pw := io.Pipe()
+		pipes = append(pipes, pw)
+		go func() {
+			w := httptest.NewRecorder()
+			ch.ServeHTTP(w, httptest.NewRequest("POST", "/test", pr))
+			done <- w.Code
+		}()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ch.inflight) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Requests beyond the limit are shed
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		ch.ServeHTTP(w, httptest.NewRequest("POST", "/test", strings.NewReader("CALL 001")))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("request over limit: status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("503 should carry Retry-After")
+		}
+	}
+	if got := ch.GetStats().RejectedOverload; got != 3 {
+		t.Errorf("RejectedOverload = %d, want 3", got)
+	}
+
+	// Finishing the held requests frees their slots
+	for _, pw := range pipes {
+		pw.Write([]byte("CALL 002"))
+		pw.Close()
+	}
+	for i := 0; i < 2; i++ {
+		if code := <-done; code != http.StatusOK {
+			t.Errorf("held request: status = %d, want %d", code, http.StatusOK)
+		}
+	}
+	w := httptest.NewRecorder()
+	ch.ServeHTTP(w, httptest.NewRequest("POST", "/test", strings.NewReader("CALL 003")))
+	if w.Code != http.StatusOK {
+		t.Errorf("request after slots freed: status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := ch.GetStats().Errors; got != 0 {
+		t.Errorf("Errors = %d, want 0 (overload is counted separately)", got)
+	}
+}
+
+func TestHTTPChannelUnlimitedConcurrency(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test"}, config.AppConfig{}, nil, logger)
+	if ch.inflight != nil {
+		t.Error("max_concurrent_requests 0 should not limit concurrency")
+	}
+}
+
+func TestHTTPChannelRecordPreview(t *testing.T) {
+	writer, _ := newTestHTTPWriter(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test", RecordPreview: true}, config.AppConfig{}, writer, logger)
+
+	ch.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/test", strings.NewReader("CALL 001")))
+	if got := ch.LastRecord(); got != "CALL 001" {
+		t.Errorf("LastRecord() = %q, want %q", got, "CALL 001")
+	}
+
+	// Large bodies are truncated
+	ch.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/test", strings.NewReader(strings.Repeat("x", 2000))))
+	if got := ch.LastRecord(); got != strings.Repeat("x", recordPreviewMaxBytes) {
+		t.Errorf("LastRecord() length = %d, want %d", len(got), recordPreviewMaxBytes)
+	}
+
+	// Off by default
+	off := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test"}, config.AppConfig{}, writer, logger)
+	off.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/test", strings.NewReader("CALL 002")))
+	if got := off.LastRecord(); got != "" {
+		t.Errorf("LastRecord() without record_preview = %q, want empty", got)
+	}
+}
+
+func TestHTTPChannelAppendChecksum(t *testing.T) {
+	writer, logPath := newTestHTTPWriter(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ch := NewHTTPChannel(config.PortConfig{Type: "http", Path: "/test", AppendChecksum: output.ChecksumCRC32}, config.AppConfig{}, writer, logger)
+
+	w := httptest.NewRecorder()
+	ch.ServeHTTP(w, httptest.NewRequest("POST", "/test", strings.NewReader("123456789")))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Computed over the body only, so it's the standard CRC-32 check value
+	data, _ := os.ReadFile(logPath)
+	if !strings.HasSuffix(strings.TrimRight(string(data), "\n"), "123456789 [crc32:cbf43926]") {
+		t.Errorf("log = %q, want record ending in [crc32:cbf43926]", data)
+	}
+}
+
+func TestRecordPreviewRuneBoundary(t *testing.T) {
+	// A two-byte rune straddling the limit is dropped whole
+	record := strings.Repeat("a", recordPreviewMaxBytes-1) + "é"
+	got := recordPreview(record)
+	if len(got) != recordPreviewMaxBytes-1 || !utf8.ValidString(got) {
+		t.Errorf("recordPreview() = %d bytes (valid UTF-8: %v), want %d", len(got), utf8.ValidString(got), recordPreviewMaxBytes-1)
+	}
+	if got := recordPreview("short"); got != "short" {
+		t.Errorf("recordPreview(short) = %q", got)
+	}
+}
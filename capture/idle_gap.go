@@ -0,0 +1,153 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"nectarcollector/serial"
+)
+
+// idleGapReadBufferSize is the per-read buffer for idle-gap framing
+const idleGapReadBufferSize = 4096
+
+// gapSplitter accumulates bytes from a device that doesn't terminate its
+// records, and treats a stretch of silence as the end of a record.
+type gapSplitter struct {
+	gap      time.Duration
+	buf      []byte
+	lastData time.Time
+}
+
+// newGapSplitter creates a gapSplitter that ends records after gap of silence
+func newGapSplitter(gap time.Duration) *gapSplitter {
+	return &gapSplitter{gap: gap}
+}
+
+// Add appends bytes received at now
+func (g *gapSplitter) Add(data []byte, now time.Time) {
+	if len(data) == 0 {
+		return
+	}
+	g.buf = append(g.buf, data...)
+	g.lastData = now
+}
+
+// Ready returns the accumulated record once gap has passed since the last byte.
+// A record that reaches MaxLineBufferSize is returned immediately so a device
+// that never pauses can't grow the buffer without bound.
+func (g *gapSplitter) Ready(now time.Time) (string, bool) {
+	if len(g.buf) == 0 {
+		return "", false
+	}
+	if now.Sub(g.lastData) < g.gap && len(g.buf) < MaxLineBufferSize {
+		return "", false
+	}
+	return g.Flush()
+}
+
+// Flush returns whatever has accumulated, regardless of timing.
+// Trailing CR/LF is trimmed, matching newline framing.
+func (g *gapSplitter) Flush() (string, bool) {
+	if len(g.buf) == 0 {
+		return "", false
+	}
+	record := strings.TrimRight(string(g.buf), "\r\n")
+	g.buf = g.buf[:0]
+	return record, true
+}
+
+// readIdleGapLoop is the readLoop counterpart for ports with idle_gap_ms set.
+// bufio.Scanner can only split on content, so this reads raw bytes and uses
+// the read timeout as a tick to notice when the line has gone quiet.
+// Like readLoop, it blocks while NATS is disconnected.
+func (c *Channel) readIdleGapLoop(ctx context.Context, device string) error {
+	splitter := newGapSplitter(c.config.IdleGap())
+	buf := make([]byte, idleGapReadBufferSize)
+	sessionStart := time.Now()
+	lastDeviceCheck := sessionStart
+
+	// emit hands a completed record to the normal line pipeline
+	emit := func(record string) error {
+		if !c.checkLineQuality(record) {
+			return errBaudRateDrift
+		}
+		c.processLine(record)
+		return nil
+	}
+
+	// flush emits any partial record before the loop exits - on shutdown or
+	// disconnect the gap will never come, but the bytes are still a record
+	flush := func() {
+		if record, ok := splitter.Flush(); ok {
+			c.processLine(record)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return nil
+		case <-c.stopCh:
+			flush()
+			return nil
+		case <-c.redetectCh:
+			flush()
+			return errRedetect
+		default:
+		}
+
+		if c.sessionExpired(sessionStart, time.Now()) {
+			flush()
+			return errSessionExpired
+		}
+
+		if time.Since(lastDeviceCheck) >= devicePollInterval {
+			lastDeviceCheck = time.Now()
+			if device != "" && !serial.DevicePresent(device) {
+				flush()
+				c.markDeviceRemoved()
+				return errDeviceRemoved
+			}
+			c.checkModemSignal(lastDeviceCheck)
+		}
+
+		if !c.waitForNATS(ctx) {
+			flush()
+			return nil
+		}
+
+		n, err := c.reader.Read(buf)
+		now := time.Now()
+		splitter.Add(buf[:n], now)
+
+		if record, ok := splitter.Ready(now); ok {
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+
+		if err == nil {
+			continue
+		}
+
+		switch {
+		case err == io.EOF:
+			flush()
+			return nil
+		case err == serial.ErrLineStall:
+			c.logger.Warn("Line stall detected - triggering re-detection",
+				"device", c.config.Device)
+			return err
+		case isTimeoutError(err):
+			continue
+		default:
+			flush()
+			c.reader.IncrementErrors()
+			return fmt.Errorf("read error: %w", err)
+		}
+	}
+}
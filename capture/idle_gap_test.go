@@ -0,0 +1,168 @@
+package capture
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+	"nectarcollector/serial"
+)
+
+func TestGapSplitter(t *testing.T) {
+	g := newGapSplitter(100 * time.Millisecond)
+	start := time.Now()
+
+	g.Add([]byte("CALL 0"), start)
+	g.Add([]byte("01 IN\r\n"), start.Add(20*time.Millisecond))
+
+	if _, ok := g.Ready(start.Add(50 * time.Millisecond)); ok {
+		t.Fatal("Ready() returned a record before the gap elapsed")
+	}
+
+	record, ok := g.Ready(start.Add(130 * time.Millisecond))
+	if !ok {
+		t.Fatal("Ready() returned no record after the gap elapsed")
+	}
+	if record != "CALL 001 IN" {
+		t.Errorf("record = %q, want %q", record, "CALL 001 IN")
+	}
+
+	if _, ok := g.Ready(start.Add(time.Second)); ok {
+		t.Error("Ready() returned a record with nothing buffered")
+	}
+}
+
+func TestGapSplitterMaxSize(t *testing.T) {
+	g := newGapSplitter(time.Hour)
+	now := time.Now()
+
+	g.Add(make([]byte, MaxLineBufferSize), now)
+
+	if _, ok := g.Ready(now); !ok {
+		t.Error("Ready() should return a full buffer without waiting for the gap")
+	}
+}
+
+func TestGapSplitterFlush(t *testing.T) {
+	g := newGapSplitter(time.Hour)
+	g.Add([]byte("partial"), time.Now())
+
+	record, ok := g.Flush()
+	if !ok || record != "partial" {
+		t.Errorf("Flush() = %q, %v, want %q, true", record, ok, "partial")
+	}
+	if _, ok := g.Flush(); ok {
+		t.Error("Flush() returned a record after already flushing")
+	}
+}
+
+// readStep is one scripted Read result for scriptedReader
+type readStep struct {
+	delay time.Duration
+	data  string
+	err   error
+}
+
+// scriptedReader plays back a fixed sequence of reads, then returns io.EOF
+type scriptedReader struct {
+	steps []readStep
+	index int
+}
+
+func (s *scriptedReader) Read(p []byte) (int, error) {
+	if s.index >= len(s.steps) {
+		return 0, io.EOF
+	}
+	step := s.steps[s.index]
+	s.index++
+	time.Sleep(step.delay)
+	return copy(p, step.data), step.err
+}
+
+func (s *scriptedReader) Close() error                                 { return nil }
+func (s *scriptedReader) Device() string                               { return "/dev/ttyTEST" }
+func (s *scriptedReader) IsOpen() bool                                 { return true }
+func (s *scriptedReader) Reconfigure(int, bool) error                  { return nil }
+func (s *scriptedReader) SetBaudRate(int) error                        { return nil }
+func (s *scriptedReader) SetReadTimeout(time.Duration) error           { return nil }
+func (s *scriptedReader) ResetInputBuffer() error                      { return nil }
+func (s *scriptedReader) GetModemStatus() (*serial.ModemStatus, error) { return nil, nil }
+
+func TestReadIdleGapLoopSplitsOnGap(t *testing.T) {
+	dir := t.TempDir()
+	device := filepath.Join(dir, "ttyTEST")
+	if err := os.WriteFile(device, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       device,
+		Identifier:   "1429010002-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two records each split across reads, separated by silence that the
+	// read timeout reports as serial.ErrReadTimeout
+	reader := &scriptedReader{steps: []readStep{
+		{data: "CALL 0"},
+		{delay: 10 * time.Millisecond, data: "01 IN"},
+		{delay: 30 * time.Millisecond, err: serial.ErrReadTimeout},
+		{delay: 30 * time.Millisecond, err: serial.ErrReadTimeout},
+		{data: "CALL 0"},
+		{delay: 10 * time.Millisecond, data: "02 OUT"},
+		{delay: 30 * time.Millisecond, err: serial.ErrReadTimeout},
+		{delay: 30 * time.Millisecond, err: serial.ErrReadTimeout},
+		{data: "TRAILING"},
+	}}
+
+	c := &Channel{
+		config: &config.PortConfig{
+			Device:          device,
+			SideDesignation: "A1",
+			IdleGapMs:       50,
+		},
+		appConfig:   &config.AppConfig{FIPSCode: "1429010002"},
+		reader:      serial.NewReaderWithStats(reader),
+		dualWriter:  writer,
+		natsChecker: &MockNATSChecker{connected: true},
+		stopCh:      make(chan struct{}),
+		logger:      logger,
+	}
+
+	if err := c.readIdleGapLoop(context.Background(), device); err != nil {
+		t.Fatalf("readIdleGapLoop() error = %v", err)
+	}
+	writer.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "1429010002-A1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	want := []string{"CALL 001 IN", "CALL 002 OUT", "TRAILING"}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d records, want %d: %q", len(lines), len(want), lines)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "[1429010002][A1][") {
+			t.Errorf("record %d missing header: %q", i, line)
+		}
+		if !strings.HasSuffix(line, "] "+want[i]) {
+			t.Errorf("record %d = %q, want payload %q", i, line, want[i])
+		}
+	}
+}
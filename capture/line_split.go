@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"bytes"
+
+	"nectarcollector/config"
+)
+
+// lineSplitter splits lines like bufio.ScanLines, but with a
+// per-port length limit. A line longer than max is truncated to its first max
+// bytes (or dropped) and the rest of it is discarded up to the next newline,
+// instead of failing the scanner with bufio.ErrTooLong.
+type lineSplitter struct {
+	max        int
+	drop       bool
+	skipping   bool   // Discarding the remainder of an oversized line
+	onOversize func() // Called once per oversized line (optional)
+}
+
+// newLineSplitter builds a splitter from the port's max_line_bytes and oversize_lines
+func newLineSplitter(portCfg *config.PortConfig, onOversize func()) *lineSplitter {
+	max := portCfg.MaxLineBytes
+	if max <= 0 {
+		max = MaxLineBufferSize
+	}
+	return &lineSplitter{
+		max:        max,
+		drop:       portCfg.OversizeLines == config.OversizeDrop,
+		onOversize: onOversize,
+	}
+}
+
+// BufferSize is the scanner buffer needed to see max bytes plus CRLF
+func (s *lineSplitter) BufferSize() int {
+	return s.max + 2
+}
+
+// Split implements bufio.SplitFunc
+func (s *lineSplitter) Split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	i := bytes.IndexByte(data, '\n')
+
+	if s.skipping {
+		if i < 0 {
+			return len(data), nil, nil
+		}
+		s.skipping = false
+		return i + 1, nil, nil
+	}
+
+	if i >= 0 {
+		line := dropCR(data[:i])
+		if len(line) > s.max {
+			return i + 1, s.oversize(line), nil
+		}
+		return i + 1, line, nil
+	}
+
+	// No newline yet but already too long: keep or drop what we have and
+	// skip the rest of the line as it arrives
+	if len(data) > s.max && !(len(data) == s.max+1 && data[s.max] == '\r') {
+		s.skipping = true
+		return len(data), s.oversize(data), nil
+	}
+
+	if atEOF {
+		return len(data), dropCR(data), nil
+	}
+
+	return 0, nil, nil
+}
+
+// oversize applies the policy to an oversized line and reports it
+func (s *lineSplitter) oversize(line []byte) []byte {
+	if s.onOversize != nil {
+		s.onOversize()
+	}
+	if s.drop {
+		return nil
+	}
+	return line[:s.max]
+}
+
+// dropCR drops a terminal \r from the data
+func dropCR(data []byte) []byte {
+	if len(data) > 0 && data[len(data)-1] == '\r' {
+		return data[:len(data)-1]
+	}
+	return data
+}
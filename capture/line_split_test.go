@@ -0,0 +1,92 @@
+package capture
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"nectarcollector/config"
+)
+
+func scanAll(t *testing.T, portCfg *config.PortConfig, input string, oneByte bool) ([]string, int) {
+	t.Helper()
+
+	oversize := 0
+	splitter := newLineSplitter(portCfg, func() { oversize++ })
+
+	var r = strings.NewReader(input)
+	scanner := bufio.NewScanner(r)
+	if oneByte {
+		scanner = bufio.NewScanner(iotest.OneByteReader(r))
+	}
+	scanner.Buffer(make([]byte, 4), splitter.BufferSize())
+	scanner.Split(splitter.Split)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scanner error = %v", err)
+	}
+	return lines, oversize
+}
+
+func TestLineSplitter(t *testing.T) {
+	input := "short\r\n" + strings.Repeat("X", 25) + "\nafter\nTAIL"
+
+	tests := []struct {
+		name         string
+		policy       string
+		want         []string
+		wantOversize int
+	}{
+		{"truncate", config.OversizeTruncate, []string{"short", "XXXXXXXXXX", "after", "TAIL"}, 1},
+		{"default is truncate", "", []string{"short", "XXXXXXXXXX", "after", "TAIL"}, 1},
+		{"drop", config.OversizeDrop, []string{"short", "after", "TAIL"}, 1},
+	}
+
+	for _, tt := range tests {
+		for _, oneByte := range []bool{false, true} {
+			name := tt.name
+			if oneByte {
+				name += " one byte reads"
+			}
+			t.Run(name, func(t *testing.T) {
+				portCfg := &config.PortConfig{MaxLineBytes: 10, OversizeLines: tt.policy}
+				lines, oversize := scanAll(t, portCfg, input, oneByte)
+
+				if strings.Join(lines, "|") != strings.Join(tt.want, "|") {
+					t.Errorf("lines = %q, want %q", lines, tt.want)
+				}
+				if oversize != tt.wantOversize {
+					t.Errorf("oversize = %d, want %d", oversize, tt.wantOversize)
+				}
+			})
+		}
+	}
+}
+
+func TestLineSplitterExactLimit(t *testing.T) {
+	portCfg := &config.PortConfig{MaxLineBytes: 5}
+
+	// A line of exactly max bytes must not be cut while waiting for its newline
+	for _, oneByte := range []bool{false, true} {
+		lines, oversize := scanAll(t, portCfg, "12345\r\n123456\nok\n", oneByte)
+
+		if strings.Join(lines, "|") != "12345|12345|ok" {
+			t.Errorf("oneByte=%v lines = %q, want [12345 12345 ok]", oneByte, lines)
+		}
+		if oversize != 1 {
+			t.Errorf("oneByte=%v oversize = %d, want 1 (only the 6-byte line)", oneByte, oversize)
+		}
+	}
+}
+
+func TestNewLineSplitterDefault(t *testing.T) {
+	s := newLineSplitter(&config.PortConfig{}, nil)
+	if s.max != MaxLineBufferSize || s.drop {
+		t.Errorf("default splitter max = %d, drop = %v, want %d, false", s.max, s.drop, MaxLineBufferSize)
+	}
+}
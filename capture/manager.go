@@ -4,56 +4,89 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"nectarcollector/config"
 	"nectarcollector/forward"
 	"nectarcollector/output"
+	"nectarcollector/serial"
 )
 
-// Manager manages multiple capture channels (serial and HTTP)
+// Manager manages multiple capture channels (serial, UDP and HTTP)
 type Manager struct {
 	config          *config.Config
 	configPath      string         // Path to config file for saving
 	channels        []*Channel     // Serial channels
 	httpChannels    []*HTTPChannel // HTTP channels
+	udpChannels     []*UDPChannel  // UDP channels
 	natsConn        *output.NATSConnection
 	healthPublisher *output.HealthPublisher
 	eventPublisher  *output.EventPublisher
+	eventCallback   output.EventCallback // Overrides eventPublisher when set
+	channelCallback ChannelChangeCallback
 	forwarder       *forward.Forwarder
+	version         string // Application version reported in events and health
 	logger          *slog.Logger
 	ctx             context.Context // Context for starting new channels
+	channelsReady   chan struct{}   // Closed once Start has created the configured channels
 	mu              sync.RWMutex
 }
 
 // NewManager creates a new capture manager
 func NewManager(cfg *config.Config, configPath string, logger *slog.Logger) *Manager {
 	return &Manager{
-		config:       cfg,
-		configPath:   configPath,
-		channels:     make([]*Channel, 0),
-		httpChannels: make([]*HTTPChannel, 0),
-		logger:       logger,
+		config:        cfg,
+		configPath:    configPath,
+		channels:      make([]*Channel, 0),
+		httpChannels:  make([]*HTTPChannel, 0),
+		udpChannels:   make([]*UDPChannel, 0),
+		channelsReady: make(chan struct{}),
+		logger:        logger,
 	}
 }
 
+// SetVersion sets the application version reported in the service_start
+// event and health heartbeats. Call before Start.
+func (m *Manager) SetVersion(version string) {
+	m.version = version
+}
+
 // Start initializes and starts all enabled capture channels.
-// NATS connection is required - returns error if NATS is unavailable.
+// NATS connection is required - returns error if NATS is still unavailable
+// after nats.connect_wait_sec.
 func (m *Manager) Start(ctx context.Context) error {
 	m.ctx = ctx // Store context for starting new channels later
 	m.logger.Info("Starting capture manager", "instance", m.config.App.InstanceID)
 
 	// Connect to NATS - required for operation
-	natsConn, err := output.NewNATSConnection(
-		m.config.NATS.URL,
-		m.config.NATS.MaxReconnects,
-		m.logger,
-	)
+	natsConn, err := output.NewNATSConnection(&output.NATSConnectionConfig{
+		URL:           m.config.NATS.URL,
+		Name:          m.config.App.InstanceID,
+		MaxReconnects: m.config.NATS.MaxReconnects,
+		ReconnectWait: m.config.NATS.ReconnectWait(),
+		ConnectWait:   m.config.NATS.ConnectWait(),
+		Logger:        m.logger,
+		// The client adds 0..jitter to each wait rather than ±
+		ReconnectJitter: time.Duration(float64(m.config.NATS.ReconnectWait()) * m.config.Recovery.ReconnectJitter()),
+	})
 	if err != nil {
 		return fmt.Errorf("NATS connection required: %w", err)
 	}
 	m.natsConn = natsConn
+	m.natsConn.StartRTTMonitor(ctx, output.NATSRTTInterval)
+
+	// Provision streams before anything publishes or binds to them
+	if m.config.NATS.EnsureStreams {
+		if err := m.natsConn.EnsureStreams(streamSpecs(m.config.NATS.StreamConfigs())); err != nil {
+			m.logger.Error("Failed to provision JetStream streams", "error", err)
+		}
+	}
 
 	// Create event publisher (optional - nil-safe if NATS fails later)
 	eventsSubject := output.BuildEventsSubject(m.config.NATS.SubjectPrefix, m.config.App.InstanceID)
@@ -68,77 +101,12 @@ func (m *Manager) Start(ctx context.Context) error {
 	m.eventPublisher.CheckAndPublishUncleanShutdown()
 
 	// Publish service start event
-	m.eventPublisher.PublishServiceStart("1.0.0")
-
-	// Create and start channels for enabled ports
-	startedCount := 0
-	for _, portCfg := range m.config.Ports {
-		if !portCfg.Enabled {
-			portID := portCfg.Device
-			if portCfg.IsHTTP() {
-				portID = portCfg.Path
-			}
-			m.logger.Info("Skipping disabled port", "port", portID)
-			continue
-		}
-
-		if portCfg.IsHTTP() {
-			// Create HTTP channel (will be registered with HTTP server later)
-			httpChannel, err := m.createHTTPChannel(portCfg)
-			if err != nil {
-				m.logger.Error("Failed to create HTTP channel", "path", portCfg.Path, "error", err)
-				continue
-			}
-
-			m.mu.Lock()
-			m.httpChannels = append(m.httpChannels, httpChannel)
-			m.mu.Unlock()
-
-			startedCount++
-			m.logger.Info("Created HTTP capture channel",
-				"path", portCfg.Path,
-				"side_designation", portCfg.SideDesignation)
-		} else {
-			// Create serial channel
-			channel, err := NewChannel(
-				&portCfg,
-				&m.config.Detection,
-				&m.config.NATS,
-				&m.config.Recovery,
-				&m.config.App,
-				&m.config.Logging,
-				m.natsConn,
-				m.logger.With("device", portCfg.Device),
-			)
-			if err != nil {
-				m.logger.Error("Failed to create channel", "device", portCfg.Device, "error", err)
-				continue
-			}
-
-			// Wire event callback - channel calls this, we publish to NATS
-			// This keeps Channel decoupled from EventPublisher
-			if m.eventPublisher != nil {
-				channel.SetEventCallback(func(event output.Event) {
-					m.eventPublisher.Publish(event)
-				})
-			}
+	m.eventPublisher.PublishServiceStart(m.version)
 
-			if err := channel.Start(ctx); err != nil {
-				m.logger.Error("Failed to start channel", "device", portCfg.Device, "error", err)
-				continue
-			}
-
-			m.mu.Lock()
-			m.channels = append(m.channels, channel)
-			m.mu.Unlock()
-
-			startedCount++
-			m.logger.Info("Started serial capture channel",
-				"device", portCfg.Device,
-				"side_designation", portCfg.SideDesignation)
-		}
-	}
+	m.warnUnbackedSubjects()
 
+	// Create and start channels for enabled ports
+	startedCount := m.startChannels()
 	if startedCount == 0 {
 		return fmt.Errorf("failed to start any capture channels")
 	}
@@ -150,6 +118,7 @@ func (m *Manager) Start(ctx context.Context) error {
 		Subject:    healthSubject,
 		InstanceID: m.config.App.InstanceID,
 		FIPSCode:   m.config.App.FIPSCode,
+		AppVersion: m.version,
 		Interval:   60 * time.Second,
 		Logger:     m.logger,
 		StatsFunc:  m.getHealthStats,
@@ -176,13 +145,133 @@ func (m *Manager) Start(ctx context.Context) error {
 	return nil
 }
 
+// portStartResult is the outcome of starting one port: exactly one of
+// channel, httpChannel, udpChannel or err is set
+type portStartResult struct {
+	channel     *Channel
+	httpChannel *HTTPChannel
+	udpChannel  *UDPChannel
+	err         error
+}
+
+// startChannels creates and starts channels for the enabled ports in config
+// order, returning how many started. Serial detection runs in each channel's
+// capture loop, so a slow port doesn't hold up the others. ChannelsReady is
+// closed when it returns.
+func (m *Manager) startChannels() int {
+	defer close(m.channelsReady)
+
+	startedCount := 0
+	for _, portCfg := range m.config.Ports {
+		if !portCfg.Enabled {
+			m.logger.Info("Skipping disabled port", "port", portCfg.ID())
+			continue
+		}
+
+		result := m.startPort(portCfg)
+		if result.err != nil {
+			m.logger.Error("Failed to start channel", "port", portCfg.ID(), "error", result.err)
+			continue
+		}
+
+		m.mu.Lock()
+		if result.httpChannel != nil {
+			m.httpChannels = append(m.httpChannels, result.httpChannel)
+		} else if result.udpChannel != nil {
+			m.udpChannels = append(m.udpChannels, result.udpChannel)
+		} else {
+			m.channels = append(m.channels, result.channel)
+		}
+		m.mu.Unlock()
+
+		startedCount++
+		if portCfg.IsHTTP() {
+			m.logger.Info("Created HTTP capture channel",
+				"path", portCfg.Path,
+				"side_designation", portCfg.SideDesignation)
+		} else if portCfg.IsUDP() {
+			m.logger.Info("Started UDP capture channel",
+				"address", portCfg.Address,
+				"side_designation", portCfg.SideDesignation)
+		} else {
+			m.logger.Info("Started serial capture channel",
+				"device", portCfg.Source(),
+				"side_designation", portCfg.SideDesignation)
+		}
+	}
+
+	return startedCount
+}
+
+// ChannelsReady is closed once Start has created the channels for the
+// configured ports (whether or not each one started). Channels started after
+// that are reported through SetChannelChangeCallback.
+func (m *Manager) ChannelsReady() <-chan struct{} {
+	return m.channelsReady
+}
+
+// startPort creates one channel and, for serial ports, starts its capture
+// loop. It doesn't touch Manager state; startChannels registers the result.
+func (m *Manager) startPort(portCfg config.PortConfig) portStartResult {
+	if portCfg.IsHTTP() {
+		// Create HTTP channel (will be registered with HTTP server later)
+		httpChannel, err := m.createHTTPChannel(portCfg)
+		if err != nil {
+			return portStartResult{err: fmt.Errorf("failed to create HTTP channel: %w", err)}
+		}
+		return portStartResult{httpChannel: httpChannel}
+	}
+	if portCfg.IsUDP() {
+		udpChannel, err := m.startUDPChannel(portCfg)
+		if err != nil {
+			return portStartResult{err: err}
+		}
+		return portStartResult{udpChannel: udpChannel}
+	}
+
+	channel, err := NewChannel(
+		&portCfg,
+		&m.config.Detection,
+		&m.config.NATS,
+		&m.config.Recovery,
+		&m.config.App,
+		&m.config.Logging,
+		m.natsConn,
+		m.logger.With("device", portCfg.Source()),
+	)
+	if err != nil {
+		return portStartResult{err: fmt.Errorf("failed to create channel: %w", err)}
+	}
+
+	// Wire event callback - channel calls this, we publish to NATS
+	// This keeps Channel decoupled from EventPublisher
+	if m.eventPublisher != nil {
+		channel.SetEventCallback(func(event output.Event) {
+			m.eventPublisher.Publish(event)
+		})
+	}
+
+	if err := channel.Start(m.ctx); err != nil {
+		return portStartResult{err: err}
+	}
+	return portStartResult{channel: channel}
+}
+
 // Stop gracefully stops all capture channels
 func (m *Manager) Stop() {
-	m.logger.Info("Stopping capture manager")
+	m.StopWithReason("shutdown requested")
+}
+
+// StopWithReason is Stop with the triggering cause (e.g. the signal name)
+// recorded in the service_stop event
+func (m *Manager) StopWithReason(reason string) {
+	m.logger.Info("Stopping capture manager", "reason", reason)
 
 	// Publish service stop event before shutting down
-	if m.eventPublisher != nil {
-		m.eventPublisher.PublishServiceStop("shutdown requested")
+	if m.eventCallback != nil {
+		m.eventCallback(output.ServiceStopEvent(reason))
+	} else if m.eventPublisher != nil {
+		m.eventPublisher.PublishServiceStop(reason)
 	}
 
 	// Stop forwarder first (drains pending messages)
@@ -198,6 +287,8 @@ func (m *Manager) Stop() {
 	m.mu.RLock()
 	channels := make([]*Channel, len(m.channels))
 	copy(channels, m.channels)
+	udpChannels := make([]*UDPChannel, len(m.udpChannels))
+	copy(udpChannels, m.udpChannels)
 	m.mu.RUnlock()
 
 	// Stop all channels concurrently
@@ -209,6 +300,13 @@ func (m *Manager) Stop() {
 			ch.Stop()
 		}(channel)
 	}
+	for _, channel := range udpChannels {
+		wg.Add(1)
+		go func(ch *UDPChannel) {
+			defer wg.Done()
+			ch.Stop()
+		}(channel)
+	}
 
 	wg.Wait()
 
@@ -279,6 +377,61 @@ func (m *Manager) NATSConnected() bool {
 	return m.natsConn != nil && m.natsConn.IsConnected()
 }
 
+// Readiness reports whether the collector can capture and deliver data
+type Readiness struct {
+	Ready         bool   `json:"ready"`
+	NATSConnected bool   `json:"nats_connected"`
+	ReadyChannels int    `json:"ready_channels"`
+	TotalChannels int    `json:"total_channels"`
+	Reason        string `json:"reason,omitempty"` // Why not ready
+}
+
+// Readiness checks NATS and channel states. Ready means NATS is connected and
+// at least one channel is running. Channels detecting, reconnecting, without
+// signal, paused, waiting for NATS, errored, or stopped don't count.
+func (m *Manager) Readiness() Readiness {
+	m.mu.RLock()
+	states := make([]ChannelState, 0, len(m.channels)+len(m.httpChannels)+len(m.udpChannels))
+	for _, ch := range m.channels {
+		states = append(states, ch.State())
+	}
+	// HTTP channels are ready while their listen port is bound; UDP channels
+	// are only kept once their listener is bound
+	for _, h := range m.httpChannels {
+		if h.State() == HTTPStateRunning {
+			states = append(states, StateRunning)
+		} else {
+			states = append(states, StateError)
+		}
+	}
+	for range m.udpChannels {
+		states = append(states, StateRunning)
+	}
+	m.mu.RUnlock()
+
+	return evaluateReadiness(m.NATSConnected(), states)
+}
+
+// evaluateReadiness is the readiness decision, separated for testing
+func evaluateReadiness(natsConnected bool, states []ChannelState) Readiness {
+	r := Readiness{NATSConnected: natsConnected, TotalChannels: len(states)}
+	for _, state := range states {
+		if state == StateRunning {
+			r.ReadyChannels++
+		}
+	}
+
+	switch {
+	case !natsConnected:
+		r.Reason = "NATS not connected"
+	case r.ReadyChannels == 0:
+		r.Reason = "no channels ready"
+	default:
+		r.Ready = true
+	}
+	return r
+}
+
 // NATSConn returns the NATS connection (for API event fetching)
 func (m *Manager) NATSConn() *output.NATSConnection {
 	return m.natsConn
@@ -297,7 +450,36 @@ type ChannelInfo struct {
 	SideDesignation string      `json:"side_designation"`
 	FIPSCode        string      `json:"fips_code"`
 	State           string      `json:"state"`
+	StateCode       int         `json:"state_code"` // Numeric State, see ChannelState.StateCode
+	UptimeSec       int64       `json:"uptime_sec"` // Seconds since Stats StartTime (0 if not started)
 	Stats           interface{} `json:"stats"`
+
+	// Whether a JetStream stream captures the channel's CDR subject (nil =
+	// unknown, e.g. NATS disconnected). False means publishes are dropped.
+	JetStreamBacked *bool `json:"jetstream_backed,omitempty"`
+}
+
+// streamSpecs converts configured streams to output stream specs
+func streamSpecs(streams []config.StreamConfig) []output.StreamSpec {
+	specs := make([]output.StreamSpec, 0, len(streams))
+	for _, s := range streams {
+		specs = append(specs, output.StreamSpec{
+			Name:      s.Name,
+			Subjects:  s.Subjects,
+			Retention: s.Retention,
+			MaxAge:    time.Duration(s.MaxAgeHours) * time.Hour,
+			MaxBytes:  s.MaxBytes,
+		})
+	}
+	return specs
+}
+
+// uptimeSec returns whole seconds elapsed since start, or 0 if start is unset
+func uptimeSec(start, now time.Time) int64 {
+	if start.IsZero() || now.Before(start) {
+		return 0
+	}
+	return int64(now.Sub(start).Seconds())
 }
 
 // GetAllStats returns detailed stats for all channels (for API)
@@ -307,44 +489,34 @@ func (m *Manager) GetAllStats() map[string]interface{} {
 	copy(channels, m.channels)
 	httpChannels := make([]*HTTPChannel, len(m.httpChannels))
 	copy(httpChannels, m.httpChannels)
+	udpChannels := make([]*UDPChannel, len(m.udpChannels))
+	copy(udpChannels, m.udpChannels)
 	m.mu.RUnlock()
 
-	channelInfos := make([]ChannelInfo, 0, len(channels)+len(httpChannels))
-
-	// Serial channels
+	channelInfos := make([]ChannelInfo, 0, len(channels)+len(httpChannels)+len(udpChannels))
+	subjects := make([]string, 0, cap(channelInfos)) // CDR subject per channelInfos entry
 	for _, ch := range channels {
-		// Get FIPS code (port-specific or app-level)
-		fipsCode := ch.config.FIPSCode
-		if fipsCode == "" {
-			fipsCode = m.config.App.FIPSCode
-		}
-
-		channelInfos = append(channelInfos, ChannelInfo{
-			Device:          ch.Device(),
-			Type:            "serial",
-			SideDesignation: ch.config.SideDesignation,
-			FIPSCode:        fipsCode,
-			State:           ch.State().String(),
-			Stats:           ch.Stats(),
-		})
+		channelInfos = append(channelInfos, m.serialChannelInfo(ch))
+		subjects = append(subjects, m.cdrSubject(ch.config))
 	}
-
-	// HTTP channels
 	for _, ch := range httpChannels {
 		cfg := ch.Config()
-		fipsCode := cfg.FIPSCode
-		if fipsCode == "" {
-			fipsCode = m.config.App.FIPSCode
-		}
+		channelInfos = append(channelInfos, m.httpChannelInfo(ch))
+		subjects = append(subjects, m.cdrSubject(&cfg))
+	}
+	for _, ch := range udpChannels {
+		cfg := ch.Config()
+		channelInfos = append(channelInfos, m.udpChannelInfo(ch))
+		subjects = append(subjects, m.cdrSubject(&cfg))
+	}
 
-		channelInfos = append(channelInfos, ChannelInfo{
-			Path:            cfg.Path,
-			Type:            "http",
-			SideDesignation: cfg.SideDesignation,
-			FIPSCode:        fipsCode,
-			State:           "running",
-			Stats:           ch.GetStats(),
-		})
+	if m.natsConn != nil {
+		backed := m.natsConn.JetStreamBacked(subjects)
+		for i, subject := range subjects {
+			if b, ok := backed[subject]; ok {
+				channelInfos[i].JetStreamBacked = &b
+			}
+		}
 	}
 
 	// Get NATS stats with JetStream stream info
@@ -363,6 +535,11 @@ func (m *Manager) GetAllStats() map[string]interface{} {
 		"channels":       channelInfos,
 	}
 
+	// Consumer lag - shows when the forwarder or an external consumer falls behind
+	if m.natsConn != nil {
+		result["consumers"] = m.natsConn.ConsumerStats(m.consumerRefs())
+	}
+
 	// Add forwarder stats if enabled
 	if m.forwarder != nil {
 		result["forwarder"] = m.forwarder.Stats()
@@ -371,15 +548,143 @@ func (m *Manager) GetAllStats() map[string]interface{} {
 	return result
 }
 
+// cdrSubject returns the NATS subject a port's records are published to
+func (m *Manager) cdrSubject(portCfg *config.PortConfig) string {
+	_, subject := channelNaming(portCfg, &m.config.App, m.config.NATS.SubjectPrefix)
+	return subject
+}
+
+// warnUnbackedSubjects logs a warning for each enabled port whose CDR subject
+// no JetStream stream captures. NATS accepts those publishes and drops them,
+// so without this the only symptom is a stream count that never moves.
+func (m *Manager) warnUnbackedSubjects() {
+	ports := make([]config.PortConfig, 0, len(m.config.Ports))
+	subjects := make([]string, 0, len(m.config.Ports))
+	for _, portCfg := range m.config.Ports {
+		if portCfg.Enabled {
+			ports = append(ports, portCfg)
+			subjects = append(subjects, m.cdrSubject(&portCfg))
+		}
+	}
+
+	backed := m.natsConn.JetStreamBacked(subjects)
+	for i, subject := range subjects {
+		if b, ok := backed[subject]; ok && !b {
+			m.logger.Warn("CDR subject is not captured by any JetStream stream - records will only reach the log",
+				"port", ports[i].ID(),
+				"subject", subject)
+		}
+	}
+}
+
+// consumerRefs lists the durable consumers to report lag for: those
+// configured, plus the forwarder's when it's enabled
+func (m *Manager) consumerRefs() []output.ConsumerRef {
+	refs := make([]output.ConsumerRef, 0, len(m.config.NATS.Consumers)+1)
+	for _, c := range m.config.NATS.Consumers {
+		refs = append(refs, output.ConsumerRef{Stream: c.Stream, Name: c.Name})
+	}
+	if m.config.Forwarder.Enabled {
+		refs = append(refs, output.ConsumerRef{Stream: "cdr", Name: forward.ConsumerName(m.config.App.InstanceID)})
+	}
+	return refs
+}
+
+// GetChannelInfo returns one channel's info by port ID (device without /dev/
+// for serial, path for HTTP, address for TCP and UDP). Returns false if no
+// running channel matches.
+func (m *Manager) GetChannelInfo(id string) (ChannelInfo, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, ch := range m.channels {
+		if ch.config.ID() == id {
+			return m.serialChannelInfo(ch), true
+		}
+	}
+	for _, ch := range m.httpChannels {
+		cfg := ch.Config()
+		if cfg.ID() == id {
+			return m.httpChannelInfo(ch), true
+		}
+	}
+	for _, ch := range m.udpChannels {
+		if ch.Address() == id {
+			return m.udpChannelInfo(ch), true
+		}
+	}
+	return ChannelInfo{}, false
+}
+
+// serialChannelInfo builds the API view of a serial channel
+func (m *Manager) serialChannelInfo(ch *Channel) ChannelInfo {
+	// Get FIPS code (port-specific or app-level)
+	fipsCode := ch.config.FIPSCode
+	if fipsCode == "" {
+		fipsCode = m.config.App.FIPSCode
+	}
+
+	stats := ch.Stats()
+	return ChannelInfo{
+		Device:          ch.Device(),
+		Type:            "serial",
+		SideDesignation: ch.config.SideDesignation,
+		FIPSCode:        fipsCode,
+		State:           ch.State().String(),
+		StateCode:       ch.State().StateCode(),
+		UptimeSec:       uptimeSec(stats.StartTime, time.Now()),
+		Stats:           stats,
+	}
+}
+
+// httpChannelInfo builds the API view of an HTTP channel
+func (m *Manager) httpChannelInfo(ch *HTTPChannel) ChannelInfo {
+	cfg := ch.Config()
+	fipsCode := cfg.FIPSCode
+	if fipsCode == "" {
+		fipsCode = m.config.App.FIPSCode
+	}
+
+	stats := ch.GetStats()
+	return ChannelInfo{
+		Path:            cfg.Path,
+		Type:            "http",
+		SideDesignation: cfg.SideDesignation,
+		FIPSCode:        fipsCode,
+		State:           ch.State(),
+		StateCode:       ch.StateCode(),
+		UptimeSec:       uptimeSec(stats.StartTime, time.Now()),
+		Stats:           stats,
+	}
+}
+
+// udpChannelInfo builds the API view of a UDP channel
+func (m *Manager) udpChannelInfo(ch *UDPChannel) ChannelInfo {
+	cfg := ch.Config()
+	stats := ch.GetStats()
+	return ChannelInfo{
+		Device:          cfg.Address,
+		Type:            config.PortTypeUDP,
+		SideDesignation: cfg.SideDesignation,
+		FIPSCode:        portFIPSCode(&cfg, &m.config.App),
+		State:           "running",
+		StateCode:       StateRunning.StateCode(),
+		UptimeSec:       uptimeSec(stats.StartTime, time.Now()),
+		Stats:           stats,
+	}
+}
+
 // getHealthStats returns health stats for the health publisher
 func (m *Manager) getHealthStats() output.HealthStats {
 	m.mu.RLock()
 	channels := make([]*Channel, len(m.channels))
 	copy(channels, m.channels)
+	udpChannels := make([]*UDPChannel, len(m.udpChannels))
+	copy(udpChannels, m.udpChannels)
 	m.mu.RUnlock()
 
 	now := time.Now()
-	channelHealth := make([]output.ChannelHealth, 0, len(channels))
+	channelHealth := make([]output.ChannelHealth, 0, len(channels)+len(udpChannels))
 
 	for _, ch := range channels {
 		stats := ch.Stats()
@@ -394,11 +699,38 @@ func (m *Manager) getHealthStats() output.HealthStats {
 			Device:          ch.Device(),
 			SideDesignation: ch.config.SideDesignation,
 			State:           ch.State().String(),
+			StateCode:       ch.State().StateCode(),
 			BaudRate:        stats.DetectedBaud,
+			BaudSource:      stats.BaudSource,
 			Reconnects:      stats.Reconnects,
 			BytesRead:       stats.BytesRead,
 			LinesRead:       stats.LinesRead,
 			Errors:          stats.Errors,
+			ParityErrors:    stats.ParityErrors,
+			FramingErrors:   stats.FramingErrors,
+			OverrunErrors:   stats.OverrunErrors,
+			Breaks:          stats.Breaks,
+			LastLineAgo:     lastLineAgo,
+		})
+	}
+
+	// UDP channels are running once registered; each datagram is one line
+	for _, u := range udpChannels {
+		stats := u.GetStats()
+
+		var lastLineAgo int64 = -1
+		if !stats.LastPacketTime.IsZero() {
+			lastLineAgo = int64(now.Sub(stats.LastPacketTime).Seconds())
+		}
+
+		channelHealth = append(channelHealth, output.ChannelHealth{
+			Device:          u.Address(),
+			SideDesignation: u.SideDesignation(),
+			State:           StateRunning.String(),
+			StateCode:       StateRunning.StateCode(),
+			BytesRead:       stats.BytesRead,
+			LinesRead:       stats.Packets,
+			Errors:          stats.Errors,
 			LastLineAgo:     lastLineAgo,
 		})
 	}
@@ -411,34 +743,56 @@ func (m *Manager) getHealthStats() output.HealthStats {
 
 // createHTTPChannel creates an HTTP capture channel with its DualWriter
 func (m *Manager) createHTTPChannel(portCfg config.PortConfig) (*HTTPChannel, error) {
-	// Get FIPS code
-	fipsCode := portCfg.FIPSCode
-	if fipsCode == "" {
-		fipsCode = m.config.App.FIPSCode
-	}
+	identifier, natsSubject := channelNaming(&portCfg, &m.config.App, m.config.NATS.SubjectPrefix)
 
-	// Build identifier for log file (e.g., "1429010002-A1")
-	identifier := fmt.Sprintf("%s-%s", fipsCode, portCfg.SideDesignation)
+	// Create DualWriter config
+	dwConfig := &output.DualWriterConfig{
+		Device:          portCfg.Path, // Use path as device identifier for HTTP
+		Identifier:      identifier,
+		LogFilename:     logFilename(&portCfg, &m.config.App, &m.config.Logging),
+		LogBasePath:     m.config.Logging.BasePath,
+		LogMaxSizeMB:    m.config.Logging.MaxSizeMB,
+		LogMaxBackups:   m.config.Logging.MaxBackups,
+		LogCompress:     m.config.Logging.Compress,
+		NATSConn:        m.natsConn,
+		NATSSubject:     natsSubject,
+		CompressPayload: portCfg.CompressPayload,
+		ExtraSubjects:   portCfg.ExtraSubjects,
+		Logger:          m.logger,
+
+		LogBufferSize:    m.config.Logging.WriteBufferKB * 1024,
+		LogFlushInterval: m.config.Logging.FlushInterval(),
+	}
 
-	// Build NATS subject
-	var natsSubject string
-	if portCfg.Vendor != "" {
-		natsSubject = fmt.Sprintf("%s.%s.%s", m.config.NATS.SubjectPrefix, portCfg.Vendor, fipsCode)
-	} else {
-		natsSubject = fmt.Sprintf("%s.%s", m.config.NATS.SubjectPrefix, fipsCode)
+	dualWriter, err := output.NewDualWriter(dwConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dual writer: %w", err)
 	}
 
-	// Create DualWriter config
+	return NewHTTPChannel(portCfg, m.config.App, dualWriter, m.logger), nil
+}
+
+// startUDPChannel creates a UDP capture channel with its DualWriter and binds
+// its listener
+func (m *Manager) startUDPChannel(portCfg config.PortConfig) (*UDPChannel, error) {
+	identifier, natsSubject := channelNaming(&portCfg, &m.config.App, m.config.NATS.SubjectPrefix)
+
 	dwConfig := &output.DualWriterConfig{
-		Device:        portCfg.Path, // Use path as device identifier for HTTP
-		Identifier:    identifier,
-		LogBasePath:   m.config.Logging.BasePath,
-		LogMaxSizeMB:  m.config.Logging.MaxSizeMB,
-		LogMaxBackups: m.config.Logging.MaxBackups,
-		LogCompress:   m.config.Logging.Compress,
-		NATSConn:      m.natsConn,
-		NATSSubject:   natsSubject,
-		Logger:        m.logger,
+		Device:          portCfg.Address,
+		Identifier:      identifier,
+		LogFilename:     logFilename(&portCfg, &m.config.App, &m.config.Logging),
+		LogBasePath:     m.config.Logging.BasePath,
+		LogMaxSizeMB:    m.config.Logging.MaxSizeMB,
+		LogMaxBackups:   m.config.Logging.MaxBackups,
+		LogCompress:     m.config.Logging.Compress,
+		NATSConn:        m.natsConn,
+		NATSSubject:     natsSubject,
+		CompressPayload: portCfg.CompressPayload,
+		ExtraSubjects:   portCfg.ExtraSubjects,
+		Logger:          m.logger,
+
+		LogBufferSize:    m.config.Logging.WriteBufferKB * 1024,
+		LogFlushInterval: m.config.Logging.FlushInterval(),
 	}
 
 	dualWriter, err := output.NewDualWriter(dwConfig)
@@ -446,7 +800,12 @@ func (m *Manager) createHTTPChannel(portCfg config.PortConfig) (*HTTPChannel, er
 		return nil, fmt.Errorf("failed to create dual writer: %w", err)
 	}
 
-	return NewHTTPChannel(portCfg, m.config.App, dualWriter, m.logger), nil
+	channel := NewUDPChannel(portCfg, m.config.App, dualWriter, m.logger)
+	if err := channel.Start(m.ctx); err != nil {
+		dualWriter.Close()
+		return nil, err
+	}
+	return channel, nil
 }
 
 // GetHTTPChannels returns all HTTP capture channels for route registration
@@ -464,8 +823,10 @@ type PortInfo struct {
 	ID              string            `json:"id"`
 	Type            string            `json:"type"`
 	Device          string            `json:"device,omitempty"`
+	Address         string            `json:"address,omitempty"`
 	Path            string            `json:"path,omitempty"`
 	ListenPort      int               `json:"listen_port,omitempty"`
+	BindAddress     string            `json:"bind_address,omitempty"`
 	SideDesignation string            `json:"side_designation"`
 	FIPSCode        string            `json:"fips_code"`
 	Vendor          string            `json:"vendor,omitempty"`
@@ -473,6 +834,7 @@ type PortInfo struct {
 	State           string            `json:"state"`
 	Config          PortConfigDetails `json:"config"`
 	Stats           interface{}       `json:"stats,omitempty"`
+	LastRecord      string            `json:"last_record,omitempty"` // Only from GET /api/ports/config/{id} with record_preview on
 }
 
 // PortConfigDetails contains configurable port settings
@@ -482,6 +844,7 @@ type PortConfigDetails struct {
 	Parity         string  `json:"parity,omitempty"`
 	StopBits       float64 `json:"stop_bits,omitempty"`
 	UseFlowControl *bool   `json:"use_flow_control,omitempty"`
+	FlowControl    string  `json:"flow_control,omitempty"`
 }
 
 // GetPortConfigs returns all port configurations with their current state
@@ -510,11 +873,12 @@ func (m *Manager) GetPortConfigs() []PortInfo {
 			info.Type = "http"
 			info.Path = portCfg.Path
 			info.ListenPort = portCfg.ListenPort
+			info.BindAddress = portCfg.BindAddress
 
 			// Find running HTTP channel
 			for _, ch := range m.httpChannels {
 				if ch.Path() == portCfg.Path {
-					info.State = "running"
+					info.State = ch.State()
 					info.Stats = ch.GetStats()
 					break
 				}
@@ -522,6 +886,39 @@ func (m *Manager) GetPortConfigs() []PortInfo {
 			if info.State == "" {
 				info.State = "stopped"
 			}
+		} else if portCfg.IsUDP() {
+			info.Type = config.PortTypeUDP
+			info.Address = portCfg.Address
+			info.State = "stopped"
+			for _, ch := range m.udpChannels {
+				if ch.Address() == portCfg.Address {
+					info.State = "running"
+					info.Stats = ch.GetStats()
+					break
+				}
+			}
+		} else if portCfg.IsTCP() {
+			info.Type = config.PortTypeTCP
+			info.Address = portCfg.Address
+			info.State = "stopped"
+			for _, ch := range m.channels {
+				if ch.Device() == portCfg.Address {
+					info.State = ch.State().String()
+					info.Stats = ch.Stats()
+					break
+				}
+			}
+		} else if portCfg.IsFile() {
+			info.Type = config.PortTypeFile
+			info.Device = portCfg.ReplayFile
+			info.State = "stopped"
+			for _, ch := range m.channels {
+				if ch.Device() == portCfg.ReplayFile {
+					info.State = ch.State().String()
+					info.Stats = ch.Stats()
+					break
+				}
+			}
 		} else {
 			info.Type = "serial"
 			info.Device = portCfg.Device
@@ -531,6 +928,7 @@ func (m *Manager) GetPortConfigs() []PortInfo {
 				Parity:         portCfg.Parity,
 				StopBits:       portCfg.StopBits,
 				UseFlowControl: portCfg.UseFlowControl,
+				FlowControl:    portCfg.FlowControl,
 			}
 
 			// Find running channel
@@ -552,18 +950,363 @@ func (m *Manager) GetPortConfigs() []PortInfo {
 	return ports
 }
 
-// findPortIndex finds a port config by ID and returns its index
-func (m *Manager) findPortIndex(id string) int {
-	for i := range m.config.Ports {
-		if m.config.Ports[i].ID() == id {
-			return i
+// LogPath returns the log file path for a channel identifier (e.g.,
+// "1429010002-A1"), which may differ from {identifier}.log when
+// logging.log_filename_template is set
+func (m *Manager) LogPath(identifier string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, ch := range m.channels {
+		if ch.FIPSCode()+"-"+ch.SideDesignation() == identifier && ch.LogPath() != "" {
+			return ch.LogPath(), true
 		}
 	}
-	return -1
+	for _, ch := range m.httpChannels {
+		if portFIPSCode(&ch.config, &ch.appConfig)+"-"+ch.SideDesignation() == identifier && ch.LogPath() != "" {
+			return ch.LogPath(), true
+		}
+	}
+	for _, ch := range m.udpChannels {
+		if portFIPSCode(&ch.config, &ch.appConfig)+"-"+ch.SideDesignation() == identifier && ch.LogPath() != "" {
+			return ch.LogPath(), true
+		}
+	}
+	return "", false
+}
+
+// LogPaths returns the log file of every open channel, keyed by identifier
+// (FIPS-A, e.g., "1429010002-A1")
+func (m *Manager) LogPaths() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paths := make(map[string]string, len(m.channels)+len(m.httpChannels)+len(m.udpChannels))
+	for _, ch := range m.channels {
+		if path := ch.LogPath(); path != "" {
+			paths[ch.FIPSCode()+"-"+ch.SideDesignation()] = path
+		}
+	}
+	for _, ch := range m.httpChannels {
+		if path := ch.LogPath(); path != "" {
+			paths[portFIPSCode(&ch.config, &ch.appConfig)+"-"+ch.SideDesignation()] = path
+		}
+	}
+	for _, ch := range m.udpChannels {
+		if path := ch.LogPath(); path != "" {
+			paths[portFIPSCode(&ch.config, &ch.appConfig)+"-"+ch.SideDesignation()] = path
+		}
+	}
+	return paths
+}
+
+// RedetectPort signals a running serial channel to re-run detection
+func (m *Manager) RedetectPort(id string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	idx := m.findPortIndex(id)
+	if idx < 0 {
+		return fmt.Errorf("port not found: %s", id)
+	}
+	portCfg := &m.config.Ports[idx]
+	if portCfg.IsHTTP() {
+		return fmt.Errorf("port %s is an HTTP endpoint, nothing to detect", id)
+	}
+	if portCfg.IsTCP() {
+		return fmt.Errorf("port %s is a TCP source, nothing to detect", id)
+	}
+	if portCfg.IsUDP() {
+		return fmt.Errorf("port %s is a UDP listener, nothing to detect", id)
+	}
+	if portCfg.IsFile() {
+		return fmt.Errorf("port %s is a file replay, nothing to detect", id)
+	}
+
+	for _, ch := range m.channels {
+		if ch.config.Device == portCfg.Device {
+			ch.TriggerRedetect()
+			return nil
+		}
+	}
+	return fmt.Errorf("port %s is not running", id)
+}
+
+// PausePort stops a running serial, TCP or file channel writing records
+// without closing its port (e.g., during maintenance at the PSAP). Lines are
+// still read and counted as PausedDropped. Pausing isn't saved to the
+// config, so a restart resumes the port.
+func (m *Manager) PausePort(id, source string) error {
+	return m.setPortPaused(id, source, true)
+}
+
+// ResumePort undoes PausePort
+func (m *Manager) ResumePort(id, source string) error {
+	return m.setPortPaused(id, source, false)
+}
+
+func (m *Manager) setPortPaused(id, source string, paused bool) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	idx := m.findPortIndex(id)
+	if idx < 0 {
+		return fmt.Errorf("port not found: %s", id)
+	}
+	portCfg := &m.config.Ports[idx]
+	if portCfg.IsHTTP() || portCfg.IsUDP() {
+		return fmt.Errorf("port %s has no read loop to pause", id)
+	}
+
+	for _, ch := range m.channels {
+		if ch.Device() != portCfg.Source() {
+			continue
+		}
+		action := "resumed"
+		if paused {
+			action = "paused"
+		}
+		if ch.Paused() == paused {
+			return fmt.Errorf("port already %s: %s", action, id)
+		}
+		if paused {
+			ch.Pause()
+		} else {
+			ch.Resume()
+		}
+		m.logger.Info("Port "+action, "id", id)
+		m.publishConfigChange(action, portCfg, nil, source)
+		return nil
+	}
+	return fmt.Errorf("port %s is not running", id)
+}
+
+// SelfTestReport is the result of a port self-test. When the port's channel
+// is running the port isn't touched; InUse is set and Stats are the live ones.
+type SelfTestReport struct {
+	Port          string        `json:"port"`
+	InUse         bool          `json:"in_use"`
+	State         string        `json:"state,omitempty"` // Channel state when InUse
+	Stats         *ChannelStats `json:"stats,omitempty"` // Live stats when InUse
+	BaudRate      int           `json:"baud_rate,omitempty"`
+	Signals       *ModemSignals `json:"signals,omitempty"`
+	DataReceived  bool          `json:"data_received"`
+	BytesRead     int           `json:"bytes_read"`
+	ValidityRatio float64       `json:"validity_ratio"`
+}
+
+// SelfTestPort briefly opens a serial port that has no running channel,
+// reports its modem signals and listens for data at the configured baud rate
+// (or the first detection rate). Blocks for up to serial.SelfTestDuration.
+func (m *Manager) SelfTestPort(id string) (*SelfTestReport, error) {
+	m.mu.RLock()
+	idx := m.findPortIndex(id)
+	if idx < 0 {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("port not found: %s", id)
+	}
+	portCfg := m.config.Ports[idx]
+	if !portCfg.IsSerial() {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("port %s is not a serial port, nothing to test", id)
+	}
+	for _, ch := range m.channels {
+		if ch.config.Device == portCfg.Device {
+			m.mu.RUnlock()
+			stats := ch.Stats()
+			return &SelfTestReport{
+				Port:  id,
+				InUse: true,
+				State: ch.State().String(),
+				Stats: &stats,
+			}, nil
+		}
+	}
+	baudRate := portCfg.BaudRate
+	if baudRate == 0 && len(m.config.Detection.BaudRates) > 0 {
+		baudRate = m.config.Detection.BaudRates[0]
+	}
+	m.mu.RUnlock()
+
+	if baudRate == 0 {
+		baudRate = 9600
+	}
+	device, err := serial.ResolveDevicePath(portCfg.Device, portCfg.DeviceByID)
+	if err != nil {
+		return nil, err
+	}
+
+	serialConfig := serial.SerialConfig{
+		BaudRate:    baudRate,
+		DataBits:    portCfg.DataBits,
+		Parity:      portCfg.Parity,
+		StopBits:    portCfg.StopBits,
+		FlowControl: portCfg.FlowControl,
+		AssertDTR:   portCfg.AssertDTR,
+		AssertRTS:   portCfg.AssertRTS,
+	}
+	if portCfg.UseFlowControl != nil {
+		serialConfig.UseFlowControl = *portCfg.UseFlowControl
+	}
+	result, err := serial.RunSelfTest(device, serialConfig, serial.SelfTestDuration)
+	if err != nil {
+		return nil, fmt.Errorf("self-test failed: %w", err)
+	}
+
+	report := &SelfTestReport{
+		Port:          id,
+		BaudRate:      baudRate,
+		DataReceived:  result.DataReceived(),
+		BytesRead:     result.BytesRead,
+		ValidityRatio: result.ValidityRatio,
+	}
+	if result.Signals != nil {
+		report.Signals = &ModemSignals{
+			CTS: result.Signals.CTS,
+			DSR: result.Signals.DSR,
+			DCD: result.Signals.DCD,
+			RI:  result.Signals.RI,
+		}
+	}
+	return report, nil
+}
+
+// AllowsCustomBaud reports whether a port may use a non-standard baud rate,
+// via its own allow_custom_baud or the detection-wide setting
+func (m *Manager) AllowsCustomBaud(id string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.config.Detection.AllowCustomBaud {
+		return true
+	}
+	idx := m.findPortIndex(id)
+	return idx >= 0 && m.config.Ports[idx].AllowCustomBaud
+}
+
+// findPortIndex finds a port config by ID and returns its index
+func (m *Manager) findPortIndex(id string) int {
+	for i := range m.config.Ports {
+		if m.config.Ports[i].ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// PortLogPath returns the log file path for a port, whether or not its
+// channel is running
+func (m *Manager) PortLogPath(id string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	idx := m.findPortIndex(id)
+	if idx < 0 {
+		return "", fmt.Errorf("port not found: %s", id)
+	}
+	portCfg := &m.config.Ports[idx]
+	return m.config.Logging.LogPath(portFIPSCode(portCfg, &m.config.App), portCfg.SideDesignation, portCfg.County, m.config.App.InstanceID), nil
+}
+
+// PortLastRecord returns the last record a port captured, truncated for
+// preview. It's "" if record_preview is off, the channel isn't running, or
+// nothing has arrived yet.
+func (m *Manager) PortLastRecord(id string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	idx := m.findPortIndex(id)
+	if idx < 0 {
+		return "", fmt.Errorf("port not found: %s", id)
+	}
+	portCfg := &m.config.Ports[idx]
+	if !portCfg.RecordPreview {
+		return "", nil
+	}
+
+	if portCfg.IsHTTP() {
+		for _, ch := range m.httpChannels {
+			if ch.Path() == portCfg.Path {
+				return ch.LastRecord(), nil
+			}
+		}
+		return "", nil
+	}
+	for _, ch := range m.channels {
+		if ch.Device() == portCfg.Source() {
+			return ch.LastRecord(), nil
+		}
+	}
+	return "", nil
+}
+
+// ChannelChange reports a serial or HTTP channel started or stopped at
+// runtime (ports added, deleted, enabled, disabled or reconfigured)
+type ChannelChange struct {
+	Identifier string // FIPSCODE-A1
+	LogPath    string
+	Started    bool // false = stopped
+}
+
+// ChannelChangeCallback receives ChannelChanges. It runs with the manager
+// lock held, so it must not call back into the Manager.
+type ChannelChangeCallback func(ChannelChange)
+
+// SetChannelChangeCallback registers cb to hear about channels started and
+// stopped after startup (e.g., so the monitoring server can tail their logs)
+func (m *Manager) SetChannelChangeCallback(cb ChannelChangeCallback) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.channelCallback = cb
+}
+
+// notifyChannelChange reports a started or stopped channel. Caller must hold m.mu.
+func (m *Manager) notifyChannelChange(identifier, logPath string, started bool) {
+	if m.channelCallback != nil {
+		m.channelCallback(ChannelChange{Identifier: identifier, LogPath: logPath, Started: started})
+	}
+}
+
+// SetEventCallback routes manager events (e.g., config changes) to cb
+// instead of the NATS event publisher
+func (m *Manager) SetEventCallback(cb output.EventCallback) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.eventCallback = cb
+}
+
+// publishConfigChange emits a config_change event for an API mutation.
+// source identifies who made the change (e.g., "admin@10.0.0.5"); may be empty.
+// Caller must hold m.mu.
+func (m *Manager) publishConfigChange(action string, portCfg *config.PortConfig, changes map[string]interface{}, source string) {
+	details := map[string]any{
+		"action":  action,
+		"port_id": portCfg.ID(),
+	}
+	if len(changes) > 0 {
+		details["changes"] = changes
+	}
+	if source != "" {
+		details["source"] = source
+	}
+
+	event := output.Event{
+		Type:    output.EventConfigChange,
+		Channel: portCfg.SideDesignation,
+		Device:  portCfg.Device,
+		Message: fmt.Sprintf("Port %s %s via API", portCfg.ID(), action),
+		Details: details,
+	}
+
+	if m.eventCallback != nil {
+		m.eventCallback(event)
+		return
+	}
+	m.eventPublisher.Publish(event)
 }
 
 // EnablePort enables a disabled port and starts its channel
-func (m *Manager) EnablePort(id string) error {
+func (m *Manager) EnablePort(id, source string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -591,11 +1334,12 @@ func (m *Manager) EnablePort(id string) error {
 	}
 
 	m.logger.Info("Enabled port", "id", id)
+	m.publishConfigChange("enabled", portCfg, nil, source)
 	return nil
 }
 
 // DisablePort disables a running port and stops its channel
-func (m *Manager) DisablePort(id string) error {
+func (m *Manager) DisablePort(id, source string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -622,11 +1366,12 @@ func (m *Manager) DisablePort(id string) error {
 	}
 
 	m.logger.Info("Disabled port", "id", id)
+	m.publishConfigChange("disabled", portCfg, nil, source)
 	return nil
 }
 
 // UpdatePortConfig updates port settings and restarts the channel if needed
-func (m *Manager) UpdatePortConfig(id string, updates map[string]interface{}) error {
+func (m *Manager) UpdatePortConfig(id string, updates map[string]interface{}, source string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -637,9 +1382,108 @@ func (m *Manager) UpdatePortConfig(id string, updates map[string]interface{}) er
 
 	portCfg := &m.config.Ports[idx]
 	wasEnabled := portCfg.Enabled
-	needsRestart := false
 
-	// Apply updates
+	if err := m.checkSideDesignationLocked(idx, updates); err != nil {
+		return err
+	}
+
+	// Apply to a copy so a bad field leaves the stored config untouched
+	updated := *portCfg
+	needsRestart, err := applyPortUpdates(&updated, updates)
+	if err != nil {
+		return err
+	}
+	*portCfg = updated
+	// An HTTP path edit changes the port's ID
+	m.config.RenamePort(id, portCfg.ID())
+
+	// Restart channel if needed and was running
+	if needsRestart && wasEnabled {
+		if err := m.stopChannelLocked(portCfg); err != nil {
+			m.logger.Warn("Failed to stop channel for update", "id", id, "error", err)
+		}
+		if err := m.startChannelLocked(portCfg); err != nil {
+			return fmt.Errorf("failed to restart channel: %w", err)
+		}
+	}
+
+	// Save config
+	if err := m.config.Save(m.configPath); err != nil {
+		m.logger.Warn("Failed to save config after update", "id", id, "error", err)
+	}
+
+	m.logger.Info("Updated port config", "id", id, "updates", updates)
+	m.publishConfigChange("updated", portCfg, updates, source)
+	return nil
+}
+
+// PortUpdatePlan describes what UpdatePortConfig would do, without doing it
+type PortUpdatePlan struct {
+	PortID         string `json:"port_id"`
+	NeedsRestart   bool   `json:"needs_restart"` // Changes affect the open channel
+	WillRestart    bool   `json:"will_restart"`  // NeedsRestart and the port is enabled
+	Identifier     string `json:"identifier"`    // Log identifier after the update
+	NATSSubject    string `json:"nats_subject"`  // NATS subject after the update
+	SubjectChanged bool   `json:"subject_changed"`
+	LogChanged     bool   `json:"log_changed"`
+}
+
+// PlanPortUpdate previews the effect of UpdatePortConfig without mutating
+// config or restarting the channel
+func (m *Manager) PlanPortUpdate(id string, updates map[string]interface{}) (*PortUpdatePlan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	idx := m.findPortIndex(id)
+	if idx < 0 {
+		return nil, fmt.Errorf("port not found: %s", id)
+	}
+
+	if err := m.checkSideDesignationLocked(idx, updates); err != nil {
+		return nil, err
+	}
+
+	current := m.config.Ports[idx]
+	updated := current
+	needsRestart, err := applyPortUpdates(&updated, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	oldIdentifier, oldSubject := channelNaming(&current, &m.config.App, m.config.NATS.SubjectPrefix)
+	identifier, subject := channelNaming(&updated, &m.config.App, m.config.NATS.SubjectPrefix)
+
+	return &PortUpdatePlan{
+		PortID:         updated.ID(),
+		NeedsRestart:   needsRestart,
+		WillRestart:    needsRestart && current.Enabled,
+		Identifier:     identifier,
+		NATSSubject:    subject,
+		SubjectChanged: subject != oldSubject,
+		LogChanged:     identifier != oldIdentifier,
+	}, nil
+}
+
+// checkSideDesignationLocked rejects a side_designation update that collides
+// with another enabled port, since the designation drives log file names and
+// NATS routing. Caller must hold m.mu.
+func (m *Manager) checkSideDesignationLocked(idx int, updates map[string]interface{}) error {
+	v, ok := updates["side_designation"].(string)
+	if !ok || v == m.config.Ports[idx].SideDesignation {
+		return nil
+	}
+	for i, p := range m.config.Ports {
+		if i != idx && p.Enabled && p.SideDesignation == v {
+			return fmt.Errorf("side_designation already in use: %s", v)
+		}
+	}
+	return nil
+}
+
+// applyPortUpdates applies API updates to portCfg and reports whether the
+// change requires restarting the channel
+func applyPortUpdates(portCfg *config.PortConfig, updates map[string]interface{}) (bool, error) {
+	needsRestart := false
 	for key, value := range updates {
 		switch key {
 		case "baud_rate":
@@ -647,6 +1491,10 @@ func (m *Manager) UpdatePortConfig(id string, updates map[string]interface{}) er
 				portCfg.BaudRate = int(v)
 				needsRestart = true
 			}
+		case "allow_custom_baud":
+			if v, ok := value.(bool); ok {
+				portCfg.AllowCustomBaud = v
+			}
 		case "data_bits":
 			if v, ok := value.(float64); ok {
 				portCfg.DataBits = int(v)
@@ -670,6 +1518,11 @@ func (m *Manager) UpdatePortConfig(id string, updates map[string]interface{}) er
 				portCfg.UseFlowControl = nil
 				needsRestart = true
 			}
+		case "flow_control":
+			if v, ok := value.(string); ok {
+				portCfg.FlowControl = v
+				needsRestart = true
+			}
 		case "listen_port":
 			if v, ok := value.(float64); ok {
 				portCfg.ListenPort = int(v)
@@ -704,31 +1557,14 @@ func (m *Manager) UpdatePortConfig(id string, updates map[string]interface{}) er
 				portCfg.Description = v
 			}
 		default:
-			return fmt.Errorf("unknown config field: %s", key)
+			return false, fmt.Errorf("unknown config field: %s", key)
 		}
 	}
-
-	// Restart channel if needed and was running
-	if needsRestart && wasEnabled {
-		if err := m.stopChannelLocked(portCfg); err != nil {
-			m.logger.Warn("Failed to stop channel for update", "id", id, "error", err)
-		}
-		if err := m.startChannelLocked(portCfg); err != nil {
-			return fmt.Errorf("failed to restart channel: %w", err)
-		}
-	}
-
-	// Save config
-	if err := m.config.Save(m.configPath); err != nil {
-		m.logger.Warn("Failed to save config after update", "id", id, "error", err)
-	}
-
-	m.logger.Info("Updated port config", "id", id, "updates", updates)
-	return nil
+	return needsRestart, nil
 }
 
 // AddPort adds a new port configuration
-func (m *Manager) AddPort(portCfg config.PortConfig) error {
+func (m *Manager) AddPort(portCfg config.PortConfig, source string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -747,6 +1583,33 @@ func (m *Manager) AddPort(portCfg config.PortConfig) error {
 				return fmt.Errorf("HTTP path already exists: %s", portCfg.Path)
 			}
 		}
+	} else if portCfg.IsUDP() {
+		if portCfg.Address == "" {
+			return fmt.Errorf("address is required for UDP ports")
+		}
+		for _, p := range m.config.Ports {
+			if p.IsUDP() && p.Address == portCfg.Address {
+				return fmt.Errorf("UDP address already configured: %s", portCfg.Address)
+			}
+		}
+	} else if portCfg.IsFile() {
+		if portCfg.ReplayFile == "" {
+			return fmt.Errorf("replay_file is required for file ports")
+		}
+		for _, p := range m.config.Ports {
+			if p.IsFile() && p.ReplayFile == portCfg.ReplayFile {
+				return fmt.Errorf("replay file already configured: %s", portCfg.ReplayFile)
+			}
+		}
+	} else if portCfg.IsTCP() {
+		if portCfg.Address == "" {
+			return fmt.Errorf("address is required for TCP ports")
+		}
+		for _, p := range m.config.Ports {
+			if p.IsTCP() && p.Address == portCfg.Address {
+				return fmt.Errorf("address already configured: %s", portCfg.Address)
+			}
+		}
 	} else {
 		if portCfg.Device == "" {
 			return fmt.Errorf("device is required for serial ports")
@@ -779,6 +1642,12 @@ func (m *Manager) AddPort(portCfg config.PortConfig) error {
 		}
 	}
 
+	// Run the same per-port checks as config load, so a bad combination is
+	// rejected here rather than persisted and failing at channel start
+	if err := m.config.ValidateNewPort(portCfg); err != nil {
+		return fmt.Errorf("invalid port: %w", err)
+	}
+
 	// Add to config
 	m.config.Ports = append(m.config.Ports, portCfg)
 
@@ -797,11 +1666,12 @@ func (m *Manager) AddPort(portCfg config.PortConfig) error {
 	}
 
 	m.logger.Info("Added port", "id", portCfg.ID(), "type", portCfg.Type)
+	m.publishConfigChange("added", &portCfg, nil, source)
 	return nil
 }
 
 // DeletePort removes a port configuration
-func (m *Manager) DeletePort(id string) error {
+func (m *Manager) DeletePort(id, source string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -811,6 +1681,7 @@ func (m *Manager) DeletePort(id string) error {
 	}
 
 	portCfg := &m.config.Ports[idx]
+	deleted := *portCfg // Ports slice is about to shift under portCfg
 
 	// Stop channel if running
 	if portCfg.Enabled {
@@ -828,18 +1699,83 @@ func (m *Manager) DeletePort(id string) error {
 	}
 
 	m.logger.Info("Deleted port", "id", id)
+	m.publishConfigChange("deleted", &deleted, nil, source)
 	return nil
 }
 
+// Serial device scan locations - variables so tests can point them at fakes
+var (
+	serialDevDir   = "/dev"
+	serialSysfsDir = "/sys/class/tty"
+)
+
+// serialDevicePrefixes are the device name prefixes treated as serial ports,
+// in the order they're listed: onboard UARTs, USB adapters, USB CDC-ACM devices
+var serialDevicePrefixes = []string{"ttyS", "ttyUSB", "ttyACM"}
+
+// scanSerialDevices lists serial device nodes in devDir, sorted by prefix then
+// number (ttyS1, ttyS2, ..., ttyS10, ttyUSB0, ttyACM0). ttyS0 is skipped since
+// it's the console. Onboard ttyS ports that sysfs reports as type 0 (no UART
+// present) are skipped too - the kernel creates those nodes whether or not the
+// hardware exists.
+func scanSerialDevices(devDir, sysfsDir string) []string {
+	entries, err := os.ReadDir(devDir)
+	if err != nil {
+		return []string{}
+	}
+
+	type serialDevice struct {
+		prefix int
+		number int
+		path   string
+	}
+	var devices []serialDevice
+
+	for _, entry := range entries {
+		name := entry.Name()
+		for i, prefix := range serialDevicePrefixes {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			number, err := strconv.Atoi(name[len(prefix):])
+			if err != nil {
+				continue // e.g., ttyS matched ttySAC0 - not ours
+			}
+			if name == "ttyS0" {
+				continue
+			}
+			if prefix == "ttyS" {
+				if data, err := os.ReadFile(filepath.Join(sysfsDir, name, "type")); err == nil &&
+					strings.TrimSpace(string(data)) == "0" {
+					continue
+				}
+			}
+			devices = append(devices, serialDevice{prefix: i, number: number, path: filepath.Join(devDir, name)})
+			break
+		}
+	}
+
+	sort.Slice(devices, func(a, b int) bool {
+		if devices[a].prefix != devices[b].prefix {
+			return devices[a].prefix < devices[b].prefix
+		}
+		return devices[a].number < devices[b].number
+	})
+
+	paths := make([]string, 0, len(devices))
+	for _, d := range devices {
+		paths = append(paths, d.path)
+	}
+	return paths
+}
+
 // GetAvailableSerialPorts returns a list of serial ports not currently configured
 func (m *Manager) GetAvailableSerialPorts() []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Standard COM ports on Linux (ttyS1-ttyS5, skipping ttyS0 which is console)
-	allPorts := []string{
-		"/dev/ttyS1", "/dev/ttyS2", "/dev/ttyS3", "/dev/ttyS4", "/dev/ttyS5",
-	}
+	// Onboard UARTs plus USB serial adapters present on this system
+	allPorts := scanSerialDevices(serialDevDir, serialSysfsDir)
 
 	// Build set of configured devices
 	configured := make(map[string]bool)
@@ -869,6 +1805,14 @@ func (m *Manager) startChannelLocked(portCfg *config.PortConfig) error {
 		}
 		m.httpChannels = append(m.httpChannels, httpChannel)
 		m.logger.Info("Started HTTP channel", "path", portCfg.Path)
+		m.notifyChannelChange(httpChannel.Identifier(), httpChannel.LogPath(), true)
+	} else if portCfg.IsUDP() {
+		udpChannel, err := m.startUDPChannel(*portCfg)
+		if err != nil {
+			return err
+		}
+		m.udpChannels = append(m.udpChannels, udpChannel)
+		m.logger.Info("Started UDP channel", "address", portCfg.Address)
 	} else {
 		channel, err := NewChannel(
 			portCfg,
@@ -878,7 +1822,7 @@ func (m *Manager) startChannelLocked(portCfg *config.PortConfig) error {
 			&m.config.App,
 			&m.config.Logging,
 			m.natsConn,
-			m.logger.With("device", portCfg.Device),
+			m.logger.With("device", portCfg.Source()),
 		)
 		if err != nil {
 			return err
@@ -895,7 +1839,8 @@ func (m *Manager) startChannelLocked(portCfg *config.PortConfig) error {
 		}
 
 		m.channels = append(m.channels, channel)
-		m.logger.Info("Started serial channel", "device", portCfg.Device)
+		m.logger.Info("Started serial channel", "device", portCfg.Source())
+		m.notifyChannelChange(channel.Identifier(), channel.LogPath(), true)
 	}
 	return nil
 }
@@ -910,15 +1855,28 @@ func (m *Manager) stopChannelLocked(portCfg *config.PortConfig) error {
 				}
 				m.httpChannels = append(m.httpChannels[:i], m.httpChannels[i+1:]...)
 				m.logger.Info("Stopped HTTP channel", "path", portCfg.Path)
+				m.notifyChannelChange(ch.Identifier(), ch.LogPath(), false)
+				return nil
+			}
+		}
+	} else if portCfg.IsUDP() {
+		for i, ch := range m.udpChannels {
+			if ch.Address() == portCfg.Address {
+				if err := ch.Stop(); err != nil {
+					return err
+				}
+				m.udpChannels = append(m.udpChannels[:i], m.udpChannels[i+1:]...)
+				m.logger.Info("Stopped UDP channel", "address", portCfg.Address)
 				return nil
 			}
 		}
 	} else {
 		for i, ch := range m.channels {
-			if ch.Device() == portCfg.Device {
+			if ch.Device() == portCfg.Source() {
 				ch.Stop()
 				m.channels = append(m.channels[:i], m.channels[i+1:]...)
-				m.logger.Info("Stopped serial channel", "device", portCfg.Device)
+				m.logger.Info("Stopped serial channel", "device", portCfg.Source())
+				m.notifyChannelChange(ch.Identifier(), ch.LogPath(), false)
 				return nil
 			}
 		}
@@ -1,11 +1,17 @@
 package capture
 
 import (
+	"errors"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"nectarcollector/config"
+	"nectarcollector/output"
 )
 
 func TestNewManager(t *testing.T) {
@@ -248,7 +254,7 @@ func TestManagerEnablePortNotFound(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	manager := NewManager(cfg, "", logger)
-	err := manager.EnablePort("nonexistent")
+	err := manager.EnablePort("nonexistent", "")
 
 	if err == nil {
 		t.Error("EnablePort() should return error for non-existent port")
@@ -260,7 +266,7 @@ func TestManagerDisablePortNotFound(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	manager := NewManager(cfg, "", logger)
-	err := manager.DisablePort("nonexistent")
+	err := manager.DisablePort("nonexistent", "")
 
 	if err == nil {
 		t.Error("DisablePort() should return error for non-existent port")
@@ -274,7 +280,7 @@ func TestManagerUpdatePortConfigNotFound(t *testing.T) {
 	manager := NewManager(cfg, "", logger)
 	err := manager.UpdatePortConfig("nonexistent", map[string]interface{}{
 		"baud_rate": 9600,
-	})
+	}, "")
 
 	if err == nil {
 		t.Error("UpdatePortConfig() should return error for non-existent port")
@@ -286,7 +292,7 @@ func TestManagerDeletePortNotFound(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	manager := NewManager(cfg, "", logger)
-	err := manager.DeletePort("nonexistent")
+	err := manager.DeletePort("nonexistent", "")
 
 	if err == nil {
 		t.Error("DeletePort() should return error for non-existent port")
@@ -308,13 +314,52 @@ func TestManagerAddPortDuplicate(t *testing.T) {
 	err := manager.AddPort(config.PortConfig{
 		Device:          "/dev/ttyS1",
 		SideDesignation: "A2",
-	})
+	}, "")
 
 	if err == nil {
 		t.Error("AddPort() should return error for duplicate device")
 	}
 }
 
+func TestManagerAddPortInvalidSerial(t *testing.T) {
+	tests := []struct {
+		name string
+		port config.PortConfig
+	}{
+		{"non-standard baud", config.PortConfig{Device: "/dev/ttyS2", SideDesignation: "A2", BaudRate: 12345}},
+		{"1.5 stop bits with 8 data bits", config.PortConfig{Device: "/dev/ttyS2", SideDesignation: "A2", StopBits: 1.5}},
+		{"1.5 stop bits with 5 data bits", config.PortConfig{Device: "/dev/ttyS2", SideDesignation: "A2", DataBits: 5, StopBits: 1.5}},
+		{"bad parity", config.PortConfig{Device: "/dev/ttyS2", SideDesignation: "A2", Parity: "sideways"}},
+		{"bad side designation", config.PortConfig{Device: "/dev/ttyS2", SideDesignation: "C2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Ports: []config.PortConfig{
+					{Device: "/dev/ttyS1", SideDesignation: "A1", Enabled: true},
+				},
+			}
+			manager := NewManager(cfg, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			err := manager.AddPort(tt.port, "")
+			if err == nil || !strings.Contains(err.Error(), "invalid port") {
+				t.Fatalf("AddPort() error = %v, want invalid port", err)
+			}
+			if len(cfg.Ports) != 1 {
+				t.Errorf("rejected port was appended: %d ports", len(cfg.Ports))
+			}
+		})
+	}
+
+	// A valid framing combination still goes through
+	cfg := &config.Config{Ports: []config.PortConfig{{Device: "/dev/ttyS1", SideDesignation: "A1", Enabled: true}}}
+	manager := NewManager(cfg, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := manager.AddPort(config.PortConfig{Device: "/dev/ttyS2", SideDesignation: "A2", BaudRate: 9600, DataBits: 7, Parity: "even", StopBits: 2}, ""); err != nil {
+		t.Errorf("AddPort() of 7 data bits, even parity, 2 stop bits: %v", err)
+	}
+}
+
 func TestManagerAddPortDuplicateSideDesignation(t *testing.T) {
 	cfg := &config.Config{
 		Ports: []config.PortConfig{
@@ -330,7 +375,7 @@ func TestManagerAddPortDuplicateSideDesignation(t *testing.T) {
 	err := manager.AddPort(config.PortConfig{
 		Device:          "/dev/ttyS2",
 		SideDesignation: "A1",
-	})
+	}, "")
 
 	if err == nil {
 		t.Error("AddPort() should return error for duplicate side designation")
@@ -350,7 +395,7 @@ func TestManagerEnableAlreadyEnabled(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	manager := NewManager(cfg, "", logger)
-	err := manager.EnablePort("ttyS1")
+	err := manager.EnablePort("ttyS1", "")
 
 	if err == nil {
 		t.Error("EnablePort() should return error for already enabled port")
@@ -370,7 +415,7 @@ func TestManagerDisableAlreadyDisabled(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	manager := NewManager(cfg, "", logger)
-	err := manager.DisablePort("ttyS1")
+	err := manager.DisablePort("ttyS1", "")
 
 	if err == nil {
 		t.Error("DisablePort() should return error for already disabled port")
@@ -440,3 +485,567 @@ func TestPortInfoHTTP(t *testing.T) {
 		t.Errorf("ListenPort = %d, want 8080", info.ListenPort)
 	}
 }
+
+func TestScanSerialDevices(t *testing.T) {
+	devDir := t.TempDir()
+	sysfsDir := t.TempDir()
+
+	for _, name := range []string{
+		"ttyS0", "ttyS1", "ttyS2", "ttyS10", "ttyS4",
+		"ttyUSB1", "ttyUSB0", "ttyACM0",
+		"tty1", "console", "ttySAC0", "null",
+	} {
+		if err := os.WriteFile(filepath.Join(devDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ttyS4 exists as a node but sysfs says there's no UART behind it
+	if err := os.MkdirAll(filepath.Join(sysfsDir, "ttyS4"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sysfsDir, "ttyS4", "type"), []byte("0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := scanSerialDevices(devDir, sysfsDir)
+	want := []string{
+		filepath.Join(devDir, "ttyS1"),
+		filepath.Join(devDir, "ttyS2"),
+		filepath.Join(devDir, "ttyS10"),
+		filepath.Join(devDir, "ttyUSB0"),
+		filepath.Join(devDir, "ttyUSB1"),
+		filepath.Join(devDir, "ttyACM0"),
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("scanSerialDevices() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("scanSerialDevices()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestManagerGetAvailableSerialPortsFiltersConfigured(t *testing.T) {
+	devDir := t.TempDir()
+	for _, name := range []string{"ttyS0", "ttyS1", "ttyS2", "ttyUSB0", "ttyACM0"} {
+		if err := os.WriteFile(filepath.Join(devDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldDev, oldSysfs := serialDevDir, serialSysfsDir
+	serialDevDir, serialSysfsDir = devDir, t.TempDir()
+	defer func() { serialDevDir, serialSysfsDir = oldDev, oldSysfs }()
+
+	cfg := &config.Config{
+		Ports: []config.PortConfig{
+			{Device: filepath.Join(devDir, "ttyS1"), SideDesignation: "A1"},
+			{Device: filepath.Join(devDir, "ttyUSB0"), SideDesignation: "A2"},
+			{Type: "http", Path: "/cdr", SideDesignation: "A3"},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := NewManager(cfg, "", logger)
+
+	got := manager.GetAvailableSerialPorts()
+	want := []string{filepath.Join(devDir, "ttyS2"), filepath.Join(devDir, "ttyACM0")}
+
+	if len(got) != len(want) {
+		t.Fatalf("GetAvailableSerialPorts() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GetAvailableSerialPorts()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestManagerGetChannelInfo(t *testing.T) {
+	cfg := &config.Config{
+		App: config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := NewManager(cfg, "", logger)
+
+	serialCfg := &config.PortConfig{Device: "/dev/ttyS1", SideDesignation: "A1"}
+	serialCh := &Channel{
+		config: serialCfg,
+		state:  StateRunning,
+		stats: ChannelStats{
+			LinesRead: 42,
+			LastError: "port /dev/ttyS1 is busy",
+			StartTime: time.Now().Add(-90 * time.Second),
+		},
+		logger: logger,
+	}
+	httpCh := NewHTTPChannel(config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		SideDesignation: "A2",
+		FIPSCode:        "3100100001",
+	}, cfg.App, nil, logger)
+
+	manager.channels = append(manager.channels, serialCh)
+	manager.httpChannels = append(manager.httpChannels, httpCh)
+
+	info, ok := manager.GetChannelInfo("ttyS1")
+	if !ok {
+		t.Fatal("GetChannelInfo(ttyS1) not found")
+	}
+	if info.Type != "serial" || info.SideDesignation != "A1" || info.State != "running" {
+		t.Errorf("serial info = %+v", info)
+	}
+	if info.FIPSCode != "1429010002" {
+		t.Errorf("serial FIPSCode = %q, want app-level %q", info.FIPSCode, "1429010002")
+	}
+	if stats, ok := info.Stats.(ChannelStats); !ok || stats.LinesRead != 42 {
+		t.Errorf("serial Stats = %+v, want LinesRead 42", info.Stats)
+	} else if stats.LastError != "port /dev/ttyS1 is busy" {
+		t.Errorf("serial LastError = %q, want it surfaced", stats.LastError)
+	}
+	if info.UptimeSec < 90 || info.UptimeSec > 95 {
+		t.Errorf("serial UptimeSec = %d, want ~90", info.UptimeSec)
+	}
+
+	info, ok = manager.GetChannelInfo("/cdr")
+	if !ok {
+		t.Fatal("GetChannelInfo(/cdr) not found")
+	}
+	if info.Type != "http" || info.Path != "/cdr" || info.FIPSCode != "3100100001" {
+		t.Errorf("http info = %+v", info)
+	}
+	httpStats := info.Stats.(HTTPChannelStats)
+	if want := uptimeSec(httpStats.StartTime, time.Now()); info.UptimeSec > want {
+		t.Errorf("http UptimeSec = %d, want <= %d", info.UptimeSec, want)
+	}
+
+	if _, ok := manager.GetChannelInfo("ttyS9"); ok {
+		t.Error("GetChannelInfo(ttyS9) should not be found")
+	}
+}
+
+func TestUpdatePortConfigPathKeepsFragment(t *testing.T) {
+	dir := t.TempDir()
+	base := `{
+		"app": {"name": "Test", "instance_id": "test-01", "fips_code": "1429010002"},
+		"ports": [{"device": "/dev/ttyS1", "side_designation": "A1", "baud_rate": 9600, "enabled": true}],
+		"nats": {"url": "nats://localhost:4222", "subject_prefix": "ne.cdr"},
+		"logging": {"base_path": "` + dir + `"}
+	}`
+	fragment := `{"ports": [{"type": "http", "path": "/cdr", "side_designation": "B1"}]}`
+	if err := os.WriteFile(filepath.Join(dir, config.BaseConfigFile), []byte(base), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, config.PortsDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, config.PortsDir, "antelope.json"), []byte(fragment), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	manager := NewManager(cfg, dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := manager.UpdatePortConfig("/cdr", map[string]interface{}{"path": "/cdr/antelope"}, "admin"); err != nil {
+		t.Fatalf("UpdatePortConfig() error = %v", err)
+	}
+
+	saved, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() after update error = %v", err)
+	}
+	if len(saved.Ports) != 2 || saved.Ports[1].Path != "/cdr/antelope" {
+		t.Fatalf("saved ports = %+v, want ttyS1 and the renamed HTTP port", saved.Ports)
+	}
+	if got, want := saved.PortFile("/cdr/antelope"), filepath.Join(config.PortsDir, "antelope.json"); got != want {
+		t.Errorf("renamed port saved to %s, want %s", got, want)
+	}
+}
+
+func TestManagerConfigChangeEvents(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: dir},
+		NATS:    config.NATSConfig{SubjectPrefix: "ne.cdr"},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := NewManager(cfg, filepath.Join(dir, "config.json"), logger)
+
+	var events []output.Event
+	manager.SetEventCallback(func(e output.Event) {
+		events = append(events, e)
+	})
+
+	mutations := []struct {
+		name   string
+		action string
+		run    func() error
+	}{
+		{"add", "added", func() error {
+			return manager.AddPort(config.PortConfig{
+				Type:            config.PortTypeHTTP,
+				Path:            "/cdr",
+				SideDesignation: "A3",
+			}, "admin@10.0.0.5:5555")
+		}},
+		{"enable", "enabled", func() error { return manager.EnablePort("/cdr", "admin@10.0.0.5:5555") }},
+		{"update", "updated", func() error {
+			return manager.UpdatePortConfig("/cdr", map[string]interface{}{"description": "CDR feed"}, "admin@10.0.0.5:5555")
+		}},
+		{"disable", "disabled", func() error { return manager.DisablePort("/cdr", "admin@10.0.0.5:5555") }},
+		{"delete", "deleted", func() error { return manager.DeletePort("/cdr", "admin@10.0.0.5:5555") }},
+	}
+
+	for _, m := range mutations {
+		t.Run(m.name, func(t *testing.T) {
+			events = nil
+			if err := m.run(); err != nil {
+				t.Fatalf("%s failed: %v", m.name, err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			e := events[0]
+			if e.Type != output.EventConfigChange {
+				t.Errorf("Type = %q, want %q", e.Type, output.EventConfigChange)
+			}
+			if e.Channel != "A3" {
+				t.Errorf("Channel = %q, want A3", e.Channel)
+			}
+			if e.Details["action"] != m.action {
+				t.Errorf("action = %v, want %q", e.Details["action"], m.action)
+			}
+			if e.Details["port_id"] != "/cdr" {
+				t.Errorf("port_id = %v, want /cdr", e.Details["port_id"])
+			}
+			if e.Details["source"] != "admin@10.0.0.5:5555" {
+				t.Errorf("source = %v", e.Details["source"])
+			}
+		})
+	}
+
+	// Failed mutations must not emit events
+	events = nil
+	if err := manager.DeletePort("/cdr", ""); err == nil {
+		t.Fatal("DeletePort() of removed port should fail")
+	}
+	if len(events) != 0 {
+		t.Errorf("failed mutation emitted %d events", len(events))
+	}
+}
+
+func TestManagerUpdatePortConfigDuplicateSideDesignation(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Ports: []config.PortConfig{
+			{Device: "/dev/ttyS1", SideDesignation: "A1", Enabled: true},
+			{Device: "/dev/ttyS2", SideDesignation: "A2"},
+			{Device: "/dev/ttyS3", SideDesignation: "A3"},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := NewManager(cfg, filepath.Join(dir, "config.json"), logger)
+
+	err := manager.UpdatePortConfig("ttyS2", map[string]interface{}{
+		"side_designation": "A1",
+		"description":      "should not apply",
+	}, "")
+	if err == nil {
+		t.Fatal("UpdatePortConfig() should reject side_designation used by an enabled port")
+	}
+	if got := cfg.Ports[1].SideDesignation; got != "A2" {
+		t.Errorf("SideDesignation = %q, want original %q", got, "A2")
+	}
+	if got := cfg.Ports[1].Description; got != "" {
+		t.Errorf("Description = %q, want update rejected as a whole", got)
+	}
+
+	// Disabled ports don't hold their designation
+	if err := manager.UpdatePortConfig("ttyS2", map[string]interface{}{
+		"side_designation": "A3",
+	}, ""); err != nil {
+		t.Errorf("UpdatePortConfig() to disabled port's designation: %v", err)
+	}
+}
+
+func TestUptimeSec(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		want  int64
+	}{
+		{"not started", time.Time{}, 0},
+		{"just started", now, 0},
+		{"running", now.Add(-3*24*time.Hour - 4*time.Hour), 273600},
+		{"fractional second truncates", now.Add(-1500 * time.Millisecond), 1},
+		{"clock skew", now.Add(time.Minute), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uptimeSec(tt.start, now); got != tt.want {
+				t.Errorf("uptimeSec() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Uptime never decreases as time moves forward
+	start := now.Add(-time.Hour)
+	prev := uptimeSec(start, now)
+	for i := 1; i <= 5; i++ {
+		got := uptimeSec(start, now.Add(time.Duration(i)*time.Second))
+		if got < prev {
+			t.Errorf("uptime went backwards: %d after %d", got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestEvaluateReadiness(t *testing.T) {
+	tests := []struct {
+		name          string
+		natsConnected bool
+		states        []ChannelState
+		wantReady     bool
+		wantChannels  int
+		wantReason    string
+	}{
+		{"all running", true, []ChannelState{StateRunning, StateRunning}, true, 2, ""},
+		{"one running among failures", true, []ChannelState{StateError, StateRunning, StateStopped}, true, 1, ""},
+		{"detecting not ready", true, []ChannelState{StateDetecting}, false, 0, "no channels ready"},
+		{"reconnecting not ready", true, []ChannelState{StateReconnecting, StateNoSignal}, false, 0, "no channels ready"},
+		{"paused not ready", true, []ChannelState{StatePaused}, false, 0, "no channels ready"},
+		{"nats down", false, []ChannelState{StateRunning}, false, 1, "NATS not connected"},
+		{"all waiting for nats", true, []ChannelState{StateWaitingForNATS, StateWaitingForNATS}, false, 0, "no channels ready"},
+		{"all errored", true, []ChannelState{StateError}, false, 0, "no channels ready"},
+		{"no channels", true, nil, false, 0, "no channels ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateReadiness(tt.natsConnected, tt.states)
+			if got.Ready != tt.wantReady || got.ReadyChannels != tt.wantChannels || got.Reason != tt.wantReason {
+				t.Errorf("evaluateReadiness() = %+v, want ready=%v channels=%d reason=%q",
+					got, tt.wantReady, tt.wantChannels, tt.wantReason)
+			}
+			if got.TotalChannels != len(tt.states) {
+				t.Errorf("TotalChannels = %d, want %d", got.TotalChannels, len(tt.states))
+			}
+		})
+	}
+}
+
+func TestGetHealthStatsIncludesUDP(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{Name: "Test", InstanceID: "test-01"}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	manager := NewManager(cfg, "", logger)
+	udp := NewUDPChannel(config.PortConfig{Type: config.PortTypeUDP, Address: "0.0.0.0:5140", SideDesignation: "A2"},
+		config.AppConfig{}, nil, logger)
+	udp.packetCount.Add(3)
+	udp.bytesRead.Add(42)
+	manager.channels = append(manager.channels,
+		&Channel{config: &config.PortConfig{Device: "/dev/ttyS1", SideDesignation: "A1"}, state: StateRunning, logger: logger})
+	manager.udpChannels = append(manager.udpChannels, udp)
+
+	health := manager.getHealthStats()
+	if len(health.Channels) != 2 {
+		t.Fatalf("len(Channels) = %d, want 2", len(health.Channels))
+	}
+	got := health.Channels[1]
+	if got.Device != "0.0.0.0:5140" || got.SideDesignation != "A2" || got.State != StateRunning.String() {
+		t.Errorf("UDP health = %+v, want 0.0.0.0:5140/A2 running", got)
+	}
+	if got.LinesRead != 3 || got.BytesRead != 42 || got.LastLineAgo != -1 {
+		t.Errorf("UDP health = %+v, want 3 lines, 42 bytes, never", got)
+	}
+}
+
+func TestManagerReadiness(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{Name: "Test", InstanceID: "test-01"}}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := NewManager(cfg, "", logger)
+	manager.channels = append(manager.channels,
+		&Channel{config: &config.PortConfig{Device: "/dev/ttyS1"}, state: StateRunning, logger: logger},
+		&Channel{config: &config.PortConfig{Device: "/dev/ttyS2"}, state: StateWaitingForNATS, logger: logger},
+	)
+
+	// No NATS connection in tests, so channels alone can't make it ready
+	r := manager.Readiness()
+	if r.Ready || r.NATSConnected {
+		t.Errorf("Readiness() = %+v, want not ready without NATS", r)
+	}
+	if r.ReadyChannels != 1 || r.TotalChannels != 2 {
+		t.Errorf("ReadyChannels = %d/%d, want 1/2", r.ReadyChannels, r.TotalChannels)
+	}
+}
+
+func TestManagerReadinessHTTPBindFailed(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{Name: "Test", InstanceID: "test-01"}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	manager := NewManager(cfg, "", logger)
+	h := NewHTTPChannel(config.PortConfig{Type: config.PortTypeHTTP, Path: "/cdr", SideDesignation: "A3"},
+		config.AppConfig{}, nil, logger)
+	manager.httpChannels = append(manager.httpChannels, h)
+
+	if r := manager.Readiness(); r.ReadyChannels != 1 || r.TotalChannels != 1 {
+		t.Errorf("bound HTTP channel: ReadyChannels = %d/%d, want 1/1", r.ReadyChannels, r.TotalChannels)
+	}
+
+	// A listener that never bound takes no records, so it isn't ready
+	h.SetBindError(errors.New("listen tcp :8081: bind: address already in use"))
+	r := manager.Readiness()
+	if r.ReadyChannels != 0 || r.TotalChannels != 1 {
+		t.Errorf("unbound HTTP channel: ReadyChannels = %d/%d, want 0/1", r.ReadyChannels, r.TotalChannels)
+	}
+	if r.Reason != "NATS not connected" {
+		t.Errorf("Reason = %q, want NATS checked first", r.Reason)
+	}
+
+	h.SetBindError(nil)
+	if r := manager.Readiness(); r.ReadyChannels != 1 {
+		t.Errorf("rebound HTTP channel: ReadyChannels = %d, want 1", r.ReadyChannels)
+	}
+}
+
+func TestManagerSelfTestPort(t *testing.T) {
+	cfg := &config.Config{
+		Ports: []config.PortConfig{
+			{Device: "/dev/ttyS1", SideDesignation: "A1", Enabled: true},
+			{Device: filepath.Join(t.TempDir(), "ttyMISSING"), SideDesignation: "A2"},
+			{Type: config.PortTypeHTTP, Path: "/cdr", SideDesignation: "A3"},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := NewManager(cfg, "", logger)
+
+	// A running channel owns the port - report its live stats instead
+	ch := &Channel{config: &cfg.Ports[0], state: StateRunning, logger: logger}
+	ch.stats.LinesRead = 42
+	manager.channels = append(manager.channels, ch)
+
+	report, err := manager.SelfTestPort("ttyS1")
+	if err != nil {
+		t.Fatalf("SelfTestPort(ttyS1) error = %v", err)
+	}
+	if !report.InUse || report.State != "running" || report.Stats == nil || report.Stats.LinesRead != 42 {
+		t.Errorf("SelfTestPort(ttyS1) = %+v, want in-use report with live stats", report)
+	}
+
+	for _, id := range []string{cfg.Ports[1].ID(), "/cdr", "ttyS9"} {
+		if _, err := manager.SelfTestPort(id); err == nil {
+			t.Errorf("SelfTestPort(%s) should fail", id)
+		}
+	}
+}
+
+func TestManagerChannelsReady(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+		Ports: []config.PortConfig{
+			{Type: config.PortTypeHTTP, Path: "/cdr", SideDesignation: "A1", Enabled: true},
+			{Type: config.PortTypeHTTP, Path: "/off", SideDesignation: "A2"},
+		},
+	}
+	manager := NewManager(cfg, "", slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	defer manager.Stop()
+
+	select {
+	case <-manager.ChannelsReady():
+		t.Fatal("ChannelsReady closed before any channels were created")
+	default:
+	}
+
+	if started := manager.startChannels(); started != 1 {
+		t.Fatalf("startChannels() = %d, want 1", started)
+	}
+
+	select {
+	case <-manager.ChannelsReady():
+	default:
+		t.Fatal("ChannelsReady still open after startup")
+	}
+	if n := len(manager.GetHTTPChannels()); n != 1 {
+		t.Errorf("GetHTTPChannels() = %d channels once ready, want 1", n)
+	}
+}
+
+func TestManagerStopWithReason(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+	}
+	manager := NewManager(cfg, "", slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	var events []output.Event
+	manager.SetEventCallback(func(e output.Event) {
+		events = append(events, e)
+	})
+
+	manager.StopWithReason("SIGTERM (service stop)")
+
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	if events[0].Type != output.EventServiceStop {
+		t.Errorf("Type = %q, want %q", events[0].Type, output.EventServiceStop)
+	}
+	if events[0].Details["reason"] != "SIGTERM (service stop)" {
+		t.Errorf("reason = %v, want SIGTERM (service stop)", events[0].Details["reason"])
+	}
+}
+
+func TestManagerPauseResumePort(t *testing.T) {
+	cfg := &config.Config{
+		Ports: []config.PortConfig{
+			{Device: "/dev/ttyS1", SideDesignation: "A1", Enabled: true},
+			{Type: config.PortTypeHTTP, Path: "/cdr", SideDesignation: "A2", Enabled: true},
+			{Device: "/dev/ttyS3", SideDesignation: "A3"},
+		},
+	}
+	manager := NewManager(cfg, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var events []output.Event
+	manager.SetEventCallback(func(e output.Event) { events = append(events, e) })
+
+	// Stand in for the running channel of the first port
+	ch := &Channel{config: &cfg.Ports[0], logger: manager.logger}
+	ch.setState(StateRunning)
+	manager.channels = append(manager.channels, ch)
+
+	if err := manager.PausePort("ttyS1", "admin@10.0.0.5:5555"); err != nil {
+		t.Fatalf("PausePort() error = %v", err)
+	}
+	if ch.State() != StatePaused {
+		t.Errorf("state = %s, want paused", ch.State())
+	}
+	if err := manager.PausePort("ttyS1", ""); err == nil || !strings.Contains(err.Error(), "already paused") {
+		t.Errorf("second PausePort() error = %v, want already paused", err)
+	}
+	if err := manager.ResumePort("ttyS1", ""); err != nil {
+		t.Fatalf("ResumePort() error = %v", err)
+	}
+	if ch.State() != StateRunning {
+		t.Errorf("state = %s, want running", ch.State())
+	}
+	if err := manager.ResumePort("ttyS1", ""); err == nil {
+		t.Error("ResumePort() of a running port should fail")
+	}
+
+	if len(events) != 2 || events[0].Details["action"] != "paused" || events[1].Details["action"] != "resumed" {
+		t.Errorf("config_change events = %v, want paused then resumed", events)
+	}
+
+	for _, id := range []string{"/cdr", "ttyS3", "ttyS9"} {
+		if err := manager.PausePort(id, ""); err == nil {
+			t.Errorf("PausePort(%q) should fail", id)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package capture
+
+import "unicode/utf8"
+
+// recordPreviewMaxBytes caps the last-record preview kept for record_preview
+// ports; enough to eyeball a CDR without holding large HTTP bodies
+const recordPreviewMaxBytes = 500
+
+// recordPreview truncates record to recordPreviewMaxBytes without splitting
+// a UTF-8 sequence
+func recordPreview(record string) string {
+	if len(record) <= recordPreviewMaxBytes {
+		return record
+	}
+	cut := recordPreviewMaxBytes
+	for cut > 0 && !utf8.RuneStart(record[cut]) {
+		cut--
+	}
+	return record[:cut]
+}
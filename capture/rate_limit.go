@@ -0,0 +1,50 @@
+package capture
+
+import "time"
+
+// tokenBucket is a simple token bucket for per-channel line rate limiting.
+// It holds up to one second's worth of tokens, so short bursts at the limit
+// pass but a sustained flood is cut to the configured rate.
+type tokenBucket struct {
+	rate   float64 // Tokens added per second
+	burst  float64 // Bucket capacity
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full bucket allowing perSec events per second.
+// Returns nil (unlimited) if perSec is not positive.
+func newTokenBucket(perSec int, now time.Time) *tokenBucket {
+	if perSec <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		rate:   float64(perSec),
+		burst:  float64(perSec),
+		tokens: float64(perSec),
+		last:   now,
+	}
+}
+
+// Allow takes a token if one is available
+func (b *tokenBucket) Allow(now time.Time) bool {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Full reports whether the bucket has (nearly) refilled, meaning traffic
+// has stayed under the rate long enough to consider the burst over
+func (b *tokenBucket) Full() bool {
+	return b.tokens >= b.burst-1
+}
@@ -0,0 +1,138 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+	"nectarcollector/serial"
+)
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Date(2025, 12, 3, 15, 0, 0, 0, time.UTC)
+	b := newTokenBucket(5, start)
+
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		if b.Allow(start) {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("burst allowed %d, want 5", allowed)
+	}
+
+	// 200ms refills one token at 5/s
+	if !b.Allow(start.Add(200 * time.Millisecond)) {
+		t.Error("Allow() after refill = false, want true")
+	}
+	if b.Allow(start.Add(200 * time.Millisecond)) {
+		t.Error("Allow() with empty bucket = true, want false")
+	}
+
+	// A quiet second refills completely, capped at the burst size
+	later := start.Add(10 * time.Second)
+	allowed = 0
+	for i := 0; i < 20; i++ {
+		if b.Allow(later) {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("allowed %d after idle, want 5 (capped)", allowed)
+	}
+
+	if newTokenBucket(0, start) != nil {
+		t.Error("newTokenBucket(0) should be nil (unlimited)")
+	}
+}
+
+func TestProcessLineRateLimit(t *testing.T) {
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       filepath.Join(dir, "ttyTEST"),
+		Identifier:   "1429010002-A1",
+		LogBasePath:  dir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	portCfg := &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1", MaxLinesPerSec: 10}
+	var events []output.Event
+	c := &Channel{
+		config:        portCfg,
+		appConfig:     &config.AppConfig{FIPSCode: "1429010002"},
+		reader:        serial.NewReaderWithStats(&scriptedReader{}),
+		dualWriter:    writer,
+		limiter:       newTokenBucket(portCfg.MaxLinesPerSec, time.Now()),
+		eventCallback: func(e output.Event) { events = append(events, e) },
+		logger:        logger,
+	}
+
+	for i := 0; i < 25; i++ {
+		c.processLine(fmt.Sprintf("GARBAGE %d", i))
+	}
+	writer.Close()
+
+	c.statsMutex.RLock()
+	limited := c.stats.RateLimited
+	c.statsMutex.RUnlock()
+	if limited != 15 {
+		t.Errorf("RateLimited = %d, want 15", limited)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "1429010002-A1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 10 {
+		t.Errorf("wrote %d lines, want 10", lines)
+	}
+
+	if len(events) != 1 || events[0].Type != output.EventRateLimited {
+		t.Errorf("events = %+v, want one %s event", events, output.EventRateLimited)
+	}
+}
+
+func TestAllowLineEventRearms(t *testing.T) {
+	start := time.Date(2025, 12, 3, 15, 0, 0, 0, time.UTC)
+	var events int
+	c := &Channel{
+		config:        &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1", MaxLinesPerSec: 2},
+		limiter:       newTokenBucket(2, start),
+		eventCallback: func(output.Event) { events++ },
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	// First flood fires once
+	for i := 0; i < 10; i++ {
+		c.allowLine(start)
+	}
+	// Still over the limit: a refilled token passes but no new event
+	for i := 0; i < 10; i++ {
+		c.allowLine(start.Add(600 * time.Millisecond))
+	}
+	if events != 1 {
+		t.Fatalf("events = %d during sustained flood, want 1", events)
+	}
+
+	// Quiet long enough to refill, then flood again
+	c.allowLine(start.Add(5 * time.Second))
+	for i := 0; i < 10; i++ {
+		c.allowLine(start.Add(5 * time.Second))
+	}
+	if events != 2 {
+		t.Errorf("events = %d after second flood, want 2", events)
+	}
+}
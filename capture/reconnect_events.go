@@ -0,0 +1,94 @@
+package capture
+
+import (
+	"sync"
+	"time"
+)
+
+// reconnectCoalescer keeps a reconnect storm (e.g., a flapping cable) from
+// flooding the events stream. The first attempt of a storm is reported
+// immediately; later attempts are reported at most once per interval with
+// the number of attempts since the last report. Once a session stays open
+// for an interval the storm is over and a single recovered report is due.
+// The zero value is ready to use.
+type reconnectCoalescer struct {
+	mu         sync.Mutex
+	storming   bool
+	stormStart time.Time
+	lastEmit   time.Time
+	pending    int64 // Attempts not yet reported
+	total      int64 // Attempts in this storm
+	generation int64 // Bumped by each attempt, invalidating an armed recovery
+	recovery   *time.Timer
+}
+
+// Attempt records a reconnect attempt and reports whether an event should be
+// emitted now, and if so how many attempts it covers (including this one)
+func (r *reconnectCoalescer) Attempt(now time.Time, interval time.Duration) (emit bool, attempts int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	if r.recovery != nil {
+		r.recovery.Stop()
+		r.recovery = nil
+	}
+
+	r.pending++
+	r.total++
+	if !r.storming {
+		r.storming = true
+		r.stormStart = now
+	} else if now.Sub(r.lastEmit) < interval {
+		return false, 0
+	}
+
+	attempts = r.pending
+	r.pending = 0
+	r.lastEmit = now
+	return true, attempts
+}
+
+// ArmRecovery schedules onRecovered to run once interval passes without
+// another attempt. It does nothing outside a storm. onRecovered receives the
+// storm's total attempts and how long it lasted.
+func (r *reconnectCoalescer) ArmRecovery(interval time.Duration, onRecovered func(total int64, duration time.Duration)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.storming {
+		return
+	}
+	if r.recovery != nil {
+		r.recovery.Stop()
+	}
+
+	generation := r.generation
+	r.recovery = time.AfterFunc(interval, func() {
+		r.mu.Lock()
+		if !r.storming || r.generation != generation {
+			r.mu.Unlock()
+			return
+		}
+		total, duration := r.total, time.Since(r.stormStart)
+		r.storming = false
+		r.pending = 0
+		r.total = 0
+		r.recovery = nil
+		r.mu.Unlock()
+
+		onRecovered(total, duration)
+	})
+}
+
+// Disarm cancels any pending recovery report (used on shutdown)
+func (r *reconnectCoalescer) Disarm() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	if r.recovery != nil {
+		r.recovery.Stop()
+		r.recovery = nil
+	}
+}
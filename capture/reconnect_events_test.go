@@ -0,0 +1,136 @@
+package capture
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+)
+
+func TestReconnectCoalescerAttempt(t *testing.T) {
+	var r reconnectCoalescer
+	start := time.Date(2025, 12, 3, 15, 0, 0, 0, time.UTC)
+	interval := time.Minute
+
+	// First attempt of a storm is reported immediately
+	if emit, attempts := r.Attempt(start, interval); !emit || attempts != 1 {
+		t.Fatalf("first Attempt = (%v, %d), want (true, 1)", emit, attempts)
+	}
+
+	// 500 attempts within the interval are held back
+	for i := 0; i < 500; i++ {
+		if emit, _ := r.Attempt(start.Add(time.Duration(i)*time.Millisecond), interval); emit {
+			t.Fatalf("attempt %d emitted inside the interval", i)
+		}
+	}
+
+	// The next one after the interval reports all of them plus itself
+	if emit, attempts := r.Attempt(start.Add(interval), interval); !emit || attempts != 501 {
+		t.Errorf("Attempt after interval = (%v, %d), want (true, 501)", emit, attempts)
+	}
+}
+
+func TestReconnectCoalescerRecovery(t *testing.T) {
+	var r reconnectCoalescer
+
+	// Nothing to recover from outside a storm
+	fired := make(chan int64, 2)
+	r.ArmRecovery(time.Millisecond, func(total int64, _ time.Duration) { fired <- total })
+	select {
+	case <-fired:
+		t.Fatal("recovery fired without a storm")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		r.Attempt(now, time.Minute)
+	}
+
+	// An attempt after arming cancels the pending recovery
+	r.ArmRecovery(20*time.Millisecond, func(total int64, _ time.Duration) { fired <- total })
+	r.Attempt(now, time.Minute)
+	select {
+	case <-fired:
+		t.Fatal("recovery fired despite a later attempt")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A session that holds reports recovery once, covering the whole storm
+	r.ArmRecovery(time.Millisecond, func(total int64, _ time.Duration) { fired <- total })
+	select {
+	case total := <-fired:
+		if total != 4 {
+			t.Errorf("recovered total = %d, want 4", total)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("recovery did not fire")
+	}
+
+	// The storm is over: arming again does nothing, the next attempt is immediate
+	r.ArmRecovery(time.Millisecond, func(total int64, _ time.Duration) { fired <- total })
+	select {
+	case <-fired:
+		t.Fatal("recovery fired twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if emit, attempts := r.Attempt(now, time.Minute); !emit || attempts != 1 {
+		t.Errorf("Attempt after recovery = (%v, %d), want (true, 1)", emit, attempts)
+	}
+}
+
+func TestChannelReconnectStormEvents(t *testing.T) {
+	var mu sync.Mutex
+	var events []output.Event
+	c := &Channel{
+		config:   &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1"},
+		recovery: &config.RecoveryConfig{ReconnectEventIntervalSec: 60},
+		eventCallback: func(e output.Event) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for i := 0; i < 200; i++ {
+		c.handleReconnect(context.Background())
+	}
+
+	mu.Lock()
+	if len(events) != 1 || events[0].Type != output.EventReconnect {
+		t.Fatalf("200 rapid reconnects produced %d events, want 1 reconnect: %+v", len(events), events)
+	}
+	mu.Unlock()
+	if c.stats.Reconnects != 200 {
+		t.Errorf("Reconnects = %d, want 200 (stats are not coalesced)", c.stats.Reconnects)
+	}
+
+	// Arm with a short interval so the open session counts as stable quickly
+	c.reconnectEvents.ArmRecovery(10*time.Millisecond, c.emitRecovered)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(events)
+		mu.Unlock()
+		if n >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 || events[1].Type != output.EventRecovered {
+		t.Fatalf("events = %+v, want reconnect then recovered", events)
+	}
+	if got := events[1].Details["attempts"]; got != int64(200) {
+		t.Errorf("recovered attempts = %v, want 200", got)
+	}
+}
@@ -0,0 +1,60 @@
+package capture
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+)
+
+// lineRedactor masks PII (e.g., caller numbers) in a line before it's logged
+// or published, applying a port's redaction_rules in order
+type lineRedactor struct {
+	rules []redactRule
+}
+
+type redactRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// newLineRedactor compiles the port's redaction rules.
+// Returns nil (lines pass through) if there are none.
+func newLineRedactor(portCfg *config.PortConfig) (*lineRedactor, error) {
+	if len(portCfg.RedactionRules) == 0 {
+		return nil, nil
+	}
+
+	r := &lineRedactor{rules: make([]redactRule, 0, len(portCfg.RedactionRules))}
+	for i, rule := range portCfg.RedactionRules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction_rules[%d] pattern: %w", i, err)
+		}
+		r.rules = append(r.rules, redactRule{re: re, replacement: rule.Replacement})
+	}
+	return r, nil
+}
+
+// Redact returns line with every rule applied. Safe to call on nil.
+func (r *lineRedactor) Redact(line string) string {
+	if r == nil {
+		return line
+	}
+	for _, rule := range r.rules {
+		line = rule.re.ReplaceAllString(line, rule.replacement)
+	}
+	return line
+}
+
+// publishUnredacted sends the original record to the port's
+// unredacted_subject. Callers only do so when redaction changed something.
+func publishUnredacted(dw *output.DualWriter, portCfg *config.PortConfig, record string, logger *slog.Logger) {
+	if err := dw.PublishTo(portCfg.UnredactedSubject, record); err != nil {
+		logger.Warn("Failed to publish unredacted record",
+			"subject", portCfg.UnredactedSubject,
+			"error", err)
+	}
+}
@@ -0,0 +1,217 @@
+package capture
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+	"nectarcollector/serial"
+)
+
+func TestLineRedactor(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.RedactionRule
+		line  string
+		want  string
+	}{
+		{
+			name:  "no rules",
+			rules: nil,
+			line:  "CALL 555-867-5309",
+			want:  "CALL 555-867-5309",
+		},
+		{
+			name:  "phone number masked",
+			rules: []config.RedactionRule{{Pattern: `\d{3}-\d{3}-\d{4}`, Replacement: "XXX-XXX-XXXX"}},
+			line:  "CALL 555-867-5309 FROM 555-123-4567",
+			want:  "CALL XXX-XXX-XXXX FROM XXX-XXX-XXXX",
+		},
+		{
+			name:  "group reference keeps area code",
+			rules: []config.RedactionRule{{Pattern: `(\d{3})-\d{3}-\d{4}`, Replacement: "$1-XXX-XXXX"}},
+			line:  "CALL 555-867-5309",
+			want:  "CALL 555-XXX-XXXX",
+		},
+		{
+			name: "rules applied in order",
+			rules: []config.RedactionRule{
+				{Pattern: `\d{3}-\d{3}-\d{4}`, Replacement: "PHONE"},
+				{Pattern: `PHONE`, Replacement: "[redacted]"},
+			},
+			line: "CALL 555-867-5309",
+			want: "CALL [redacted]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newLineRedactor(&config.PortConfig{RedactionRules: tt.rules})
+			if err != nil {
+				t.Fatalf("newLineRedactor() error = %v", err)
+			}
+			if got := r.Redact(tt.line); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newLineRedactor(&config.PortConfig{RedactionRules: []config.RedactionRule{{Pattern: `(`}}}); err == nil {
+		t.Error("newLineRedactor() with invalid pattern: expected error")
+	}
+}
+
+// newRedactTestWriter creates a DualWriter publishing to test.1429010002 and
+// returns it along with a func that groups the published records by subject
+func newRedactTestWriter(t *testing.T) (*output.DualWriter, func() map[string][]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var published []*nats.Msg
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       "/dev/ttyTEST",
+		Identifier:   "1429010002-A1",
+		LogBasePath:  t.TempDir(),
+		LogMaxSizeMB: 1,
+		NATSSubject:  "test.1429010002",
+		Publish: func(msg *nats.Msg) error {
+			mu.Lock()
+			published = append(published, msg)
+			mu.Unlock()
+			return nil
+		},
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { writer.Close() })
+
+	return writer, func() map[string][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		bySubject := map[string][]string{}
+		for _, msg := range published {
+			bySubject[msg.Subject] = append(bySubject[msg.Subject], string(msg.Data))
+		}
+		return bySubject
+	}
+}
+
+func TestChannelRedactsOutput(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	writer, published := newRedactTestWriter(t)
+
+	portCfg := &config.PortConfig{
+		Device:            "/dev/ttyTEST",
+		SideDesignation:   "A1",
+		RecordPreview:     true,
+		RedactionRules:    []config.RedactionRule{{Pattern: `\d{3}-\d{3}-\d{4}`, Replacement: "XXX-XXX-XXXX"}},
+		UnredactedSubject: "restricted.1429010002",
+	}
+	redactor, err := newLineRedactor(portCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Channel{
+		config:     portCfg,
+		appConfig:  &config.AppConfig{FIPSCode: "1429010002"},
+		reader:     serial.NewReaderWithStats(&scriptedReader{}),
+		dualWriter: writer,
+		redactor:   redactor,
+		logger:     logger,
+	}
+
+	c.processLine("CALL 555-867-5309")
+	c.processLine("KEEPALIVE")
+
+	data, _ := os.ReadFile(writer.LogPath())
+	if strings.Contains(string(data), "867-5309") || !strings.Contains(string(data), "CALL XXX-XXX-XXXX") {
+		t.Errorf("log = %q, want the phone number masked", data)
+	}
+	if preview := c.LastRecord(); strings.Contains(preview, "867-5309") {
+		t.Errorf("record preview = %q, want the phone number masked", preview)
+	}
+
+	bySubject := published()
+	primary := bySubject["test.1429010002"]
+	if len(primary) != 2 || strings.Contains(primary[0], "867-5309") || !strings.Contains(primary[0], "CALL XXX-XXX-XXXX") {
+		t.Errorf("primary subject got %q, want 2 records with the phone number masked", primary)
+	}
+	// Only the line that was actually redacted goes to the restricted subject
+	restricted := bySubject["restricted.1429010002"]
+	if len(restricted) != 1 || !strings.Contains(restricted[0], "CALL 555-867-5309") {
+		t.Errorf("restricted subject got %q, want the one unredacted record", restricted)
+	}
+}
+
+func TestHTTPChannelRedactsOutput(t *testing.T) {
+	writer, published := newRedactTestWriter(t)
+	ch := NewHTTPChannel(config.PortConfig{
+		Type:              config.PortTypeHTTP,
+		Path:              "/cdr",
+		SideDesignation:   "A1",
+		RecordPreview:     true,
+		RedactionRules:    []config.RedactionRule{{Pattern: `\d{3}-\d{3}-\d{4}`, Replacement: "XXX-XXX-XXXX"}},
+		UnredactedSubject: "restricted.1429010002",
+	}, config.AppConfig{FIPSCode: "1429010002"}, writer, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	ch.ServeHTTP(w, httptest.NewRequest("POST", "/cdr", strings.NewReader("CALL 555-867-5309")))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	data, _ := os.ReadFile(writer.LogPath())
+	if strings.Contains(string(data), "867-5309") || !strings.Contains(string(data), "CALL XXX-XXX-XXXX") {
+		t.Errorf("log = %q, want the phone number masked", data)
+	}
+	if preview := ch.LastRecord(); preview != "CALL XXX-XXX-XXXX" {
+		t.Errorf("record preview = %q, want the phone number masked", preview)
+	}
+
+	bySubject := published()
+	if primary := bySubject["test.1429010002"]; len(primary) != 1 || strings.Contains(primary[0], "867-5309") {
+		t.Errorf("primary subject got %q, want 1 record with the phone number masked", primary)
+	}
+	if restricted := bySubject["restricted.1429010002"]; len(restricted) != 1 || !strings.Contains(restricted[0], "CALL 555-867-5309") {
+		t.Errorf("restricted subject got %q, want the one unredacted record", restricted)
+	}
+}
+
+func TestUDPChannelRedactsOutput(t *testing.T) {
+	writer, published := newRedactTestWriter(t)
+	ch := NewUDPChannel(config.PortConfig{
+		Type:              config.PortTypeUDP,
+		Address:           "127.0.0.1:0",
+		SideDesignation:   "A1",
+		RedactionRules:    []config.RedactionRule{{Pattern: `\d{3}-\d{3}-\d{4}`, Replacement: "XXX-XXX-XXXX"}},
+		UnredactedSubject: "restricted.1429010002",
+	}, config.AppConfig{FIPSCode: "1429010002"}, writer, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9999}
+	ch.handleRecord("CALL 555-867-5309", addr)
+	ch.handleRecord("KEEPALIVE", addr)
+
+	data, _ := os.ReadFile(writer.LogPath())
+	if strings.Contains(string(data), "867-5309") || !strings.Contains(string(data), "CALL XXX-XXX-XXXX") {
+		t.Errorf("log = %q, want the phone number masked", data)
+	}
+
+	bySubject := published()
+	if primary := bySubject["test.1429010002"]; len(primary) != 2 || strings.Contains(primary[0], "867-5309") {
+		t.Errorf("primary subject got %q, want 2 records with the phone number masked", primary)
+	}
+	if restricted := bySubject["restricted.1429010002"]; len(restricted) != 1 || !strings.Contains(restricted[0], "CALL 555-867-5309") {
+		t.Errorf("restricted subject got %q, want the one unredacted record", restricted)
+	}
+}
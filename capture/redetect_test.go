@@ -0,0 +1,86 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"nectarcollector/config"
+	"nectarcollector/serial"
+)
+
+func TestTriggerRedetectStopsReadLoop(t *testing.T) {
+	c := &Channel{
+		config:      &config.PortConfig{Device: "/dev/ttyTEST", SideDesignation: "A1"},
+		appConfig:   &config.AppConfig{FIPSCode: "1429010002"},
+		reader:      serial.NewReaderWithStats(&scriptedReader{steps: []readStep{{data: "never read\n"}}}),
+		natsChecker: &MockNATSChecker{connected: true},
+		redetectCh:  make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	// Repeated triggers collapse into one pending request
+	c.TriggerRedetect()
+	c.TriggerRedetect()
+
+	if err := c.readLoop(context.Background(), "/dev/null"); !errors.Is(err, errRedetect) {
+		t.Fatalf("readLoop() error = %v, want errRedetect", err)
+	}
+	if c.redetectRequested() {
+		t.Error("second trigger should have collapsed into the first")
+	}
+}
+
+func TestRedetectRequested(t *testing.T) {
+	c := &Channel{redetectCh: make(chan struct{}, 1)}
+
+	if c.redetectRequested() {
+		t.Error("redetectRequested() = true with nothing pending")
+	}
+
+	// Interrupted session: captureLoop sets forceDetect
+	c.forceDetect = true
+	if !c.redetectRequested() {
+		t.Error("redetectRequested() = false after interrupted session")
+	}
+	if c.redetectRequested() {
+		t.Error("forceDetect should apply to one session only")
+	}
+
+	// Trigger arriving between sessions
+	c.TriggerRedetect()
+	if !c.redetectRequested() {
+		t.Error("redetectRequested() = false with trigger pending")
+	}
+}
+
+func TestManagerRedetectPort(t *testing.T) {
+	cfg := &config.Config{
+		Ports: []config.PortConfig{
+			{Device: "/dev/ttyS1", SideDesignation: "A1", Enabled: true},
+			{Device: "/dev/ttyS2", SideDesignation: "A2"},
+			{Type: config.PortTypeHTTP, Path: "/cdr", SideDesignation: "A3"},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	manager := NewManager(cfg, "", logger)
+
+	ch := &Channel{config: &cfg.Ports[0], redetectCh: make(chan struct{}, 1), logger: logger}
+	manager.channels = append(manager.channels, ch)
+
+	if err := manager.RedetectPort("ttyS1"); err != nil {
+		t.Fatalf("RedetectPort(ttyS1) error = %v", err)
+	}
+	if !ch.redetectRequested() {
+		t.Error("RedetectPort() did not signal the channel")
+	}
+
+	for _, id := range []string{"ttyS2", "/cdr", "ttyS9"} {
+		if err := manager.RedetectPort(id); err == nil {
+			t.Errorf("RedetectPort(%s) should fail", id)
+		}
+	}
+}
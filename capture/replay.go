@@ -0,0 +1,103 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"nectarcollector/serial"
+)
+
+// runReplaySession feeds a replay file through the normal line path, so a
+// captured log exercises the log writer and NATS exactly like live data.
+// Headers written by the collector are stripped (replayed records get a new
+// one); with replay_paced the gaps between header timestamps are reproduced.
+// At the end of the file the channel idles until stopped rather than
+// replaying again.
+func (c *Channel) runReplaySession(ctx context.Context) error {
+	fileReader, err := serial.NewFileReader(c.config.ReplayFile)
+	if err != nil {
+		return err
+	}
+
+	c.reader = serial.NewReaderWithStats(fileReader)
+	defer func() {
+		c.reader.Close()
+		c.reader = nil
+	}()
+
+	c.logger.Info("Replaying file", "file", c.config.ReplayFile, "paced", c.config.ReplayPaced)
+	c.setState(StateRunning)
+	c.sessionOpened()
+
+	scanner := bufio.NewScanner(c.reader)
+	splitter := newLineSplitter(c.config, c.recordOversizeLine)
+	buf := make([]byte, min(InitialLineBufferSize, splitter.BufferSize()))
+	scanner.Buffer(buf, splitter.BufferSize())
+	scanner.Split(splitter.Split)
+
+	var lastHeaderTime time.Time
+	for scanner.Scan() {
+		if !c.waitForNATS(ctx) {
+			return nil
+		}
+
+		record, headerTime, ok := c.splitReplayLine(scanner.Text())
+		if ok && c.config.ReplayPaced {
+			if !lastHeaderTime.IsZero() && !c.replayWait(ctx, headerTime.Sub(lastHeaderTime)) {
+				return nil
+			}
+			lastHeaderTime = headerTime
+		}
+
+		c.processLine(record)
+	}
+	if err := scanner.Err(); err != nil {
+		c.reader.IncrementErrors()
+		return fmt.Errorf("replay read error: %w", err)
+	}
+
+	_, lines, _ := c.reader.Stats()
+	c.logger.Info("Replay complete", "file", c.config.ReplayFile, "lines", lines)
+
+	select {
+	case <-ctx.Done():
+	case <-c.stopCh:
+	}
+	return nil
+}
+
+// splitReplayLine strips a collector header from a replayed line, returning
+// the record and the header time. Lines without a header (raw CDR) are
+// returned as-is with ok false.
+func (c *Channel) splitReplayLine(line string) (record string, headerTime time.Time, ok bool) {
+	headerTime, ok = c.headerFmt.ParseTime(line)
+	if !ok {
+		return line, time.Time{}, false
+	}
+	// A parsed header is [FIPS][A][timestamp], followed by a space
+	parts := strings.SplitN(line, "]", 4)
+	return strings.TrimPrefix(parts[3], " "), headerTime, true
+}
+
+// replayWait sleeps d between paced records. Returns false if the channel is
+// stopping.
+func (c *Channel) replayWait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-c.stopCh:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package capture
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+)
+
+// replayChannel builds a file port channel whose NATS publishes are recorded
+func replayChannel(t *testing.T, portCfg *config.PortConfig) (*Channel, func() []*nats.Msg) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var mu sync.Mutex
+	var published []*nats.Msg
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       portCfg.Source(),
+		Identifier:   "1429010002-A1",
+		LogBasePath:  t.TempDir(),
+		LogMaxSizeMB: 1,
+		NATSSubject:  "test.1429010002",
+		Publish: func(msg *nats.Msg) error {
+			mu.Lock()
+			published = append(published, msg)
+			mu.Unlock()
+			return nil
+		},
+		Logger: logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { writer.Close() })
+
+	c := &Channel{
+		config:      portCfg,
+		appConfig:   &config.AppConfig{FIPSCode: "1429010002"},
+		dualWriter:  writer,
+		natsChecker: &MockNATSChecker{connected: true},
+		redetectCh:  make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+		logger:      logger,
+	}
+	return c, func() []*nats.Msg {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*nats.Msg(nil), published...)
+	}
+}
+
+// runReplay runs the replay session until want records are published, then
+// stops it and returns the publishes
+func runReplay(t *testing.T, c *Channel, published func() []*nats.Msg, want int) []*nats.Msg {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- c.runCaptureSession(context.Background()) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(published()) < want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(c.stopCh)
+	if err := <-done; err != nil {
+		t.Fatalf("runCaptureSession() error = %v", err)
+	}
+	return published()
+}
+
+func writeReplayFile(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "replay.log")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReplayPublishesRecords(t *testing.T) {
+	path := writeReplayFile(t,
+		"[1429010002][A1][2025-12-03 15:04:05.000] CALL 001 IN 5551234",
+		"[1429010002][A1][2025-12-03 15:04:06.000] CALL 002 OUT 5559876",
+		"RAW 003 NO HEADER",
+	)
+	c, published := replayChannel(t, &config.PortConfig{Type: config.PortTypeFile, ReplayFile: path, SideDesignation: "A1"})
+
+	msgs := runReplay(t, c, published, 3)
+	if len(msgs) != 3 {
+		t.Fatalf("published %d records, want 3", len(msgs))
+	}
+
+	for i, want := range []string{"CALL 001 IN 5551234", "CALL 002 OUT 5559876", "RAW 003 NO HEADER"} {
+		data := strings.TrimSuffix(string(msgs[i].Data), "\n")
+		if msgs[i].Subject != "test.1429010002" {
+			t.Errorf("record %d subject = %q", i, msgs[i].Subject)
+		}
+		// Replayed records get a fresh header, not a second one
+		if !strings.HasPrefix(data, "[1429010002][A1][") || !strings.HasSuffix(data, "] "+want) {
+			t.Errorf("record %d = %q, want one header + %q", i, data, want)
+		}
+		if strings.Count(data, "[1429010002]") != 1 {
+			t.Errorf("record %d has a doubled header: %q", i, data)
+		}
+	}
+	if c.State() != StateRunning {
+		t.Errorf("State() = %v, want running after replay", c.State())
+	}
+}
+
+func TestReplayPaced(t *testing.T) {
+	path := writeReplayFile(t,
+		"[1429010002][A1][2025-12-03 15:04:05.000] CALL 001",
+		"[1429010002][A1][2025-12-03 15:04:05.150] CALL 002",
+		"[1429010002][A1][2025-12-03 15:04:05.300] CALL 003",
+	)
+	c, published := replayChannel(t, &config.PortConfig{
+		Type: config.PortTypeFile, ReplayFile: path, ReplayPaced: true, SideDesignation: "A1",
+	})
+
+	start := time.Now()
+	msgs := runReplay(t, c, published, 3)
+	if len(msgs) != 3 {
+		t.Fatalf("published %d records, want 3", len(msgs))
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("paced replay took %v, want at least the 300ms spanned by the headers", elapsed)
+	}
+}
+
+func TestReplayMissingFile(t *testing.T) {
+	c, _ := replayChannel(t, &config.PortConfig{
+		Type: config.PortTypeFile, ReplayFile: filepath.Join(t.TempDir(), "missing.log"), SideDesignation: "A1",
+	})
+	if err := c.runCaptureSession(context.Background()); err == nil {
+		t.Fatal("runCaptureSession() with a missing file should fail")
+	}
+}
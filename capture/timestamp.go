@@ -0,0 +1,83 @@
+package capture
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+)
+
+// lineTimestamper extracts the event time embedded in a record so the header
+// reflects when the call happened rather than when we received it. This
+// matters during catch-up after an outage, when CPE replays buffered CDRs.
+type lineTimestamper struct {
+	re     *regexp.Regexp
+	layout string
+	loc    *time.Location
+}
+
+// newLineTimestamper builds a timestamper from the port config.
+// Returns nil (receive-time headers) if timestamp_regex is not set.
+func newLineTimestamper(portCfg *config.PortConfig) (*lineTimestamper, error) {
+	if portCfg.TimestampRegex == "" {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(portCfg.TimestampRegex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp_regex: %w", err)
+	}
+	loc, err := portCfg.TimestampLocation()
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp_tz: %w", err)
+	}
+
+	return &lineTimestamper{
+		re:     re,
+		layout: portCfg.TimestampLayout,
+		loc:    loc,
+	}, nil
+}
+
+// Timestamp returns the time embedded in line, in UTC, or received if the
+// line doesn't match or the match doesn't parse. Safe to call on nil receiver.
+func (t *lineTimestamper) Timestamp(line string, received time.Time) time.Time {
+	if t == nil {
+		return received
+	}
+
+	m := t.re.FindStringSubmatch(line)
+	if m == nil {
+		return received
+	}
+	value := m[0]
+	if len(m) > 1 {
+		value = m[1]
+	}
+
+	parsed, err := time.ParseInLocation(t.layout, value, t.loc)
+	if err != nil {
+		return received
+	}
+
+	// Layouts without a year (e.g., "Jan _2 15:04:05") parse as year 0
+	if parsed.Year() == 0 {
+		parsed = parsed.AddDate(received.In(t.loc).Year(), 0, 0)
+	}
+
+	return parsed.UTC()
+}
+
+// newHeaderFormat returns the header format for the app's time_zone and
+// timestamp_precision. Both are checked at config load, so on error it logs
+// and falls back to UTC milliseconds.
+func newHeaderFormat(appCfg *config.AppConfig, logger *slog.Logger) output.HeaderFormat {
+	format, err := output.NewHeaderFormat(appCfg.TimeZone, appCfg.TimestampPrecision)
+	if err != nil {
+		logger.Error("Invalid header settings, writing UTC milliseconds", "error", err)
+	}
+	return format
+}
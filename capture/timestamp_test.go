@@ -0,0 +1,97 @@
+package capture
+
+import (
+	"testing"
+	"time"
+
+	"nectarcollector/config"
+)
+
+func TestLineTimestamper(t *testing.T) {
+	received := time.Date(2025, 12, 3, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		portCfg config.PortConfig
+		line    string
+		want    time.Time
+	}{
+		{
+			name: "matching line",
+			portCfg: config.PortConfig{
+				TimestampRegex:  `^(\d{2}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`,
+				TimestampLayout: "01/02/06 15:04:05",
+			},
+			line: "12/03/25 14:59:58 TRUNK 01 ANI 4025551234",
+			want: time.Date(2025, 12, 3, 14, 59, 58, 0, time.UTC),
+		},
+		{
+			name: "non-matching line",
+			portCfg: config.PortConfig{
+				TimestampRegex:  `^(\d{2}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`,
+				TimestampLayout: "01/02/06 15:04:05",
+			},
+			line: "KEEPALIVE",
+			want: received,
+		},
+		{
+			name: "malformed timestamp",
+			portCfg: config.PortConfig{
+				TimestampRegex:  `^(\d{2}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`,
+				TimestampLayout: "01/02/06 15:04:05",
+			},
+			line: "13/45/25 99:59:58 TRUNK 01",
+			want: received,
+		},
+		{
+			name: "configured timezone",
+			portCfg: config.PortConfig{
+				TimestampRegex:  `TIME=(\S+ \S+)`,
+				TimestampLayout: "2006-01-02 15:04:05",
+				TimestampTZ:     "America/Chicago",
+			},
+			line: "CALL TIME=2025-12-03 08:59:58 POS 3",
+			want: time.Date(2025, 12, 3, 14, 59, 58, 0, time.UTC),
+		},
+		{
+			name: "layout without year uses receive year",
+			portCfg: config.PortConfig{
+				TimestampRegex:  `^\w{3} [ \d]\d \d{2}:\d{2}:\d{2}`,
+				TimestampLayout: "Jan _2 15:04:05",
+			},
+			line: "Dec  3 14:59:58 position 3 answered",
+			want: time.Date(2025, 12, 3, 14, 59, 58, 0, time.UTC),
+		},
+		{
+			name:    "not configured",
+			portCfg: config.PortConfig{},
+			line:    "12/03/25 14:59:58 TRUNK 01",
+			want:    received,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := newLineTimestamper(&tt.portCfg)
+			if err != nil {
+				t.Fatalf("newLineTimestamper() error = %v", err)
+			}
+			got := ts.Timestamp(tt.line, received)
+			if !got.Equal(tt.want) {
+				t.Errorf("Timestamp() = %v, want %v", got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("Timestamp() location = %v, want UTC", got.Location())
+			}
+		})
+	}
+}
+
+func TestNewLineTimestamperInvalid(t *testing.T) {
+	if _, err := newLineTimestamper(&config.PortConfig{TimestampRegex: "("}); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+	if _, err := newLineTimestamper(&config.PortConfig{TimestampRegex: ".", TimestampTZ: "Mars/Olympus"}); err == nil {
+		t.Error("expected error for invalid timezone")
+	}
+}
@@ -0,0 +1,254 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nectarcollector/config"
+	"nectarcollector/output"
+)
+
+// maxUDPDatagram is the largest datagram read; anything longer is truncated
+// by the kernel
+const maxUDPDatagram = 64 * 1024
+
+// UDPChannel captures CDR sent as UDP datagrams, one record per datagram
+type UDPChannel struct {
+	config    config.PortConfig
+	appConfig config.AppConfig
+	logger    *slog.Logger
+
+	dualWriter *output.DualWriter
+
+	timestamper *lineTimestamper // Header time from the datagram (nil = receive time)
+	redactor    *lineRedactor    // Masks PII before output (nil = off)
+	redactorErr error            // Set when redaction_rules failed to compile; datagrams are dropped
+	headerFmt   output.HeaderFormat
+
+	conn      net.PacketConn
+	stopping  chan struct{} // Closed with the listener so a read backoff ends early
+	closeOnce sync.Once
+	done      chan struct{}
+
+	// Stats
+	statsMutex  sync.RWMutex
+	stats       UDPChannelStats
+	bytesRead   atomic.Int64
+	packetCount atomic.Int64
+	errorCount  atomic.Int64
+}
+
+// UDPChannelStats tracks statistics for a UDP capture channel
+type UDPChannelStats struct {
+	BytesRead      int64     `json:"bytes_read"`
+	Packets        int64     `json:"packets"`
+	Errors         int64     `json:"errors"`
+	LastPacketTime time.Time `json:"last_packet_time"`
+	StartTime      time.Time `json:"start_time"`
+	ExtraPubErrors int64     `json:"extra_publish_errors"` // Failed publishes to extra_subjects
+	LogDropped     int64     `json:"log_dropped"`          // Buffered records lost to log write failures
+}
+
+// NewUDPChannel creates a new UDP capture channel. Start binds the listener.
+func NewUDPChannel(
+	portCfg config.PortConfig,
+	appCfg config.AppConfig,
+	dualWriter *output.DualWriter,
+	logger *slog.Logger,
+) *UDPChannel {
+	u := &UDPChannel{
+		config:     portCfg,
+		appConfig:  appCfg,
+		dualWriter: dualWriter,
+		logger:     logger.With("channel", portCfg.SideDesignation, "address", portCfg.Address),
+		stopping:   make(chan struct{}),
+		done:       make(chan struct{}),
+		stats: UDPChannelStats{
+			StartTime: time.Now(),
+		},
+	}
+
+	var err error
+	if u.timestamper, err = newLineTimestamper(&portCfg); err != nil {
+		u.logger.Error("Invalid timestamp settings, using receive time", "error", err)
+	}
+	if u.redactor, err = newLineRedactor(&portCfg); err != nil {
+		// Patterns are checked at config load; fail closed rather than log PII
+		u.logger.Error("Invalid redaction_rules, dropping all datagrams", "error", err)
+		u.redactorErr = err
+	}
+	u.headerFmt = newHeaderFormat(&appCfg, u.logger)
+
+	return u
+}
+
+// Start binds the listen address and reads datagrams until Stop or ctx ends
+func (u *UDPChannel) Start(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", u.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", u.config.Address, err)
+	}
+	u.conn = conn
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			u.closeListener()
+		case <-u.done:
+		}
+	}()
+	go u.readLoop()
+
+	u.logger.Info("Listening for UDP datagrams", "local_addr", conn.LocalAddr().String())
+	return nil
+}
+
+// Wait between failed reads, doubling per consecutive failure, so a socket
+// stuck returning errors doesn't spin
+const (
+	udpReadBackoffMin = 10 * time.Millisecond
+	udpReadBackoffMax = time.Second
+)
+
+// readLoop writes each datagram as one record until the listener closes
+func (u *UDPChannel) readLoop() {
+	defer close(u.done)
+
+	buf := make([]byte, maxUDPDatagram)
+	var backoff time.Duration
+	for {
+		n, addr, err := u.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			u.errorCount.Add(1)
+			if backoff == 0 {
+				// Only the first failure of a run is logged
+				u.logger.Warn("Failed to read datagram", "error", err)
+			}
+			backoff = min(max(backoff*2, udpReadBackoffMin), udpReadBackoffMax)
+			select {
+			case <-time.After(backoff):
+			case <-u.stopping:
+				return
+			}
+			continue
+		}
+		backoff = 0
+
+		record := strings.TrimRight(string(buf[:n]), "\r\n")
+		if record == "" {
+			continue
+		}
+		u.handleRecord(record, addr)
+	}
+}
+
+// handleRecord prefixes the header and writes one datagram
+func (u *UDPChannel) handleRecord(record string, addr net.Addr) {
+	if u.redactorErr != nil {
+		u.errorCount.Add(1)
+		return
+	}
+
+	header := u.headerFmt.Build(portFIPSCode(&u.config, &u.appConfig), u.config.SideDesignation,
+		u.timestamper.Timestamp(record, time.Now().UTC()))
+
+	// The log and primary subject only ever see the redacted form
+	redacted := u.redactor.Redact(record)
+	line := output.AppendChecksum(header+redacted, u.config.AppendChecksum, []byte(redacted))
+	if err := u.dualWriter.WriteLine(line); err != nil {
+		u.errorCount.Add(1)
+		u.logger.Warn("Failed to write record", "error", err)
+		return
+	}
+
+	// The original goes only to the restricted subject, if configured
+	if u.config.UnredactedSubject != "" && redacted != record {
+		publishUnredacted(u.dualWriter, &u.config, output.AppendChecksum(header+record, u.config.AppendChecksum, []byte(record)), u.logger)
+	}
+
+	u.bytesRead.Add(int64(len(record)))
+	u.packetCount.Add(1)
+	u.statsMutex.Lock()
+	u.stats.LastPacketTime = time.Now()
+	u.statsMutex.Unlock()
+
+	u.logger.Debug("Captured UDP datagram", "length", len(record), "remote_addr", addr.String())
+}
+
+// GetStats returns current channel statistics
+func (u *UDPChannel) GetStats() UDPChannelStats {
+	u.statsMutex.RLock()
+	defer u.statsMutex.RUnlock()
+
+	return UDPChannelStats{
+		BytesRead:      u.bytesRead.Load(),
+		Packets:        u.packetCount.Load(),
+		Errors:         u.errorCount.Load(),
+		LastPacketTime: u.stats.LastPacketTime,
+		StartTime:      u.stats.StartTime,
+		ExtraPubErrors: extraPublishErrors(u.dualWriter),
+		LogDropped:     logDropped(u.dualWriter),
+	}
+}
+
+// Config returns the port configuration
+func (u *UDPChannel) Config() config.PortConfig {
+	return u.config
+}
+
+// Address returns the configured listen address
+func (u *UDPChannel) Address() string {
+	return u.config.Address
+}
+
+// LocalAddr returns the bound address (nil before Start)
+func (u *UDPChannel) LocalAddr() net.Addr {
+	if u.conn == nil {
+		return nil
+	}
+	return u.conn.LocalAddr()
+}
+
+// SideDesignation returns the A designation for this channel
+func (u *UDPChannel) SideDesignation() string {
+	return u.config.SideDesignation
+}
+
+// LogPath returns the channel's log file path ("" if not open)
+func (u *UDPChannel) LogPath() string {
+	if u.dualWriter == nil {
+		return ""
+	}
+	return u.dualWriter.LogPath()
+}
+
+// closeListener closes the socket, ending the read loop; safe to call twice
+func (u *UDPChannel) closeListener() {
+	u.closeOnce.Do(func() {
+		close(u.stopping)
+		u.conn.Close()
+	})
+}
+
+// Stop closes the listener, waits for the read loop, and closes the dual writer
+func (u *UDPChannel) Stop() error {
+	u.logger.Info("Stopping UDP channel")
+	if u.conn != nil {
+		u.closeListener()
+		<-u.done
+	}
+	if u.dualWriter != nil {
+		return u.dualWriter.Close()
+	}
+	return nil
+}
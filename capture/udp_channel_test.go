@@ -0,0 +1,181 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nectarcollector/config"
+)
+
+func TestUDPChannelCapturesDatagrams(t *testing.T) {
+	writer, logPath := newTestHTTPWriter(t)
+	ch := NewUDPChannel(
+		config.PortConfig{Type: config.PortTypeUDP, Address: "127.0.0.1:0", SideDesignation: "A1"},
+		config.AppConfig{FIPSCode: "1234567890"},
+		writer,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := ch.Start(ctx); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer ch.Stop()
+
+	conn, err := net.Dial("udp", ch.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	datagrams := []string{"CALL 1 5551234\r\n", "CALL 2 5555678", "\n"}
+	for _, d := range datagrams {
+		if _, err := conn.Write([]byte(d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The blank datagram is skipped, so two records are expected
+	deadline := time.Now().Add(2 * time.Second)
+	for ch.GetStats().Packets < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stats := ch.GetStats()
+	if stats.Packets != 2 {
+		t.Fatalf("Packets = %d, want 2", stats.Packets)
+	}
+	if want := int64(len("CALL 1 5551234") + len("CALL 2 5555678")); stats.BytesRead != want {
+		t.Errorf("BytesRead = %d, want %d", stats.BytesRead, want)
+	}
+	if stats.LastPacketTime.IsZero() {
+		t.Error("LastPacketTime not set")
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log has %d lines, want 2: %q", len(lines), data)
+	}
+	for i, want := range []string{"CALL 1 5551234", "CALL 2 5555678"} {
+		if !strings.HasPrefix(lines[i], "[1234567890][A1][") || !strings.HasSuffix(lines[i], want) {
+			t.Errorf("line %d = %q, want header + %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestUDPChannelStopClosesListener(t *testing.T) {
+	writer, _ := newTestHTTPWriter(t)
+	ch := NewUDPChannel(
+		config.PortConfig{Type: config.PortTypeUDP, Address: "127.0.0.1:0", SideDesignation: "A1"},
+		config.AppConfig{FIPSCode: "1234567890"},
+		writer,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	if err := ch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	addr := ch.LocalAddr().String()
+
+	done := make(chan struct{})
+	go func() {
+		ch.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop() did not return")
+	}
+
+	// The address is free again once stopped
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		t.Fatalf("address still bound after Stop: %v", err)
+	}
+	conn.Close()
+}
+
+func TestUDPChannelStartBindFailure(t *testing.T) {
+	taken, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	writer, _ := newTestHTTPWriter(t)
+	ch := NewUDPChannel(
+		config.PortConfig{Type: config.PortTypeUDP, Address: taken.LocalAddr().String(), SideDesignation: "A1"},
+		config.AppConfig{FIPSCode: "1234567890"},
+		writer,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	if err := ch.Start(context.Background()); err == nil {
+		ch.Stop()
+		t.Fatal("Start() on a bound address should fail")
+	}
+}
+
+// failingPacketConn returns the same read error until closed
+type failingPacketConn struct {
+	net.PacketConn
+	reads  atomic.Int32
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (f *failingPacketConn) ReadFrom([]byte) (int, net.Addr, error) {
+	select {
+	case <-f.closed:
+		return 0, nil, net.ErrClosed
+	default:
+	}
+	f.reads.Add(1)
+	return 0, nil, errors.New("connection refused")
+}
+
+func (f *failingPacketConn) Close() error {
+	f.once.Do(func() { close(f.closed) })
+	return nil
+}
+
+func TestUDPChannelReadErrorBackoff(t *testing.T) {
+	conn := &failingPacketConn{closed: make(chan struct{})}
+	ch := NewUDPChannel(
+		config.PortConfig{Type: config.PortTypeUDP, Address: "127.0.0.1:0", SideDesignation: "A1"},
+		config.AppConfig{},
+		nil,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	ch.conn = conn
+	go ch.readLoop()
+
+	// Backoff doubles from 10ms, so 300ms allows only a handful of reads
+	time.Sleep(300 * time.Millisecond)
+	if reads := conn.reads.Load(); reads < 2 || reads > 8 {
+		t.Errorf("reads in 300ms = %d, want a handful with backoff", reads)
+	}
+
+	// Stop ends the backoff wait rather than sleeping it out
+	start := time.Now()
+	ch.Stop()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Stop() took %v during backoff, want prompt", elapsed)
+	}
+	if got := ch.GetStats().Errors; got != int64(conn.reads.Load()) {
+		t.Errorf("Errors = %d, want one per failed read (%d)", got, conn.reads.Load())
+	}
+}
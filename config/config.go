@@ -3,52 +3,131 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Config is the root configuration structure
 type Config struct {
-	App        AppConfig        `json:"app"`
-	Ports      []PortConfig     `json:"ports"`
-	Detection  DetectionConfig  `json:"detection"`
-	NATS       NATSConfig       `json:"nats"`
-	Logging    LoggingConfig    `json:"logging"`
-	Monitoring MonitoringConfig `json:"monitoring"`
-	Recovery   RecoveryConfig   `json:"recovery"`
-	Forwarder  ForwarderConfig  `json:"forwarder"`
+	SchemaVersion int              `json:"schema_version"` // Config format version; older files are migrated on load (missing = 0)
+	App           AppConfig        `json:"app"`
+	Ports         []PortConfig     `json:"ports"`
+	Detection     DetectionConfig  `json:"detection"`
+	NATS          NATSConfig       `json:"nats"`
+	Logging       LoggingConfig    `json:"logging"`
+	Monitoring    MonitoringConfig `json:"monitoring"`
+	Recovery      RecoveryConfig   `json:"recovery"`
+	Forwarder     ForwarderConfig  `json:"forwarder"`
+
+	loadedSchemaVersion int // SchemaVersion as read from the file, before migration
+
+	// Set when loaded from a config directory (see BaseConfigFile)
+	fragments     map[string]string // Port ID -> ports.d file it was loaded from
+	fragmentFiles []string          // Every ports.d file, in load order
 }
 
 // AppConfig contains application-level settings
 type AppConfig struct {
-	Name       string `json:"name"`
-	InstanceID string `json:"instance_id"`
-	FIPSCode   string `json:"fips_code"` // Default FIPS code for all ports
+	Name               string `json:"name"`
+	InstanceID         string `json:"instance_id"`
+	FIPSCode           string `json:"fips_code"`           // Default FIPS code for all ports
+	TimeZone           string `json:"time_zone"`           // IANA zone for record header timestamps, e.g. "America/Chicago" (default: UTC)
+	TimestampPrecision string `json:"timestamp_precision"` // Header fractional seconds: "ms" (default), "us" or "ns"
 }
 
 // PortType constants
 const (
 	PortTypeSerial = "serial" // Default: serial port capture
 	PortTypeHTTP   = "http"   // HTTP POST endpoint capture
+	PortTypeTCP    = "tcp"    // Raw TCP stream from a terminal server (serial-over-IP)
+	PortTypeUDP    = "udp"    // UDP datagrams, one record each (syslog-style)
+	PortTypeFile   = "file"   // Replay of a captured log file (testing)
 )
 
-// PortConfig defines configuration for a capture channel (serial or HTTP)
+// CaptureHealthPath is the unauthenticated liveness route on dedicated HTTP
+// capture ports, reserved so a capture endpoint can't shadow it
+const CaptureHealthPath = "/healthz"
+
+// PortConfig defines configuration for a capture channel (serial, TCP, UDP, HTTP or file replay)
 type PortConfig struct {
-	Type            string  `json:"type"`             // "serial" (default) or "http"
-	Device          string  `json:"device"`           // Serial: e.g., "/dev/ttyUSB0"
-	Path            string  `json:"path"`             // HTTP: endpoint path, e.g., "/cdr"
-	ListenPort      int     `json:"listen_port"`      // HTTP: port to listen on (0 = use monitoring port)
-	SideDesignation string  `json:"side_designation"` // "A1" through "A16" or "B1" through "B16"
-	FIPSCode        string  `json:"fips_code"`        // Optional override for this port
-	Vendor          string  `json:"vendor"`           // CPE vendor: "intrado", "solacom", "zetron", "vesta", etc.
-	County          string  `json:"county"`           // County name (lowercase): "lancaster", "douglas", etc.
-	BaudRate        int     `json:"baud_rate"`        // Serial: 0 = auto-detect
-	DataBits        int     `json:"data_bits"`        // Serial: 5, 6, 7, or 8 (default: 8)
-	Parity          string  `json:"parity"`           // Serial: "none", "odd", "even", "mark", "space" (default: "none")
-	StopBits        float64 `json:"stop_bits"`        // Serial: 1, 1.5, or 2 (default: 1)
-	UseFlowControl  *bool   `json:"use_flow_control"` // Serial: nil = auto-detect
-	Enabled         bool    `json:"enabled"`
-	Description     string  `json:"description"`
+	Type                  string   `json:"type"`                     // "serial" (default), "tcp", "udp", "http" or "file"
+	Device                string   `json:"device"`                   // Serial: e.g., "/dev/ttyUSB0"
+	Address               string   `json:"address"`                  // TCP: terminal server host:port, e.g., "10.0.0.5:4001"; UDP: listen address, e.g., ":5140"
+	DeviceByID            string   `json:"device_by_id"`             // Serial: stable symlink, e.g., "/dev/serial/by-id/usb-FTDI_..." (overrides device when opening)
+	Path                  string   `json:"path"`                     // HTTP: endpoint path, e.g., "/cdr"; a trailing "/" also captures sub-paths ("/cdr/" takes /cdr/{session-id})
+	ListenPort            int      `json:"listen_port"`              // HTTP: port to listen on (0 = use monitoring port)
+	BindAddress           string   `json:"bind_address"`             // HTTP: interface for listen_port, e.g., "10.0.0.5" or "fd00::5" (empty = all)
+	SideDesignation       string   `json:"side_designation"`         // "A1" through "A16" or "B1" through "B16"
+	FIPSCode              string   `json:"fips_code"`                // Optional override for this port
+	Vendor                string   `json:"vendor"`                   // CPE vendor: "intrado", "solacom", "zetron", "vesta", etc.
+	County                string   `json:"county"`                   // County name (lowercase): "lancaster", "douglas", etc.
+	BaudRate              int      `json:"baud_rate"`                // Serial: 0 = auto-detect
+	AllowCustomBaud       bool     `json:"allow_custom_baud"`        // Serial: accept a baud_rate outside the standard set (e.g., 230400)
+	DataBits              int      `json:"data_bits"`                // Serial: 5, 6, 7, or 8 (default: 8)
+	Parity                string   `json:"parity"`                   // Serial: "none", "odd", "even", "mark", "space" (default: "none")
+	StopBits              float64  `json:"stop_bits"`                // Serial: 1, 1.5, or 2 (default: 1)
+	UseFlowControl        *bool    `json:"use_flow_control"`         // Serial: nil = auto-detect
+	FlowControl           string   `json:"flow_control"`             // Serial: "none", "hardware", "software" (overrides use_flow_control)
+	UseModemSignalState   bool     `json:"use_modem_signal_state"`   // Serial: enter no_signal when DCD and DSR drop and data stops (opt-in; many devices never assert them)
+	IdleGapMs             int      `json:"idle_gap_ms"`              // Serial: end a record after this much silence (0 = split on newline)
+	TLSCertFile           string   `json:"tls_cert_file"`            // HTTP: serve HTTPS with this certificate (requires listen_port)
+	TLSKeyFile            string   `json:"tls_key_file"`             // HTTP: private key for tls_cert_file
+	TLSClientCAFile       string   `json:"tls_client_ca"`            // HTTP: require client certs signed by this CA (mutual TLS)
+	AuthToken             string   `json:"auth_token"`               // HTTP: require "Authorization: Bearer <token>" (empty = open)
+	HMACSecret            string   `json:"hmac_secret"`              // HTTP: require an HMAC-SHA256 of the body signed with this secret
+	HMACHeader            string   `json:"hmac_header"`              // HTTP: header carrying the hex signature (default: X-Signature)
+	AllowedCIDRs          []string `json:"allowed_cidrs"`            // HTTP: only accept requests from these ranges (empty = any)
+	TrustedProxies        []string `json:"trusted_proxies"`          // HTTP: proxies whose X-Forwarded-For is honored for allowed_cidrs
+	MaxBodyBytes          int64    `json:"max_body_bytes"`           // HTTP: reject larger bodies (0 = 50MB default)
+	MaxConcurrentRequests int      `json:"max_concurrent_requests"`  // HTTP: answer 503 beyond this many in-flight requests (0 = unlimited)
+	AllowedMethods        []string `json:"allowed_methods"`          // HTTP: "POST", "PUT", "GET" (default: POST only)
+	AllowedContentTypes   []string `json:"allowed_content_types"`    // HTTP: accept only these media types, e.g., ["application/xml"] (empty = any)
+	ResponseStatus        int      `json:"response_status"`          // HTTP: success status returned to the sender (default: 200)
+	ResponseBody          string   `json:"response_body"`            // HTTP: success body returned verbatim (default: {"status":"ok"})
+	ResponseContentType   string   `json:"response_content_type"`    // HTTP: Content-Type of response_body (default: application/json)
+	CompressPayload       bool     `json:"compress_payload"`         // gzip NATS payloads (Content-Encoding: gzip header); log stays plain
+	ExtraSubjects         []string `json:"extra_subjects"`           // Also publish each record to these NATS subjects (e.g., an archival hierarchy); failures don't fail the write
+	AppendChecksum        string   `json:"append_checksum"`          // Append a hash of the device data to each record: "crc32" or "sha256" (truncated); empty = off
+	TimestampRegex        string   `json:"timestamp_regex"`          // Take the header time from data matching this (first group, else whole match)
+	TimestampLayout       string   `json:"timestamp_layout"`         // Go time layout for the timestamp_regex match, e.g. "01/02/06 15:04:05"
+	TimestampTZ           string   `json:"timestamp_tz"`             // IANA zone of embedded timestamps, e.g. "America/Chicago" (default: UTC)
+	DedupeWindowMs        int      `json:"dedupe_window_ms"`         // Serial: suppress identical consecutive lines within this long of the first (0 = off)
+	DedupeCount           int      `json:"dedupe_count"`             // Serial: suppress at most this many repeats before writing one again (0 = off)
+	DedupeExempt          string   `json:"dedupe_exempt"`            // Serial: lines matching this regex are never deduped (e.g., keepalives)
+	MaxLinesPerSec        int      `json:"max_lines_per_sec"`        // Serial: drop lines beyond this rate (0 = unlimited)
+	MaxLineBytes          int      `json:"max_line_bytes"`           // Serial: longest line accepted (0 = 1MB)
+	OversizeLines         string   `json:"oversize_lines"`           // Serial: "truncate" (default, keep the first max_line_bytes) or "drop"
+	StallAfterSec         int      `json:"stall_after_sec"`          // Serial: flag the channel stalled after this long without a line (0 = off; PSAP feeds can be quiet for hours)
+	ReplayFile            string   `json:"replay_file"`              // File: captured log (or raw CDR) to replay, one record per line
+	ReplayPaced           bool     `json:"replay_paced"`             // File: replay at the pace of the header timestamps (default: as fast as possible)
+	MaxSessionDurationSec int      `json:"max_session_duration_sec"` // Serial: overrides recovery.max_session_duration_sec for this port (0 = use recovery's)
+	RecordPreview         bool     `json:"record_preview"`           // Serial/HTTP: keep the last record (truncated) for GET /api/ports/config/{id}; may contain PII
+	Enabled               bool     `json:"enabled"`
+	Description           string   `json:"description"`
+
+	// Serial: mask PII (e.g., caller numbers) before records are logged or
+	// published. The log and CDR subject only ever hold the redacted form.
+	RedactionRules    []RedactionRule `json:"redaction_rules"`
+	UnredactedSubject string          `json:"unredacted_subject"` // Also publish the original record here, a subject only authorized consumers can read (empty = never)
+
+	// Serial: whether opening the port raises DTR and RTS (nil = raise, as
+	// Scannex does). Set assert_dtr false for devices that reset when DTR
+	// toggles and drop their first records after every reconnect.
+	AssertDTR *bool `json:"assert_dtr,omitempty"`
+	AssertRTS *bool `json:"assert_rts,omitempty"`
+}
+
+// RedactionRule replaces matches of Pattern (a Go regexp) with Replacement,
+// which may reference groups as $1, e.g. {"pattern": "\\d{3}-\\d{4}", "replacement": "XXX-XXXX"}
+type RedactionRule struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
 }
 
 // IsSerial returns true if this is a serial port config
@@ -56,16 +135,85 @@ func (p *PortConfig) IsSerial() bool {
 	return p.Type == "" || p.Type == PortTypeSerial
 }
 
+// IsTCP returns true if this is a TCP stream source config
+func (p *PortConfig) IsTCP() bool {
+	return p.Type == PortTypeTCP
+}
+
+// IsUDP returns true if this is a UDP datagram listener config
+func (p *PortConfig) IsUDP() bool {
+	return p.Type == PortTypeUDP
+}
+
+// IsFile returns true if this is a file replay config
+func (p *PortConfig) IsFile() bool {
+	return p.Type == PortTypeFile
+}
+
+// Source returns what a line-oriented port reads from: the device path for
+// serial ports, host:port for TCP ports, the replay file for file ports
+func (p *PortConfig) Source() string {
+	if p.IsTCP() {
+		return p.Address
+	}
+	if p.IsFile() {
+		return p.ReplayFile
+	}
+	return p.Device
+}
+
 // IsHTTP returns true if this is an HTTP endpoint config
 func (p *PortConfig) IsHTTP() bool {
 	return p.Type == PortTypeHTTP
 }
 
+// IsPrefixPath returns true if this HTTP endpoint captures every sub-path
+// of Path (Path ends in "/")
+func (p *PortConfig) IsPrefixPath() bool {
+	return len(p.Path) > 1 && strings.HasSuffix(p.Path, "/")
+}
+
+// PathMatches reports whether a request path is served by this HTTP endpoint
+func (p *PortConfig) PathMatches(path string) bool {
+	if p.IsPrefixPath() {
+		return strings.HasPrefix(path, p.Path)
+	}
+	return path == p.Path
+}
+
+// UsesTLS returns true if this HTTP endpoint is served over HTTPS
+func (p *PortConfig) UsesTLS() bool {
+	return p.TLSCertFile != ""
+}
+
+// HTTPMethods returns the request methods an HTTP endpoint accepts
+func (p *PortConfig) HTTPMethods() []string {
+	if len(p.AllowedMethods) == 0 {
+		return []string{"POST"}
+	}
+	return p.AllowedMethods
+}
+
+// ParseCIDRs parses a list of CIDR ranges, e.g. allowed_cidrs
+func ParseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
 // DetectionConfig contains parameters for autobaud and pinout detection
 type DetectionConfig struct {
 	BaudRates           []int `json:"baud_rates"`            // List of baud rates to try
 	DetectionTimeoutSec int   `json:"detection_timeout_sec"` // Timeout per detection attempt
 	MinBytesForValid    int   `json:"min_bytes_for_valid"`   // Minimum bytes to consider valid
+	DetectFraming       bool  `json:"detect_framing"`        // Also sweep data bits/parity (8N1, 7E1, 7O1) not set on the port - slower
+	AllowCustomBaud     bool  `json:"allow_custom_baud"`     // Accept non-standard rates in baud_rates and on every port
 }
 
 // NATSConfig contains NATS JetStream connection settings
@@ -74,6 +222,46 @@ type NATSConfig struct {
 	SubjectPrefix    string `json:"subject_prefix"`     // Prefix for subjects (e.g., "serial")
 	MaxReconnects    int    `json:"max_reconnects"`     // Max reconnection attempts
 	ReconnectWaitSec int    `json:"reconnect_wait_sec"` // Wait between reconnects
+	ConnectWaitSec   int    `json:"connect_wait_sec"`   // Startup keeps retrying an unreachable server this long before failing (default: 30)
+	// Durable consumers whose lag is reported in /api/stats (the forwarder's is added automatically)
+	Consumers []ConsumerConfig `json:"consumers"`
+	// EnsureStreams creates missing JetStream streams on startup (existing ones
+	// are never modified, only logged if they differ); off by default since
+	// streams are normally provisioned with the NATS server
+	EnsureStreams bool           `json:"ensure_streams"`
+	Streams       []StreamConfig `json:"streams"` // Streams to provision (default: cdr, health, events)
+}
+
+// StreamConfig describes a JetStream stream provisioned by ensure_streams
+type StreamConfig struct {
+	Name        string   `json:"name"`          // e.g., "cdr"
+	Subjects    []string `json:"subjects"`      // e.g., ["*.cdr.>"]
+	Retention   string   `json:"retention"`     // "limits" (default), "interest", or "workqueue"
+	MaxAgeHours int      `json:"max_age_hours"` // 0 = keep forever
+	MaxBytes    int64    `json:"max_bytes"`     // 0 = unlimited
+}
+
+// DefaultStreams mirrors the streams created by the NATS Ansible playbook
+func DefaultStreams() []StreamConfig {
+	return []StreamConfig{
+		{Name: "cdr", Subjects: []string{"*.cdr.>"}, Retention: "limits", MaxBytes: 5 << 30},
+		{Name: "health", Subjects: []string{"*.health.>"}, Retention: "limits", MaxAgeHours: 30 * 24, MaxBytes: 1 << 30},
+		{Name: "events", Subjects: []string{"*.events.>"}, Retention: "limits", MaxBytes: 500 << 20},
+	}
+}
+
+// StreamConfigs returns the streams ensure_streams provisions
+func (n *NATSConfig) StreamConfigs() []StreamConfig {
+	if len(n.Streams) == 0 {
+		return DefaultStreams()
+	}
+	return n.Streams
+}
+
+// ConsumerConfig names a JetStream durable consumer
+type ConsumerConfig struct {
+	Stream string `json:"stream"` // e.g., "cdr"
+	Name   string `json:"name"`   // Durable name
 }
 
 // LoggingConfig contains logging and log rotation settings
@@ -83,40 +271,250 @@ type LoggingConfig struct {
 	MaxBackups int    `json:"max_backups"` // Max number of old log files
 	Compress   bool   `json:"compress"`    // Compress rotated logs
 	Level      string `json:"level"`       // Log level: debug, info, warn, error
+	// LogFilenameTemplate names channel log files relative to base_path.
+	// Tokens: {fips}, {a}, {county}, {instance}; may contain subdirectories,
+	// e.g. "{county}/{fips}-{a}.log" (default: "{fips}-{a}.log")
+	LogFilenameTemplate string `json:"log_filename_template"`
+	// Syslog optionally ships operational logs to a central syslog server
+	Syslog SyslogConfig `json:"syslog"`
+	// Sinks lists where operational logs go, any of "file", "stdout" and
+	// "syslog" at once. Unset means the file, plus syslog when enabled.
+	Sinks []string `json:"sinks"`
+	// WriteBufferKB buffers channel log writes in memory for busy ports,
+	// flushed when full, every flush_interval_ms and on shutdown (0 = write
+	// each record immediately). NATS publishes are never delayed.
+	WriteBufferKB   int `json:"write_buffer_kb"`
+	FlushIntervalMs int `json:"flush_interval_ms"` // Max time a record sits in the buffer (default: 1000)
+}
+
+// DefaultFlushIntervalMs is used when flush_interval_ms is unset
+const DefaultFlushIntervalMs = 1000
+
+// Operational log sinks for LoggingConfig.Sinks
+const (
+	LogSinkFile   = "file"   // Rotating nectarcollector.log under base_path
+	LogSinkStdout = "stdout" // Text to standard output (e.g., journald, containers)
+	LogSinkSyslog = "syslog" // RFC 5424 to the server in Syslog
+)
+
+// LogSinks returns the operational log sinks in effect: Sinks if set,
+// otherwise the file plus syslog per the Syslog settings
+func (l *LoggingConfig) LogSinks() []string {
+	if len(l.Sinks) > 0 {
+		return l.Sinks
+	}
+	if !l.Syslog.Enabled {
+		return []string{LogSinkFile}
+	}
+	if l.Syslog.Exclusive {
+		return []string{LogSinkSyslog}
+	}
+	return []string{LogSinkFile, LogSinkSyslog}
+}
+
+// SyslogConfig contains settings for the RFC 5424 syslog log sink
+type SyslogConfig struct {
+	Enabled   bool   `json:"enabled"`   // Send operational logs to syslog
+	Network   string `json:"network"`   // "udp" or "tcp" (default: udp)
+	Address   string `json:"address"`   // host:port of the syslog server, e.g., "logs.example.net:514"
+	Facility  string `json:"facility"`  // e.g., "daemon", "local0" (default: local0)
+	Exclusive bool   `json:"exclusive"` // Log only to syslog instead of also to file/stdout
+}
+
+// DefaultLogFilenameTemplate produces the historical FIPS-designation.log names
+const DefaultLogFilenameTemplate = "{fips}-{a}.log"
+
+// logFilenameTokens are the placeholders recognized in LogFilenameTemplate
+var logFilenameTokens = []string{"{fips}", "{a}", "{county}", "{instance}"}
+
+// LogFilename expands LogFilenameTemplate into a path relative to BasePath.
+// An empty county expands to "unknown" so the path never has an empty segment.
+func (l *LoggingConfig) LogFilename(fips, sideDesignation, county, instance string) string {
+	tmpl := l.LogFilenameTemplate
+	if tmpl == "" {
+		tmpl = DefaultLogFilenameTemplate
+	}
+	if county == "" {
+		county = "unknown"
+	}
+	r := strings.NewReplacer(
+		"{fips}", fips,
+		"{a}", sideDesignation,
+		"{county}", county,
+		"{instance}", instance,
+	)
+	return filepath.Clean(r.Replace(tmpl))
+}
+
+// LogPath returns the full path of a channel's log file
+func (l *LoggingConfig) LogPath(fips, sideDesignation, county, instance string) string {
+	return filepath.Join(l.BasePath, l.LogFilename(fips, sideDesignation, county, instance))
 }
 
 // MonitoringConfig contains HTTP monitoring server settings
 type MonitoringConfig struct {
-	Port     int    `json:"port"`     // HTTP port for monitoring endpoints
-	Username string `json:"username"` // Basic auth username (empty = no auth)
-	Password string `json:"password"` // Basic auth password
+	Port            int      `json:"port"`              // HTTP port for monitoring endpoints
+	BindAddress     string   `json:"bind_address"`      // Interface to listen on, e.g., "127.0.0.1" or "::1" (empty = all)
+	Username        string   `json:"username"`          // Basic auth username (empty = no auth)
+	Password        string   `json:"password"`          // Basic auth password
+	AllowedOrigins  []string `json:"allowed_origins"`   // CORS origins allowed to call /api/* (empty = no CORS, "*" = any)
+	SSEKeepaliveSec int      `json:"sse_keepalive_sec"` // Seconds between SSE keepalive comments (default: 15)
+	SSEClientBuffer int      `json:"sse_client_buffer"` // Lines queued per SSE client before dropping (default: 64)
+	AccessLog       bool     `json:"access_log"`        // Log every request (method, path, status, bytes, remote, duration) - never bodies
+	// Device -> label shown by /api/ports, e.g. {"/dev/ttyUSB0": "Front desk"}
+	// (empty = /dev/ttyS1-5 as COM2-6, ttyS0 being the console)
+	PortLabels map[string]string `json:"port_labels"`
+	// HTTP server timeouts, applied to the monitoring server and dedicated
+	// capture ports. SSE streams are exempt from the read and write timeouts.
+	ReadHeaderTimeoutSec int `json:"read_header_timeout_sec"` // Time to send request headers (default: 10)
+	ReadTimeoutSec       int `json:"read_timeout_sec"`        // Time to send the whole request, body included (default: 60)
+	WriteTimeoutSec      int `json:"write_timeout_sec"`       // Time to write the response (default: 60)
+	IdleTimeoutSec       int `json:"idle_timeout_sec"`        // Keep-alive connections idle longer are closed (default: 120)
+	// Bounds the stream reads behind one /api/events request: at most this
+	// many windows of `count` events are scanned for matches (default: 10)
+	EventScanWindows int `json:"event_scan_windows"`
+}
+
+// SSE defaults, also used when MonitoringConfig values are unset
+const (
+	DefaultSSEKeepaliveSec = 15
+	DefaultSSEClientBuffer = 64
+)
+
+// HTTP server timeout defaults, also used when MonitoringConfig values are unset
+const (
+	DefaultReadHeaderTimeoutSec = 10
+	DefaultReadTimeoutSec       = 60
+	DefaultWriteTimeoutSec      = 60
+	DefaultIdleTimeoutSec       = 120
+)
+
+// ListenAddr returns the host:port the monitoring server binds to
+func (m *MonitoringConfig) ListenAddr() string {
+	return listenAddr(m.BindAddress, m.Port)
+}
+
+// listenAddr joins a bind address and port, bracketing IPv6 literals.
+// An empty host listens on all interfaces.
+func listenAddr(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// SSEKeepalive returns the interval between SSE keepalive comments
+func (m *MonitoringConfig) SSEKeepalive() time.Duration {
+	if m.SSEKeepaliveSec <= 0 {
+		return DefaultSSEKeepaliveSec * time.Second
+	}
+	return time.Duration(m.SSEKeepaliveSec) * time.Second
+}
+
+// ReadHeaderTimeout returns how long a client may take to send request headers
+func (m *MonitoringConfig) ReadHeaderTimeout() time.Duration {
+	return secondsOrDefault(m.ReadHeaderTimeoutSec, DefaultReadHeaderTimeoutSec)
+}
+
+// ReadTimeout returns how long a client may take to send a whole request
+func (m *MonitoringConfig) ReadTimeout() time.Duration {
+	return secondsOrDefault(m.ReadTimeoutSec, DefaultReadTimeoutSec)
+}
+
+// WriteTimeout returns how long writing a response may take
+func (m *MonitoringConfig) WriteTimeout() time.Duration {
+	return secondsOrDefault(m.WriteTimeoutSec, DefaultWriteTimeoutSec)
+}
+
+// IdleTimeout returns how long an idle keep-alive connection is kept open
+func (m *MonitoringConfig) IdleTimeout() time.Duration {
+	return secondsOrDefault(m.IdleTimeoutSec, DefaultIdleTimeoutSec)
+}
+
+// secondsOrDefault converts a seconds setting, using def when it's unset
+func secondsOrDefault(sec, def int) time.Duration {
+	if sec <= 0 {
+		sec = def
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// DefaultPortLabels returns the stock device -> COM mapping for /api/ports
+func DefaultPortLabels() map[string]string {
+	return map[string]string{
+		"/dev/ttyS1": "COM2",
+		"/dev/ttyS2": "COM3",
+		"/dev/ttyS3": "COM4",
+		"/dev/ttyS4": "COM5",
+		"/dev/ttyS5": "COM6",
+	}
+}
+
+// Labels returns the device -> label mapping for /api/ports
+func (m *MonitoringConfig) Labels() map[string]string {
+	if len(m.PortLabels) == 0 {
+		return DefaultPortLabels()
+	}
+	return m.PortLabels
+}
+
+// DefaultEventScanWindows bounds /api/events scans when event_scan_windows is unset
+const DefaultEventScanWindows = 10
+
+// EventScanWindowLimit returns how many windows /api/events may scan per request
+func (m *MonitoringConfig) EventScanWindowLimit() int {
+	if m.EventScanWindows <= 0 {
+		return DefaultEventScanWindows
+	}
+	return m.EventScanWindows
+}
+
+// SSEClientBufferSize returns how many lines each SSE client can queue
+func (m *MonitoringConfig) SSEClientBufferSize() int {
+	if m.SSEClientBuffer <= 0 {
+		return DefaultSSEClientBuffer
+	}
+	return m.SSEClientBuffer
 }
 
 // RecoveryConfig contains reconnection and recovery settings
 type RecoveryConfig struct {
-	ReconnectDelaySec    int  `json:"reconnect_delay_sec"`     // Initial reconnect delay
-	MaxReconnectDelaySec int  `json:"max_reconnect_delay_sec"` // Maximum reconnect delay
-	ExponentialBackoff   bool `json:"exponential_backoff"`     // Use exponential backoff
+	ReconnectDelaySec         int  `json:"reconnect_delay_sec"`          // Initial reconnect delay
+	MaxReconnectDelaySec      int  `json:"max_reconnect_delay_sec"`      // Maximum reconnect delay
+	ExponentialBackoff        bool `json:"exponential_backoff"`          // Use exponential backoff
+	ReconnectEventIntervalSec int  `json:"reconnect_event_interval_sec"` // Publish at most one reconnect event per this long (default: 60)
+	MaxSessionDurationSec     int  `json:"max_session_duration_sec"`     // Close and reopen each port after this long (0 = never); clears adapters that wedge
+	StopGraceMs               int  `json:"stop_grace_ms"`                // How long Stop waits for a channel's read loop before force-closing its port (default: 1000)
+	ReconnectJitterPct        int  `json:"reconnect_jitter_pct"`         // Randomize reconnect delays (serial and NATS) by up to this percent so a fleet doesn't retry in lockstep (0 = off)
 }
 
+// DefaultStopGraceMs is used when stop_grace_ms is unset
+const DefaultStopGraceMs = 1000
+
+// DefaultReconnectEventIntervalSec is used when reconnect_event_interval_sec is unset
+const DefaultReconnectEventIntervalSec = 60
+
 // ForwarderConfig contains settings for forwarding CDR data to a remote NATS server
 type ForwarderConfig struct {
 	Enabled       bool   `json:"enabled"`        // Enable forwarding to remote NATS
 	RemoteURL     string `json:"remote_url"`     // Remote NATS server URL (e.g., "nats://remote:4222")
 	RemoteSubject string `json:"remote_subject"` // Explicit subject to publish to (e.g., "ne.cdr.psna-ne-northeast-norfolk-01.1315010001")
 	RemoteCreds   string `json:"remote_creds"`   // Path to NATS credentials file (optional)
+
+	// MaxForwardPerSec paces forwarding so catch-up after an outage doesn't
+	// saturate a metered WAN link (0 = unlimited)
+	MaxForwardPerSec int `json:"max_forward_per_sec"`
 }
 
-// Load reads and parses the configuration file
+// Load reads and parses the configuration file, or a config directory of a
+// base config plus ports.d fragments (see BaseConfigFile). Fragments are
+// merged before validation, so the checks see the site's whole config.
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	cfg, err := readConfig(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+	// Bring older config formats up to date before defaults fill the gaps
+	if err := cfg.migrate(); err != nil {
+		return nil, err
 	}
 
 	// Set defaults
@@ -127,6 +525,24 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	return cfg, nil
+}
+
+// readConfig parses a config file or directory without defaults or validation
+func readConfig(path string) (*Config, error) {
+	if isDir(path) {
+		return readConfigDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
 	return &cfg, nil
 }
 
@@ -189,11 +605,37 @@ func (c *Config) setDefaults() {
 	if c.Logging.Level == "" {
 		c.Logging.Level = "info"
 	}
+	if slices.Contains(c.Logging.LogSinks(), LogSinkSyslog) {
+		if c.Logging.Syslog.Network == "" {
+			c.Logging.Syslog.Network = "udp"
+		}
+		if c.Logging.Syslog.Facility == "" {
+			c.Logging.Syslog.Facility = "local0"
+		}
+	}
 
 	// Monitoring defaults
 	if c.Monitoring.Port == 0 {
 		c.Monitoring.Port = 8080
 	}
+	if c.Monitoring.SSEKeepaliveSec == 0 {
+		c.Monitoring.SSEKeepaliveSec = DefaultSSEKeepaliveSec
+	}
+	if c.Monitoring.SSEClientBuffer == 0 {
+		c.Monitoring.SSEClientBuffer = DefaultSSEClientBuffer
+	}
+	if c.Monitoring.ReadHeaderTimeoutSec == 0 {
+		c.Monitoring.ReadHeaderTimeoutSec = DefaultReadHeaderTimeoutSec
+	}
+	if c.Monitoring.ReadTimeoutSec == 0 {
+		c.Monitoring.ReadTimeoutSec = DefaultReadTimeoutSec
+	}
+	if c.Monitoring.WriteTimeoutSec == 0 {
+		c.Monitoring.WriteTimeoutSec = DefaultWriteTimeoutSec
+	}
+	if c.Monitoring.IdleTimeoutSec == 0 {
+		c.Monitoring.IdleTimeoutSec = DefaultIdleTimeoutSec
+	}
 
 	// Recovery defaults
 	if c.Recovery.ReconnectDelaySec == 0 {
@@ -202,6 +644,9 @@ func (c *Config) setDefaults() {
 	if c.Recovery.MaxReconnectDelaySec == 0 {
 		c.Recovery.MaxReconnectDelaySec = 60 // Cap at 1 minute
 	}
+	if c.Recovery.ReconnectEventIntervalSec == 0 {
+		c.Recovery.ReconnectEventIntervalSec = DefaultReconnectEventIntervalSec
+	}
 }
 
 // Helper methods for time conversions
@@ -213,6 +658,16 @@ func (n *NATSConfig) ReconnectWait() time.Duration {
 	return time.Duration(n.ReconnectWaitSec) * time.Second
 }
 
+// DefaultNATSConnectWaitSec is how long startup waits for NATS when
+// connect_wait_sec is unset
+const DefaultNATSConnectWaitSec = 30
+
+// ConnectWait returns how long startup retries the initial NATS connection
+// (DefaultNATSConnectWaitSec when connect_wait_sec is 0)
+func (n *NATSConfig) ConnectWait() time.Duration {
+	return secondsOrDefault(n.ConnectWaitSec, DefaultNATSConnectWaitSec)
+}
+
 func (r *RecoveryConfig) ReconnectDelay() time.Duration {
 	return time.Duration(r.ReconnectDelaySec) * time.Second
 }
@@ -221,16 +676,67 @@ func (r *RecoveryConfig) MaxReconnectDelay() time.Duration {
 	return time.Duration(r.MaxReconnectDelaySec) * time.Second
 }
 
-// Save writes the configuration to a file atomically
+func (r *RecoveryConfig) ReconnectEventInterval() time.Duration {
+	return time.Duration(r.ReconnectEventIntervalSec) * time.Second
+}
+
+// MaxSessionDuration returns max_session_duration_sec as a duration: how long a
+// serial session runs before the port is closed and reopened (0 = never)
+func (r *RecoveryConfig) MaxSessionDuration() time.Duration {
+	return time.Duration(r.MaxSessionDurationSec) * time.Second
+}
+
+// ReconnectJitter returns reconnect_jitter_pct as a fraction (0.2 for 20)
+func (r *RecoveryConfig) ReconnectJitter() float64 {
+	return float64(r.ReconnectJitterPct) / 100
+}
+
+// StopGrace returns how long a stopping channel gets to leave its read loop
+// before the port is closed out from under it
+func (r *RecoveryConfig) StopGrace() time.Duration {
+	if r.StopGraceMs <= 0 {
+		return DefaultStopGraceMs * time.Millisecond
+	}
+	return time.Duration(r.StopGraceMs) * time.Millisecond
+}
+
+// FlushInterval returns how often buffered channel log writes are flushed
+func (l *LoggingConfig) FlushInterval() time.Duration {
+	if l.FlushIntervalMs <= 0 {
+		return DefaultFlushIntervalMs * time.Millisecond
+	}
+	return time.Duration(l.FlushIntervalMs) * time.Millisecond
+}
+
+// ConfigBackupsKept is how many timestamped .bak copies Save retains
+const ConfigBackupsKept = 5
+
+// Save writes the configuration to a file atomically. The temp file and its
+// directory are fsynced so a power loss can't leave a truncated or missing
+// config, and the previous config is kept as <path>.<timestamp>.bak. If path
+// is a config directory, each port is written back to its own file.
 func (c *Config) Save(path string) error {
+	if isDir(path) {
+		return c.saveDir(path)
+	}
+
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
+	return saveFile(path, data)
+}
+
+// saveFile atomically replaces path with data, backing up the old file
+func saveFile(path string, data []byte) error {
+	if err := backupConfig(path); err != nil {
+		return err
+	}
 
 	// Write to temp file first, then rename for atomic operation
 	tempPath := path + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+	if err := writeFileSync(tempPath, data, 0644); err != nil {
+		os.Remove(tempPath)
 		return fmt.Errorf("failed to write temp config file: %w", err)
 	}
 
@@ -239,16 +745,127 @@ func (c *Config) Save(path string) error {
 		return fmt.Errorf("failed to rename config file: %w", err)
 	}
 
+	// Persist the rename itself
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to sync config directory: %w", err)
+	}
+
 	return nil
 }
 
+// backupConfig copies the current config at path to a timestamped .bak and
+// prunes old backups beyond ConfigBackupsKept. A missing config is not an error.
+func backupConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config for backup: %w", err)
+	}
+
+	backupPath := fmt.Sprintf("%s.%s.bak", path, time.Now().UTC().Format("20060102T150405.000"))
+	if err := writeFileSync(backupPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config backup: %w", err)
+	}
+
+	// Timestamps sort lexically, so the oldest backups come first
+	backups, err := filepath.Glob(path + ".*.bak")
+	if err != nil {
+		return nil
+	}
+	sort.Strings(backups)
+	for i := 0; i < len(backups)-ConfigBackupsKept; i++ {
+		os.Remove(backups[i])
+	}
+
+	return nil
+}
+
+// writeFileSync writes data to path and fsyncs it before closing
+func writeFileSync(path string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir fsyncs a directory so renames within it survive a power loss
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// ListenAddr returns the host:port a dedicated HTTP capture server binds to
+func (p *PortConfig) ListenAddr() string {
+	return listenAddr(p.BindAddress, p.ListenPort)
+}
+
+// Oversize line policies for oversize_lines
+const (
+	OversizeTruncate = "truncate"
+	OversizeDrop     = "drop"
+)
+
+// MaxLineBytesLimit caps max_line_bytes so one port can't exhaust memory
+const MaxLineBytesLimit = 16 * 1024 * 1024
+
+// IdleGap returns the inter-record silence that ends a record (0 = newline framing)
+func (p *PortConfig) IdleGap() time.Duration {
+	return time.Duration(p.IdleGapMs) * time.Millisecond
+}
+
+// DedupeWindow returns how long identical consecutive lines are suppressed (0 = no time limit)
+func (p *PortConfig) DedupeWindow() time.Duration {
+	return time.Duration(p.DedupeWindowMs) * time.Millisecond
+}
+
+// StallAfter returns how long without a line marks the channel stalled (0 = never)
+func (p *PortConfig) StallAfter() time.Duration {
+	return time.Duration(p.StallAfterSec) * time.Second
+}
+
+// MaxSessionDuration returns the port's session length limit (0 = use the recovery default)
+func (p *PortConfig) MaxSessionDuration() time.Duration {
+	return time.Duration(p.MaxSessionDurationSec) * time.Second
+}
+
+// TimestampLocation returns the zone embedded timestamps are in (UTC unless timestamp_tz is set)
+func (p *PortConfig) TimestampLocation() (*time.Location, error) {
+	if p.TimestampTZ == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(p.TimestampTZ)
+}
+
 // ID returns a unique identifier for this port config
 // For serial: the device name without /dev/ prefix (e.g., "ttyS1")
 // For HTTP: the path (e.g., "/cdr")
+// For TCP and UDP: the address (e.g., "10.0.0.5:4001", ":5140")
+// For file: the replay file path
 func (p *PortConfig) ID() string {
 	if p.IsHTTP() {
 		return p.Path
 	}
+	if p.IsTCP() || p.IsUDP() {
+		return p.Address
+	}
+	if p.IsFile() {
+		return p.ReplayFile
+	}
 	// Strip /dev/ prefix if present
 	device := p.Device
 	if len(device) > 5 && device[:5] == "/dev/" {
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -126,3 +128,200 @@ func TestRecoveryConfigDelays(t *testing.T) {
 		t.Errorf("MaxReconnectDelay() = %v, want 300s", cfg.MaxReconnectDelay())
 	}
 }
+
+func TestLoggingConfigLogFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		county   string
+		want     string
+	}{
+		{"default template", "", "lancaster", "1429010002-A1.log"},
+		{"county subdirectory", "{county}/{fips}-{a}.log", "lancaster", "lancaster/1429010002-A1.log"},
+		{"all tokens", "{instance}/{county}/{a}-{fips}.log", "douglas", "psna-ne-01/douglas/A1-1429010002.log"},
+		{"empty county", "{county}/{fips}-{a}.log", "", "unknown/1429010002-A1.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := LoggingConfig{BasePath: "/var/log/nectarcollector", LogFilenameTemplate: tt.template}
+
+			got := cfg.LogFilename("1429010002", "A1", tt.county, "psna-ne-01")
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("LogFilename() = %q, want %q", got, tt.want)
+			}
+			if path := cfg.LogPath("1429010002", "A1", tt.county, "psna-ne-01"); path != filepath.Join(cfg.BasePath, got) {
+				t.Errorf("LogPath() = %q, want under base path", path)
+			}
+		})
+	}
+}
+
+func TestLoggingConfigLogSinks(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  LoggingConfig
+		want []string
+	}{
+		{"default", LoggingConfig{}, []string{"file"}},
+		{"syslog alongside file", LoggingConfig{Syslog: SyslogConfig{Enabled: true}}, []string{"file", "syslog"}},
+		{"syslog exclusive", LoggingConfig{Syslog: SyslogConfig{Enabled: true, Exclusive: true}}, []string{"syslog"}},
+		{"explicit sinks win", LoggingConfig{Sinks: []string{"stdout", "file"}, Syslog: SyslogConfig{Enabled: true}}, []string{"stdout", "file"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.LogSinks(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LogSinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveKeepsBackup(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	cfg := validConfig(t)
+	cfg.App.Name = "Original"
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("first Save() error = %v", err)
+	}
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// First save has nothing to back up
+	if backups, _ := filepath.Glob(configPath + ".*.bak"); len(backups) != 0 {
+		t.Errorf("backups after first save = %v, want none", backups)
+	}
+
+	// Simulate a truncated temp file left behind by a crash mid-write
+	if err := os.WriteFile(configPath+".tmp", []byte(`{"app":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.App.Name = "Updated"
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("second Save() error = %v", err)
+	}
+
+	backups, _ := filepath.Glob(configPath + ".*.bak")
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want 1", backups)
+	}
+	backup, err := os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != string(original) {
+		t.Error("backup does not match the previous config")
+	}
+
+	// Main file is complete and reflects the new config
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() after Save() error = %v", err)
+	}
+	if loaded.App.Name != "Updated" {
+		t.Errorf("App.Name = %q, want %q", loaded.App.Name, "Updated")
+	}
+	if _, err := os.Stat(configPath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temp file should not remain after Save()")
+	}
+}
+
+func TestSavePrunesBackups(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := validConfig(t)
+
+	// Stale backups from earlier saves
+	for i := 0; i < ConfigBackupsKept+3; i++ {
+		stale := fmt.Sprintf("%s.20240101T0000%02d.000.bak", configPath, i)
+		if err := os.WriteFile(stale, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, _ := filepath.Glob(configPath + ".*.bak")
+	if len(backups) != ConfigBackupsKept {
+		t.Fatalf("backups = %d, want %d", len(backups), ConfigBackupsKept)
+	}
+	if _, err := os.Stat(fmt.Sprintf("%s.20240101T000000.000.bak", configPath)); !os.IsNotExist(err) {
+		t.Error("oldest backup should have been pruned")
+	}
+}
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		name string
+		bind string
+		port int
+		want string
+	}{
+		{"all interfaces", "", 8080, ":8080"},
+		{"ipv4", "10.0.0.5", 8080, "10.0.0.5:8080"},
+		{"ipv6", "fd00::5", 8443, "[fd00::5]:8443"},
+		{"ipv6 loopback", "::1", 8080, "[::1]:8080"},
+		{"hostname", "mgmt.local", 9000, "mgmt.local:9000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mon := MonitoringConfig{BindAddress: tt.bind, Port: tt.port}
+			if got := mon.ListenAddr(); got != tt.want {
+				t.Errorf("MonitoringConfig.ListenAddr() = %q, want %q", got, tt.want)
+			}
+			port := PortConfig{Type: PortTypeHTTP, BindAddress: tt.bind, ListenPort: tt.port}
+			if got := port.ListenAddr(); got != tt.want {
+				t.Errorf("PortConfig.ListenAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortConfigPathMatches(t *testing.T) {
+	tests := []struct {
+		path    string
+		request string
+		want    bool
+	}{
+		{"/cdr", "/cdr", true},
+		{"/cdr", "/cdr/abc", false},
+		{"/cdr/", "/cdr/abc", true},
+		{"/cdr/", "/cdr/abc/def", true},
+		{"/cdr/", "/cdr/", true},
+		{"/cdr/", "/cdrx", false},
+		{"/cdr/", "/ali/abc", false},
+	}
+
+	for _, tt := range tests {
+		p := PortConfig{Type: PortTypeHTTP, Path: tt.path}
+		if got := p.PathMatches(tt.request); got != tt.want {
+			t.Errorf("PathMatches(%q) with path %q = %v, want %v", tt.request, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNATSConfigStreamConfigs(t *testing.T) {
+	var n NATSConfig
+	defaults := n.StreamConfigs()
+	names := make([]string, 0, len(defaults))
+	for _, s := range defaults {
+		names = append(names, s.Name)
+	}
+	if len(names) != 3 || names[0] != "cdr" || names[1] != "health" || names[2] != "events" {
+		t.Errorf("default streams = %v, want [cdr health events]", names)
+	}
+
+	n.Streams = []StreamConfig{{Name: "cdr", Subjects: []string{"ne.cdr.>"}}}
+	if got := n.StreamConfigs(); len(got) != 1 || got[0].Subjects[0] != "ne.cdr.>" {
+		t.Errorf("StreamConfigs() = %v, want configured streams", got)
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// A config directory splits a large site's config into files that can be
+// edited (and merged) independently, e.g. one ports file per county:
+//
+//	<dir>/config.json     the base config; may also hold ports
+//	<dir>/ports.d/*.json  {"ports": [...]}, appended in filename order
+//
+// Pass the directory as -config. Save writes each port back to the file it
+// was loaded from, and new ports to config.json.
+const (
+	BaseConfigFile = "config.json"
+	PortsDir       = "ports.d"
+)
+
+// portsFragment is the contents of a ports.d file
+type portsFragment struct {
+	Ports []PortConfig `json:"ports"`
+}
+
+// readConfigDir reads the base config and merges in every ports.d fragment.
+// A port defined in two files is an error naming both, since the merged
+// duplicate would otherwise only be reported by index.
+func readConfigDir(dir string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BaseConfigFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", BaseConfigFile, err)
+	}
+
+	// Glob sorts, so ports keep a stable order across loads
+	files, err := filepath.Glob(filepath.Join(dir, PortsDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", PortsDir, err)
+	}
+
+	definedIn := make(map[string]string, len(cfg.Ports))
+	for _, port := range cfg.Ports {
+		definedIn[port.ID()] = BaseConfigFile
+	}
+
+	cfg.fragments = make(map[string]string)
+	for _, file := range files {
+		name := filepath.Join(PortsDir, filepath.Base(file))
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		var frag portsFragment
+		if err := json.Unmarshal(data, &frag); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		for _, port := range frag.Ports {
+			id := port.ID()
+			if other, ok := definedIn[id]; ok {
+				return nil, fmt.Errorf("port %s is defined in both %s and %s", id, other, name)
+			}
+			definedIn[id] = name
+			cfg.fragments[id] = name
+		}
+		cfg.Ports = append(cfg.Ports, frag.Ports...)
+		cfg.fragmentFiles = append(cfg.fragmentFiles, name)
+	}
+
+	return &cfg, nil
+}
+
+// PortFile returns the file, relative to the config directory, that a port
+// is saved to: its ports.d fragment, or config.json
+func (c *Config) PortFile(id string) string {
+	if name, ok := c.fragments[id]; ok {
+		return name
+	}
+	return BaseConfigFile
+}
+
+// RenamePort keeps a port in its file when its ID changes, e.g. an HTTP
+// port's path is edited. Without it the port would be saved to config.json
+// and the old fragment entry left behind.
+func (c *Config) RenamePort(oldID, newID string) {
+	name, ok := c.fragments[oldID]
+	if !ok || oldID == newID {
+		return
+	}
+	delete(c.fragments, oldID)
+	c.fragments[newID] = name
+}
+
+// saveDir writes the config back across the directory's files. Only files
+// whose contents changed are rewritten (and backed up), so editing one
+// county's port leaves every other file untouched.
+func (c *Config) saveDir(dir string) error {
+	byFile := make(map[string][]PortConfig)
+	for _, port := range c.Ports {
+		file := c.PortFile(port.ID())
+		byFile[file] = append(byFile[file], port)
+	}
+
+	base := *c
+	base.Ports = byFile[BaseConfigFile]
+	if base.Ports == nil {
+		base.Ports = []PortConfig{}
+	}
+	data, err := json.MarshalIndent(&base, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := saveIfChanged(filepath.Join(dir, BaseConfigFile), data); err != nil {
+		return err
+	}
+
+	// A fragment whose ports were all removed is kept, empty
+	for _, name := range c.fragmentFiles {
+		frag := portsFragment{Ports: byFile[name]}
+		if frag.Ports == nil {
+			frag.Ports = []PortConfig{}
+		}
+		data, err := json.MarshalIndent(&frag, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		if err := saveIfChanged(filepath.Join(dir, name), data); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// saveIfChanged writes data to path via saveFile unless the file already
+// holds exactly data
+func saveIfChanged(path string, data []byte) error {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	return saveFile(path, data)
+}
+
+// isDir reports whether path is an existing directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfigDir writes validConfig as the base config plus the given
+// ports.d fragments, returning the directory
+func writeConfigDir(t *testing.T, fragments map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	data, err := json.MarshalIndent(validConfig(t), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, BaseConfigFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, PortsDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range fragments {
+		if err := os.WriteFile(filepath.Join(dir, PortsDir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadConfigDir(t *testing.T) {
+	dir := writeConfigDir(t, map[string]string{
+		"madison.json": `{"ports": [
+			{"type": "serial", "device": "/dev/ttyS3", "side_designation": "A3", "baud_rate": 9600, "enabled": true}
+		]}`,
+		"antelope.json": `{"ports": [
+			{"type": "serial", "device": "/dev/ttyS2", "side_designation": "A2", "baud_rate": 9600, "enabled": true},
+			{"type": "http", "path": "/cdr", "side_designation": "B1", "enabled": true}
+		]}`,
+		"notes.txt": `not a fragment`,
+	})
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Base ports first, then fragments in filename order
+	var ids []string
+	for _, port := range cfg.Ports {
+		ids = append(ids, port.ID())
+	}
+	if got, want := strings.Join(ids, ","), "ttyS1,ttyS2,/cdr,ttyS3"; got != want {
+		t.Errorf("merged ports = %s, want %s", got, want)
+	}
+
+	for id, want := range map[string]string{
+		"ttyS1": BaseConfigFile,
+		"ttyS2": filepath.Join(PortsDir, "antelope.json"),
+		"/cdr":  filepath.Join(PortsDir, "antelope.json"),
+		"ttyS3": filepath.Join(PortsDir, "madison.json"),
+	} {
+		if got := cfg.PortFile(id); got != want {
+			t.Errorf("PortFile(%s) = %s, want %s", id, got, want)
+		}
+	}
+}
+
+func TestLoadConfigDirErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		fragments map[string]string
+		wantErr   string
+	}{
+		{
+			name: "device in two fragments",
+			fragments: map[string]string{
+				"antelope.json": `{"ports": [{"type": "serial", "device": "/dev/ttyS2", "side_designation": "A2", "enabled": true}]}`,
+				"madison.json":  `{"ports": [{"type": "serial", "device": "/dev/ttyS2", "side_designation": "A3", "enabled": true}]}`,
+			},
+			wantErr: "port ttyS2 is defined in both ports.d/antelope.json and ports.d/madison.json",
+		},
+		{
+			name: "fragment repeats a base port",
+			fragments: map[string]string{
+				"madison.json": `{"ports": [{"type": "serial", "device": "/dev/ttyS1", "side_designation": "A3", "enabled": true}]}`,
+			},
+			wantErr: "port ttyS1 is defined in both config.json and ports.d/madison.json",
+		},
+		{
+			name: "merged config fails validation",
+			fragments: map[string]string{
+				"madison.json": `{"ports": [{"type": "serial", "device": "/dev/ttyS3", "side_designation": "A1", "enabled": true}]}`,
+			},
+			wantErr: "invalid configuration",
+		},
+		{
+			name:      "unparseable fragment",
+			fragments: map[string]string{"madison.json": `{"ports": [`},
+			wantErr:   "failed to parse ports.d/madison.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfigDir(t, tt.fragments))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveConfigDir(t *testing.T) {
+	dir := writeConfigDir(t, map[string]string{
+		"antelope.json": `{"ports": [{"type": "serial", "device": "/dev/ttyS2", "side_designation": "A2", "baud_rate": 9600, "enabled": true}]}`,
+		"madison.json":  `{"ports": [{"type": "serial", "device": "/dev/ttyS3", "side_designation": "A3", "baud_rate": 9600, "enabled": true}]}`,
+	})
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	// Normalize every file to Save's formatting so only real changes show
+	if err := cfg.Save(dir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	base, antelope := read(BaseConfigFile), read(filepath.Join(PortsDir, "antelope.json"))
+	antelopeBackups, _ := filepath.Glob(filepath.Join(dir, PortsDir, "antelope.json.*.bak"))
+
+	// Edit a madison port and add a new one
+	for i := range cfg.Ports {
+		if cfg.Ports[i].ID() == "ttyS3" {
+			cfg.Ports[i].Description = "Madison County 911"
+		}
+	}
+	cfg.Ports = append(cfg.Ports, PortConfig{Type: PortTypeSerial, Device: "/dev/ttyS4", SideDesignation: "A4", BaudRate: 9600})
+	if err := cfg.Save(dir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if got := read(filepath.Join(PortsDir, "antelope.json")); got != antelope {
+		t.Error("untouched fragment was rewritten")
+	}
+	if !strings.Contains(read(filepath.Join(PortsDir, "madison.json")), "Madison County 911") {
+		t.Error("edited port not written to its fragment")
+	}
+	if read(BaseConfigFile) == base || !strings.Contains(read(BaseConfigFile), "/dev/ttyS4") {
+		t.Error("new port not written to the base config")
+	}
+	if backups, _ := filepath.Glob(filepath.Join(dir, PortsDir, "antelope.json.*.bak")); len(backups) != len(antelopeBackups) {
+		t.Errorf("untouched fragment backed up: %v", backups)
+	}
+
+	// The saved directory loads back to the same ports
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() after Save() error = %v", err)
+	}
+	if len(loaded.Ports) != 4 || loaded.PortFile("ttyS4") != BaseConfigFile {
+		t.Errorf("reloaded %d ports, ttyS4 in %s; want 4 ports, ttyS4 in %s",
+			len(loaded.Ports), loaded.PortFile("ttyS4"), BaseConfigFile)
+	}
+}
+
+func TestSaveConfigDirRenamedPort(t *testing.T) {
+	dir := writeConfigDir(t, map[string]string{
+		"antelope.json": `{"ports": [{"type": "http", "path": "/cdr", "side_designation": "B1", "enabled": true}]}`,
+	})
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for i := range cfg.Ports {
+		if cfg.Ports[i].ID() == "/cdr" {
+			cfg.Ports[i].Path = "/cdr/antelope"
+		}
+	}
+	cfg.RenamePort("/cdr", "/cdr/antelope")
+	if err := cfg.Save(dir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() after Save() error = %v", err)
+	}
+	if len(loaded.Ports) != 2 {
+		t.Fatalf("reloaded %d ports, want 2", len(loaded.Ports))
+	}
+	if got, want := loaded.PortFile("/cdr/antelope"), filepath.Join(PortsDir, "antelope.json"); got != want {
+		t.Errorf("renamed port saved to %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import "fmt"
+
+// CurrentSchemaVersion is the config format this build writes. Bump it and
+// append to migrations whenever a field is renamed, moved or changes meaning.
+//
+// History:
+//   - 0: no schema_version field; ports without "type" are serial
+//   - 1: every port names its type explicitly
+const CurrentSchemaVersion = 1
+
+// migrations[i] upgrades a config from version i to i+1
+var migrations = []func(*Config){
+	migrateV0ToV1,
+}
+
+// migrate upgrades c in place to CurrentSchemaVersion. A version newer than
+// this build understands is an error: silently dropping fields we don't know
+// about would lose configuration.
+func (c *Config) migrate() error {
+	c.loadedSchemaVersion = c.SchemaVersion
+
+	if c.SchemaVersion < 0 {
+		return fmt.Errorf("invalid schema_version %d", c.SchemaVersion)
+	}
+	if c.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("config schema_version %d is newer than this build supports (%d) - upgrade nectarcollector",
+			c.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	for v := c.SchemaVersion; v < CurrentSchemaVersion; v++ {
+		migrations[v](c)
+		c.SchemaVersion = v + 1
+	}
+	return nil
+}
+
+// Migrated reports whether Load upgraded the file's schema, i.e. the file on
+// disk is older than the config in memory and can be rewritten with Save
+func (c *Config) Migrated() bool {
+	return c.loadedSchemaVersion < c.SchemaVersion
+}
+
+// LoadedSchemaVersion returns the schema_version the file was written with
+func (c *Config) LoadedSchemaVersion() int {
+	return c.loadedSchemaVersion
+}
+
+// migrateV0ToV1 makes the implicit serial port type explicit, so a file read
+// by a future version can't be reinterpreted if the default ever changes
+func migrateV0ToV1(c *Config) {
+	for i := range c.Ports {
+		if c.Ports[i].Type == "" {
+			c.Ports[i].Type = PortTypeSerial
+		}
+	}
+}
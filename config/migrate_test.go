@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfigFile writes a minimal config with the given schema_version
+// fragment (e.g., `"schema_version": 1,`) and returns its path
+func writeConfigFile(t *testing.T, versionField string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	data := `{
+		` + versionField + `
+		"app": {"instance_id": "test-01", "fips_code": "1234567890"},
+		"ports": [
+			{"device": "/dev/ttyS1", "side_designation": "A1", "enabled": true},
+			{"type": "http", "path": "/cdr", "side_designation": "A2", "enabled": true}
+		],
+		"logging": {"base_path": "` + dir + `"},
+		"monitoring": {"port": 8080}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMigratesV0Config(t *testing.T) {
+	path := writeConfigFile(t, "")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", cfg.SchemaVersion, CurrentSchemaVersion)
+	}
+	if cfg.LoadedSchemaVersion() != 0 {
+		t.Errorf("LoadedSchemaVersion() = %d, want 0", cfg.LoadedSchemaVersion())
+	}
+	if !cfg.Migrated() {
+		t.Error("Migrated() = false for a v0 config")
+	}
+	if cfg.Ports[0].Type != PortTypeSerial {
+		t.Errorf("Ports[0].Type = %q, want %q", cfg.Ports[0].Type, PortTypeSerial)
+	}
+	if cfg.Ports[1].Type != PortTypeHTTP {
+		t.Errorf("Ports[1].Type = %q, want %q", cfg.Ports[1].Type, PortTypeHTTP)
+	}
+
+	// Loading doesn't touch the file; rewriting it makes the migration stick
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "schema_version") {
+		t.Error("Load() should not rewrite the config file")
+	}
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after Save error = %v", err)
+	}
+	if reloaded.Migrated() {
+		t.Error("Migrated() = true after rewriting in the current schema")
+	}
+	if reloaded.LoadedSchemaVersion() != CurrentSchemaVersion {
+		t.Errorf("LoadedSchemaVersion() = %d, want %d", reloaded.LoadedSchemaVersion(), CurrentSchemaVersion)
+	}
+}
+
+func TestLoadSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		wantErr string
+	}{
+		{"current", `"schema_version": 1,`, ""},
+		{"future", `"schema_version": 99,`, "newer than this build supports"},
+		{"negative", `"schema_version": -1,`, "invalid schema_version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(writeConfigFile(t, tt.field))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				if cfg.Migrated() {
+					t.Error("Migrated() = true for a current config")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// Schema lists the values port validation accepts, so UIs and provisioning
+// tools can build forms without hard-coding them. Everything is derived from
+// the tables Validate and the port update API check against.
+type Schema struct {
+	PortTypes              []string `json:"port_types"`
+	BaudRates              []int    `json:"baud_rates"`
+	Parities               []string `json:"parities"`
+	FlowControls           []string `json:"flow_controls"`
+	HTTPMethods            []string `json:"http_methods"`
+	OversizeLines          []string `json:"oversize_lines"`
+	Checksums              []string `json:"checksums"`
+	SideDesignationPattern string   `json:"side_designation_pattern"`
+	FIPSCodePattern        string   `json:"fips_code_pattern"`
+}
+
+// ConfigSchema returns the allowed values for port configuration
+func ConfigSchema() Schema {
+	return Schema{
+		PortTypes:              slices.Clone(validPortTypes),
+		BaudRates:              slices.Sorted(maps.Keys(validBaudRates)),
+		Parities:               slices.Clone(validParities),
+		FlowControls:           slices.Sorted(maps.Keys(validFlowControls)),
+		HTTPMethods:            slices.Sorted(maps.Keys(validHTTPMethods)),
+		OversizeLines:          []string{OversizeTruncate, OversizeDrop},
+		Checksums:              slices.Sorted(maps.Keys(validChecksums)),
+		SideDesignationPattern: sideDesignationPattern.String(),
+		FIPSCodePattern:        fipsCodePattern.String(),
+	}
+}
+
+// ValidBaudRate reports whether baud is one of the standard rates
+func ValidBaudRate(baud int) bool {
+	return validBaudRates[baud]
+}
+
+// ValidParity reports whether parity is a supported serial parity mode
+func ValidParity(parity string) bool {
+	return slices.Contains(validParities, parity)
+}
+
+// ValidFlowControl reports whether mode is a supported flow control mode
+func ValidFlowControl(mode string) bool {
+	return validFlowControls[mode]
+}
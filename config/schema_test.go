@@ -0,0 +1,53 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestConfigSchema(t *testing.T) {
+	schema := ConfigSchema()
+
+	if !slices.IsSorted(schema.BaudRates) {
+		t.Errorf("BaudRates not sorted: %v", schema.BaudRates)
+	}
+	for _, baud := range []int{300, 9600, 115200} {
+		if !slices.Contains(schema.BaudRates, baud) {
+			t.Errorf("BaudRates missing %d: %v", baud, schema.BaudRates)
+		}
+	}
+	for _, parity := range []string{"none", "odd", "even", "mark", "space"} {
+		if !slices.Contains(schema.Parities, parity) {
+			t.Errorf("Parities missing %q: %v", parity, schema.Parities)
+		}
+	}
+	for _, typ := range []string{PortTypeSerial, PortTypeTCP, PortTypeUDP, PortTypeHTTP, PortTypeFile} {
+		if !slices.Contains(schema.PortTypes, typ) {
+			t.Errorf("PortTypes missing %q: %v", typ, schema.PortTypes)
+		}
+	}
+	if !slices.Contains(schema.FlowControls, "hardware") {
+		t.Errorf("FlowControls missing hardware: %v", schema.FlowControls)
+	}
+
+	side := regexp.MustCompile(schema.SideDesignationPattern)
+	if !side.MatchString("A1") || !side.MatchString("B16") || side.MatchString("C1") {
+		t.Errorf("SideDesignationPattern %q does not match A1/B16 only", schema.SideDesignationPattern)
+	}
+
+	// Every advertised baud rate must pass Validate
+	for _, baud := range schema.BaudRates {
+		cfg := validConfig(t)
+		cfg.Ports[0].BaudRate = baud
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("baud_rate %d from schema rejected: %v", baud, err)
+		}
+	}
+
+	// Callers can't mutate the validation tables through the schema
+	schema.Parities[0] = "bogus"
+	if ValidParity("bogus") {
+		t.Error("mutating schema changed validParities")
+	}
+}
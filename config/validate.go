@@ -2,9 +2,16 @@ package config
 
 import (
 	"fmt"
+	"mime"
+	"net"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -29,6 +36,48 @@ var (
 		"error": true,
 	}
 
+	// Valid syslog facility names (RFC 5424)
+	validSyslogFacilities = map[string]bool{
+		"kern": true, "user": true, "mail": true, "daemon": true,
+		"auth": true, "syslog": true, "lpr": true, "news": true,
+		"uucp": true, "cron": true, "authpriv": true, "ftp": true,
+		"local0": true, "local1": true, "local2": true, "local3": true,
+		"local4": true, "local5": true, "local6": true, "local7": true,
+	}
+
+	// Header timestamp precisions (output.Precision*)
+	validTimestampPrecisions = map[string]bool{
+		"ms": true,
+		"us": true,
+		"ns": true,
+	}
+
+	// Valid port types, in documentation order
+	validPortTypes = []string{PortTypeSerial, PortTypeTCP, PortTypeUDP, PortTypeHTTP, PortTypeFile}
+
+	// Valid serial parity modes
+	validParities = []string{"none", "odd", "even", "mark", "space"}
+
+	// Record checksum algorithms (output.Checksum*)
+	validChecksums = map[string]bool{
+		"crc32":  true,
+		"sha256": true,
+	}
+
+	// Valid serial flow control modes
+	validFlowControls = map[string]bool{
+		"none":     true,
+		"hardware": true,
+		"software": true,
+	}
+
+	// Request methods HTTP capture endpoints can accept
+	validHTTPMethods = map[string]bool{
+		"POST": true,
+		"PUT":  true,
+		"GET":  true,
+	}
+
 	// A/B designation pattern: A1-A16 or B1-B16
 	sideDesignationPattern = regexp.MustCompile(`^[AB]([1-9]|1[0-6])$`)
 
@@ -86,29 +135,87 @@ func (c *Config) validateApp() error {
 		return fmt.Errorf("fips_code must be 10 digits, got: %s", c.App.FIPSCode)
 	}
 
+	if c.App.TimeZone != "" {
+		if _, err := time.LoadLocation(c.App.TimeZone); err != nil {
+			return fmt.Errorf("invalid time_zone %q: %w", c.App.TimeZone, err)
+		}
+	}
+
+	if c.App.TimestampPrecision != "" && !validTimestampPrecisions[c.App.TimestampPrecision] {
+		return fmt.Errorf("invalid timestamp_precision %q, must be one of: ms, us, ns", c.App.TimestampPrecision)
+	}
+
 	return nil
 }
 
+// pathsOverlap reports whether one HTTP path is a prefix path ("/cdr/")
+// that also matches the other; identical paths are checked separately
+func pathsOverlap(a, b string) bool {
+	if a == b {
+		return false
+	}
+	return (strings.HasSuffix(a, "/") && strings.HasPrefix(b, a)) ||
+		(strings.HasSuffix(b, "/") && strings.HasPrefix(a, b))
+}
+
 func (c *Config) validatePorts() error {
 	if len(c.Ports) == 0 {
 		return fmt.Errorf("at least one port must be configured")
 	}
 
-	enabledCount := 0
+	if err := c.checkPorts(); err != nil {
+		return err
+	}
+
+	for _, port := range c.Ports {
+		if port.Enabled {
+			return nil
+		}
+	}
+	return fmt.Errorf("at least one port must be enabled")
+}
+
+// ValidateNewPort checks port as if it were appended to c.Ports: its own
+// settings and conflicts with the ports already configured. Used when a port
+// is added at runtime, where nothing else would catch a bad setting before
+// the channel fails to start. Serial framing is only checked here, so
+// configs that loaded before the check existed keep loading.
+func (c *Config) ValidateNewPort(port PortConfig) error {
+	candidate := *c
+	candidate.Ports = append(slices.Clone(c.Ports), port)
+	if err := candidate.checkPorts(); err != nil {
+		return err
+	}
+
+	if port.IsSerial() {
+		if err := validateFraming(port); err != nil {
+			return fmt.Errorf("port %d (%s): %w", len(c.Ports), port.Device, err)
+		}
+	}
+	return nil
+}
+
+// checkPorts validates each port and the conflicts between them
+func (c *Config) checkPorts() error {
 	devicesSeen := make(map[string]bool)
 	pathsSeen := make(map[string]bool)
+	pathsByListenPort := make(map[int][]string)
+	tlsByListenPort := make(map[int]string)
+	bindByListenPort := make(map[int]string)
 	sideDesignationsSeen := make(map[string]bool)
 
 	for i, port := range c.Ports {
 		// Validate port type
-		if port.Type != "" && port.Type != PortTypeSerial && port.Type != PortTypeHTTP {
-			return fmt.Errorf("port %d: invalid type %q, must be %q or %q", i, port.Type, PortTypeSerial, PortTypeHTTP)
+		if port.Type != "" && !slices.Contains(validPortTypes, port.Type) {
+			return fmt.Errorf("port %d: invalid type %q, must be one of: %s", i, port.Type, strings.Join(validPortTypes, ", "))
 		}
 
 		// Port identifier for error messages
-		portID := port.Device
+		portID := port.Source()
 		if port.IsHTTP() {
 			portID = port.Path
+		} else if port.IsUDP() {
+			portID = port.Address
 		}
 
 		// Type-specific validation
@@ -124,10 +231,66 @@ func (c *Config) validatePorts() error {
 			devicesSeen[port.Device] = true
 
 			// Validate baud rate if specified
-			if port.BaudRate != 0 && !validBaudRates[port.BaudRate] {
-				return fmt.Errorf("port %d (%s): invalid baud_rate %d, must be one of: 300, 1200, 2400, 4800, 9600, 19200, 38400, 57600, 115200",
+			if port.BaudRate < 0 {
+				return fmt.Errorf("port %d (%s): baud_rate must be positive, got: %d", i, port.Device, port.BaudRate)
+			}
+			customOK := port.AllowCustomBaud || c.Detection.AllowCustomBaud
+			if port.BaudRate != 0 && !customOK && !validBaudRates[port.BaudRate] {
+				return fmt.Errorf("port %d (%s): invalid baud_rate %d, must be one of: 300, 1200, 2400, 4800, 9600, 19200, 38400, 57600, 115200 (or set allow_custom_baud)",
 					i, port.Device, port.BaudRate)
 			}
+
+			if err := validateLineSettings(port); err != nil {
+				return fmt.Errorf("port %d (%s): %w", i, port.Device, err)
+			}
+
+			// Validate flow control if specified
+			if port.FlowControl != "" && !validFlowControls[port.FlowControl] {
+				return fmt.Errorf("port %d (%s): invalid flow_control %q, must be one of: none, hardware, software",
+					i, port.Device, port.FlowControl)
+			}
+
+			// Under RTS/CTS, RTS low tells the device never to send
+			hardwareFlow := port.FlowControl == "hardware" ||
+				(port.FlowControl == "" && port.UseFlowControl != nil && *port.UseFlowControl)
+			if hardwareFlow && port.AssertRTS != nil && !*port.AssertRTS {
+				return fmt.Errorf("port %d (%s): assert_rts false is incompatible with hardware flow control", i, port.Device)
+			}
+		} else if port.IsTCP() {
+			if err := validateTCPAddress(port.Address); err != nil {
+				return fmt.Errorf("port %d: %w", i, err)
+			}
+			if devicesSeen[port.Address] {
+				return fmt.Errorf("port %d: duplicate address %s", i, port.Address)
+			}
+			devicesSeen[port.Address] = true
+
+			if err := validateLineSettings(port); err != nil {
+				return fmt.Errorf("port %d (%s): %w", i, port.Address, err)
+			}
+		} else if port.IsUDP() {
+			if err := validateUDPAddress(port.Address); err != nil {
+				return fmt.Errorf("port %d: %w", i, err)
+			}
+			if devicesSeen["udp "+port.Address] {
+				return fmt.Errorf("port %d: duplicate UDP listen address %s", i, port.Address)
+			}
+			devicesSeen["udp "+port.Address] = true
+		} else if port.IsFile() {
+			if port.ReplayFile == "" {
+				return fmt.Errorf("port %d: replay_file is required for file ports", i)
+			}
+			if _, err := os.Stat(port.ReplayFile); err != nil {
+				return fmt.Errorf("port %d: replay_file not readable: %w", i, err)
+			}
+			if devicesSeen["file "+port.ReplayFile] {
+				return fmt.Errorf("port %d: duplicate replay_file %s", i, port.ReplayFile)
+			}
+			devicesSeen["file "+port.ReplayFile] = true
+
+			if err := validateLineSettings(port); err != nil {
+				return fmt.Errorf("port %d (%s): %w", i, port.ReplayFile, err)
+			}
 		} else if port.IsHTTP() {
 			// HTTP port requires path
 			if port.Path == "" {
@@ -136,16 +299,116 @@ func (c *Config) validatePorts() error {
 			if !strings.HasPrefix(port.Path, "/") {
 				return fmt.Errorf("port %d: path must start with /, got: %s", i, port.Path)
 			}
+			if port.Path == CaptureHealthPath {
+				return fmt.Errorf("port %d: path %s is reserved for the capture port health check", i, CaptureHealthPath)
+			}
+			// "/" would swallow the dashboard and API
+			if port.Path == "/" {
+				return fmt.Errorf("port %d: path / would capture every request, use a prefix such as /cdr/", i)
+			}
 			// Validate listen_port if specified
 			if port.ListenPort != 0 && (port.ListenPort < 1 || port.ListenPort > 65535) {
 				return fmt.Errorf("port %d: listen_port must be between 1 and 65535, got: %d", i, port.ListenPort)
 			}
-			// Check for duplicate paths (on same listen port)
-			pathKey := fmt.Sprintf("%d:%s", port.ListenPort, port.Path)
+			if port.BindAddress != "" {
+				if port.ListenPort == 0 || port.ListenPort == c.Monitoring.Port {
+					return fmt.Errorf("port %d: bind_address requires a dedicated listen_port", i)
+				}
+				if err := validateBindAddress(port.BindAddress); err != nil {
+					return fmt.Errorf("port %d: %w", i, err)
+				}
+			}
+			// Validate TLS settings if specified
+			if port.TLSCertFile != "" || port.TLSKeyFile != "" || port.TLSClientCAFile != "" {
+				if port.TLSCertFile == "" || port.TLSKeyFile == "" {
+					return fmt.Errorf("port %d: tls_cert_file and tls_key_file must both be set", i)
+				}
+				if port.ListenPort == 0 {
+					return fmt.Errorf("port %d: TLS requires a dedicated listen_port", i)
+				}
+				for _, file := range []string{port.TLSCertFile, port.TLSKeyFile, port.TLSClientCAFile} {
+					if file == "" {
+						continue
+					}
+					if _, err := os.Stat(file); os.IsNotExist(err) {
+						return fmt.Errorf("port %d: TLS file not found: %s", i, file)
+					}
+				}
+			}
+			for _, method := range port.AllowedMethods {
+				if !validHTTPMethods[method] {
+					return fmt.Errorf("port %d: invalid allowed_methods entry %q, must be one of: POST, PUT, GET", i, method)
+				}
+			}
+			for _, ct := range port.AllowedContentTypes {
+				if _, _, err := mime.ParseMediaType(ct); err != nil {
+					return fmt.Errorf("port %d: invalid allowed_content_types entry %q: %w", i, ct, err)
+				}
+			}
+			if port.MaxBodyBytes < 0 {
+				return fmt.Errorf("port %d: max_body_bytes must be non-negative, got: %d", i, port.MaxBodyBytes)
+			}
+			if port.MaxConcurrentRequests < 0 {
+				return fmt.Errorf("port %d: max_concurrent_requests must be non-negative, got: %d", i, port.MaxConcurrentRequests)
+			}
+			if port.ResponseStatus != 0 && (port.ResponseStatus < 200 || port.ResponseStatus > 299) {
+				return fmt.Errorf("port %d: response_status must be a 2xx status, got: %d", i, port.ResponseStatus)
+			}
+			// 204 No Content and 205 Reset Content can't carry a body
+			if port.ResponseBody != "" && (port.ResponseStatus == 204 || port.ResponseStatus == 205) {
+				return fmt.Errorf("port %d: response_status %d does not allow a response_body", i, port.ResponseStatus)
+			}
+			if port.ResponseContentType != "" {
+				if _, _, err := mime.ParseMediaType(port.ResponseContentType); err != nil {
+					return fmt.Errorf("port %d: invalid response_content_type %q: %w", i, port.ResponseContentType, err)
+				}
+			}
+			// Validate source IP restrictions
+			if _, err := ParseCIDRs(port.AllowedCIDRs); err != nil {
+				return fmt.Errorf("port %d: allowed_cidrs: %w", i, err)
+			}
+			if _, err := ParseCIDRs(port.TrustedProxies); err != nil {
+				return fmt.Errorf("port %d: trusted_proxies: %w", i, err)
+			}
+			// All endpoints sharing a listen port share one server, so they must agree on TLS
+			if port.ListenPort != 0 {
+				tlsKey := port.TLSCertFile + "|" + port.TLSKeyFile + "|" + port.TLSClientCAFile
+				if prev, ok := tlsByListenPort[port.ListenPort]; ok && prev != tlsKey {
+					return fmt.Errorf("port %d: TLS settings differ from other endpoints on port %d", i, port.ListenPort)
+				}
+				tlsByListenPort[port.ListenPort] = tlsKey
+
+				if prev, ok := bindByListenPort[port.ListenPort]; ok && prev != port.BindAddress {
+					return fmt.Errorf("port %d: bind_address differs from other endpoints on port %d", i, port.ListenPort)
+				}
+				bindByListenPort[port.ListenPort] = port.BindAddress
+			}
+			// Check for duplicate paths (on same listen port). listen_port 0 and an
+			// explicit monitoring port both land on the monitoring server's mux.
+			listenPort := port.ListenPort
+			if listenPort == 0 {
+				listenPort = c.Monitoring.Port
+			}
+			pathKey := fmt.Sprintf("%d:%s", listenPort, port.Path)
 			if pathsSeen[pathKey] {
-				return fmt.Errorf("port %d: duplicate path %s on port %d", i, port.Path, port.ListenPort)
+				return fmt.Errorf("port %d: duplicate path %s on port %d", i, port.Path, listenPort)
 			}
 			pathsSeen[pathKey] = true
+
+			// A prefix path must not leave it ambiguous which endpoint gets a request
+			if listenPort == c.Monitoring.Port {
+				for _, reserved := range []string{"/api/", "/media/"} {
+					if port.Path == reserved || pathsOverlap(port.Path, reserved) {
+						return fmt.Errorf("port %d: path %s overlaps the monitoring server's %s routes", i, port.Path, reserved)
+					}
+				}
+			}
+			for _, other := range pathsByListenPort[listenPort] {
+				if pathsOverlap(port.Path, other) {
+					return fmt.Errorf("port %d: path %s overlaps %s on port %d", i, port.Path, other, listenPort)
+				}
+			}
+			pathsByListenPort[listenPort] = append(pathsByListenPort[listenPort], port.Path)
 		}
 
 		// Check A designation (required for all types)
@@ -164,20 +427,85 @@ func (c *Config) validatePorts() error {
 			sideDesignationsSeen[port.SideDesignation] = true
 		}
 
+		// Extra subjects are published to verbatim, so no wildcards or whitespace
+		for _, subject := range port.ExtraSubjects {
+			if subject == "" || strings.ContainsAny(subject, "*> \t") {
+				return fmt.Errorf("port %d (%s): invalid extra_subjects entry %q", i, portID, subject)
+			}
+		}
+
+		for j, rule := range port.RedactionRules {
+			if rule.Pattern == "" {
+				return fmt.Errorf("port %d (%s): redaction_rules[%d] pattern is required", i, portID, j)
+			}
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf("port %d (%s): invalid redaction_rules[%d] pattern: %w", i, portID, j, err)
+			}
+		}
+		if port.UnredactedSubject != "" {
+			if len(port.RedactionRules) == 0 {
+				return fmt.Errorf("port %d (%s): unredacted_subject requires redaction_rules", i, portID)
+			}
+			if strings.ContainsAny(port.UnredactedSubject, "*> \t") {
+				return fmt.Errorf("port %d (%s): invalid unredacted_subject %q", i, portID, port.UnredactedSubject)
+			}
+		}
+
+		if port.AppendChecksum != "" && !validChecksums[port.AppendChecksum] {
+			return fmt.Errorf("port %d (%s): invalid append_checksum %q, must be crc32 or sha256", i, portID, port.AppendChecksum)
+		}
+
 		// Validate FIPS code if specified
 		if port.FIPSCode != "" && !fipsCodePattern.MatchString(port.FIPSCode) {
 			return fmt.Errorf("port %d (%s): fips_code must be 10 digits, got: %s", i, portID, port.FIPSCode)
 		}
 
-		if port.Enabled {
-			enabledCount++
+		// Validate embedded timestamp extraction
+		if port.TimestampRegex != "" {
+			if _, err := regexp.Compile(port.TimestampRegex); err != nil {
+				return fmt.Errorf("port %d (%s): invalid timestamp_regex: %w", i, portID, err)
+			}
+			if port.TimestampLayout == "" {
+				return fmt.Errorf("port %d (%s): timestamp_layout is required with timestamp_regex", i, portID)
+			}
+		} else if port.TimestampLayout != "" || port.TimestampTZ != "" {
+			return fmt.Errorf("port %d (%s): timestamp_layout and timestamp_tz require timestamp_regex", i, portID)
+		}
+		if _, err := port.TimestampLocation(); err != nil {
+			return fmt.Errorf("port %d (%s): invalid timestamp_tz %q: %w", i, portID, port.TimestampTZ, err)
 		}
 	}
 
-	if enabledCount == 0 {
-		return fmt.Errorf("at least one port must be enabled")
-	}
+	return nil
+}
 
+// stopBits15Supported reports whether the serial driver can open a port with
+// 1.5 stop bits. go.bug.st/serial rejects them on every unix platform
+// (InvalidStopBits), so they only work on Windows.
+var stopBits15Supported = runtime.GOOS == "windows"
+
+// validateFraming checks a serial port's data bits, parity and stop bits
+// (zero values take the 8N1 defaults)
+func validateFraming(port PortConfig) error {
+	if port.DataBits != 0 && (port.DataBits < 5 || port.DataBits > 8) {
+		return fmt.Errorf("data_bits must be 5, 6, 7, or 8, got: %d", port.DataBits)
+	}
+	if port.Parity != "" && !slices.Contains(validParities, port.Parity) {
+		return fmt.Errorf("invalid parity %q, must be one of: %s", port.Parity, strings.Join(validParities, ", "))
+	}
+	switch port.StopBits {
+	case 0, 1, 2:
+	case 1.5:
+		if !stopBits15Supported {
+			return fmt.Errorf("stop_bits 1.5 is not supported by the serial driver on %s", runtime.GOOS)
+		}
+		// UARTs only do 1.5 stop bits with 5-bit characters
+		if port.DataBits != 5 {
+			return fmt.Errorf("stop_bits 1.5 requires data_bits 5")
+		}
+	default:
+		return fmt.Errorf("stop_bits must be 1, 1.5, or 2, got: %v", port.StopBits)
+	}
 	return nil
 }
 
@@ -187,7 +515,7 @@ func (c *Config) validateDetection() error {
 	}
 
 	for _, baudRate := range c.Detection.BaudRates {
-		if !validBaudRates[baudRate] {
+		if baudRate <= 0 || (!c.Detection.AllowCustomBaud && !validBaudRates[baudRate]) {
 			return fmt.Errorf("invalid baud rate %d in detection config", baudRate)
 		}
 	}
@@ -225,9 +553,43 @@ func (c *Config) validateNATS() error {
 		return fmt.Errorf("reconnect_wait_sec must be positive, got: %d", c.NATS.ReconnectWaitSec)
 	}
 
+	if c.NATS.ConnectWaitSec < 0 {
+		return fmt.Errorf("connect_wait_sec must be non-negative, got: %d", c.NATS.ConnectWaitSec)
+	}
+
+	for i, consumer := range c.NATS.Consumers {
+		if consumer.Stream == "" || consumer.Name == "" {
+			return fmt.Errorf("consumers[%d]: stream and name are required", i)
+		}
+	}
+
+	streamsSeen := make(map[string]bool)
+	for i, stream := range c.NATS.Streams {
+		if stream.Name == "" || len(stream.Subjects) == 0 {
+			return fmt.Errorf("streams[%d]: name and subjects are required", i)
+		}
+		if streamsSeen[stream.Name] {
+			return fmt.Errorf("streams[%d]: duplicate stream %s", i, stream.Name)
+		}
+		streamsSeen[stream.Name] = true
+		if stream.Retention != "" && !validStreamRetentions[stream.Retention] {
+			return fmt.Errorf("streams[%d]: invalid retention %q, must be one of: limits, interest, workqueue", i, stream.Retention)
+		}
+		if stream.MaxAgeHours < 0 || stream.MaxBytes < 0 {
+			return fmt.Errorf("streams[%d]: max_age_hours and max_bytes must be non-negative", i)
+		}
+	}
+
 	return nil
 }
 
+// validStreamRetentions are the JetStream retention policies
+var validStreamRetentions = map[string]bool{
+	"limits":    true,
+	"interest":  true,
+	"workqueue": true,
+}
+
 func (c *Config) validateLogging() error {
 	if c.Logging.BasePath == "" {
 		return fmt.Errorf("base_path is required")
@@ -249,10 +611,86 @@ func (c *Config) validateLogging() error {
 		return fmt.Errorf("max_backups must be non-negative, got: %d", c.Logging.MaxBackups)
 	}
 
+	if c.Logging.WriteBufferKB < 0 {
+		return fmt.Errorf("write_buffer_kb must be non-negative, got: %d", c.Logging.WriteBufferKB)
+	}
+
+	if c.Logging.FlushIntervalMs < 0 {
+		return fmt.Errorf("flush_interval_ms must be non-negative, got: %d", c.Logging.FlushIntervalMs)
+	}
+
 	if !validLogLevels[c.Logging.Level] {
 		return fmt.Errorf("invalid log level %s, must be one of: debug, info, warn, error", c.Logging.Level)
 	}
 
+	seen := make(map[string]bool, len(c.Logging.Sinks))
+	for _, sink := range c.Logging.Sinks {
+		if sink != LogSinkFile && sink != LogSinkStdout && sink != LogSinkSyslog {
+			return fmt.Errorf("invalid sink %q, must be %q, %q or %q", sink, LogSinkFile, LogSinkStdout, LogSinkSyslog)
+		}
+		if seen[sink] {
+			return fmt.Errorf("duplicate sink %q", sink)
+		}
+		seen[sink] = true
+	}
+
+	if err := c.validateSyslog(); err != nil {
+		return fmt.Errorf("syslog: %w", err)
+	}
+
+	if tmpl := c.Logging.LogFilenameTemplate; tmpl != "" {
+		if err := validateLogFilenameTemplate(tmpl); err != nil {
+			return fmt.Errorf("log_filename_template %q: %w", tmpl, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateSyslog() error {
+	s := c.Logging.Syslog
+	if !slices.Contains(c.Logging.LogSinks(), LogSinkSyslog) {
+		return nil
+	}
+
+	if s.Network != "udp" && s.Network != "tcp" {
+		return fmt.Errorf("network must be udp or tcp, got: %q", s.Network)
+	}
+	if _, _, err := net.SplitHostPort(s.Address); err != nil {
+		return fmt.Errorf("address must be host:port: %w", err)
+	}
+	if !validSyslogFacilities[s.Facility] {
+		return fmt.Errorf("invalid facility %q, must be an RFC 5424 facility name such as daemon or local0-local7", s.Facility)
+	}
+
+	return nil
+}
+
+// validateLogFilenameTemplate checks that a template yields one distinct
+// .log file per channel, inside base_path
+func validateLogFilenameTemplate(tmpl string) error {
+	if !strings.Contains(tmpl, "{a}") {
+		return fmt.Errorf("must contain {a} so each channel gets its own file")
+	}
+	if !strings.HasSuffix(tmpl, ".log") {
+		return fmt.Errorf("must end in .log")
+	}
+	if filepath.IsAbs(tmpl) {
+		return fmt.Errorf("must be relative to base_path")
+	}
+	for _, part := range strings.Split(filepath.ToSlash(tmpl), "/") {
+		if part == ".." {
+			return fmt.Errorf("must not contain ..")
+		}
+	}
+
+	rest := tmpl
+	for _, token := range logFilenameTokens {
+		rest = strings.ReplaceAll(rest, token, "")
+	}
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("unknown token (supported: %s)", strings.Join(logFilenameTokens, ", "))
+	}
 	return nil
 }
 
@@ -261,6 +699,45 @@ func (c *Config) validateMonitoring() error {
 		return fmt.Errorf("port must be between 1 and 65535, got: %d", c.Monitoring.Port)
 	}
 
+	if c.Monitoring.BindAddress != "" {
+		if err := validateBindAddress(c.Monitoring.BindAddress); err != nil {
+			return err
+		}
+	}
+
+	for _, origin := range c.Monitoring.AllowedOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return fmt.Errorf("allowed_origins entry must be \"*\" or start with http:// or https://, got: %s", origin)
+		}
+	}
+
+	// Keepalives must beat common proxy idle timeouts (typically 60s); 0 = default
+	if c.Monitoring.SSEKeepaliveSec < 0 || c.Monitoring.SSEKeepaliveSec > 55 {
+		return fmt.Errorf("sse_keepalive_sec must be between 1 and 55, got: %d", c.Monitoring.SSEKeepaliveSec)
+	}
+
+	if c.Monitoring.SSEClientBuffer < 0 || c.Monitoring.SSEClientBuffer > 10000 {
+		return fmt.Errorf("sse_client_buffer must be between 1 and 10000, got: %d", c.Monitoring.SSEClientBuffer)
+	}
+
+	if c.Monitoring.EventScanWindows < 0 {
+		return fmt.Errorf("event_scan_windows must be non-negative, got: %d", c.Monitoring.EventScanWindows)
+	}
+
+	for _, t := range []struct {
+		name string
+		sec  int
+	}{
+		{"read_header_timeout_sec", c.Monitoring.ReadHeaderTimeoutSec},
+		{"read_timeout_sec", c.Monitoring.ReadTimeoutSec},
+		{"write_timeout_sec", c.Monitoring.WriteTimeoutSec},
+		{"idle_timeout_sec", c.Monitoring.IdleTimeoutSec},
+	} {
+		if t.sec < 0 {
+			return fmt.Errorf("%s must be non-negative, got: %d", t.name, t.sec)
+		}
+	}
+
 	return nil
 }
 
@@ -278,6 +755,22 @@ func (c *Config) validateRecovery() error {
 			c.Recovery.MaxReconnectDelaySec, c.Recovery.ReconnectDelaySec)
 	}
 
+	if c.Recovery.ReconnectEventIntervalSec < 0 {
+		return fmt.Errorf("reconnect_event_interval_sec must be positive, got: %d", c.Recovery.ReconnectEventIntervalSec)
+	}
+
+	if c.Recovery.MaxSessionDurationSec < 0 {
+		return fmt.Errorf("max_session_duration_sec must be non-negative, got: %d", c.Recovery.MaxSessionDurationSec)
+	}
+
+	if c.Recovery.ReconnectJitterPct < 0 || c.Recovery.ReconnectJitterPct > 100 {
+		return fmt.Errorf("reconnect_jitter_pct must be between 0 and 100, got: %d", c.Recovery.ReconnectJitterPct)
+	}
+
+	if c.Recovery.StopGraceMs < 0 {
+		return fmt.Errorf("stop_grace_ms must be non-negative, got: %d", c.Recovery.StopGraceMs)
+	}
+
 	return nil
 }
 
@@ -306,5 +799,90 @@ func (c *Config) validateForwarder() error {
 		}
 	}
 
+	if c.Forwarder.MaxForwardPerSec < 0 {
+		return fmt.Errorf("max_forward_per_sec must be non-negative, got: %d", c.Forwarder.MaxForwardPerSec)
+	}
+
+	return nil
+}
+
+// validateBindAddress accepts an IP literal (IPv4 or bare IPv6) or a hostname
+func validateBindAddress(addr string) error {
+	if net.ParseIP(addr) != nil {
+		return nil
+	}
+	if strings.ContainsAny(addr, "[]:/ ") {
+		return fmt.Errorf("bind_address must be an IP or hostname without brackets or port, got: %s", addr)
+	}
+	return nil
+}
+
+// validateLineSettings checks the line framing and filtering settings shared
+// by serial and TCP ports
+func validateLineSettings(port PortConfig) error {
+	if port.IdleGapMs < 0 {
+		return fmt.Errorf("idle_gap_ms must be non-negative, got: %d", port.IdleGapMs)
+	}
+
+	if port.DedupeWindowMs < 0 || port.DedupeCount < 0 {
+		return fmt.Errorf("dedupe_window_ms and dedupe_count must be non-negative")
+	}
+	if _, err := regexp.Compile(port.DedupeExempt); err != nil {
+		return fmt.Errorf("invalid dedupe_exempt: %w", err)
+	}
+
+	if port.StallAfterSec < 0 {
+		return fmt.Errorf("stall_after_sec must be non-negative, got: %d", port.StallAfterSec)
+	}
+
+	if port.MaxSessionDurationSec < 0 {
+		return fmt.Errorf("max_session_duration_sec must be non-negative, got: %d", port.MaxSessionDurationSec)
+	}
+
+	if port.MaxLinesPerSec < 0 {
+		return fmt.Errorf("max_lines_per_sec must be non-negative, got: %d", port.MaxLinesPerSec)
+	}
+
+	if port.MaxLineBytes < 0 || port.MaxLineBytes > MaxLineBytesLimit {
+		return fmt.Errorf("max_line_bytes must be between 0 and %d, got: %d", MaxLineBytesLimit, port.MaxLineBytes)
+	}
+	if port.OversizeLines != "" && port.OversizeLines != OversizeTruncate && port.OversizeLines != OversizeDrop {
+		return fmt.Errorf("invalid oversize_lines %q, must be %q or %q", port.OversizeLines, OversizeTruncate, OversizeDrop)
+	}
+	return nil
+}
+
+// validateTCPAddress requires host:port with a non-empty host and a numeric
+// port, e.g., "10.0.0.5:4001" or "[fd00::5]:4001"
+func validateTCPAddress(addr string) error {
+	if addr == "" {
+		return fmt.Errorf("address is required for TCP ports")
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "" {
+		return fmt.Errorf("address %q must include a host", addr)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("address %q: port must be between 1 and 65535", addr)
+	}
+	return nil
+}
+
+// validateUDPAddress requires a listen address of [host]:port with a numeric
+// port, e.g., ":5140" or "10.0.0.5:5140"
+func validateUDPAddress(addr string) error {
+	if addr == "" {
+		return fmt.Errorf("address is required for UDP ports")
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("address %q: port must be between 1 and 65535", addr)
+	}
 	return nil
 }
@@ -39,7 +39,9 @@ func validConfig(t *testing.T) *Config {
 			Level:      "info",
 		},
 		Monitoring: MonitoringConfig{
-			Port: 8080,
+			Port:            8080,
+			SSEKeepaliveSec: 15,
+			SSEClientBuffer: 64,
 		},
 		Recovery: RecoveryConfig{
 			ReconnectDelaySec:    5,
@@ -86,6 +88,26 @@ func TestValidateAppConfig(t *testing.T) {
 			modify:  func(c *Config) { c.App.FIPSCode = "123456789a" },
 			wantErr: true,
 		},
+		{
+			name:    "valid time_zone",
+			modify:  func(c *Config) { c.App.TimeZone = "America/Chicago" },
+			wantErr: false,
+		},
+		{
+			name:    "unknown time_zone",
+			modify:  func(c *Config) { c.App.TimeZone = "Central" },
+			wantErr: true,
+		},
+		{
+			name:    "microsecond timestamp_precision",
+			modify:  func(c *Config) { c.App.TimestampPrecision = "us" },
+			wantErr: false,
+		},
+		{
+			name:    "invalid timestamp_precision",
+			modify:  func(c *Config) { c.App.TimestampPrecision = "seconds" },
+			wantErr: true,
+		},
 		{
 			name:    "empty fips_code is valid",
 			modify:  func(c *Config) { c.App.FIPSCode = "" },
@@ -121,6 +143,23 @@ func TestValidatePortConfig(t *testing.T) {
 			modify:  func(c *Config) { c.Ports = nil },
 			wantErr: true,
 		},
+		{
+			name:    "append_checksum sha256",
+			modify:  func(c *Config) { c.Ports[0].AppendChecksum = "sha256" },
+			wantErr: false,
+		},
+		{
+			// Framing is only checked for ports added at runtime, so
+			// existing configs keep loading (see ValidateNewPort)
+			name:    "framing not checked at load",
+			modify:  func(c *Config) { c.Ports[0].DataBits = 9; c.Ports[0].StopBits = 1.5 },
+			wantErr: false,
+		},
+		{
+			name:    "unknown append_checksum",
+			modify:  func(c *Config) { c.Ports[0].AppendChecksum = "md5" },
+			wantErr: true,
+		},
 		{
 			name:    "no enabled ports",
 			modify:  func(c *Config) { c.Ports[0].Enabled = false },
@@ -161,6 +200,139 @@ func TestValidatePortConfig(t *testing.T) {
 			modify:  func(c *Config) { c.Ports[0].BaudRate = 12345 },
 			wantErr: true,
 		},
+		{
+			name:    "valid flow_control software",
+			modify:  func(c *Config) { c.Ports[0].FlowControl = "software" },
+			wantErr: false,
+		},
+		{
+			name:    "invalid flow_control",
+			modify:  func(c *Config) { c.Ports[0].FlowControl = "xonxoff" },
+			wantErr: true,
+		},
+		{
+			name:    "assert_dtr false",
+			modify:  func(c *Config) { c.Ports[0].AssertDTR = new(bool) },
+			wantErr: false,
+		},
+		{
+			name:    "assert_rts false with hardware flow control",
+			modify:  func(c *Config) { c.Ports[0].FlowControl = "hardware"; c.Ports[0].AssertRTS = new(bool) },
+			wantErr: true,
+		},
+		{
+			name:    "assert_rts false without flow control",
+			modify:  func(c *Config) { c.Ports[0].FlowControl = "none"; c.Ports[0].AssertRTS = new(bool) },
+			wantErr: false,
+		},
+		{
+			name:    "custom baud_rate rejected without allow_custom_baud",
+			modify:  func(c *Config) { c.Ports[0].BaudRate = 230400 },
+			wantErr: true,
+		},
+		{
+			name: "custom baud_rate with port allow_custom_baud",
+			modify: func(c *Config) {
+				c.Ports[0].BaudRate = 230400
+				c.Ports[0].AllowCustomBaud = true
+			},
+			wantErr: false,
+		},
+		{
+			name: "custom baud_rate with detection allow_custom_baud",
+			modify: func(c *Config) {
+				c.Ports[0].BaudRate = 230400
+				c.Detection.AllowCustomBaud = true
+			},
+			wantErr: false,
+		},
+		{
+			name:    "negative baud_rate",
+			modify:  func(c *Config) { c.Ports[0].BaudRate = -1; c.Ports[0].AllowCustomBaud = true },
+			wantErr: true,
+		},
+		{
+			name:    "negative idle_gap_ms",
+			modify:  func(c *Config) { c.Ports[0].IdleGapMs = -1 },
+			wantErr: true,
+		},
+		{
+			name: "timestamp regex with layout and tz",
+			modify: func(c *Config) {
+				c.Ports[0].TimestampRegex = `^(\d{2}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`
+				c.Ports[0].TimestampLayout = "01/02/06 15:04:05"
+				c.Ports[0].TimestampTZ = "America/Chicago"
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid timestamp regex",
+			modify: func(c *Config) {
+				c.Ports[0].TimestampRegex = "("
+				c.Ports[0].TimestampLayout = "15:04:05"
+			},
+			wantErr: true,
+		},
+		{
+			name:    "timestamp regex without layout",
+			modify:  func(c *Config) { c.Ports[0].TimestampRegex = `\d+` },
+			wantErr: true,
+		},
+		{
+			name:    "timestamp layout without regex",
+			modify:  func(c *Config) { c.Ports[0].TimestampLayout = "15:04:05" },
+			wantErr: true,
+		},
+		{
+			name: "invalid timestamp tz",
+			modify: func(c *Config) {
+				c.Ports[0].TimestampRegex = `\d+`
+				c.Ports[0].TimestampLayout = "15:04:05"
+				c.Ports[0].TimestampTZ = "Mars/Olympus"
+			},
+			wantErr: true,
+		},
+		{
+			name: "dedupe window with exempt pattern",
+			modify: func(c *Config) {
+				c.Ports[0].DedupeWindowMs = 5000
+				c.Ports[0].DedupeExempt = "^KEEPALIVE"
+			},
+			wantErr: false,
+		},
+		{
+			name:    "negative dedupe_count",
+			modify:  func(c *Config) { c.Ports[0].DedupeCount = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "invalid dedupe_exempt",
+			modify:  func(c *Config) { c.Ports[0].DedupeExempt = "[" },
+			wantErr: true,
+		},
+		{
+			name:    "negative max_lines_per_sec",
+			modify:  func(c *Config) { c.Ports[0].MaxLinesPerSec = -1 },
+			wantErr: true,
+		},
+		{
+			name: "max_line_bytes with drop policy",
+			modify: func(c *Config) {
+				c.Ports[0].MaxLineBytes = 4096
+				c.Ports[0].OversizeLines = OversizeDrop
+			},
+			wantErr: false,
+		},
+		{
+			name:    "max_line_bytes too large",
+			modify:  func(c *Config) { c.Ports[0].MaxLineBytes = MaxLineBytesLimit + 1 },
+			wantErr: true,
+		},
+		{
+			name:    "invalid oversize_lines",
+			modify:  func(c *Config) { c.Ports[0].OversizeLines = "split" },
+			wantErr: true,
+		},
 		{
 			name:    "baud_rate 0 is valid (auto-detect)",
 			modify:  func(c *Config) { c.Ports[0].BaudRate = 0 },
@@ -268,38 +440,470 @@ func TestValidatePortConfig(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "http port with invalid listen_port negative",
+			name: "http port with invalid listen_port negative",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					ListenPort:      -1,
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http tls cert without key",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					ListenPort:      8443,
+					TLSCertFile:     "/etc/nectarcollector/cert.pem",
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http tls on monitoring port",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					TLSCertFile:     "/etc/nectarcollector/cert.pem",
+					TLSKeyFile:      "/etc/nectarcollector/key.pem",
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http tls files missing",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					ListenPort:      8443,
+					TLSCertFile:     "/nonexistent/cert.pem",
+					TLSKeyFile:      "/nonexistent/key.pem",
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http invalid allowed_methods",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					AllowedMethods:  []string{"PUT", "DELETE"},
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http negative max_body_bytes",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					MaxBodyBytes:    -1,
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http negative max_concurrent_requests",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:                  PortTypeHTTP,
+					Path:                  "/cdr",
+					MaxConcurrentRequests: -1,
+					SideDesignation:       "A1",
+					Enabled:               true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "extra_subjects wildcard",
+			modify: func(c *Config) {
+				c.Ports[0].ExtraSubjects = []string{"archive.>"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "extra_subjects empty entry",
+			modify: func(c *Config) {
+				c.Ports[0].ExtraSubjects = []string{""}
+			},
+			wantErr: true,
+		},
+		{
+			name: "extra_subjects valid",
+			modify: func(c *Config) {
+				c.Ports[0].ExtraSubjects = []string{"archive.cdr.1234567890", "realtime.cdr"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "redaction_rules invalid pattern",
+			modify: func(c *Config) {
+				c.Ports[0].RedactionRules = []RedactionRule{{Pattern: `(\d{3}`, Replacement: "XXX"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "redaction_rules empty pattern",
+			modify: func(c *Config) {
+				c.Ports[0].RedactionRules = []RedactionRule{{Replacement: "XXX"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unredacted_subject without redaction_rules",
+			modify: func(c *Config) {
+				c.Ports[0].UnredactedSubject = "restricted.cdr"
+			},
+			wantErr: true,
+		},
+		{
+			name: "unredacted_subject wildcard",
+			modify: func(c *Config) {
+				c.Ports[0].RedactionRules = []RedactionRule{{Pattern: `\d{3}-\d{3}-\d{4}`, Replacement: "XXX-XXX-XXXX"}}
+				c.Ports[0].UnredactedSubject = "restricted.*"
+			},
+			wantErr: true,
+		},
+		{
+			name: "redaction_rules valid",
+			modify: func(c *Config) {
+				c.Ports[0].RedactionRules = []RedactionRule{{Pattern: `\d{3}-\d{3}-\d{4}`, Replacement: "XXX-XXX-XXXX"}}
+				c.Ports[0].UnredactedSubject = "restricted.cdr"
+			},
+			wantErr: false,
+		},
+		{
+			name: "http invalid allowed_cidrs",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					AllowedCIDRs:    []string{"10.0.0.0/8", "10.0.0.300/32"},
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http valid allowed_cidrs",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{
+					Type:            PortTypeHTTP,
+					Path:            "/cdr",
+					AllowedCIDRs:    []string{"10.0.0.0/8", "fd00::/8"},
+					TrustedProxies:  []string{"127.0.0.1/32"},
+					SideDesignation: "A1",
+					Enabled:         true,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate http paths on same port",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http path on monitoring port both implicitly and explicitly",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr", SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8080, SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http path explicit then implicit monitoring port",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8080, SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr", SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "same http path on monitoring and dedicated port is ok",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr", SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "http prefix path with sub-path endpoint",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr/", SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr/ali", SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http nested prefix paths",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr/a/", ListenPort: 8081, SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr/", ListenPort: 8081, SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http prefix path beside exact path is ok",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr/", SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr", SideDesignation: "A2", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/ali/", SideDesignation: "A3", Enabled: true},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "http prefix path over the api on the monitoring port",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/api/", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http prefix path /api/ on a dedicated port is ok",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/api/", ListenPort: 8081, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: false,
+		},
+		{
+			name: "http root path",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/", ListenPort: 8081, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "same http path on different ports is ok",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8082, SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "http bind_address on dedicated port",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, BindAddress: "fd00::5", SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/ali", ListenPort: 8081, BindAddress: "fd00::5", SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "http bind_address without listen_port",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/cdr", BindAddress: "10.0.0.5", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http bind_address with brackets",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, BindAddress: "[fd00::5]", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http bind_address differs on shared port",
+			modify: func(c *Config) {
+				c.Ports = []PortConfig{
+					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, BindAddress: "10.0.0.5", SideDesignation: "A1", Enabled: true},
+					{Type: PortTypeHTTP, Path: "/ali", ListenPort: 8081, SideDesignation: "A2", Enabled: true},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http custom response",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/cdr", ResponseStatus: 202, ResponseBody: "<ack/>", ResponseContentType: "application/xml", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: false,
+		},
+		{
+			name: "http non-2xx response_status",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/cdr", ResponseStatus: 302, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http response_body with 204",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/cdr", ResponseStatus: 204, ResponseBody: "ok", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "http path reserved for health check",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/healthz", ListenPort: 9000, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp source",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeTCP, Address: "10.0.0.5:4001", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: false,
+		},
+		{
+			name: "tcp missing address",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeTCP, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp address without port",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeTCP, Address: "10.0.0.5", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp address without host",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeTCP, Address: ":4001", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp address port out of range",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeTCP, Address: "moxa.local:70000", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp duplicate address",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeTCP, Address: "10.0.0.5:4001", SideDesignation: "A1", Enabled: true}
+				c.Ports = append(c.Ports, PortConfig{Type: PortTypeTCP, Address: "10.0.0.5:4001", SideDesignation: "A2", Enabled: true})
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative stall_after_sec",
+			modify: func(c *Config) {
+				c.Ports[0].StallAfterSec = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp invalid line settings",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeTCP, Address: "10.0.0.5:4001", IdleGapMs: -1, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "udp listener",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeUDP, Address: ":5140", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: false,
+		},
+		{
+			name: "udp missing address",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeUDP, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "udp address port out of range",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeUDP, Address: ":0", SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "udp duplicate address",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeUDP, Address: ":5140", SideDesignation: "A1", Enabled: true}
+				c.Ports = append(c.Ports, PortConfig{Type: PortTypeUDP, Address: ":5140", SideDesignation: "A2", Enabled: true})
+			},
+			wantErr: true,
+		},
+		{
+			name: "file replay",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeFile, ReplayFile: "validate_test.go", ReplayPaced: true, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: false,
+		},
+		{
+			name: "file missing replay_file",
 			modify: func(c *Config) {
-				c.Ports[0] = PortConfig{
-					Type:            PortTypeHTTP,
-					Path:            "/cdr",
-					ListenPort:      -1,
-					SideDesignation: "A1",
-					Enabled:         true,
-				}
+				c.Ports[0] = PortConfig{Type: PortTypeFile, SideDesignation: "A1", Enabled: true}
 			},
 			wantErr: true,
 		},
 		{
-			name: "duplicate http paths on same port",
+			name: "file replay_file not found",
 			modify: func(c *Config) {
-				c.Ports = []PortConfig{
-					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, SideDesignation: "A1", Enabled: true},
-					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, SideDesignation: "A2", Enabled: true},
-				}
+				c.Ports[0] = PortConfig{Type: PortTypeFile, ReplayFile: "/nonexistent/replay.log", SideDesignation: "A1", Enabled: true}
 			},
 			wantErr: true,
 		},
 		{
-			name: "same http path on different ports is ok",
+			name: "http allowed_content_types",
 			modify: func(c *Config) {
-				c.Ports = []PortConfig{
-					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8081, SideDesignation: "A1", Enabled: true},
-					{Type: PortTypeHTTP, Path: "/cdr", ListenPort: 8082, SideDesignation: "A2", Enabled: true},
-				}
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/cdr", AllowedContentTypes: []string{"application/xml"}, SideDesignation: "A1", Enabled: true}
 			},
 			wantErr: false,
 		},
+		{
+			name: "http invalid allowed_content_types",
+			modify: func(c *Config) {
+				c.Ports[0] = PortConfig{Type: PortTypeHTTP, Path: "/cdr", AllowedContentTypes: []string{"xml;;"}, SideDesignation: "A1", Enabled: true}
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid port type",
 			modify: func(c *Config) {
@@ -367,6 +971,14 @@ func TestValidateDetectionConfig(t *testing.T) {
 			modify:  func(c *Config) { c.Detection.MinBytesForValid = 0 },
 			wantErr: true,
 		},
+		{
+			name: "custom baud_rate in list with allow_custom_baud",
+			modify: func(c *Config) {
+				c.Detection.BaudRates = []int{9600, 230400}
+				c.Detection.AllowCustomBaud = true
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -427,6 +1039,21 @@ func TestValidateNATSConfig(t *testing.T) {
 			modify:  func(c *Config) { c.NATS.ReconnectWaitSec = 0 },
 			wantErr: true,
 		},
+		{
+			name:    "negative connect_wait",
+			modify:  func(c *Config) { c.NATS.ConnectWaitSec = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "consumer missing name",
+			modify:  func(c *Config) { c.NATS.Consumers = []ConsumerConfig{{Stream: "cdr"}} },
+			wantErr: true,
+		},
+		{
+			name:    "valid consumer",
+			modify:  func(c *Config) { c.NATS.Consumers = []ConsumerConfig{{Stream: "cdr", Name: "archiver"}} },
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -467,11 +1094,121 @@ func TestValidateLoggingConfig(t *testing.T) {
 			modify:  func(c *Config) { c.Logging.MaxBackups = -1 },
 			wantErr: true,
 		},
+		{
+			name: "negative forwarder max_forward_per_sec",
+			modify: func(c *Config) {
+				c.Forwarder = ForwarderConfig{Enabled: true, RemoteURL: "nats://central:4222", RemoteSubject: "ne.cdr.test", MaxForwardPerSec: -1}
+			},
+			wantErr: true,
+		},
+		{
+			name: "forwarder max_forward_per_sec",
+			modify: func(c *Config) {
+				c.Forwarder = ForwarderConfig{Enabled: true, RemoteURL: "nats://central:4222", RemoteSubject: "ne.cdr.test", MaxForwardPerSec: 50}
+			},
+			wantErr: false,
+		},
+		{
+			name:    "negative write_buffer_kb",
+			modify:  func(c *Config) { c.Logging.WriteBufferKB = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative flush_interval_ms",
+			modify:  func(c *Config) { c.Logging.FlushIntervalMs = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "buffered log writes",
+			modify:  func(c *Config) { c.Logging.WriteBufferKB = 64; c.Logging.FlushIntervalMs = 250 },
+			wantErr: false,
+		},
 		{
 			name:    "invalid log level",
 			modify:  func(c *Config) { c.Logging.Level = "trace" },
 			wantErr: true,
 		},
+		{
+			name:    "county log_filename_template",
+			modify:  func(c *Config) { c.Logging.LogFilenameTemplate = "{county}/{fips}-{a}.log" },
+			wantErr: false,
+		},
+		{
+			name:    "log_filename_template without {a}",
+			modify:  func(c *Config) { c.Logging.LogFilenameTemplate = "{county}/{fips}.log" },
+			wantErr: true,
+		},
+		{
+			name:    "log_filename_template unknown token",
+			modify:  func(c *Config) { c.Logging.LogFilenameTemplate = "{state}/{fips}-{a}.log" },
+			wantErr: true,
+		},
+		{
+			name:    "log_filename_template escapes base_path",
+			modify:  func(c *Config) { c.Logging.LogFilenameTemplate = "../{fips}-{a}.log" },
+			wantErr: true,
+		},
+		{
+			name:    "log_filename_template without .log",
+			modify:  func(c *Config) { c.Logging.LogFilenameTemplate = "{fips}-{a}.txt" },
+			wantErr: true,
+		},
+		{
+			name: "valid syslog",
+			modify: func(c *Config) {
+				c.Logging.Syslog = SyslogConfig{Enabled: true, Network: "tcp", Address: "logs.example.net:514", Facility: "daemon"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "syslog missing port",
+			modify: func(c *Config) {
+				c.Logging.Syslog = SyslogConfig{Enabled: true, Network: "udp", Address: "logs.example.net", Facility: "local0"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "syslog invalid network",
+			modify: func(c *Config) {
+				c.Logging.Syslog = SyslogConfig{Enabled: true, Network: "unix", Address: "logs.example.net:514", Facility: "local0"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "syslog invalid facility",
+			modify: func(c *Config) {
+				c.Logging.Syslog = SyslogConfig{Enabled: true, Network: "udp", Address: "logs.example.net:514", Facility: "local9"}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "disabled syslog not validated",
+			modify:  func(c *Config) { c.Logging.Syslog = SyslogConfig{Network: "bogus"} },
+			wantErr: false,
+		},
+		{
+			name:    "file and stdout sinks",
+			modify:  func(c *Config) { c.Logging.Sinks = []string{"file", "stdout"} },
+			wantErr: false,
+		},
+		{
+			name: "syslog sink validates syslog settings",
+			modify: func(c *Config) {
+				c.Logging.Sinks = []string{"stdout", "syslog"}
+				c.Logging.Syslog = SyslogConfig{Network: "udp", Address: "logs.example.net", Facility: "local0"}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown sink",
+			modify:  func(c *Config) { c.Logging.Sinks = []string{"file", "journald"} },
+			wantErr: true,
+		},
+		{
+			name:    "duplicate sink",
+			modify:  func(c *Config) { c.Logging.Sinks = []string{"stdout", "stdout"} },
+			wantErr: true,
+		},
 		{
 			name:    "valid debug level",
 			modify:  func(c *Config) { c.Logging.Level = "debug" },
@@ -527,6 +1264,89 @@ func TestValidateMonitoringConfig(t *testing.T) {
 			modify:  func(c *Config) { c.Monitoring.Port = 65535 },
 			wantErr: false,
 		},
+		{
+			name:    "valid allowed_origins",
+			modify:  func(c *Config) { c.Monitoring.AllowedOrigins = []string{"https://ui.example.com", "*"} },
+			wantErr: false,
+		},
+		{
+			name:    "allowed_origins without scheme",
+			modify:  func(c *Config) { c.Monitoring.AllowedOrigins = []string{"ui.example.com"} },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMonitoring(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			modify:  func(c *Config) {},
+			wantErr: false,
+		},
+		{
+			name: "custom keepalive and buffer",
+			modify: func(c *Config) {
+				c.Monitoring.SSEKeepaliveSec = 30
+				c.Monitoring.SSEClientBuffer = 1024
+			},
+			wantErr: false,
+		},
+		{
+			name:    "ipv6 bind address",
+			modify:  func(c *Config) { c.Monitoring.BindAddress = "::1" },
+			wantErr: false,
+		},
+		{
+			name:    "bind address with port",
+			modify:  func(c *Config) { c.Monitoring.BindAddress = "127.0.0.1:8080" },
+			wantErr: true,
+		},
+		{
+			name:    "keepalive too long for proxies",
+			modify:  func(c *Config) { c.Monitoring.SSEKeepaliveSec = 120 },
+			wantErr: true,
+		},
+		{
+			name:    "negative keepalive",
+			modify:  func(c *Config) { c.Monitoring.SSEKeepaliveSec = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "buffer too large",
+			modify:  func(c *Config) { c.Monitoring.SSEClientBuffer = 100000 },
+			wantErr: true,
+		},
+		{
+			name:    "negative read_header_timeout",
+			modify:  func(c *Config) { c.Monitoring.ReadHeaderTimeoutSec = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative write_timeout",
+			modify:  func(c *Config) { c.Monitoring.WriteTimeoutSec = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative event_scan_windows",
+			modify:  func(c *Config) { c.Monitoring.EventScanWindows = -1 },
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -570,6 +1390,41 @@ func TestValidateRecoveryConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "negative reconnect_event_interval",
+			modify:  func(c *Config) { c.Recovery.ReconnectEventIntervalSec = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "max_session_duration",
+			modify:  func(c *Config) { c.Recovery.MaxSessionDurationSec = 86400 },
+			wantErr: false,
+		},
+		{
+			name:    "negative max_session_duration",
+			modify:  func(c *Config) { c.Recovery.MaxSessionDurationSec = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative port max_session_duration",
+			modify:  func(c *Config) { c.Ports[0].MaxSessionDurationSec = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "reconnect_jitter_pct 20",
+			modify:  func(c *Config) { c.Recovery.ReconnectJitterPct = 20 },
+			wantErr: false,
+		},
+		{
+			name:    "reconnect_jitter_pct over 100",
+			modify:  func(c *Config) { c.Recovery.ReconnectJitterPct = 101 },
+			wantErr: true,
+		},
+		{
+			name:    "negative stop_grace_ms",
+			modify:  func(c *Config) { c.Recovery.StopGraceMs = -1 },
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -632,3 +1487,112 @@ func TestFIPSCodePattern(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateNATSStreams(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		{
+			name:    "ensure_streams with defaults",
+			modify:  func(c *Config) { c.NATS.EnsureStreams = true },
+			wantErr: false,
+		},
+		{
+			name: "custom stream",
+			modify: func(c *Config) {
+				c.NATS.EnsureStreams = true
+				c.NATS.Streams = []StreamConfig{{Name: "cdr", Subjects: []string{"ne.cdr.>"}, Retention: "interest", MaxAgeHours: 72}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing subjects",
+			modify: func(c *Config) {
+				c.NATS.Streams = []StreamConfig{{Name: "cdr"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate stream",
+			modify: func(c *Config) {
+				c.NATS.Streams = []StreamConfig{
+					{Name: "cdr", Subjects: []string{"*.cdr.>"}},
+					{Name: "cdr", Subjects: []string{"ne.cdr.>"}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid retention",
+			modify: func(c *Config) {
+				c.NATS.Streams = []StreamConfig{{Name: "cdr", Subjects: []string{"*.cdr.>"}, Retention: "forever"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max_bytes",
+			modify: func(c *Config) {
+				c.NATS.Streams = []StreamConfig{{Name: "cdr", Subjects: []string{"*.cdr.>"}, MaxBytes: -1}}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNewPortFraming(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    PortConfig
+		wantErr bool
+	}{
+		{"8N1 defaults", PortConfig{}, false},
+		{"7E1", PortConfig{DataBits: 7, Parity: "even", StopBits: 1}, false},
+		{"data_bits 9", PortConfig{DataBits: 9}, true},
+		{"unknown parity", PortConfig{Parity: "sideways"}, true},
+		{"stop_bits 1.5 with 8 data bits", PortConfig{DataBits: 8, StopBits: 1.5}, true},
+		{"stop_bits 3", PortConfig{StopBits: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := tt.port
+			port.Device = "/dev/ttyS2"
+			port.SideDesignation = "A2"
+			port.BaudRate = 9600
+			err := validConfig(t).ValidateNewPort(port)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNewPort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNewPortStopBits15(t *testing.T) {
+	port := PortConfig{Device: "/dev/ttyS2", SideDesignation: "A2", BaudRate: 9600, DataBits: 5, StopBits: 1.5}
+
+	// go.bug.st/serial can't open 1.5 stop bits on unix
+	orig := stopBits15Supported
+	defer func() { stopBits15Supported = orig }()
+	stopBits15Supported = false
+	if err := validConfig(t).ValidateNewPort(port); err == nil {
+		t.Error("ValidateNewPort() accepted 1.5 stop bits where the driver rejects them")
+	}
+
+	stopBits15Supported = true
+	if err := validConfig(t).ValidateNewPort(port); err != nil {
+		t.Errorf("ValidateNewPort() of 5 data bits, 1.5 stop bits where supported: %v", err)
+	}
+}
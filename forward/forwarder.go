@@ -18,18 +18,56 @@ type Forwarder struct {
 	cfg        *config.ForwarderConfig
 	instanceID string
 	localConn  *nats.Conn
-	remoteConn *nats.Conn
-	sub        *nats.Subscription
+	remoteConn remotePublisher
+	sub        pullSubscription
 	logger     *slog.Logger
 
-	mu        sync.Mutex
-	forwarded int64
+	// Swappable in tests
+	subscribe      func() (pullSubscription, error) // Binds the durable consumer
+	localConnected func() bool
+
+	mu              sync.Mutex
+	forwarded       int64
+	lastError       string
+	lastErrorAt     time.Time
+	lastForwardedAt time.Time
 
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 }
 
+// pullSubscription is the part of *nats.Subscription the forwarder reads with
+type pullSubscription interface {
+	Fetch(batch int, opts ...nats.PullOpt) ([]*nats.Msg, error)
+	Unsubscribe() error
+}
+
+// remotePublisher is the part of *nats.Conn the forwarder publishes with
+type remotePublisher interface {
+	PublishMsg(msg *nats.Msg) error
+	Flush() error
+	IsConnected() bool
+	Close()
+}
+
+// Resubscribe tuning: after resubscribeAfter consecutive fetch failures the
+// pull subscription is assumed dead (e.g., the local server restarted) and
+// is rebuilt, retrying with backoff from resubscribeBackoff up to
+// maxResubscribeBackoff. Variables so tests can shorten them.
+var (
+	resubscribeAfter      = 3
+	resubscribeBackoff    = time.Second
+	maxResubscribeBackoff = 30 * time.Second
+	disconnectedPoll      = time.Second // How often to recheck a lost connection
+)
+
+// Ack and Nak a fetched message; variables so tests can use unbound messages
+var (
+	ackMsg = (*nats.Msg).Ack
+	nakMsg = (*nats.Msg).Nak
+)
+
 type ForwarderConfig struct {
 	Config     *config.ForwarderConfig
 	InstanceID string
@@ -41,15 +79,23 @@ type Stats struct {
 	Enabled   bool  `json:"enabled"`
 	Connected bool  `json:"connected"`
 	Forwarded int64 `json:"forwarded"`
+	// Why forwarding might be stalled: the most recent failure is kept after
+	// forwarding recovers, so compare LastErrorAt with LastForwardedAt
+	LastError       string     `json:"last_error,omitempty"`
+	LastErrorAt     *time.Time `json:"last_error_at,omitempty"`
+	LastForwardedAt *time.Time `json:"last_forwarded_at,omitempty"` // Last record acked after reaching the remote
 }
 
 func New(cfg *ForwarderConfig) *Forwarder {
-	return &Forwarder{
+	f := &Forwarder{
 		cfg:        cfg.Config,
 		instanceID: cfg.InstanceID,
 		localConn:  cfg.LocalConn,
 		logger:     cfg.Logger,
 	}
+	f.subscribe = f.bindConsumer
+	f.localConnected = func() bool { return f.localConn.IsConnected() }
+	return f
 }
 
 func (f *Forwarder) Start(ctx context.Context) error {
@@ -68,20 +114,35 @@ func (f *Forwarder) Start(ctx context.Context) error {
 	if f.cfg.RemoteCreds != "" {
 		opts = append(opts, nats.UserCredentials(f.cfg.RemoteCreds))
 	}
-	var err error
-	f.remoteConn, err = nats.Connect(f.cfg.RemoteURL, opts...)
+	remoteConn, err := nats.Connect(f.cfg.RemoteURL, opts...)
 	if err != nil {
 		return fmt.Errorf("remote NATS: %w", err)
 	}
+	f.remoteConn = remoteConn
 
-	// Setup durable consumer on local cdr stream
-	js, err := f.localConn.JetStream()
+	f.sub, err = f.subscribe()
 	if err != nil {
 		f.remoteConn.Close()
-		return fmt.Errorf("local JetStream: %w", err)
+		return err
 	}
 
-	name := f.instanceID + "-forwarder"
+	f.wg.Add(1)
+	go f.run()
+
+	f.logger.Info("Forwarder started", "remote", f.cfg.RemoteURL)
+	return nil
+}
+
+// bindConsumer creates the durable consumer on the local cdr stream if it's
+// missing and binds a pull subscription to it. The consumer survives a local
+// server restart on file storage, but is recreated if it was lost.
+func (f *Forwarder) bindConsumer() (pullSubscription, error) {
+	js, err := f.localConn.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("local JetStream: %w", err)
+	}
+
+	name := ConsumerName(f.instanceID)
 	if _, err := js.ConsumerInfo("cdr", name); errors.Is(err, nats.ErrConsumerNotFound) {
 		_, err = js.AddConsumer("cdr", &nats.ConsumerConfig{
 			Durable:       name,
@@ -92,22 +153,20 @@ func (f *Forwarder) Start(ctx context.Context) error {
 			DeliverPolicy: nats.DeliverAllPolicy,
 		})
 		if err != nil {
-			f.remoteConn.Close()
-			return fmt.Errorf("create consumer: %w", err)
+			return nil, fmt.Errorf("create consumer: %w", err)
 		}
 	}
 
-	f.sub, err = js.PullSubscribe("", name, nats.Bind("cdr", name))
+	sub, err := js.PullSubscribe("", name, nats.Bind("cdr", name))
 	if err != nil {
-		f.remoteConn.Close()
-		return fmt.Errorf("subscribe: %w", err)
+		return nil, fmt.Errorf("subscribe: %w", err)
 	}
+	return sub, nil
+}
 
-	f.wg.Add(1)
-	go f.run()
-
-	f.logger.Info("Forwarder started", "remote", f.cfg.RemoteURL)
-	return nil
+// ConsumerName returns the durable consumer the forwarder reads the cdr stream with
+func ConsumerName(instanceID string) string {
+	return instanceID + "-forwarder"
 }
 
 func (f *Forwarder) Stop() {
@@ -127,19 +186,40 @@ func (f *Forwarder) Stop() {
 
 func (f *Forwarder) Stats() Stats {
 	f.mu.Lock()
-	fwd := f.forwarded
-	f.mu.Unlock()
-	return Stats{
-		Enabled:   f.cfg.Enabled,
-		Connected: f.remoteConn != nil && f.remoteConn.IsConnected(),
-		Forwarded: fwd,
+	stats := Stats{
+		Forwarded: f.forwarded,
+		LastError: f.lastError,
+	}
+	if !f.lastErrorAt.IsZero() {
+		at := f.lastErrorAt
+		stats.LastErrorAt = &at
+	}
+	if !f.lastForwardedAt.IsZero() {
+		at := f.lastForwardedAt
+		stats.LastForwardedAt = &at
 	}
+	f.mu.Unlock()
+
+	stats.Enabled = f.cfg.Enabled
+	stats.Connected = f.remoteConn != nil && f.remoteConn.IsConnected()
+	return stats
+}
+
+// recordError keeps err as the last forwarding failure
+func (f *Forwarder) recordError(err error) {
+	f.mu.Lock()
+	f.lastError = err.Error()
+	f.lastErrorAt = time.Now()
+	f.mu.Unlock()
 }
 
 func (f *Forwarder) run() {
 	defer f.wg.Done()
 
 	subject := f.cfg.RemoteSubject
+	failures := 0 // Consecutive failed fetches
+	backoff := resubscribeBackoff
+	limiter := newTokenBucket(f.cfg.MaxForwardPerSec, time.Now())
 
 	for {
 		select {
@@ -148,29 +228,102 @@ func (f *Forwarder) run() {
 		default:
 		}
 
-		if !f.remoteConn.IsConnected() {
-			time.Sleep(time.Second)
+		if !f.remoteConn.IsConnected() || !f.localConnected() {
+			f.sleep(disconnectedPoll)
+			continue
+		}
+
+		// The local server restarted (or the consumer was deleted) and the
+		// old subscription will never deliver again: rebind once it's back
+		if f.sub == nil || failures >= resubscribeAfter {
+			if !f.resubscribe() {
+				f.sleep(backoff)
+				backoff = min(backoff*2, maxResubscribeBackoff)
+				continue
+			}
+			failures = 0
+			backoff = resubscribeBackoff
+		}
+
+		// Throttle before fetching, so no record sits unacked (and
+		// redelivered once AckWait passes) while we wait. A fetch that
+		// brings back nothing gives its token back.
+		if wait := limiter.Reserve(time.Now()); wait > 0 {
+			f.sleep(wait)
 			continue
 		}
 
 		msgs, err := f.sub.Fetch(1, nats.MaxWait(2*time.Second))
-		if err != nil || len(msgs) == 0 {
+		if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+			limiter.Refund()
+			failures = 0 // Nothing to forward; the subscription is fine
+			continue
+		}
+		if err != nil {
+			limiter.Refund()
+			failures++
+			f.logger.Debug("Forwarder fetch failed", "failures", failures, "error", err)
+			continue
+		}
+		failures = 0
+		if len(msgs) == 0 {
+			limiter.Refund()
 			continue
 		}
 
+		// Carry headers across so consumers still see Content-Encoding on compressed payloads
 		msg := msgs[0]
-		err = f.remoteConn.Publish(subject, msg.Data)
-		if err == nil {
-			err = f.remoteConn.Flush()
+		if err := f.remoteConn.PublishMsg(&nats.Msg{Subject: subject, Data: msg.Data, Header: msg.Header}); err != nil {
+			f.recordError(fmt.Errorf("publish: %w", err))
+			nakMsg(msg)
+			continue
 		}
-		if err != nil {
-			msg.Nak()
+		if err := f.remoteConn.Flush(); err != nil {
+			f.recordError(fmt.Errorf("flush: %w", err))
+			nakMsg(msg)
 			continue
 		}
 
-		msg.Ack()
+		// The record reached the remote either way; a failed ack means it
+		// will be redelivered and forwarded again
+		ackErr := ackMsg(msg)
+		if ackErr != nil {
+			f.recordError(fmt.Errorf("ack: %w", ackErr))
+		}
 		f.mu.Lock()
 		f.forwarded++
+		if ackErr == nil {
+			f.lastForwardedAt = time.Now()
+		}
 		f.mu.Unlock()
 	}
 }
+
+// resubscribe drops the current pull subscription and binds a new one.
+// Returns false if the consumer couldn't be bound yet.
+func (f *Forwarder) resubscribe() bool {
+	if f.sub != nil {
+		f.sub.Unsubscribe()
+		f.sub = nil
+	}
+
+	sub, err := f.subscribe()
+	if err != nil {
+		f.recordError(fmt.Errorf("rebind local consumer: %w", err))
+		f.logger.Warn("Failed to rebind forwarder consumer, will retry", "error", err)
+		return false
+	}
+	f.sub = sub
+	f.logger.Info("Forwarder consumer rebound after local NATS failure")
+	return true
+}
+
+// sleep waits for d or until the forwarder stops
+func (f *Forwarder) sleep(d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-f.ctx.Done():
+	case <-t.C:
+	}
+}
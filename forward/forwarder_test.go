@@ -0,0 +1,251 @@
+package forward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nectarcollector/config"
+
+	"github.com/nats-io/nats.go"
+)
+
+// fakeSub serves queued messages, then the given error (ErrTimeout if nil)
+type fakeSub struct {
+	mu           sync.Mutex
+	msgs         []*nats.Msg
+	err          error
+	unsubscribed bool
+}
+
+func (s *fakeSub) Fetch(int, ...nats.PullOpt) ([]*nats.Msg, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.msgs) > 0 {
+		msg := s.msgs[0]
+		s.msgs = s.msgs[1:]
+		return []*nats.Msg{msg}, nil
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	time.Sleep(time.Millisecond) // Stand in for MaxWait
+	return nil, nats.ErrTimeout
+}
+
+func (s *fakeSub) Unsubscribe() error {
+	s.mu.Lock()
+	s.unsubscribed = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *fakeSub) fail(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// fakeRemote records what's published to the remote server
+type fakeRemote struct {
+	mu         sync.Mutex
+	published  []string
+	publishErr error
+}
+
+func (r *fakeRemote) PublishMsg(msg *nats.Msg) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.publishErr != nil {
+		return r.publishErr
+	}
+	r.published = append(r.published, string(msg.Data))
+	return nil
+}
+
+func (r *fakeRemote) Flush() error      { return nil }
+func (r *fakeRemote) IsConnected() bool { return true }
+func (r *fakeRemote) Close()            {}
+
+func (r *fakeRemote) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.published)
+}
+
+func records(data ...string) []*nats.Msg {
+	msgs := make([]*nats.Msg, len(data))
+	for i, d := range data {
+		msgs[i] = &nats.Msg{Subject: "ne.cdr.1429010002", Data: []byte(d)}
+	}
+	return msgs
+}
+
+// newTestForwarder returns a forwarder reading sub and publishing to remote,
+// with acks stubbed and resubscribe timings shortened
+func newTestForwarder(t *testing.T, sub pullSubscription, remote *fakeRemote) *Forwarder {
+	t.Helper()
+
+	origAck, origNak := ackMsg, nakMsg
+	origAfter, origBackoff, origMax, origPoll := resubscribeAfter, resubscribeBackoff, maxResubscribeBackoff, disconnectedPoll
+	ackMsg = func(*nats.Msg, ...nats.AckOpt) error { return nil }
+	nakMsg = func(*nats.Msg, ...nats.AckOpt) error { return nil }
+	resubscribeAfter = 3
+	resubscribeBackoff = time.Millisecond
+	maxResubscribeBackoff = 5 * time.Millisecond
+	disconnectedPoll = time.Millisecond
+	t.Cleanup(func() {
+		ackMsg, nakMsg = origAck, origNak
+		resubscribeAfter, resubscribeBackoff, maxResubscribeBackoff, disconnectedPoll = origAfter, origBackoff, origMax, origPoll
+	})
+
+	f := New(&ForwarderConfig{
+		Config:     &config.ForwarderConfig{Enabled: true, RemoteSubject: "central.cdr"},
+		InstanceID: "psna-ne-test-01",
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	f.remoteConn = remote
+	f.sub = sub
+	f.localConnected = func() bool { return true }
+	return f
+}
+
+// runForwarder starts the forward loop and returns a func that stops it
+func runForwarder(f *Forwarder) func() {
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	f.wg.Add(1)
+	go f.run()
+	return f.Stop
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestForwarderResubscribesAfterLocalRestart(t *testing.T) {
+	first := &fakeSub{msgs: records("CDR 1", "CDR 2")}
+	remote := &fakeRemote{}
+	f := newTestForwarder(t, first, remote)
+
+	var localUp atomic.Bool
+	localUp.Store(true)
+	f.localConnected = localUp.Load
+
+	second := &fakeSub{msgs: records("CDR 3", "CDR 4")}
+	var binds atomic.Int32
+	f.subscribe = func() (pullSubscription, error) {
+		// The first attempt lands while the server is still starting
+		if binds.Add(1) == 1 {
+			return nil, fmt.Errorf("create consumer: %w", nats.ErrJetStreamNotEnabled)
+		}
+		return second, nil
+	}
+
+	stop := runForwarder(f)
+	defer stop()
+	waitFor(t, "records before the restart", func() bool { return remote.count() == 2 })
+
+	// Local server goes down; the old subscription's fetches fail from then on
+	localUp.Store(false)
+	first.fail(nats.ErrConnectionClosed)
+	time.Sleep(10 * time.Millisecond)
+	if binds.Load() != 0 {
+		t.Fatal("rebound the consumer while the local connection was down")
+	}
+
+	localUp.Store(true)
+	waitFor(t, "forwarding to resume", func() bool { return remote.count() == 4 })
+
+	if got := binds.Load(); got != 2 {
+		t.Errorf("subscribe called %d times, want 2 (one failure, one success)", got)
+	}
+	first.mu.Lock()
+	if !first.unsubscribed {
+		t.Error("dead subscription was not unsubscribed")
+	}
+	first.mu.Unlock()
+	if got := f.Stats().Forwarded; got != 4 {
+		t.Errorf("Forwarded = %d, want 4", got)
+	}
+}
+
+func TestForwarderIdleDoesNotResubscribe(t *testing.T) {
+	remote := &fakeRemote{}
+	f := newTestForwarder(t, &fakeSub{}, remote)
+	var binds atomic.Int32
+	f.subscribe = func() (pullSubscription, error) {
+		binds.Add(1)
+		return &fakeSub{}, nil
+	}
+
+	stop := runForwarder(f)
+	time.Sleep(20 * time.Millisecond) // Many empty fetches
+	stop()
+
+	if got := binds.Load(); got != 0 {
+		t.Errorf("subscribe called %d times on an idle stream, want 0", got)
+	}
+}
+
+func TestForwarderStatsLastErrorAndForwarded(t *testing.T) {
+	sub := &fakeSub{msgs: records("CDR 1")}
+	remote := &fakeRemote{publishErr: nats.ErrConnectionClosed}
+	f := newTestForwarder(t, sub, remote)
+
+	if stats := f.Stats(); stats.LastError != "" || stats.LastErrorAt != nil || stats.LastForwardedAt != nil {
+		t.Fatalf("fresh stats = %+v, want no error or forward time", stats)
+	}
+
+	// Nak'd records are redelivered; feed the record back on each failure
+	var naks atomic.Int32
+	nakMsg = func(msg *nats.Msg, _ ...nats.AckOpt) error {
+		naks.Add(1)
+		sub.mu.Lock()
+		sub.msgs = append(sub.msgs, msg)
+		sub.mu.Unlock()
+		return nil
+	}
+
+	start := time.Now()
+	stop := runForwarder(f)
+	defer stop()
+	waitFor(t, "a publish failure", func() bool { return naks.Load() > 0 })
+
+	stats := f.Stats()
+	if stats.LastError != "publish: "+nats.ErrConnectionClosed.Error() {
+		t.Errorf("LastError = %q, want the publish failure", stats.LastError)
+	}
+	if stats.LastErrorAt == nil || stats.LastErrorAt.Before(start) {
+		t.Errorf("LastErrorAt = %v, want set", stats.LastErrorAt)
+	}
+	if stats.LastForwardedAt != nil || stats.Forwarded != 0 {
+		t.Errorf("after failure: LastForwardedAt = %v, Forwarded = %d, want unset and 0", stats.LastForwardedAt, stats.Forwarded)
+	}
+
+	// The remote recovers: the redelivered record goes through
+	remote.mu.Lock()
+	remote.publishErr = nil
+	remote.mu.Unlock()
+	waitFor(t, "the record to be forwarded", func() bool { return remote.count() == 1 })
+	waitFor(t, "the forward to be acked", func() bool { return f.Stats().Forwarded == 1 })
+
+	stats = f.Stats()
+	if stats.LastForwardedAt == nil || stats.LastForwardedAt.Before(*stats.LastErrorAt) {
+		t.Errorf("LastForwardedAt = %v, want after LastErrorAt %v", stats.LastForwardedAt, stats.LastErrorAt)
+	}
+	if stats.LastError == "" {
+		t.Error("LastError cleared on success, want it kept for diagnosis")
+	}
+}
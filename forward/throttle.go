@@ -0,0 +1,56 @@
+package forward
+
+import "time"
+
+// tokenBucket paces forwarding to max_forward_per_sec. It holds up to one
+// second's worth of tokens, so a short burst goes out at once but a long
+// catch-up is held to the configured rate.
+type tokenBucket struct {
+	rate   float64 // Tokens added per second
+	burst  float64 // Bucket capacity
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full bucket allowing perSec forwards per second.
+// Returns nil (unlimited) if perSec is not positive.
+func newTokenBucket(perSec int, now time.Time) *tokenBucket {
+	if perSec <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		rate:   float64(perSec),
+		burst:  float64(perSec),
+		tokens: float64(perSec),
+		last:   now,
+	}
+}
+
+// Reserve takes a token if one is available and returns 0; otherwise it
+// takes nothing and returns how long until a token will be. Safe to call on
+// nil (always 0).
+func (b *tokenBucket) Reserve(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.tokens+elapsed*b.rate, b.burst)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// Refund returns a token taken by Reserve that went unused, e.g. the fetch
+// it paced came back empty. Safe to call on nil.
+func (b *tokenBucket) Refund() {
+	if b == nil {
+		return
+	}
+	b.tokens = min(b.tokens+1, b.burst)
+}
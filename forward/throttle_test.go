@@ -0,0 +1,84 @@
+package forward
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	start := time.Date(2025, 12, 3, 15, 0, 0, 0, time.UTC)
+	b := newTokenBucket(5, start)
+
+	for i := 0; i < 5; i++ {
+		if wait := b.Reserve(start); wait != 0 {
+			t.Fatalf("burst forward %d: wait = %v, want 0", i, wait)
+		}
+	}
+
+	// Empty at 5/s: the next token is 200ms out, and asking again doesn't
+	// push it further
+	if wait := b.Reserve(start); wait != 200*time.Millisecond {
+		t.Errorf("empty bucket wait = %v, want 200ms", wait)
+	}
+	if wait := b.Reserve(start.Add(100 * time.Millisecond)); wait != 100*time.Millisecond {
+		t.Errorf("wait after 100ms = %v, want 100ms", wait)
+	}
+	if wait := b.Reserve(start.Add(200 * time.Millisecond)); wait != 0 {
+		t.Errorf("wait after refill = %v, want 0", wait)
+	}
+
+	// An unused token goes back, but never past the burst
+	b.Refund()
+	if wait := b.Reserve(start.Add(200 * time.Millisecond)); wait != 0 {
+		t.Errorf("wait after refund = %v, want 0", wait)
+	}
+	for i := 0; i < 10; i++ {
+		b.Refund()
+	}
+	if b.tokens != 5 {
+		t.Errorf("tokens after refunds = %v, want capped at 5", b.tokens)
+	}
+
+	var unlimited *tokenBucket
+	unlimited.Refund()
+	if newTokenBucket(0, start) != nil || unlimited.Reserve(start) != 0 {
+		t.Error("max_forward_per_sec 0 should be unlimited")
+	}
+}
+
+func TestForwarderMaxForwardPerSec(t *testing.T) {
+	const limit = 20
+	backlog := make([]string, 500) // Catch-up after an outage
+	for i := range backlog {
+		backlog[i] = fmt.Sprintf("CDR %d", i)
+	}
+
+	remote := &fakeRemote{}
+	f := newTestForwarder(t, &fakeSub{msgs: records(backlog...)}, remote)
+	f.cfg.MaxForwardPerSec = limit
+
+	var acks int
+	ackMsg = func(*nats.Msg, ...nats.AckOpt) error { acks++; return nil }
+
+	const window = 500 * time.Millisecond
+	stop := runForwarder(f)
+	time.Sleep(window)
+	stop()
+
+	// One second's burst plus the rate over the window
+	maxAllowed := limit + int(limit*window.Seconds()) + 1
+	got := remote.count()
+	if got > maxAllowed {
+		t.Errorf("forwarded %d records in %v, want at most %d at %d/s", got, window, maxAllowed, limit)
+	}
+	if got < limit {
+		t.Errorf("forwarded %d records, want at least the %d-record burst", got, limit)
+	}
+	// Throttling waits before fetching, so nothing is left unacked
+	if acks != got {
+		t.Errorf("acked %d of %d forwarded records", acks, got)
+	}
+}
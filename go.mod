@@ -5,6 +5,7 @@ go 1.24.5
 require (
 	github.com/nats-io/nats.go v1.31.0
 	go.bug.st/serial v1.6.1
+	golang.org/x/sys v0.5.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
@@ -14,5 +15,4 @@ require (
 	github.com/nats-io/nkeys v0.4.5 // indirect
 	github.com/nats-io/nuid v1.0.1 // indirect
 	golang.org/x/crypto v0.6.0 // indirect
-	golang.org/x/sys v0.5.0 // indirect
 )
@@ -15,6 +15,7 @@ import (
 	"nectarcollector/capture"
 	"nectarcollector/config"
 	"nectarcollector/monitoring"
+	"nectarcollector/output"
 
 	"gopkg.in/natefinch/lumberjack.v2"
 )
@@ -26,9 +27,10 @@ const (
 
 func main() {
 	// Parse command-line flags
-	configPath := flag.String("config", "", "Path to configuration file")
+	configPath := flag.String("config", "", "Path to configuration file, or a directory of config.json plus ports.d/*.json")
 	debug := flag.Bool("debug", false, "Enable debug logging")
 	version := flag.Bool("version", false, "Show version and exit")
+	migrateConfig := flag.Bool("migrate-config", false, "Rewrite an older config file in the current schema")
 	flag.Parse()
 
 	// Handle version flag
@@ -49,12 +51,26 @@ func main() {
 	}
 
 	// Setup logging
-	logger := setupLogging(cfg, *debug)
+	logger, closeLogging := setupLogging(cfg, *debug)
+	defer closeLogging()
 	logger.Info("Starting NectarCollector",
 		"version", appVersion,
 		"instance", cfg.App.InstanceID,
 		"config", *configPath)
 
+	if cfg.Migrated() {
+		logger.Info("Migrated config schema",
+			"from", cfg.LoadedSchemaVersion(),
+			"to", cfg.SchemaVersion)
+		if *migrateConfig {
+			if err := cfg.Save(*configPath); err != nil {
+				logger.Error("Failed to rewrite migrated config", "error", err)
+				os.Exit(1)
+			}
+			logger.Info("Rewrote config in current schema (previous kept as .bak)", "config", *configPath)
+		}
+	}
+
 	// Create context with signal handling
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -64,6 +80,7 @@ func main() {
 
 	// Create capture manager
 	manager := capture.NewManager(cfg, *configPath, logger)
+	manager.SetVersion(appVersion)
 
 	// Start capture channels first (creates HTTP channels we need for routing)
 	if err := manager.Start(ctx); err != nil {
@@ -101,7 +118,7 @@ func main() {
 	// Stop capture manager
 	done := make(chan struct{})
 	go func() {
-		manager.Stop()
+		manager.StopWithReason(shutdownReason(sig))
 		close(done)
 	}()
 
@@ -115,8 +132,21 @@ func main() {
 	logger.Info("NectarCollector stopped")
 }
 
-// setupLogging configures logging with optional file rotation
-func setupLogging(cfg *config.Config, debug bool) *slog.Logger {
+// shutdownReason names the signal that stopped us for the service_stop event:
+// SIGINT is an operator Ctrl-C, SIGTERM is systemd (or kill) stopping the unit
+func shutdownReason(sig os.Signal) string {
+	switch sig {
+	case syscall.SIGINT:
+		return "SIGINT (interrupt)"
+	case syscall.SIGTERM:
+		return "SIGTERM (service stop)"
+	}
+	return "signal: " + sig.String()
+}
+
+// setupLogging configures logging with optional file rotation. The returned
+// func flushes and closes sinks that hold a connection (syslog).
+func setupLogging(cfg *config.Config, debug bool) (*slog.Logger, func()) {
 	// Determine log level
 	level := slog.LevelInfo
 	if debug {
@@ -138,28 +168,61 @@ func setupLogging(cfg *config.Config, debug bool) *slog.Logger {
 		Level: level,
 	}
 
-	var handler slog.Handler
-
-	// If log base path is configured, write to rotating log file
-	if cfg.Logging.BasePath != "" {
-		// Create log directory if it doesn't exist
-		if err := os.MkdirAll(cfg.Logging.BasePath, 0755); err != nil {
-			log.Printf("Warning: failed to create log directory: %v", err)
-			handler = slog.NewTextHandler(os.Stdout, opts)
-		} else {
-			logPath := filepath.Join(cfg.Logging.BasePath, "nectarcollector.log")
+	// Build a handler per configured sink; records fan out to all of them.
+	// Sinks that can't be set up are reported once logging is running.
+	var handlers []slog.Handler
+	var closers []func() error
+	var warnings []string
+	hasStdout, fileFailed := false, false
+	for _, sink := range cfg.Logging.LogSinks() {
+		switch sink {
+		case config.LogSinkStdout:
+			handlers = append(handlers, slog.NewTextHandler(os.Stdout, opts))
+			hasStdout = true
+		case config.LogSinkFile:
+			// Create log directory if it doesn't exist
+			if err := os.MkdirAll(cfg.Logging.BasePath, 0755); err != nil {
+				log.Printf("Warning: failed to create log directory: %v", err)
+				fileFailed = true
+				continue
+			}
 			writer := &lumberjack.Logger{
-				Filename:   logPath,
+				Filename:   filepath.Join(cfg.Logging.BasePath, "nectarcollector.log"),
 				MaxSize:    cfg.Logging.MaxSizeMB,
 				MaxBackups: cfg.Logging.MaxBackups,
 				Compress:   cfg.Logging.Compress,
 			}
-			handler = slog.NewJSONHandler(writer, opts)
+			handlers = append(handlers, slog.NewJSONHandler(writer, opts))
+		case config.LogSinkSyslog:
+			sl := cfg.Logging.Syslog
+			syslogHandler, err := output.NewSyslogHandler(output.SyslogHandlerConfig{
+				Network:  sl.Network,
+				Address:  sl.Address,
+				Facility: sl.Facility,
+				AppName:  "nectarcollector",
+				Level:    level,
+			})
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("Syslog unavailable (%s): %v", sl.Address, err))
+				continue
+			}
+			handlers = append(handlers, syslogHandler)
+			closers = append(closers, syslogHandler.Close)
 		}
-	} else {
-		// Log to stdout
-		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
 
-	return slog.New(handler)
+	// Never run without any log output, or without local logs when the file can't be opened
+	if len(handlers) == 0 || (fileFailed && !hasStdout) {
+		handlers = append(handlers, slog.NewTextHandler(os.Stdout, opts))
+	}
+
+	logger := slog.New(output.NewMultiHandler(handlers...))
+	for _, w := range warnings {
+		logger.Warn(w)
+	}
+	return logger, func() {
+		for _, c := range closers {
+			c()
+		}
+	}
 }
@@ -2,24 +2,32 @@ package monitoring
 
 import (
 	"bufio"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"embed"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"nectarcollector/capture"
 	"nectarcollector/config"
+	"nectarcollector/output"
 	"nectarcollector/serial"
 
 	"github.com/nats-io/nats.go"
@@ -41,9 +49,35 @@ var logixLogo []byte
 
 // SSEClient represents a connected SSE client
 type SSEClient struct {
-	channel string
-	send    chan string
-	done    chan struct{}
+	channel  string          // Subscription as requested ("all", "A1", or "A1,A2")
+	channels map[string]bool // Subscribed channels; nil means all
+	send     chan string
+	shutdown chan struct{} // Closed when the server starts shutting down
+	done     chan struct{}
+}
+
+// wants reports whether the client is subscribed to channel
+func (c *SSEClient) wants(channel string) bool {
+	return c.channels == nil || c.channels[channel]
+}
+
+// parseSSEChannels parses a comma-separated channel list into a set.
+// Returns nil (all channels) for "" or if any entry is "all".
+func parseSSEChannels(list string) map[string]bool {
+	channels := make(map[string]bool)
+	for _, ch := range strings.Split(list, ",") {
+		ch = strings.TrimSpace(ch)
+		if ch == "all" {
+			return nil
+		}
+		if ch != "" {
+			channels[ch] = true
+		}
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+	return channels
 }
 
 // SSEBroker manages SSE client connections and message broadcasting
@@ -52,7 +86,21 @@ type SSEBroker struct {
 	register   chan *SSEClient
 	unregister chan *SSEClient
 	broadcast  chan BroadcastMessage
+	stopped    chan struct{} // Closed when Run returns
 	mu         sync.RWMutex
+
+	// How long clients get to receive the shutdown event before done closes
+	shutdownGrace time.Duration
+
+	droppedBroadcasts  atomic.Int64 // Broadcast buffer full
+	droppedClientSends atomic.Int64 // Per-client buffer full
+}
+
+// BrokerStats reports how many lines the broker has dropped
+type BrokerStats struct {
+	Clients            int   `json:"clients"`
+	DroppedBroadcasts  int64 `json:"dropped_broadcasts"`
+	DroppedClientSends int64 `json:"dropped_client_sends"`
 }
 
 // BroadcastMessage contains a line and its target channel
@@ -68,21 +116,24 @@ func NewSSEBroker() *SSEBroker {
 		register:   make(chan *SSEClient),
 		unregister: make(chan *SSEClient),
 		broadcast:  make(chan BroadcastMessage, 256),
+		stopped:    make(chan struct{}),
+
+		shutdownGrace: SSEShutdownGrace,
 	}
 }
 
+// SSEShutdownGrace is how long the broker waits after telling clients it is
+// shutting down before closing their connections
+const SSEShutdownGrace = 500 * time.Millisecond
+
 // Run starts the broker's main loop
 func (b *SSEBroker) Run(ctx context.Context) {
+	defer close(b.stopped)
+
 	for {
 		select {
 		case <-ctx.Done():
-			// Close all client connections
-			b.mu.Lock()
-			for client := range b.clients {
-				close(client.done)
-				delete(b.clients, client)
-			}
-			b.mu.Unlock()
+			b.drain()
 			return
 
 		case client := <-b.register:
@@ -102,11 +153,12 @@ func (b *SSEBroker) Run(ctx context.Context) {
 			b.mu.RLock()
 			for client := range b.clients {
 				// Send to clients subscribed to this channel or "all"
-				if client.channel == msg.Channel || client.channel == "all" {
+				if client.wants(msg.Channel) {
 					select {
 					case client.send <- msg.Line:
 					default:
 						// Client buffer full, skip this message
+						b.droppedClientSends.Add(1)
 					}
 				}
 			}
@@ -115,12 +167,53 @@ func (b *SSEBroker) Run(ctx context.Context) {
 	}
 }
 
+// drain tells every client the server is shutting down, gives them
+// shutdownGrace to send the event, then closes all client connections
+func (b *SSEBroker) drain() {
+	b.mu.Lock()
+	for client := range b.clients {
+		close(client.shutdown)
+	}
+	count := len(b.clients)
+	b.mu.Unlock()
+
+	if count > 0 {
+		time.Sleep(b.shutdownGrace)
+	}
+
+	b.mu.Lock()
+	for client := range b.clients {
+		close(client.done)
+		delete(b.clients, client)
+	}
+	b.mu.Unlock()
+}
+
+// Register adds a client. Returns false if the broker has stopped.
+func (b *SSEBroker) Register(client *SSEClient) bool {
+	select {
+	case b.register <- client:
+		return true
+	case <-b.stopped:
+		return false
+	}
+}
+
+// Unregister removes a client (no-op once the broker has stopped)
+func (b *SSEBroker) Unregister(client *SSEClient) {
+	select {
+	case b.unregister <- client:
+	case <-b.stopped:
+	}
+}
+
 // Broadcast sends a line to all clients subscribed to the channel
 func (b *SSEBroker) Broadcast(channel, line string) {
 	select {
 	case b.broadcast <- BroadcastMessage{Channel: channel, Line: line}:
 	default:
 		// Broadcast buffer full, drop message
+		b.droppedBroadcasts.Add(1)
 	}
 }
 
@@ -131,6 +224,15 @@ func (b *SSEBroker) ClientCount() int {
 	return len(b.clients)
 }
 
+// BrokerStats returns client count and drop counters
+func (b *SSEBroker) BrokerStats() BrokerStats {
+	return BrokerStats{
+		Clients:            b.ClientCount(),
+		DroppedBroadcasts:  b.droppedBroadcasts.Load(),
+		DroppedClientSends: b.droppedClientSends.Load(),
+	}
+}
+
 // Server provides HTTP monitoring endpoints
 type Server struct {
 	config      *config.MonitoringConfig
@@ -140,9 +242,13 @@ type Server struct {
 	httpServers []*http.Server // Additional servers for HTTP capture on custom ports
 	logBasePath string
 	broker      *SSEBroker
+	events      eventSource // nil = read from JetStream on each request
 	version     string
 	ctx         context.Context
 	cancel      context.CancelFunc
+
+	watchersMu sync.Mutex
+	watchers   map[string]context.CancelFunc // Log tails by identifier
 }
 
 // NewServer creates a new monitoring server
@@ -159,34 +265,87 @@ func NewServer(cfg *config.MonitoringConfig, manager *capture.Manager, logBasePa
 		version:     version,
 		ctx:         ctx,
 		cancel:      cancel,
+		watchers:    make(map[string]context.CancelFunc),
 	}
 
 	// Start broker
 	go broker.Run(ctx)
 
-	// Start log watchers for each channel
-	go s.watchLogFiles(ctx)
+	// Start log watchers for each channel, and follow ports added or
+	// removed at runtime
+	manager.SetChannelChangeCallback(s.handleChannelChange)
+	go s.watchLogFiles(ctx, manager.ChannelsReady())
 
 	return s
 }
 
-// watchLogFiles monitors log files and broadcasts new lines
-func (s *Server) watchLogFiles(ctx context.Context) {
-	// Wait for channels to be available
-	time.Sleep(2 * time.Second)
+// logTailSource is a capture channel whose log is tailed to SSE clients
+type logTailSource interface {
+	Identifier() string
+	LogPath() string
+}
 
-	channels := s.manager.GetChannels()
-	for _, ch := range channels {
-		go s.tailLogFile(ctx, ch)
+// watchLogFiles starts tailing the startup channels' logs once ready closes
+func (s *Server) watchLogFiles(ctx context.Context, ready <-chan struct{}) {
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return
 	}
+
+	s.startLogWatchers(ctx)
 }
 
-// tailLogFile tails a log file and broadcasts new lines
-func (s *Server) tailLogFile(ctx context.Context, ch *capture.Channel) {
-	// Get identifier from channel - matches the format used in channel.go
-	identifier := fmt.Sprintf("%s-%s", ch.FIPSCode(), ch.SideDesignation())
+// startLogWatchers starts a tail for every serial and HTTP channel that
+// doesn't have one yet. Safe to call again after ports are added.
+func (s *Server) startLogWatchers(ctx context.Context) {
+	var sources []logTailSource
+	for _, ch := range s.manager.GetChannels() {
+		sources = append(sources, ch)
+	}
+	for _, ch := range s.manager.GetHTTPChannels() {
+		sources = append(sources, ch)
+	}
+
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+	for _, src := range sources {
+		s.startLogWatcherLocked(ctx, src.Identifier(), src.LogPath())
+	}
+}
+
+// handleChannelChange starts or stops the log tail for a channel the manager
+// started or stopped at runtime
+func (s *Server) handleChannelChange(change capture.ChannelChange) {
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+
+	if change.Started {
+		s.startLogWatcherLocked(s.ctx, change.Identifier, change.LogPath)
+		return
+	}
+	if stop, ok := s.watchers[change.Identifier]; ok {
+		stop()
+		delete(s.watchers, change.Identifier)
+	}
+}
+
+// startLogWatcherLocked starts tailing identifier's log unless it already is
+// (must hold watchersMu)
+func (s *Server) startLogWatcherLocked(ctx context.Context, identifier, logPath string) {
+	if _, ok := s.watchers[identifier]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s.watchers[identifier] = cancel
+	go s.tailLogFile(ctx, identifier, logPath)
+}
 
-	logPath := filepath.Join(s.logBasePath, identifier+".log")
+// tailLogFile tails a log file and broadcasts new lines
+func (s *Server) tailLogFile(ctx context.Context, identifier, logPath string) {
+	if logPath == "" {
+		logPath = filepath.Join(s.logBasePath, identifier+".log")
+	}
 
 	s.logger.Debug("Starting log tail", "channel", identifier, "path", logPath)
 
@@ -279,13 +438,18 @@ func (s *Server) Start() error {
 
 	// API endpoints
 	mux.HandleFunc("/api/health", s.handleHealth)
+	mux.HandleFunc("/api/ready", s.handleReady)
 	mux.HandleFunc("/api/stats", s.handleStats)
+	mux.HandleFunc("/api/channels/", s.handleChannel)
 	mux.HandleFunc("/api/ports", s.handlePorts)
 	mux.HandleFunc("/api/ports/config", s.handlePortsConfig)
 	mux.HandleFunc("/api/ports/config/", s.handlePortConfigAction)
 	mux.HandleFunc("/api/ports/available", s.handleAvailablePorts)
+	mux.HandleFunc("/api/ports/", s.handlePortAction)
 	mux.HandleFunc("/api/system", s.handleSystem)
+	mux.HandleFunc("/api/schema", s.handleSchema)
 	mux.HandleFunc("/api/feed", s.handleFeed)
+	mux.HandleFunc("/api/feed/merged", s.handleFeedMerged)
 	mux.HandleFunc("/api/stream", s.handleSSE)
 	mux.HandleFunc("/api/events", s.handleEvents)
 
@@ -325,13 +489,22 @@ func (s *Server) Start() error {
 		handler = mux
 	}
 
-	addr := fmt.Sprintf(":%d", s.config.Port)
-	s.server = &http.Server{
-		Addr:    addr,
-		Handler: handler,
+	// CORS goes outside auth - browsers send preflights without credentials
+	if len(s.config.AllowedOrigins) > 0 {
+		handler = s.cors(handler)
+		s.logger.Info("CORS enabled for API", "origins", s.config.AllowedOrigins)
 	}
 
-	s.logger.Info("Starting HoneyView monitoring server", "port", s.config.Port)
+	// Outermost so rejected (401/403) requests are logged too
+	if s.config.AccessLog {
+		handler = s.accessLog(handler)
+		s.logger.Info("Access logging enabled")
+	}
+
+	addr := s.config.ListenAddr()
+	s.server = s.newHTTPServer(addr, handler)
+
+	s.logger.Info("Starting HoneyView monitoring server", "addr", addr)
 
 	go func() {
 		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -343,13 +516,27 @@ func (s *Server) Start() error {
 	for port, channels := range customPortChannels {
 		if err := s.startHTTPCaptureServer(port, channels); err != nil {
 			s.logger.Error("Failed to start HTTP capture server", "port", port, "error", err)
-			// Continue with other ports - don't fail entirely
+			// Continue with other ports - don't fail entirely. A bind failure
+			// keeps retrying in the background.
 		}
 	}
 
 	return nil
 }
 
+// newHTTPServer builds an http.Server with the configured timeouts, so a slow
+// or stalled client can't hold a connection open indefinitely
+func (s *Server) newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: s.config.ReadHeaderTimeout(),
+		ReadTimeout:       s.config.ReadTimeout(),
+		WriteTimeout:      s.config.WriteTimeout(),
+		IdleTimeout:       s.config.IdleTimeout(),
+	}
+}
+
 // startHTTPCaptureServer starts a dedicated HTTP server for capture endpoints on a custom port
 func (s *Server) startHTTPCaptureServer(port int, channels []*capture.HTTPChannel) error {
 	mux := http.NewServeMux()
@@ -363,38 +550,160 @@ func (s *Server) startHTTPCaptureServer(port int, channels []*capture.HTTPChanne
 		mux.Handle(path, ch)
 	}
 
-	addr := fmt.Sprintf(":%d", port)
-	server := &http.Server{
-		Addr:    addr,
-		Handler: mux,
+	// Liveness route for reverse proxies and load balancers
+	mux.HandleFunc(config.CaptureHealthPath, handleCaptureHealth)
+
+	// Validation guarantees every endpoint on this port shares the same TLS
+	// settings and bind address
+	cfg := channels[0].Config()
+	tlsConfig, err := buildCaptureTLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	var handler http.Handler = mux
+	if s.config.AccessLog {
+		handler = s.accessLog(handler)
 	}
 
+	addr := cfg.ListenAddr()
+	server := s.newHTTPServer(addr, handler)
+	server.TLSConfig = tlsConfig
+
 	s.httpServers = append(s.httpServers, server)
 
-	s.logger.Info("Starting HTTP capture server", "port", port, "endpoints", len(channels))
+	s.logger.Info("Starting HTTP capture server",
+		"addr", addr,
+		"endpoints", len(channels),
+		"tls", tlsConfig != nil,
+		"mtls", tlsConfig != nil && tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert)
 
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			s.logger.Error("HTTP capture server error", "port", port, "error", err)
+	// Bind before returning so a port held by another process shows up as
+	// bind_failed on the channels instead of a running endpoint that never answers
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		for _, ch := range channels {
+			ch.SetBindError(err)
 		}
-	}()
+		go s.retryCaptureBind(server, channels, port)
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	go s.serveCapture(server, ln, port)
 
 	return nil
 }
 
+// Capture server bind retry backoff
+var (
+	captureBindRetryMin = time.Second
+	captureBindRetryMax = time.Minute
+)
+
+// retryCaptureBind keeps trying to bind a capture server's port with
+// exponential backoff until it succeeds or the server is stopped
+func (s *Server) retryCaptureBind(server *http.Server, channels []*capture.HTTPChannel, port int) {
+	delay := captureBindRetryMin
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		ln, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			for _, ch := range channels {
+				ch.SetBindError(err)
+			}
+			delay = min(delay*2, captureBindRetryMax)
+			s.logger.Debug("HTTP capture server bind retry failed", "port", port, "error", err, "next_retry", delay)
+			continue
+		}
+
+		for _, ch := range channels {
+			ch.SetBindError(nil)
+		}
+		s.logger.Info("HTTP capture server bound after retry", "addr", server.Addr)
+		s.serveCapture(server, ln, port)
+		return
+	}
+}
+
+// serveCapture serves a capture server on its bound listener until shutdown
+func (s *Server) serveCapture(server *http.Server, ln net.Listener, port int) {
+	var err error
+	if server.TLSConfig != nil {
+		// Certificates are already loaded into TLSConfig
+		err = server.ServeTLS(ln, "", "")
+	} else {
+		err = server.Serve(ln)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		s.logger.Error("HTTP capture server error", "port", port, "error", err)
+	}
+}
+
+// handleCaptureHealth answers liveness checks on a dedicated capture port.
+// No auth - it reveals nothing beyond the server being up.
+func handleCaptureHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// buildCaptureTLSConfig loads the certificate (and client CA for mutual TLS)
+// for an HTTP capture endpoint. Returns nil if the endpoint is plain HTTP.
+func buildCaptureTLSConfig(cfg config.PortConfig) (*tls.Config, error) {
+	if !cfg.UsesTLS() {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.TLSClientCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in client CA %s", cfg.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
 // selectiveAuth applies basic auth except for CDR ingestion endpoints
 func (s *Server) selectiveAuth(next http.Handler, httpChannels []*capture.HTTPChannel) http.Handler {
-	// Build set of paths that don't need auth
-	noAuthPaths := make(map[string]bool)
+	// Endpoints that don't need auth
+	noAuth := make([]config.PortConfig, 0, len(httpChannels))
 	for _, ch := range httpChannels {
-		noAuthPaths[ch.Path()] = true
+		noAuth = append(noAuth, ch.Config())
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip auth for CDR ingestion endpoints
-		if noAuthPaths[r.URL.Path] {
-			next.ServeHTTP(w, r)
-			return
+		// Skip auth for CDR ingestion endpoints (and sub-paths of prefix endpoints)
+		for i := range noAuth {
+			if noAuth[i].PathMatches(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
 		}
 
 		// Apply basic auth for everything else
@@ -408,6 +717,130 @@ func (s *Server) selectiveAuth(next http.Handler, httpChannels []*capture.HTTPCh
 	})
 }
 
+// cors adds CORS headers to /api/* responses for allowed origins and answers
+// OPTIONS preflights. Other paths and origins pass through untouched.
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		allowOrigin := s.allowOrigin(origin)
+		if allowOrigin == "" {
+			if preflight {
+				http.Error(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Explicitly listed origins get the origin echoed back so credentialed
+		// (basic auth) requests work; the "*" wildcard never allows credentials
+		w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+		if allowOrigin != "*" {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Add("Vary", "Origin")
+		}
+
+		if preflight {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// accessLog logs one record per request once it completes. Only request
+// metadata is logged: bodies and query strings can carry CDR (PII).
+func (s *Server) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &accessLogWriter{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"bytes", rec.bytes,
+			"remote_addr", r.RemoteAddr,
+			"duration_ms", time.Since(start).Milliseconds(),
+		}
+		if user, _, ok := r.BasicAuth(); ok {
+			attrs = append(attrs, "user", user)
+		}
+		s.logger.Info("HTTP request", attrs...)
+	})
+}
+
+// accessLogWriter records the status and body size written by a handler
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush keeps SSE streaming working through the wrapper
+func (w *accessLogWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// allowOrigin checks an Origin header against AllowedOrigins and returns the
+// Access-Control-Allow-Origin value to send: the origin itself when it is
+// listed explicitly, "*" when only the wildcard matches, or "" when denied
+func (s *Server) allowOrigin(origin string) string {
+	wildcard := false
+	for _, allowed := range s.config.AllowedOrigins {
+		if allowed == origin {
+			return origin
+		}
+		if allowed == "*" {
+			wildcard = true
+		}
+	}
+	if wildcard {
+		return "*"
+	}
+	return ""
+}
+
 // basicAuth wraps a handler with HTTP Basic Authentication
 func (s *Server) basicAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -472,18 +905,32 @@ func (s *Server) handleLogo(w http.ResponseWriter, r *http.Request) {
 	w.Write(logixLogo)
 }
 
-// handleHealth returns health status
+// handleHealth returns liveness: the process is up and serving (see handleReady)
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	health := map[string]interface{}{
 		"status":      "healthy",
 		"timestamp":   time.Now().UTC().Format(time.RFC3339),
 		"sse_clients": s.broker.ClientCount(),
+		"sse":         s.broker.BrokerStats(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(health)
 }
 
+// handleReady reports readiness for load balancers and orchestrators.
+// Unlike /api/health (liveness), it returns 503 when NATS is down or no
+// channel is capturing.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	readiness := s.manager.Readiness()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !readiness.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(readiness)
+}
+
 // handleStats returns channel statistics
 func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	stats := s.manager.GetAllStats()
@@ -492,6 +939,34 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
+// handleChannel returns a single channel's state and stats: GET /api/channels/{id}
+// where id is the port ID (e.g. ttyS1, or %2Fcdr for an HTTP path)
+func (s *Server) handleChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := decodePortID(strings.TrimPrefix(r.URL.EscapedPath(), "/api/channels/"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+	if id == "" {
+		http.Error(w, "Channel ID required", http.StatusBadRequest)
+		return
+	}
+
+	info, ok := s.manager.GetChannelInfo(id)
+	if !ok {
+		http.Error(w, "Channel not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
 // PortStatus represents the status of a single COM port
 type PortStatus struct {
 	Device    string `json:"device"`
@@ -512,20 +987,19 @@ func (s *Server) handlePorts(w http.ResponseWriter, r *http.Request) {
 		channelsByDevice[ch.Device()] = ch
 	}
 
-	// Scan standard COM ports (ttyS1-ttyS5, skipping ttyS0 which is console)
+	// Scan the labeled ports (by default ttyS1-ttyS5 as COM2-COM6)
 	ports := []PortStatus{}
-	comNames := map[string]string{
-		"/dev/ttyS1": "COM2",
-		"/dev/ttyS2": "COM3",
-		"/dev/ttyS3": "COM4",
-		"/dev/ttyS4": "COM5",
-		"/dev/ttyS5": "COM6",
+	labels := s.config.Labels()
+	devices := make([]string, 0, len(labels))
+	for device := range labels {
+		devices = append(devices, device)
 	}
+	sort.Strings(devices)
 
-	for device, com := range comNames {
+	for _, device := range devices {
 		status := PortStatus{
 			Device: device,
-			COM:    com,
+			COM:    labels[device],
 		}
 
 		// Check if this port is in use by a channel
@@ -565,14 +1039,18 @@ func (s *Server) handlePorts(w http.ResponseWriter, r *http.Request) {
 
 // SystemInfo contains system health metrics
 type SystemInfo struct {
-	Hostname   string        `json:"hostname"`
-	Uptime     int64         `json:"uptime_seconds"`
-	CPU        CPUInfo       `json:"cpu"`
-	Memory     MemoryInfo    `json:"memory"`
-	Storage    []StorageInfo `json:"storage"`
-	Network    []NetInfo     `json:"network"`
-	GoRoutines int           `json:"goroutines"`
-	Version    string        `json:"version"`
+	Hostname      string        `json:"hostname"`
+	Uptime        int64         `json:"uptime_seconds"`
+	CPU           CPUInfo       `json:"cpu"`
+	Memory        MemoryInfo    `json:"memory"`
+	Storage       []StorageInfo `json:"storage"`
+	Network       []NetInfo     `json:"network"`
+	GoRoutines    int           `json:"goroutines"`
+	GoMemStats    GoMemStats    `json:"go_mem_stats"`
+	SerialHandles int64         `json:"serial_handles_open"` // Steady growth across reconnects means a leaked handle
+	Version       string        `json:"version"`
+	Platform      string        `json:"platform,omitempty"` // "unsupported" when /proc is unavailable
+	Note          string        `json:"note,omitempty"`
 }
 
 // CPUInfo contains CPU usage information
@@ -592,6 +1070,15 @@ type MemoryInfo struct {
 	UsedPercent float64 `json:"used_percent"`
 }
 
+// GoMemStats contains Go runtime heap and GC figures, for spotting leaks in
+// long-running collectors
+type GoMemStats struct {
+	HeapAlloc    uint64 `json:"heap_alloc_bytes"`
+	HeapInuse    uint64 `json:"heap_inuse_bytes"`
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotalNs uint64 `json:"pause_total_ns"`
+}
+
 // StorageInfo contains disk usage information
 type StorageInfo struct {
 	Path        string  `json:"path"`
@@ -614,11 +1101,60 @@ type NetInfo struct {
 	TxPackets uint64 `json:"tx_packets"`
 }
 
+// getGoMemStats reads the runtime's memory statistics. ReadMemStats briefly
+// stops the world but doesn't force a GC, so it's cheap enough per request.
+func getGoMemStats() GoMemStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return GoMemStats{
+		HeapAlloc:    ms.HeapAlloc,
+		HeapInuse:    ms.HeapInuse,
+		NumGC:        ms.NumGC,
+		PauseTotalNs: ms.PauseTotalNs,
+	}
+}
+
+// procDir is where Linux exposes system metrics; a var so tests can point it elsewhere
+var procDir = "/proc"
+
+// processStart stands in for system boot time when /proc/uptime is unavailable
+var processStart = time.Now()
+
+// procAvailable reports whether the Linux /proc metrics can be read.
+// They can't on macOS and other dev machines.
+func procAvailable() bool {
+	_, err := os.Stat(filepath.Join(procDir, "meminfo"))
+	return err == nil
+}
+
 // handleSystem returns system health metrics
 func (s *Server) handleSystem(w http.ResponseWriter, r *http.Request) {
+	info := collectSystemInfo(s.version)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// handleSchema returns the values port configuration accepts (baud rates,
+// parities, port types, ...) so clients don't have to hard-code them
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config.ConfigSchema())
+}
+
+// collectSystemInfo gathers system health metrics, falling back to what the
+// Go runtime knows when /proc is unavailable
+func collectSystemInfo(version string) SystemInfo {
 	info := SystemInfo{
-		GoRoutines: runtime.NumGoroutine(),
-		Version:    s.version,
+		GoRoutines:    runtime.NumGoroutine(),
+		GoMemStats:    getGoMemStats(),
+		SerialHandles: serial.OpenHandles(),
+		Version:       version,
 	}
 
 	// Hostname
@@ -626,8 +1162,18 @@ func (s *Server) handleSystem(w http.ResponseWriter, r *http.Request) {
 		info.Hostname = h
 	}
 
+	if !procAvailable() {
+		info.Platform = "unsupported"
+		info.Note = "/proc unavailable on " + runtime.GOOS + ": uptime is process uptime, load and memory not reported"
+		info.Uptime = int64(time.Since(processStart).Seconds())
+		info.CPU = CPUInfo{NumCPU: runtime.NumCPU()}
+		info.Storage = getStorageInfo()
+		info.Network = getNetworkInfo()
+		return info
+	}
+
 	// Uptime from /proc/uptime
-	if data, err := os.ReadFile("/proc/uptime"); err == nil {
+	if data, err := os.ReadFile(filepath.Join(procDir, "uptime")); err == nil {
 		fields := strings.Fields(string(data))
 		if len(fields) >= 1 {
 			if uptime, err := strconv.ParseFloat(fields[0], 64); err == nil {
@@ -648,8 +1194,7 @@ func (s *Server) handleSystem(w http.ResponseWriter, r *http.Request) {
 	// Network info
 	info.Network = getNetworkInfo()
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(info)
+	return info
 }
 
 // getCPUInfo reads CPU usage from /proc/stat and load averages
@@ -659,7 +1204,7 @@ func getCPUInfo() CPUInfo {
 	}
 
 	// Load averages from /proc/loadavg
-	if data, err := os.ReadFile("/proc/loadavg"); err == nil {
+	if data, err := os.ReadFile(filepath.Join(procDir, "loadavg")); err == nil {
 		fields := strings.Fields(string(data))
 		if len(fields) >= 3 {
 			info.LoadAvg1, _ = strconv.ParseFloat(fields[0], 64)
@@ -681,7 +1226,7 @@ func getCPUInfo() CPUInfo {
 func getMemoryInfo() MemoryInfo {
 	info := MemoryInfo{}
 
-	data, err := os.ReadFile("/proc/meminfo")
+	data, err := os.ReadFile(filepath.Join(procDir, "meminfo"))
 	if err != nil {
 		return info
 	}
@@ -746,7 +1291,7 @@ func getNetworkInfo() []NetInfo {
 
 	// Read network stats from /proc/net/dev
 	netStats := make(map[string][]uint64)
-	if data, err := os.ReadFile("/proc/net/dev"); err == nil {
+	if data, err := os.ReadFile(filepath.Join(procDir, "net", "dev")); err == nil {
 		for _, line := range strings.Split(string(data), "\n") {
 			line = strings.TrimSpace(line)
 			if !strings.Contains(line, ":") {
@@ -813,6 +1358,18 @@ func getNetworkInfo() []NetInfo {
 	return result
 }
 
+// newSSEClient creates a client whose send buffer holds sse_client_buffer lines.
+// The broker drops lines for a client whose buffer is full rather than block.
+func (s *Server) newSSEClient(channel string) *SSEClient {
+	return &SSEClient{
+		channel:  channel,
+		channels: parseSSEChannels(channel),
+		send:     make(chan string, s.config.SSEClientBufferSize()),
+		shutdown: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
 // handleSSE handles Server-Sent Events connections for real-time streaming
 func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 	// Check if client supports SSE
@@ -822,7 +1379,11 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	channel := r.URL.Query().Get("channel")
+	// ?channels=A1,A2 subscribes to a subset; ?channel=A1 or "all" still work
+	channel := r.URL.Query().Get("channels")
+	if channel == "" {
+		channel = r.URL.Query().Get("channel")
+	}
 	if channel == "" {
 		channel = "all"
 	}
@@ -831,34 +1392,40 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if w.Header().Get("Access-Control-Allow-Origin") == "" {
+		w.Header().Set("Access-Control-Allow-Origin", "*") // Unless the CORS middleware already echoed an origin
+	}
 	w.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
 
+	// The stream outlives the server's read and write timeouts by design;
+	// keepalives notice dead clients instead
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
 	// Create client
-	client := &SSEClient{
-		channel: channel,
-		send:    make(chan string, 64),
-		done:    make(chan struct{}),
-	}
+	client := s.newSSEClient(channel)
 
 	// Register client
-	s.broker.register <- client
+	if !s.broker.Register(client) {
+		http.Error(w, "Server shutting down", http.StatusServiceUnavailable)
+		return
+	}
 
 	// Ensure cleanup on disconnect
-	defer func() {
-		s.broker.unregister <- client
-	}()
+	defer s.broker.Unregister(client)
 
 	// Send initial connection event
-	fmt.Fprintf(w, "event: connected\ndata: {\"channel\":\"%s\"}\n\n", channel)
+	connected, _ := json.Marshal(map[string]string{"channel": channel})
+	fmt.Fprintf(w, "event: connected\ndata: %s\n\n", connected)
 	flusher.Flush()
 
 	// Send keepalive comment immediately
 	fmt.Fprintf(w, ": keepalive\n\n")
 	flusher.Flush()
 
-	// Start keepalive ticker (every 15s)
-	keepalive := time.NewTicker(15 * time.Second)
+	// Start keepalive ticker (sse_keepalive_sec, default 15s)
+	keepalive := time.NewTicker(s.config.SSEKeepalive())
 	defer keepalive.Stop()
 
 	// Stream events
@@ -868,8 +1435,14 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 			// Client disconnected
 			return
 
+		case <-client.shutdown:
+			// Server shutting down - let the UI show a reconnecting banner
+			// instead of a generic connection error
+			fmt.Fprintf(w, "event: shutdown\ndata: {\"message\":\"server restarting\"}\n\n")
+			flusher.Flush()
+			return
+
 		case <-client.done:
-			// Server shutting down
 			return
 
 		case line := <-client.send:
@@ -906,8 +1479,11 @@ func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
 		count = 200
 	}
 
-	logPath := filepath.Join(s.logBasePath, channel+".log")
-	lines, err := tailFile(logPath, count)
+	logPath, ok := s.manager.LogPath(channel)
+	if !ok {
+		logPath = filepath.Join(s.logBasePath, channel+".log")
+	}
+	lines, err := tailLogWithBackups(logPath, count)
 	if err != nil {
 		s.logger.Warn("Failed to read log file", "path", logPath, "error", err)
 		lines = []string{}
@@ -922,21 +1498,318 @@ func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// tailFile returns the last n lines from a file.
-// Uses a ring buffer to keep memory bounded regardless of file size.
-func tailFile(path string, n int) ([]string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	// Ring buffer to hold last n lines
-	ring := make([]string, n)
-	idx := 0
-	count := 0
+// MergedLine is one line of the merged feed, tagged with its channel
+type MergedLine struct {
+	Channel string `json:"channel"`
+	Line    string `json:"line"`
+}
 
-	scanner := bufio.NewScanner(file)
+// handleFeedMerged returns the last N lines across all channel logs,
+// merged in header timestamp order: GET /api/feed/merged?count=N
+func (s *Server) handleFeedMerged(w http.ResponseWriter, r *http.Request) {
+	// Parse optional count parameter (default 50, max 200)
+	count := 50
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		if n, err := strconv.Atoi(countStr); err == nil && n > 0 {
+			count = n
+		}
+	}
+	if count > 200 {
+		count = 200
+	}
+
+	// Each channel contributes at most count lines, so memory is bounded
+	// by count * channels regardless of log size
+	feeds := make(map[string][]string)
+	for channel, logPath := range s.manager.LogPaths() {
+		lines, err := tailLogWithBackups(logPath, count)
+		if err != nil {
+			s.logger.Warn("Failed to read log file", "path", logPath, "error", err)
+			continue
+		}
+		feeds[channel] = lines
+	}
+
+	// Headers are written in the configured zone, which may observe DST
+	headerFmt, err := output.NewHeaderFormat(s.manager.Config().App.TimeZone, s.manager.Config().App.TimestampPrecision)
+	if err != nil {
+		s.logger.Warn("Invalid header settings, parsing headers as UTC", "error", err)
+	}
+
+	response := map[string]interface{}{
+		"channels": len(feeds),
+		"lines":    mergeFeeds(feeds, count, headerFmt),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// mergeFeeds merges per-channel tails by header timestamp, parsed in
+// headerFmt's zone, and returns the last n. A line without a parseable header
+// takes the timestamp of the line before it in the same file, so it stays in
+// file order.
+func mergeFeeds(feeds map[string][]string, n int, headerFmt output.HeaderFormat) []MergedLine {
+	type stamped struct {
+		MergedLine
+		ts time.Time
+	}
+
+	// Iterate channels in a fixed order so equal timestamps merge deterministically
+	channels := make([]string, 0, len(feeds))
+	for channel := range feeds {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	var all []stamped
+	for _, channel := range channels {
+		lines := feeds[channel]
+
+		// Lines before the first header inherit the first header's time
+		var last time.Time
+		for _, line := range lines {
+			if ts, ok := headerFmt.ParseTime(line); ok {
+				last = ts
+				break
+			}
+		}
+
+		for _, line := range lines {
+			if ts, ok := headerFmt.ParseTime(line); ok {
+				last = ts
+			}
+			all = append(all, stamped{MergedLine{Channel: channel, Line: line}, last})
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].ts.Before(all[j].ts)
+	})
+
+	if len(all) > n {
+		all = all[len(all)-n:]
+	}
+	merged := make([]MergedLine, len(all))
+	for i, line := range all {
+		merged[i] = line.MergedLine
+	}
+	return merged
+}
+
+// logStatsScanLimit bounds how much of a log file the stats endpoint reads.
+// Line counts cover only this much; the newest timestamp comes from the tail.
+const logStatsScanLimit = 64 * 1024 * 1024
+
+// logStatsTailBytes is how much of the end of a large file is read to find
+// its newest header
+const logStatsTailBytes = 64 * 1024
+
+// LogFileStats summarizes a channel's current log file (rotated backups
+// aren't included)
+type LogFileStats struct {
+	Port       string     `json:"port"`
+	Path       string     `json:"path"`
+	Exists     bool       `json:"exists"`
+	SizeBytes  int64      `json:"size_bytes"`
+	ModTime    *time.Time `json:"mod_time,omitempty"`
+	Lines      int64      `json:"lines"`
+	LinesToday int64      `json:"lines_today"` // Lines whose header date is today (in the header time zone)
+	Oldest     *time.Time `json:"oldest,omitempty"`
+	Newest     *time.Time `json:"newest,omitempty"`
+	Truncated  bool       `json:"truncated"` // Only the first scan limit bytes were counted
+}
+
+// scanLogStats reads up to limit bytes of logPath, counting lines and noting
+// the first and last header timestamps. A missing file (not yet written, or
+// just rotated away) is reported with Exists false rather than an error.
+func scanLogStats(logPath string, headerFmt output.HeaderFormat, now time.Time, limit int64) (LogFileStats, error) {
+	stats := LogFileStats{Path: logPath}
+
+	file, err := os.Open(logPath)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return stats, err
+	}
+	stats.Exists = true
+	stats.SizeBytes = info.Size()
+	modTime := info.ModTime()
+	stats.ModTime = &modTime
+
+	today := headerFmt.FormatTimestamp(now)[:len("2006-01-02")]
+	var newest time.Time
+	scanner := bufio.NewScanner(io.LimitReader(file, limit))
+	scanner.Buffer(make([]byte, 64*1024), 2*1024*1024)
+	for scanner.Scan() {
+		stats.Lines++
+		ts, ok := headerFmt.ParseTime(scanner.Text())
+		if !ok {
+			continue
+		}
+		if stats.Oldest == nil {
+			oldest := ts
+			stats.Oldest = &oldest
+		}
+		newest = ts
+		if strings.HasPrefix(headerFmt.FormatTimestamp(ts), today) {
+			stats.LinesToday++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+
+	if stats.SizeBytes > limit {
+		stats.Truncated = true
+		if ts, ok := lastHeaderTime(file, stats.SizeBytes, headerFmt); ok {
+			newest = ts
+		}
+	}
+	if !newest.IsZero() {
+		stats.Newest = &newest
+	}
+	return stats, nil
+}
+
+// lastHeaderTime returns the newest header timestamp in the last
+// logStatsTailBytes of file
+func lastHeaderTime(file *os.File, size int64, headerFmt output.HeaderFormat) (time.Time, bool) {
+	offset := size - logStatsTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, size-offset)
+	n, err := file.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return time.Time{}, false
+	}
+
+	lines := strings.Split(string(buf[:n]), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if ts, ok := headerFmt.ParseTime(lines[i]); ok {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// backupTimeFormat is the timestamp lumberjack puts in rotated file names,
+// e.g. 1429010002-A1-2025-12-03T15-04-05.000.log(.gz)
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// tailLogWithBackups returns the last n lines of a log, continuing into
+// lumberjack's rotated backups (newest first, gzipped or not) when the
+// current file has fewer than n lines - e.g. right after a rotation.
+func tailLogWithBackups(logPath string, n int) ([]string, error) {
+	lines, err := tailFile(logPath, n)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	primaryErr := err
+
+	if len(lines) >= n {
+		return lines, nil
+	}
+
+	backups := rotatedBackups(logPath)
+	if primaryErr != nil && len(backups) == 0 {
+		return nil, primaryErr
+	}
+
+	for _, backup := range backups {
+		older, err := tailFile(backup, n-len(lines))
+		if err != nil {
+			// Backup may have been pruned by lumberjack since we listed it
+			continue
+		}
+		lines = append(older, lines...)
+		if len(lines) >= n {
+			break
+		}
+	}
+
+	if lines == nil {
+		lines = []string{}
+	}
+	return lines, nil
+}
+
+// rotatedBackups lists lumberjack backups of logPath, newest first
+func rotatedBackups(logPath string) []string {
+	dir := filepath.Dir(logPath)
+	ext := filepath.Ext(logPath)
+	prefix := strings.TrimSuffix(filepath.Base(logPath), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	type backup struct {
+		path string
+		ts   time.Time
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		// Parsing the timestamp also rules out other channels sharing the prefix (A1 vs A10)
+		ts, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), ts: ts})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].ts.After(backups[j].ts) })
+
+	paths := make([]string, len(backups))
+	for i, b := range backups {
+		paths[i] = b.path
+	}
+	return paths
+}
+
+// tailFile returns the last n lines from a file, decompressing .gz files.
+// Uses a ring buffer to keep memory bounded regardless of file size.
+func tailFile(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	// Ring buffer to hold last n lines
+	ring := make([]string, n)
+	idx := 0
+	count := 0
+
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		ring[idx] = scanner.Text()
 		idx = (idx + 1) % n
@@ -983,8 +1856,8 @@ func (s *Server) handlePortsConfig(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := s.manager.AddPort(portCfg); err != nil {
-			if strings.Contains(err.Error(), "already") || strings.Contains(err.Error(), "required") {
+		if err := s.manager.AddPort(portCfg, requestSource(r)); err != nil {
+			if strings.Contains(err.Error(), "already") || strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "invalid port") {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 			} else {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1023,12 +1896,14 @@ func (s *Server) handleAvailablePorts(w http.ResponseWriter, r *http.Request) {
 
 // handlePortConfigAction handles port enable/disable/update actions
 // Routes:
-//   - PUT /api/ports/config/{id} - Update port settings
+//   - PUT /api/ports/config/{id} - Update port settings (?dry_run=true to preview)
 //   - POST /api/ports/config/{id}/enable - Enable port
 //   - POST /api/ports/config/{id}/disable - Disable port
+//   - POST /api/ports/config/{id}/redetect - Re-run autobaud on a serial port
 func (s *Server) handlePortConfigAction(w http.ResponseWriter, r *http.Request) {
 	// Parse path: /api/ports/config/{id} or /api/ports/config/{id}/{action}
-	path := strings.TrimPrefix(r.URL.Path, "/api/ports/config/")
+	// Split the escaped path so an encoded slash in {id} stays within the segment
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/ports/config/")
 	parts := strings.Split(path, "/")
 
 	if len(parts) == 0 || parts[0] == "" {
@@ -1054,6 +1929,10 @@ func (s *Server) handlePortConfigAction(w http.ResponseWriter, r *http.Request)
 		s.handlePortEnable(w, r, portID)
 	case action == "disable" && r.Method == http.MethodPost:
 		s.handlePortDisable(w, r, portID)
+	case action == "redetect" && r.Method == http.MethodPost:
+		s.handlePortRedetect(w, r, portID)
+	case (action == "pause" || action == "resume") && r.Method == http.MethodPost:
+		s.handlePortPause(w, r, portID, action == "pause")
 	case action == "" && r.Method == http.MethodPut:
 		s.handlePortUpdate(w, r, portID)
 	case action == "" && r.Method == http.MethodGet:
@@ -1065,12 +1944,108 @@ func (s *Server) handlePortConfigAction(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// decodePortID decodes a URL-encoded port ID
+// handlePortAction handles diagnostics on a single port:
+// POST /api/ports/{id}/selftest
+// GET /api/ports/{id}/logs/stats
+func (s *Server) handlePortAction(w http.ResponseWriter, r *http.Request) {
+	// Split the escaped path so an encoded slash in {id} stays within the segment
+	parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/api/ports/"), "/")
+	action := strings.Join(parts[1:], "/")
+	var method string
+	switch action {
+	case "selftest":
+		method = http.MethodPost
+	case "logs/stats":
+		method = http.MethodGet
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != method {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	portID, err := decodePortID(parts[0])
+	if err != nil {
+		http.Error(w, "Invalid port ID", http.StatusBadRequest)
+		return
+	}
+	if portID == "" {
+		http.Error(w, "Port ID required", http.StatusBadRequest)
+		return
+	}
+
+	if action == "logs/stats" {
+		s.handlePortLogStats(w, r, portID)
+		return
+	}
+	s.handlePortSelfTest(w, r, portID)
+}
+
+// handlePortLogStats summarizes a port's current log file without the caller
+// having to download it
+func (s *Server) handlePortLogStats(w http.ResponseWriter, r *http.Request, portID string) {
+	logPath, err := s.manager.PortLogPath(portID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	headerFmt, err := output.NewHeaderFormat(s.manager.Config().App.TimeZone, s.manager.Config().App.TimestampPrecision)
+	if err != nil {
+		s.logger.Warn("Invalid header settings, parsing headers as UTC", "error", err)
+	}
+	stats, err := scanLogStats(logPath, headerFmt, time.Now(), logStatsScanLimit)
+	if err != nil {
+		s.logger.Warn("Failed to scan log file", "path", logPath, "error", err)
+		http.Error(w, "Failed to read log file", http.StatusInternalServerError)
+		return
+	}
+	stats.Port = portID
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// handlePortSelfTest opens an idle serial port briefly and reports signals and
+// any data seen; a running port's live stats are returned instead
+func (s *Server) handlePortSelfTest(w http.ResponseWriter, r *http.Request, portID string) {
+	s.logger.Info("Port self-test requested via API", "port", portID, "source", requestSource(r))
+
+	report, err := s.manager.SelfTestPort(portID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "not a serial port"):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			// Device missing, busy, or failed to open - a finding, not a server fault
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// requestSource identifies who made an API request for the config audit
+// trail: the basic auth user (if any) and remote address
+func requestSource(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		return user + "@" + r.RemoteAddr
+	}
+	return r.RemoteAddr
+}
+
+// decodePortID decodes a URL-encoded port ID (e.g., %2Fcdr -> /cdr)
 func decodePortID(encoded string) (string, error) {
-	// Handle URL encoding (e.g., %2F for /)
-	decoded := encoded
-	decoded = strings.ReplaceAll(decoded, "%2F", "/")
-	decoded = strings.ReplaceAll(decoded, "%2f", "/")
+	decoded, err := url.PathUnescape(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid port ID encoding %q: %w", encoded, err)
+	}
 	return decoded, nil
 }
 
@@ -1080,6 +2055,10 @@ func (s *Server) handlePortGet(w http.ResponseWriter, r *http.Request, portID st
 
 	for _, port := range ports {
 		if port.ID == portID {
+			// The preview may contain PII, so it's only served behind auth
+			if s.config.Username != "" && s.config.Password != "" {
+				port.LastRecord, _ = s.manager.PortLastRecord(portID)
+			}
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(port)
 			return
@@ -1091,7 +2070,7 @@ func (s *Server) handlePortGet(w http.ResponseWriter, r *http.Request, portID st
 
 // handlePortEnable enables a disabled port
 func (s *Server) handlePortEnable(w http.ResponseWriter, r *http.Request, portID string) {
-	if err := s.manager.EnablePort(portID); err != nil {
+	if err := s.manager.EnablePort(portID, requestSource(r)); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else if strings.Contains(err.Error(), "already enabled") {
@@ -1111,9 +2090,60 @@ func (s *Server) handlePortEnable(w http.ResponseWriter, r *http.Request, portID
 	})
 }
 
+// handlePortRedetect makes a serial channel abandon its session and re-run
+// detection, ignoring configured baud/flow control for that one session
+func (s *Server) handlePortRedetect(w http.ResponseWriter, r *http.Request, portID string) {
+	if err := s.manager.RedetectPort(portID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			// HTTP ports and disabled ports have nothing to detect
+			http.Error(w, err.Error(), http.StatusConflict)
+		}
+		return
+	}
+
+	s.logger.Info("Port re-detection triggered via API", "port", portID, "source", requestSource(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "ok",
+		"message": fmt.Sprintf("Port %s re-detection triggered", portID),
+	})
+}
+
+// handlePortPause pauses or resumes a running serial, TCP or file port
+func (s *Server) handlePortPause(w http.ResponseWriter, r *http.Request, portID string, pause bool) {
+	var err error
+	action := "resumed"
+	if pause {
+		action = "paused"
+		err = s.manager.PausePort(portID, requestSource(r))
+	} else {
+		err = s.manager.ResumePort(portID, requestSource(r))
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			// Already in that state, not running, or an HTTP/UDP port
+			http.Error(w, err.Error(), http.StatusConflict)
+		}
+		return
+	}
+
+	s.logger.Info("Port "+action+" via API", "port", portID, "source", requestSource(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "ok",
+		"message": fmt.Sprintf("Port %s %s", portID, action),
+	})
+}
+
 // handlePortDisable disables an enabled port
 func (s *Server) handlePortDisable(w http.ResponseWriter, r *http.Request, portID string) {
-	if err := s.manager.DisablePort(portID); err != nil {
+	if err := s.manager.DisablePort(portID, requestSource(r)); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else if strings.Contains(err.Error(), "already disabled") {
@@ -1135,7 +2165,7 @@ func (s *Server) handlePortDisable(w http.ResponseWriter, r *http.Request, portI
 
 // handlePortDelete removes a port configuration
 func (s *Server) handlePortDelete(w http.ResponseWriter, r *http.Request, portID string) {
-	if err := s.manager.DeletePort(portID); err != nil {
+	if err := s.manager.DeletePort(portID, requestSource(r)); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
@@ -1168,19 +2198,29 @@ func (s *Server) handlePortUpdate(w http.ResponseWriter, r *http.Request, portID
 	}
 
 	// Validate updates
-	if err := validatePortUpdates(updates); err != nil {
+	if err := validatePortUpdates(updates, s.manager.AllowsCustomBaud(portID)); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := s.manager.UpdatePortConfig(portID, updates); err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, err.Error(), http.StatusNotFound)
-		} else if strings.Contains(err.Error(), "unknown config field") {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+	// ?dry_run=true previews the change without applying it
+	if dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run")); dryRun {
+		plan, err := s.manager.PlanPortUpdate(portID, updates)
+		if err != nil {
+			writePortUpdateError(w, err)
+			return
 		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "ok",
+			"dry_run": true,
+			"plan":    plan,
+		})
+		return
+	}
+
+	if err := s.manager.UpdatePortConfig(portID, updates, requestSource(r)); err != nil {
+		writePortUpdateError(w, err)
 		return
 	}
 
@@ -1193,20 +2233,35 @@ func (s *Server) handlePortUpdate(w http.ResponseWriter, r *http.Request, portID
 	})
 }
 
-// validatePortUpdates validates port configuration updates
-func validatePortUpdates(updates map[string]interface{}) error {
+// writePortUpdateError maps a manager update error to an HTTP status
+func writePortUpdateError(w http.ResponseWriter, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		http.Error(w, err.Error(), http.StatusNotFound)
+	} else if strings.Contains(err.Error(), "unknown config field") ||
+		strings.Contains(err.Error(), "already in use") {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	} else {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// validatePortUpdates validates port configuration updates.
+// allowCustomBaud skips the standard baud rate whitelist (the port or
+// detection config has allow_custom_baud set); the update itself may also
+// set allow_custom_baud.
+func validatePortUpdates(updates map[string]interface{}, allowCustomBaud bool) error {
+	if v, ok := updates["allow_custom_baud"].(bool); ok {
+		allowCustomBaud = v
+	}
+
 	for key, value := range updates {
 		switch key {
 		case "baud_rate":
 			if v, ok := value.(float64); ok {
 				baud := int(v)
-				validBauds := []int{0, 300, 1200, 2400, 4800, 9600, 19200, 38400, 57600, 115200}
-				valid := false
-				for _, vb := range validBauds {
-					if baud == vb {
-						valid = true
-						break
-					}
+				valid := baud == 0 || config.ValidBaudRate(baud)
+				if allowCustomBaud && baud > 0 {
+					valid = true
 				}
 				if !valid {
 					return fmt.Errorf("invalid baud_rate: %d", baud)
@@ -1214,6 +2269,10 @@ func validatePortUpdates(updates map[string]interface{}) error {
 			} else {
 				return fmt.Errorf("baud_rate must be a number")
 			}
+		case "allow_custom_baud":
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("allow_custom_baud must be true or false")
+			}
 		case "data_bits":
 			if v, ok := value.(float64); ok {
 				bits := int(v)
@@ -1225,15 +2284,7 @@ func validatePortUpdates(updates map[string]interface{}) error {
 			}
 		case "parity":
 			if v, ok := value.(string); ok {
-				validParity := []string{"none", "odd", "even", "mark", "space"}
-				valid := false
-				for _, vp := range validParity {
-					if v == vp {
-						valid = true
-						break
-					}
-				}
-				if !valid {
+				if !config.ValidParity(v) {
 					return fmt.Errorf("parity must be one of: none, odd, even, mark, space")
 				}
 			} else {
@@ -1254,6 +2305,14 @@ func validatePortUpdates(updates map[string]interface{}) error {
 					return fmt.Errorf("use_flow_control must be true, false, or null")
 				}
 			}
+		case "flow_control":
+			if v, ok := value.(string); ok {
+				if v != "" && !config.ValidFlowControl(v) {
+					return fmt.Errorf("flow_control must be one of: none, hardware, software")
+				}
+			} else {
+				return fmt.Errorf("flow_control must be a string")
+			}
 		case "listen_port":
 			if v, ok := value.(float64); ok {
 				port := int(v)
@@ -1276,8 +2335,10 @@ func validatePortUpdates(updates map[string]interface{}) error {
 				return fmt.Errorf("path must be a string")
 			}
 		case "side_designation":
-			if _, ok := value.(string); !ok {
+			if v, ok := value.(string); !ok {
 				return fmt.Errorf("side_designation must be a string")
+			} else if v == "" {
+				return fmt.Errorf("side_designation cannot be empty")
 			}
 		case "fips_code":
 			if _, ok := value.(string); !ok {
@@ -1298,8 +2359,9 @@ func validatePortUpdates(updates map[string]interface{}) error {
 	return nil
 }
 
-// handleEvents returns recent events from the NATS events stream
-// Query params: count (default 50, max 200)
+// handleEvents returns recent events from the JetStream events stream.
+// Query params: count (default 50, max 200), type (comma-separated filter),
+// before_seq (cursor from a previous response's next_cursor to page back).
 func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -1315,77 +2377,340 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Get NATS connection from manager
-	natsConn := s.manager.NATSConn()
-	if natsConn == nil || !natsConn.IsConnected() {
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"events": []interface{}{},
-			"error":  "NATS not connected",
-		})
+	// Parse cursor - 0 means start from the newest event
+	var beforeSeq uint64
+	if before := r.URL.Query().Get("before_seq"); before != "" {
+		n, err := strconv.ParseUint(before, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid before_seq", http.StatusBadRequest)
+			return
+		}
+		beforeSeq = n
+	}
+
+	// Parse the optional time range; since switches to paging forward in time
+	var since, until time.Time
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		if v := r.URL.Query().Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "Invalid "+p.name+", want RFC3339 (e.g. 2025-12-03T15:00:00Z)", http.StatusBadRequest)
+				return
+			}
+			*p.dst = t
+		}
+	}
+	switch {
+	case !until.IsZero() && since.IsZero():
+		http.Error(w, "until requires since", http.StatusBadRequest)
+		return
+	case !until.IsZero() && !until.After(since):
+		http.Error(w, "until must be after since", http.StatusBadRequest)
+		return
+	case !since.IsZero() && beforeSeq != 0:
+		http.Error(w, "before_seq can't be combined with since", http.StatusBadRequest)
 		return
 	}
 
-	// Get JetStream context
-	js, err := natsConn.Conn().JetStream()
+	// Parse type filter
+	var types map[string]bool
+	if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+		types = make(map[string]bool)
+		for _, t := range strings.Split(typeParam, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types[t] = true
+			}
+		}
+	}
+
+	source := s.events
+	if source == nil {
+		var errMsg string
+		if source, errMsg = s.jetStreamEvents(); source == nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"events": []interface{}{},
+				"error":  errMsg,
+			})
+			return
+		}
+	}
+
+	windows := s.config.EventScanWindowLimit()
+	if !since.IsZero() {
+		s.handleEventsSince(w, source, since, until, count, types, windows)
+		return
+	}
+
+	page, nextCursor, err := pageEvents(source, beforeSeq, count, types, windows)
 	if err != nil {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"events": []interface{}{},
-			"error":  "JetStream not available",
+			"error":  err.Error(),
 		})
 		return
 	}
 
-	// Get events stream info to find last sequence
-	streamInfo, err := js.StreamInfo("events")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events":      rawEvents(page),
+		"count":       len(page),
+		"stream":      "events",
+		"next_cursor": nextCursor,
+	})
+}
+
+// handleEventsSince answers a since/until query. next_since is where the
+// next page starts, omitted once the range (or stream) is exhausted.
+func (s *Server) handleEventsSince(w http.ResponseWriter, source eventSource, since, until time.Time, count int, types map[string]bool, windows int) {
+	page, nextSince, err := pageEventsSince(source, since, until, count, types, windows)
 	if err != nil {
-		// Stream might not exist yet - return empty
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"events": []interface{}{},
-			"error":  "Events stream not found",
+			"error":  err.Error(),
 		})
 		return
 	}
 
-	// Calculate start sequence for last N messages
-	lastSeq := streamInfo.State.LastSeq
-	startSeq := uint64(1)
-	if lastSeq > uint64(count) {
-		startSeq = lastSeq - uint64(count) + 1
+	resp := map[string]interface{}{
+		"events": rawEvents(page),
+		"count":  len(page),
+		"stream": "events",
+	}
+	if !nextSince.IsZero() {
+		resp["next_since"] = nextSince.UTC().Format(time.RFC3339Nano)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// rawEvents returns the event payloads for a JSON response
+func rawEvents(page []storedEvent) []json.RawMessage {
+	events := make([]json.RawMessage, 0, len(page))
+	for _, ev := range page {
+		events = append(events, json.RawMessage(ev.Data))
+	}
+	return events
+}
+
+// storedEvent is an event payload with its stream sequence number and the
+// time the stream stored it
+type storedEvent struct {
+	Seq  uint64
+	Time time.Time
+	Data []byte
+}
+
+// eventSource reads events by stream sequence. JetStream in production;
+// tests substitute a fixed set.
+type eventSource interface {
+	// LastSeq returns the newest sequence in the stream
+	LastSeq() (uint64, error)
+	// FetchFrom returns up to max events with sequence >= startSeq, oldest first
+	FetchFrom(startSeq uint64, max int) ([]storedEvent, error)
+	// FetchSince returns up to max events stored at or after since, oldest first
+	FetchSince(since time.Time, max int) ([]storedEvent, error)
+}
+
+// pageEvents returns up to count events older than beforeSeq (0 = newest),
+// oldest first, optionally filtered by type. Windows of count sequences are
+// scanned backwards until the page is full, at most windows of them so a
+// rare type can't walk the whole stream in one request. The returned cursor
+// is the before_seq for the next page, or 0 once the start of the stream is
+// reached.
+func pageEvents(source eventSource, beforeSeq uint64, count int, types map[string]bool, windows int) ([]storedEvent, uint64, error) {
+	end := beforeSeq - 1
+	if beforeSeq == 0 {
+		lastSeq, err := source.LastSeq()
+		if err != nil {
+			return nil, 0, err
+		}
+		end = lastSeq
+	}
+
+	var matched []storedEvent
+	for scans := 0; scans < windows && end >= 1 && len(matched) < count; scans++ {
+		start := uint64(1)
+		if end > uint64(count) {
+			start = end - uint64(count) + 1
+		}
+
+		batch, err := source.FetchFrom(start, int(end-start+1))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var window []storedEvent
+		for _, ev := range batch {
+			if ev.Seq > end {
+				continue
+			}
+			if len(types) > 0 && !types[eventType(ev.Data)] {
+				continue
+			}
+			window = append(window, ev)
+		}
+		matched = append(window, matched...)
+		end = start - 1
+	}
+
+	// Keep the newest count; the rest are the next page
+	if len(matched) > count {
+		matched = matched[len(matched)-count:]
+		return matched, matched[0].Seq, nil
+	}
+	if end == 0 {
+		return matched, 0, nil
+	}
+	return matched, end + 1, nil
+}
+
+// pageEventsSince returns up to count events stored in [since, until)
+// (zero until = no end), oldest first, optionally filtered by type. The first
+// window starts at since; later ones continue by sequence, at most windows
+// of count events in all. The returned time is the since for the next page,
+// or zero once the range or the stream is exhausted.
+func pageEventsSince(source eventSource, since, until time.Time, count int, types map[string]bool, windows int) ([]storedEvent, time.Time, error) {
+	var matched []storedEvent
+	var last storedEvent
+	for scans := 0; scans < windows; scans++ {
+		var batch []storedEvent
+		var err error
+		if scans == 0 {
+			batch, err = source.FetchSince(since, count)
+		} else {
+			batch, err = source.FetchFrom(last.Seq+1, count)
+		}
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		for _, ev := range batch {
+			if !until.IsZero() && !ev.Time.Before(until) {
+				return matched, time.Time{}, nil
+			}
+			last = ev
+			if len(types) > 0 && !types[eventType(ev.Data)] {
+				continue
+			}
+			matched = append(matched, ev)
+			if len(matched) == count {
+				return matched, ev.Time.Add(time.Nanosecond), nil
+			}
+		}
+
+		// A short batch means the end of the stream
+		if len(batch) < count {
+			return matched, time.Time{}, nil
+		}
+	}
+
+	// Scan bound reached; resume after the last event looked at
+	return matched, last.Time.Add(time.Nanosecond), nil
+}
+
+// eventType extracts the type field from an event payload
+func eventType(data []byte) string {
+	var ev struct {
+		Type string `json:"type"`
 	}
+	json.Unmarshal(data, &ev)
+	return ev.Type
+}
+
+// eventStream is the part of nats.JetStreamContext the events API reads with
+type eventStream interface {
+	StreamInfo(stream string, opts ...nats.JSOpt) (*nats.StreamInfo, error)
+	PullSubscribe(subj, durable string, opts ...nats.SubOpt) (*nats.Subscription, error)
+}
+
+// jetStreamEventSource reads this instance's events from the "events" stream
+type jetStreamEventSource struct {
+	js      eventStream
+	subject string
+	logger  *slog.Logger
+}
+
+// Consumer start options, swappable so tests can see how a read starts
+var (
+	eventsStartSequence = nats.StartSequence
+	eventsStartTime     = nats.StartTime
+)
 
-	// Create ephemeral pull consumer starting at calculated sequence
-	eventsSubject := s.manager.EventsSubject()
-	sub, err := js.PullSubscribe(
-		eventsSubject,
+// jetStreamEvents returns the production event source, or nil and a reason
+// it's unavailable
+func (s *Server) jetStreamEvents() (eventSource, string) {
+	// Get NATS connection from manager
+	natsConn := s.manager.NATSConn()
+	if natsConn == nil || !natsConn.IsConnected() {
+		return nil, "NATS not connected"
+	}
+
+	// Get JetStream context
+	js, err := natsConn.Conn().JetStream()
+	if err != nil {
+		return nil, "JetStream not available"
+	}
+
+	// Stream might not exist yet
+	if _, err := js.StreamInfo("events"); err != nil {
+		return nil, "Events stream not found"
+	}
+
+	return &jetStreamEventSource{
+		js:      js,
+		subject: s.manager.EventsSubject(),
+		logger:  s.logger,
+	}, ""
+}
+
+// LastSeq returns the events stream's last sequence
+func (j *jetStreamEventSource) LastSeq() (uint64, error) {
+	streamInfo, err := j.js.StreamInfo("events")
+	if err != nil {
+		return 0, fmt.Errorf("events stream not found: %w", err)
+	}
+	return streamInfo.State.LastSeq, nil
+}
+
+// FetchFrom reads events starting at startSeq with an ephemeral pull consumer
+func (j *jetStreamEventSource) FetchFrom(startSeq uint64, max int) ([]storedEvent, error) {
+	return j.fetch(eventsStartSequence(startSeq), max)
+}
+
+// FetchSince reads events stored at or after since with an ephemeral pull
+// consumer, so the server seeks by time instead of us scanning sequences
+func (j *jetStreamEventSource) FetchSince(since time.Time, max int) ([]storedEvent, error) {
+	return j.fetch(eventsStartTime(since), max)
+}
+
+// fetch reads up to max events with an ephemeral pull consumer starting at start
+func (j *jetStreamEventSource) fetch(start nats.SubOpt, max int) ([]storedEvent, error) {
+	sub, err := j.js.PullSubscribe(
+		j.subject,
 		"", // ephemeral (no durable name)
-		nats.StartSequence(startSeq),
+		start,
 		nats.BindStream("events"),
 	)
 	if err != nil {
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"events": []interface{}{},
-			"error":  fmt.Sprintf("Failed to subscribe: %v", err),
-		})
-		return
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
 	}
 	defer sub.Unsubscribe()
 
 	// Fetch messages with short timeout
-	msgs, err := sub.Fetch(count, nats.MaxWait(2*time.Second))
+	msgs, err := sub.Fetch(max, nats.MaxWait(2*time.Second))
 	if err != nil && err != nats.ErrTimeout {
-		s.logger.Warn("Error fetching events", "error", err)
+		j.logger.Warn("Error fetching events", "error", err)
 	}
 
-	// Parse and return events
-	events := make([]json.RawMessage, 0, len(msgs))
+	events := make([]storedEvent, 0, len(msgs))
 	for _, msg := range msgs {
-		events = append(events, json.RawMessage(msg.Data))
+		meta, err := msg.Metadata()
+		if err != nil {
+			continue
+		}
+		events = append(events, storedEvent{Seq: meta.Sequence.Stream, Time: meta.Timestamp, Data: msg.Data})
 		msg.Ack()
 	}
-
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"events": events,
-		"count":  len(events),
-		"stream": "events",
-	})
+	return events, nil
 }
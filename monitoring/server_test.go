@@ -1,16 +1,38 @@
 package monitoring
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
 	"log/slog"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"nectarcollector/capture"
 	"nectarcollector/config"
+	"nectarcollector/output"
+
+	"github.com/nats-io/nats.go"
 )
 
 func newTestManager() *capture.Manager {
@@ -80,6 +102,42 @@ func TestHandleHealth(t *testing.T) {
 	if _, ok := response["timestamp"]; !ok {
 		t.Error("Response should include timestamp")
 	}
+	sse, ok := response["sse"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Response should include sse broker stats")
+	}
+	if _, ok := sse["dropped_client_sends"]; !ok {
+		t.Error("sse stats should include dropped_client_sends")
+	}
+}
+
+func TestHandleReadyNotReady(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+
+	req := httptest.NewRequest("GET", "/api/ready", nil)
+	rr := httptest.NewRecorder()
+	server.handleReady(rr, req)
+
+	// No NATS and no channels: alive but not ready
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("handleReady() status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response["ready"] != false || response["reason"] != "NATS not connected" {
+		t.Errorf("response = %v, want not ready because of NATS", response)
+	}
+
+	// Liveness is unaffected
+	rr = httptest.NewRecorder()
+	server.handleHealth(rr, httptest.NewRequest("GET", "/api/health", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("handleHealth() status = %d, want %d", rr.Code, http.StatusOK)
+	}
 }
 
 func TestHandleStats(t *testing.T) {
@@ -527,6 +585,151 @@ func TestHandlePortsConfig(t *testing.T) {
 	}
 }
 
+func TestHandlePortsConfigPostInvalid(t *testing.T) {
+	cfg := &config.MonitoringConfig{Port: 8080}
+	manager := newTestManagerWithPorts()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewServer(cfg, manager, "/var/log", logger, "1.0.0")
+
+	body := `{"type":"serial","device":"/dev/ttyS9","side_designation":"A9","baud_rate":12345}`
+	req := httptest.NewRequest("POST", "/api/ports/config", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	server.handlePortsConfig(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d (body %q)", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "baud_rate") {
+		t.Errorf("body = %q, want the baud_rate error", rr.Body.String())
+	}
+	if n := len(manager.GetPortConfigs()); n != 2 {
+		t.Errorf("port count = %d after rejected add, want 2", n)
+	}
+}
+
+func TestHandlePortUpdateDryRun(t *testing.T) {
+	cfg := &config.MonitoringConfig{Port: 8080}
+	manager := newTestManagerWithPorts()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(cfg, manager, "/var/log", logger, "1.0.0")
+
+	tests := []struct {
+		name        string
+		body        string
+		wantRestart bool
+		wantSubject string
+		wantIdent   string
+	}{
+		{
+			name:        "restart and subject change",
+			body:        `{"baud_rate": 19200, "vendor": "intrado"}`,
+			wantRestart: true,
+			wantSubject: ".intrado.3100000000",
+			wantIdent:   "3100000000-A1",
+		},
+		{
+			name:        "description only",
+			body:        `{"description": "Front desk"}`,
+			wantRestart: false,
+			wantSubject: ".3100000000",
+			wantIdent:   "3100000000-A1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/ports/config/ttyS1?dry_run=true", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			server.handlePortConfigAction(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+			}
+
+			var response struct {
+				DryRun bool                   `json:"dry_run"`
+				Plan   capture.PortUpdatePlan `json:"plan"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if !response.DryRun {
+				t.Error("dry_run should be true")
+			}
+			plan := response.Plan
+			if plan.NeedsRestart != tt.wantRestart || plan.WillRestart != tt.wantRestart {
+				t.Errorf("needs_restart = %v, will_restart = %v, want %v", plan.NeedsRestart, plan.WillRestart, tt.wantRestart)
+			}
+			if plan.NATSSubject != tt.wantSubject {
+				t.Errorf("nats_subject = %q, want %q", plan.NATSSubject, tt.wantSubject)
+			}
+			if plan.SubjectChanged != (tt.wantSubject != ".3100000000") {
+				t.Errorf("subject_changed = %v", plan.SubjectChanged)
+			}
+			if plan.Identifier != tt.wantIdent {
+				t.Errorf("identifier = %q, want %q", plan.Identifier, tt.wantIdent)
+			}
+		})
+	}
+
+	// Config must be untouched
+	for _, p := range manager.GetPortConfigs() {
+		if p.ID != "ttyS1" {
+			continue
+		}
+		if p.Config.BaudRate != 9600 || p.Vendor != "" {
+			t.Errorf("dry run mutated config: %+v", p)
+		}
+	}
+
+	// Errors map the same way as a real update
+	req := httptest.NewRequest("PUT", "/api/ports/config/ttyS1?dry_run=true", strings.NewReader(`{"side_designation": "B1"}`))
+	rr := httptest.NewRecorder()
+	server.handlePortConfigAction(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("collision status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandlePortRedetect(t *testing.T) {
+	cfg := &config.MonitoringConfig{Port: 8080}
+	manager := newTestManagerWithPorts()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(cfg, manager, "/var/log", logger, "1.0.0")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"serial port not running", "POST", "/api/ports/config/ttyS1/redetect", http.StatusConflict},
+		{"unknown port", "POST", "/api/ports/config/ttyS9/redetect", http.StatusNotFound},
+		{"wrong method", "GET", "/api/ports/config/ttyS1/redetect", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rr := httptest.NewRecorder()
+
+			server.handlePortConfigAction(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+
+	// HTTP endpoints have nothing to detect
+	rr := httptest.NewRecorder()
+	server.handlePortRedetect(rr, httptest.NewRequest("POST", "/api/ports/config/%2Fcdr/redetect", nil), "/cdr")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("HTTP port status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+}
+
 func TestHandlePortConfigGetNotFound(t *testing.T) {
 	cfg := &config.MonitoringConfig{Port: 8080}
 	manager := newTestManager()
@@ -619,9 +822,10 @@ func TestHandleAvailablePorts(t *testing.T) {
 
 func TestValidatePortUpdates(t *testing.T) {
 	tests := []struct {
-		name    string
-		updates map[string]interface{}
-		wantErr bool
+		name            string
+		updates         map[string]interface{}
+		allowCustomBaud bool
+		wantErr         bool
 	}{
 		{
 			name:    "empty updates",
@@ -712,14 +916,2039 @@ func TestValidatePortUpdates(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "custom baud rate rejected by default",
+			updates: map[string]interface{}{
+				"baud_rate": float64(230400),
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom baud rate allowed for port",
+			updates: map[string]interface{}{
+				"baud_rate": float64(230400),
+			},
+			allowCustomBaud: true,
+			wantErr:         false,
+		},
+		{
+			name: "custom baud rate enabled in same update",
+			updates: map[string]interface{}{
+				"baud_rate":         float64(230400),
+				"allow_custom_baud": true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative custom baud rate",
+			updates: map[string]interface{}{
+				"baud_rate": float64(-9600),
+			},
+			allowCustomBaud: true,
+			wantErr:         true,
+		},
+		{
+			name: "empty side designation",
+			updates: map[string]interface{}{
+				"side_designation": "",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validatePortUpdates(tt.updates)
+			err := validatePortUpdates(tt.updates, tt.allowCustomBaud)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("validatePortUpdates() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+// writeTestPKI creates a CA, a server cert for 127.0.0.1 and a client cert,
+// all signed by the CA, and returns the directory holding the PEM files
+func writeTestPKI(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	writePEM := func(name, blockType string, der []byte) {
+		data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	caKey := newKey()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+	writePEM("ca.pem", "CERTIFICATE", caDER)
+
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) {
+		key := newKey()
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writePEM(name+".pem", "CERTIFICATE", der)
+		writePEM(name+"-key.pem", "EC PRIVATE KEY", keyDER)
+	}
+	issue("server", 2, x509.ExtKeyUsageServerAuth)
+	issue("client", 3, x509.ExtKeyUsageClientAuth)
+
+	return dir
+}
+
+func TestHTTPCaptureServerMutualTLS(t *testing.T) {
+	pki := writeTestPKI(t)
+	logDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	// Grab a free port for the capture server
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	portCfg := config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		ListenPort:      port,
+		SideDesignation: "A1",
+		FIPSCode:        "1429010002",
+		TLSCertFile:     filepath.Join(pki, "server.pem"),
+		TLSKeyFile:      filepath.Join(pki, "server-key.pem"),
+		TLSClientCAFile: filepath.Join(pki, "ca.pem"),
+		Enabled:         true,
+	}
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       portCfg.Path,
+		Identifier:   "1429010002-A1",
+		LogBasePath:  logDir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := capture.NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+	defer ch.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), logDir, logger, "1.0.0")
+	if err := server.startHTTPCaptureServer(port, []*capture.HTTPChannel{ch}); err != nil {
+		t.Fatalf("startHTTPCaptureServer() error = %v", err)
+	}
+	defer server.Stop(context.Background())
+
+	caPEM, err := os.ReadFile(filepath.Join(pki, "ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caPEM)
+	clientCert, err := tls.LoadX509KeyPair(filepath.Join(pki, "client.pem"), filepath.Join(pki, "client-key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url := fmt.Sprintf("https://127.0.0.1:%d/cdr", port)
+	post := func(certs []tls.Certificate) (*http.Response, error) {
+		client := &http.Client{
+			Timeout: 2 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs},
+			},
+		}
+		return client.Post(url, "text/plain", strings.NewReader("CALL 001 IN"))
+	}
+
+	// The server starts in a goroutine - retry until it's listening
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = post([]tls.Certificate{clientCert})
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("POST with client cert failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST with client cert status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if resp, err := post(nil); err == nil {
+		resp.Body.Close()
+		t.Error("POST without client cert should fail the TLS handshake")
+	}
+
+	data, err := os.ReadFile(filepath.Join(logDir, "1429010002-A1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "CALL 001 IN") {
+		t.Errorf("captured log missing record body: %q", data)
+	}
+	if strings.Count(string(data), "[1429010002][A1]") != 1 {
+		t.Errorf("expected exactly one captured record, got: %q", data)
+	}
+}
+
+func TestBuildCaptureTLSConfigPlainHTTP(t *testing.T) {
+	tlsConfig, err := buildCaptureTLSConfig(config.PortConfig{Type: config.PortTypeHTTP, Path: "/cdr"})
+	if err != nil {
+		t.Fatalf("buildCaptureTLSConfig() error = %v", err)
+	}
+	if tlsConfig != nil {
+		t.Error("buildCaptureTLSConfig() should return nil without a certificate")
+	}
+}
+
+func TestHandleSystemWithoutProc(t *testing.T) {
+	// Point at a directory with no meminfo to simulate macOS/dev machines
+	origProcDir := procDir
+	procDir = filepath.Join(t.TempDir(), "proc")
+	defer func() { procDir = origProcDir }()
+
+	cfg := &config.MonitoringConfig{Port: 8080}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+
+	req := httptest.NewRequest("GET", "/api/system", nil)
+	w := httptest.NewRecorder()
+	server.handleSystem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var info SystemInfo
+	if err := json.NewDecoder(w.Body).Decode(&info); err != nil {
+		t.Fatalf("failed to decode SystemInfo: %v", err)
+	}
+	if info.Platform != "unsupported" {
+		t.Errorf("Platform = %q, want %q", info.Platform, "unsupported")
+	}
+	if info.Note == "" {
+		t.Error("Note should explain the fallback")
+	}
+	if info.Version != "1.0.0" {
+		t.Errorf("Version = %q, want %q", info.Version, "1.0.0")
+	}
+	if info.CPU.NumCPU < 1 {
+		t.Errorf("CPU.NumCPU = %d, want >= 1", info.CPU.NumCPU)
+	}
+	if info.GoRoutines < 1 {
+		t.Errorf("GoRoutines = %d, want >= 1", info.GoRoutines)
+	}
+	if info.Uptime < 0 {
+		t.Errorf("Uptime = %d, want >= 0", info.Uptime)
+	}
+	if info.Memory != (MemoryInfo{}) {
+		t.Errorf("Memory = %+v, want zero value without /proc", info.Memory)
+	}
+}
+
+func TestCollectSystemInfoReadsProcDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"uptime":  "12345.67 9999.00\n",
+		"loadavg": "0.50 0.25 0.10 1/234 5678\n",
+		"meminfo": "MemTotal:        2048000 kB\nMemFree:          512000 kB\nBuffers:           0 kB\nCached:            0 kB\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	origProcDir := procDir
+	procDir = dir
+	defer func() { procDir = origProcDir }()
+
+	info := collectSystemInfo("1.0.0")
+
+	if info.Platform == "unsupported" {
+		t.Fatalf("Platform = %q, want the /proc path", info.Platform)
+	}
+	if info.Uptime != 12345 {
+		t.Errorf("Uptime = %d, want 12345", info.Uptime)
+	}
+	if info.CPU.LoadAvg1 != 0.5 || info.CPU.LoadAvg15 != 0.1 {
+		t.Errorf("LoadAvg = %v/%v, want 0.5/0.1", info.CPU.LoadAvg1, info.CPU.LoadAvg15)
+	}
+	if info.Memory.TotalMB != 2000 || info.Memory.FreeMB != 500 {
+		t.Errorf("Memory = %+v, want 2000MB total, 500MB free", info.Memory)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	cfg := &config.MonitoringConfig{
+		Port:           8080,
+		AllowedOrigins: []string{"https://ui.example.com"},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+
+	handler := server.cors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantAllowed string
+	}{
+		{"allowed origin", "GET", "/api/stats", "https://ui.example.com", false, http.StatusOK, "https://ui.example.com"},
+		{"denied origin", "GET", "/api/stats", "https://evil.example.com", false, http.StatusOK, ""},
+		{"preflight allowed", "OPTIONS", "/api/stats", "https://ui.example.com", true, http.StatusNoContent, "https://ui.example.com"},
+		{"preflight denied", "OPTIONS", "/api/stats", "https://evil.example.com", true, http.StatusForbidden, ""},
+		{"non-api path untouched", "GET", "/", "https://ui.example.com", false, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "PUT")
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowed {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowed)
+			}
+			if tt.preflight && tt.wantAllowed != "" {
+				if !strings.Contains(rr.Header().Get("Access-Control-Allow-Methods"), "PUT") {
+					t.Errorf("Access-Control-Allow-Methods = %q, should include PUT", rr.Header().Get("Access-Control-Allow-Methods"))
+				}
+			}
+		})
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	cfg := &config.MonitoringConfig{
+		Port:           8080,
+		AllowedOrigins: []string{"*", "https://ui.example.com"},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+
+	handler := server.cors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name            string
+		origin          string
+		wantAllowed     string
+		wantCredentials string
+	}{
+		{"listed origin gets credentials", "https://ui.example.com", "https://ui.example.com", "true"},
+		{"wildcard origin has no credentials", "https://other.example.com", "*", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/stats", nil)
+			req.Header.Set("Origin", tt.origin)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowed {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowed)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+		})
+	}
+}
+
+// mockEventSource serves a fixed set of events by sequence
+type mockEventSource struct {
+	events []storedEvent
+}
+
+func (m *mockEventSource) LastSeq() (uint64, error) {
+	if len(m.events) == 0 {
+		return 0, nil
+	}
+	return m.events[len(m.events)-1].Seq, nil
+}
+
+func (m *mockEventSource) FetchFrom(startSeq uint64, max int) ([]storedEvent, error) {
+	var result []storedEvent
+	for _, ev := range m.events {
+		if ev.Seq >= startSeq && len(result) < max {
+			result = append(result, ev)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockEventSource) FetchSince(since time.Time, max int) ([]storedEvent, error) {
+	var result []storedEvent
+	for _, ev := range m.events {
+		if !ev.Time.Before(since) && len(result) < max {
+			result = append(result, ev)
+		}
+	}
+	return result, nil
+}
+
+// mockEventEpoch is when mock event 1 was stored; event n is n-1 minutes later
+var mockEventEpoch = time.Date(2025, 12, 3, 15, 0, 0, 0, time.UTC)
+
+// newMockEventSource creates sequences 1..n; every third event is a reconnect,
+// every fifth an error, the rest state changes
+func newMockEventSource(n int) *mockEventSource {
+	m := &mockEventSource{}
+	for seq := 1; seq <= n; seq++ {
+		eventType := "state_change"
+		switch {
+		case seq%5 == 0:
+			eventType = "error"
+		case seq%3 == 0:
+			eventType = "reconnect"
+		}
+		data := fmt.Sprintf(`{"type":%q,"msg":"event %d"}`, eventType, seq)
+		m.events = append(m.events, storedEvent{
+			Seq:  uint64(seq),
+			Time: mockEventEpoch.Add(time.Duration(seq-1) * time.Minute),
+			Data: []byte(data),
+		})
+	}
+	return m
+}
+
+// eventsResponse is the handleEvents response body
+type eventsResponse struct {
+	Events     []map[string]any `json:"events"`
+	Count      int              `json:"count"`
+	NextCursor uint64           `json:"next_cursor"`
+	NextSince  string           `json:"next_since"`
+	Error      string           `json:"error"`
+}
+
+func getEvents(t *testing.T, server *Server, query string) eventsResponse {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/events?"+query, nil)
+	rr := httptest.NewRecorder()
+	server.handleEvents(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var resp eventsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	return resp
+}
+
+func messages(resp eventsResponse) []string {
+	msgs := make([]string, 0, len(resp.Events))
+	for _, ev := range resp.Events {
+		msgs = append(msgs, ev["msg"].(string))
+	}
+	return msgs
+}
+
+func TestHandleEventsPagination(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+	server.events = newMockEventSource(25)
+
+	// Newest page
+	resp := getEvents(t, server, "count=10")
+	if got := messages(resp); len(got) != 10 || got[0] != "event 16" || got[9] != "event 25" {
+		t.Errorf("first page = %v, want events 16-25", got)
+	}
+	if resp.NextCursor != 16 {
+		t.Errorf("next_cursor = %d, want 16", resp.NextCursor)
+	}
+
+	// Page back using the cursor
+	resp = getEvents(t, server, fmt.Sprintf("count=10&before_seq=%d", resp.NextCursor))
+	if got := messages(resp); len(got) != 10 || got[0] != "event 6" || got[9] != "event 15" {
+		t.Errorf("second page = %v, want events 6-15", got)
+	}
+
+	// Last partial page reaches the start of the stream
+	resp = getEvents(t, server, fmt.Sprintf("count=10&before_seq=%d", resp.NextCursor))
+	if got := messages(resp); len(got) != 5 || got[0] != "event 1" {
+		t.Errorf("last page = %v, want events 1-5", got)
+	}
+	if resp.NextCursor != 0 {
+		t.Errorf("next_cursor = %d, want 0 at start of stream", resp.NextCursor)
+	}
+}
+
+func TestHandleEventsTypeFilter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+	server.events = newMockEventSource(25)
+
+	// Errors are 5,10,15,20,25; reconnects 3,6,9,12,18,21,24
+	resp := getEvents(t, server, "count=4&type=error")
+	want := []string{"event 10", "event 15", "event 20", "event 25"}
+	if got := messages(resp); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("filtered page = %v, want %v", got, want)
+	}
+	if resp.NextCursor != 10 {
+		t.Errorf("next_cursor = %d, want 10", resp.NextCursor)
+	}
+
+	resp = getEvents(t, server, "count=4&type=error&before_seq=10")
+	if got := messages(resp); fmt.Sprint(got) != "[event 5]" {
+		t.Errorf("second filtered page = %v, want [event 5]", got)
+	}
+
+	resp = getEvents(t, server, "count=50&type=reconnect,error")
+	for _, ev := range resp.Events {
+		if ev["type"] != "reconnect" && ev["type"] != "error" {
+			t.Errorf("unexpected event type %v in filtered response", ev["type"])
+		}
+	}
+	if resp.Count != 12 {
+		t.Errorf("count = %d, want 12", resp.Count)
+	}
+}
+
+func TestHandleEventsInvalidCursor(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+	server.events = newMockEventSource(5)
+
+	req := httptest.NewRequest("GET", "/api/events?before_seq=abc", nil)
+	rr := httptest.NewRecorder()
+	server.handleEvents(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleChannel(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := capture.NewManager(cfg, filepath.Join(t.TempDir(), "config.json"), logger)
+	if err := manager.AddPort(config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		SideDesignation: "A3",
+		Enabled:         true,
+	}, "test"); err != nil {
+		t.Fatalf("AddPort() error = %v", err)
+	}
+	defer manager.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, manager, cfg.Logging.BasePath, logger, "1.0.0")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/channels/", server.handleChannel)
+
+	t.Run("http channel", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/channels/%2Fcdr", nil)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		var info map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&info); err != nil {
+			t.Fatal(err)
+		}
+		if info["type"] != "http" || info["path"] != "/cdr" || info["side_designation"] != "A3" {
+			t.Errorf("unexpected channel info: %v", info)
+		}
+		if info["fips_code"] != "1429010002" {
+			t.Errorf("fips_code = %v, want app-level fallback", info["fips_code"])
+		}
+		if _, ok := info["stats"].(map[string]any); !ok {
+			t.Errorf("stats missing: %v", info)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/channels/ttyS9", nil)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/channels/ttyS1", nil)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func TestTailLogWithBackups(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "1429010002-A1.log")
+
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Primary was just rotated, so it only has two lines
+	write("1429010002-A1.log", "line7\nline8\n")
+	// Newest backup, plain
+	write("1429010002-A1-2025-12-03T15-00-00.000.log", "line4\nline5\nline6\n")
+	// Older backup, compressed
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("line1\nline2\nline3\n"))
+	gz.Close()
+	write("1429010002-A1-2025-12-02T09-30-00.000.log.gz", buf.String())
+	// Another channel sharing the prefix must be ignored
+	write("1429010002-A10-2025-12-03T16-00-00.000.log", "other\n")
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{2, []string{"line7", "line8"}},
+		{4, []string{"line5", "line6", "line7", "line8"}},
+		{7, []string{"line2", "line3", "line4", "line5", "line6", "line7", "line8"}},
+		{50, []string{"line1", "line2", "line3", "line4", "line5", "line6", "line7", "line8"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d", tt.n), func(t *testing.T) {
+			got, err := tailLogWithBackups(logPath, tt.n)
+			if err != nil {
+				t.Fatalf("tailLogWithBackups() error = %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("tailLogWithBackups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTailLogWithBackupsMissingPrimary(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := tailLogWithBackups(filepath.Join(tmpDir, "none.log"), 10); err == nil {
+		t.Error("tailLogWithBackups() should return error with no primary and no backups")
+	}
+}
+
+func TestSSEClientBufferOverflow(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	cfg := &config.MonitoringConfig{Port: 8080, SSEClientBuffer: 3}
+	server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+
+	client := server.newSSEClient("A1")
+	if cap(client.send) != 3 {
+		t.Fatalf("client buffer = %d, want 3", cap(client.send))
+	}
+
+	broker := NewSSEBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.Run(ctx)
+
+	broker.register <- client
+
+	// Nobody reads client.send; the broker must drop rather than block
+	for i := 0; i < 50; i++ {
+		broker.Broadcast("A1", fmt.Sprintf("line %d", i))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(client.send) < cap(client.send) {
+		if time.Now().After(deadline) {
+			t.Fatalf("client buffer never filled: %d", len(client.send))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Broker still services registrations with a stalled client attached
+	other := server.newSSEClient("all")
+	select {
+	case broker.register <- other:
+	case <-time.After(2 * time.Second):
+		t.Fatal("broker blocked by slow client")
+	}
+	for broker.ClientCount() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("ClientCount() = %d, want 2", broker.ClientCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := <-client.send; got != "line 0" {
+		t.Errorf("first buffered line = %q, want %q", got, "line 0")
+	}
+
+	// 50 lines into a 3-slot buffer: 47 dropped somewhere along the way
+	stats := broker.BrokerStats()
+	for stats.DroppedBroadcasts+stats.DroppedClientSends < 47 {
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped = %d broadcasts + %d client sends, want 47 total",
+				stats.DroppedBroadcasts, stats.DroppedClientSends)
+		}
+		time.Sleep(5 * time.Millisecond)
+		stats = broker.BrokerStats()
+	}
+	if stats.DroppedClientSends == 0 {
+		t.Error("DroppedClientSends = 0, want > 0")
+	}
+}
+
+func TestSSEClientChannelMembership(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+
+	tests := []struct {
+		name      string
+		subscribe string
+		want      map[string]bool
+	}{
+		{"all", "all", map[string]bool{"A1": true, "A2": true, "B1": true}},
+		{"empty means all", "", map[string]bool{"A1": true, "A2": true, "B1": true}},
+		{"single", "A1", map[string]bool{"A1": true, "A2": false, "B1": false}},
+		{"multiple", "A1,B1", map[string]bool{"A1": true, "A2": false, "B1": true}},
+		{"whitespace and empties", " A1 , ,A2,", map[string]bool{"A1": true, "A2": true, "B1": false}},
+		{"all in list", "A1,all", map[string]bool{"A1": true, "A2": true, "B1": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := server.newSSEClient(tt.subscribe)
+			for ch, want := range tt.want {
+				if got := client.wants(ch); got != want {
+					t.Errorf("wants(%q) = %v, want %v", ch, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSSEBrokerMultiChannelDelivery(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+
+	broker := NewSSEBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.Run(ctx)
+
+	subset := server.newSSEClient("A1,A3")
+	single := server.newSSEClient("A2")
+	broker.register <- subset
+	broker.register <- single
+
+	deadline := time.Now().Add(2 * time.Second)
+	for broker.ClientCount() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("ClientCount() = %d, want 2", broker.ClientCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	broker.Broadcast("A1", "one")
+	broker.Broadcast("A2", "two")
+	broker.Broadcast("A3", "three")
+
+	recv := func(c *SSEClient) string {
+		select {
+		case line := <-c.send:
+			return line
+		case <-time.After(2 * time.Second):
+			return ""
+		}
+	}
+
+	if got := recv(subset); got != "one" {
+		t.Errorf("subset first line = %q, want %q", got, "one")
+	}
+	if got := recv(subset); got != "three" {
+		t.Errorf("subset second line = %q, want %q", got, "three")
+	}
+	if got := recv(single); got != "two" {
+		t.Errorf("single line = %q, want %q", got, "two")
+	}
+	if len(single.send) != 0 {
+		t.Errorf("single client received %d extra lines", len(single.send))
+	}
+}
+
+func TestDecodePortID(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		want    string
+		wantErr bool
+	}{
+		{"serial", "ttyS1", "ttyS1", false},
+		{"encoded slash", "%2Fcdr", "/cdr", false},
+		{"lowercase encoded slash", "%2fcdr%2fne", "/cdr/ne", false},
+		{"encoded space", "%2Fcdr%20county", "/cdr county", false},
+		{"invalid escape", "%2Fcdr%zz", "", true},
+		{"truncated escape", "ttyS1%2", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePortID(tt.encoded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodePortID(%q) error = %v, wantErr %v", tt.encoded, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodePortID(%q) = %q, want %q", tt.encoded, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePortConfigActionEncodedID(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManagerWithPorts(), "/var/log", logger, "1.0.0")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/ports/config/", server.handlePortConfigAction)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{"http path id", "GET", "/api/ports/config/%2Fcdr", http.StatusOK},
+		{"http path id with action", "POST", "/api/ports/config/%2Fcdr/redetect", http.StatusConflict},
+		{"encoded space not found", "GET", "/api/ports/config/%2Fcdr%20b", http.StatusNotFound},
+		{"serial id", "GET", "/api/ports/config/ttyS1", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tt.want {
+				t.Errorf("%s %s status = %d, want %d: %s", tt.method, tt.target, rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestSSEBrokerShutdownEvent(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+	defer server.cancel()
+
+	broker := NewSSEBroker()
+	broker.shutdownGrace = 200 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	go broker.Run(ctx)
+
+	client := server.newSSEClient("all")
+	if !broker.Register(client) {
+		t.Fatal("Register() = false on a running broker")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for broker.ClientCount() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("client never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+
+	select {
+	case <-client.shutdown:
+	case <-time.After(2 * time.Second):
+		t.Fatal("client never received shutdown")
+	}
+	select {
+	case <-client.done:
+		t.Fatal("done closed before the grace period")
+	default:
+	}
+
+	select {
+	case <-client.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("done never closed after the grace period")
+	}
+
+	// A stopped broker doesn't block late (un)registrations
+	broker.Unregister(client)
+	if broker.Register(server.newSSEClient("all")) {
+		t.Error("Register() = true on a stopped broker")
+	}
+}
+
+func TestHandleSSEShutdownEvent(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+
+	ts := httptest.NewServer(http.HandlerFunc(server.handleSSE))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "?channel=A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "event: connected\n" {
+		t.Fatalf("first line = %q, %v, want connected event", line, err)
+	}
+
+	server.cancel()
+
+	var body strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		body.WriteString(line)
+		if err != nil {
+			break
+		}
+	}
+	if !strings.Contains(body.String(), "event: shutdown\ndata: ") {
+		t.Errorf("stream = %q, want a shutdown event before close", body.String())
+	}
+}
+
+func TestMergeFeeds(t *testing.T) {
+	feeds := map[string][]string{
+		"1429010002-A1": {
+			"[1429010002][A1][2025-12-03 15:00:01.000] one",
+			"continuation of one",
+			"[1429010002][A1][2025-12-03 15:00:04.000] four",
+		},
+		"1429010002-A2": {
+			"[1429010002][A2][2025-12-03 15:00:02.000] two",
+			"[1429010002][A2][2025-12-03 15:00:03.000] three",
+			"[1429010002][A2][2025-12-03 15:00:05.000] five",
+		},
+	}
+
+	got := mergeFeeds(feeds, 50, output.HeaderFormat{})
+	want := []string{"one", "continuation of one", "two", "three", "four", "five"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d: %v", len(got), len(want), got)
+	}
+	for i, line := range got {
+		if !strings.HasSuffix(line.Line, want[i]) {
+			t.Errorf("line %d = %q, want suffix %q", i, line.Line, want[i])
+		}
+	}
+	if got[1].Channel != "1429010002-A1" || got[2].Channel != "1429010002-A2" {
+		t.Errorf("channels = %s, %s, want A1 then A2", got[1].Channel, got[2].Channel)
+	}
+
+	// Only the newest n survive
+	got = mergeFeeds(feeds, 2, output.HeaderFormat{})
+	if len(got) != 2 || !strings.HasSuffix(got[0].Line, "four") || !strings.HasSuffix(got[1].Line, "five") {
+		t.Errorf("mergeFeeds(n=2) = %v, want four, five", got)
+	}
+}
+
+func TestHandleFeedMerged(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := capture.NewManager(cfg, filepath.Join(t.TempDir(), "config.json"), logger)
+	for _, port := range []config.PortConfig{
+		{Type: config.PortTypeHTTP, Path: "/a", SideDesignation: "A1", Enabled: true},
+		{Type: config.PortTypeHTTP, Path: "/b", SideDesignation: "A2", Enabled: true},
+	} {
+		if err := manager.AddPort(port, "test"); err != nil {
+			t.Fatalf("AddPort() error = %v", err)
+		}
+	}
+	defer manager.Stop()
+
+	paths := manager.LogPaths()
+	write := func(identifier, content string) {
+		path, ok := paths[identifier]
+		if !ok {
+			t.Fatalf("no log path for %s in %v", identifier, paths)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("1429010002-A1", "[1429010002][A1][2025-12-03 15:00:01.000] first\n[1429010002][A1][2025-12-03 15:00:03.000] third\n")
+	write("1429010002-A2", "[1429010002][A2][2025-12-03 15:00:02.000] second\n")
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, manager, cfg.Logging.BasePath, logger, "1.0.0")
+	req := httptest.NewRequest("GET", "/api/feed/merged?count=10", nil)
+	rr := httptest.NewRecorder()
+	server.handleFeedMerged(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var response struct {
+		Channels int          `json:"channels"`
+		Lines    []MergedLine `json:"lines"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Channels != 2 {
+		t.Errorf("channels = %d, want 2", response.Channels)
+	}
+	var order []string
+	for _, line := range response.Lines {
+		order = append(order, line.Line[strings.LastIndex(line.Line, " ")+1:])
+	}
+	if strings.Join(order, ",") != "first,second,third" {
+		t.Errorf("merged order = %v, want first,second,third", order)
+	}
+}
+
+func TestHTTPCaptureServerHealthz(t *testing.T) {
+	logDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	// Grab a free port for the capture server
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	portCfg := config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		ListenPort:      port,
+		SideDesignation: "A1",
+		FIPSCode:        "1429010002",
+		AuthToken:       "secret",
+		Enabled:         true,
+	}
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       portCfg.Path,
+		Identifier:   "1429010002-A1",
+		LogBasePath:  logDir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := capture.NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+	defer ch.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), logDir, logger, "1.0.0")
+	if err := server.startHTTPCaptureServer(port, []*capture.HTTPChannel{ch}); err != nil {
+		t.Fatalf("startHTTPCaptureServer() error = %v", err)
+	}
+	defer server.Stop(context.Background())
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	// The server starts in a goroutine - retry until it's listening
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = client.Get(base + config.CaptureHealthPath)
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", config.CaptureHealthPath, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET %s status = %d, want %d (no auth required)", config.CaptureHealthPath, resp.StatusCode, http.StatusOK)
+	}
+
+	resp, err = client.Post(base+config.CaptureHealthPath, "text/plain", strings.NewReader("CALL 001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST %s status = %d, want %d", config.CaptureHealthPath, resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+
+	// The capture path keeps its own auth
+	resp, err = client.Post(base+"/cdr", "text/plain", strings.NewReader("CALL 001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("POST /cdr without token status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080, AccessLog: true}, newTestManager(), "/var/log", logger, "1.0.0")
+
+	handler := server.accessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Error("access log wrapper should pass through http.Flusher")
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("accepted"))
+	}))
+
+	req := httptest.NewRequest("POST", "/cdr?caller=5551234", strings.NewReader("CALL 001 5551234"))
+	req.RemoteAddr = "10.0.0.5:40000"
+	req.SetBasicAuth("admin", "hunter2")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("access log is not one JSON record: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"level":       "INFO",
+		"method":      "POST",
+		"path":        "/cdr",
+		"status":      float64(http.StatusAccepted),
+		"bytes":       float64(len("accepted")),
+		"remote_addr": "10.0.0.5:40000",
+		"user":        "admin",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+	if _, ok := record["duration_ms"]; !ok {
+		t.Error("duration_ms missing")
+	}
+	for _, secret := range []string{"5551234", "hunter2"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("access log leaked %q: %s", secret, buf.String())
+		}
+	}
+}
+
+func TestHandlePortSelfTest(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManagerWithPorts(), "/var/log", logger, "1.0.0")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/ports/", server.handlePortAction)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{"unknown port", "POST", "/api/ports/ttyS9/selftest", http.StatusNotFound},
+		{"http endpoint", "POST", "/api/ports/%2Fcdr/selftest", http.StatusConflict},
+		{"wrong method", "GET", "/api/ports/ttyS1/selftest", http.StatusMethodNotAllowed},
+		{"unknown action", "POST", "/api/ports/ttyS1/reboot", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, nil))
+			if rr.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestScanLogStats(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 12, 3, 18, 0, 0, 0, time.UTC)
+
+	logPath := filepath.Join(dir, "3100000000-A1.log")
+	content := "[3100000000][A1][2025-12-02 23:59:59.000] CALL 1\n" +
+		"continuation without header\n" +
+		"[3100000000][A1][2025-12-03 08:00:00.000] CALL 2\n" +
+		"[3100000000][A1][2025-12-03 09:30:00.500] CALL 3\n"
+	if err := os.WriteFile(logPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := scanLogStats(logPath, output.HeaderFormat{}, now, logStatsScanLimit)
+	if err != nil {
+		t.Fatalf("scanLogStats() error: %v", err)
+	}
+	if !stats.Exists || stats.SizeBytes != int64(len(content)) {
+		t.Errorf("Exists = %v, SizeBytes = %d, want true, %d", stats.Exists, stats.SizeBytes, len(content))
+	}
+	if stats.Lines != 4 || stats.LinesToday != 2 {
+		t.Errorf("Lines = %d, LinesToday = %d, want 4, 2", stats.Lines, stats.LinesToday)
+	}
+	wantOldest := time.Date(2025, 12, 2, 23, 59, 59, 0, time.UTC)
+	wantNewest := time.Date(2025, 12, 3, 9, 30, 0, 500*int(time.Millisecond), time.UTC)
+	if stats.Oldest == nil || !stats.Oldest.Equal(wantOldest) {
+		t.Errorf("Oldest = %v, want %v", stats.Oldest, wantOldest)
+	}
+	if stats.Newest == nil || !stats.Newest.Equal(wantNewest) {
+		t.Errorf("Newest = %v, want %v", stats.Newest, wantNewest)
+	}
+	if stats.Truncated {
+		t.Error("Truncated = true for a small file")
+	}
+
+	// Past the scan limit, counts are partial but newest still comes from the tail
+	firstLine := int64(strings.Index(content, "\n") + 1)
+	limited, err := scanLogStats(logPath, output.HeaderFormat{}, now, firstLine)
+	if err != nil {
+		t.Fatalf("scanLogStats() with limit error: %v", err)
+	}
+	if !limited.Truncated || limited.Lines != 1 {
+		t.Errorf("Truncated = %v, Lines = %d, want true, 1", limited.Truncated, limited.Lines)
+	}
+	if limited.Newest == nil || !limited.Newest.Equal(wantNewest) {
+		t.Errorf("truncated Newest = %v, want %v", limited.Newest, wantNewest)
+	}
+
+	// Empty and missing files are reported, not errors
+	emptyPath := filepath.Join(dir, "empty.log")
+	if err := os.WriteFile(emptyPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	empty, err := scanLogStats(emptyPath, output.HeaderFormat{}, now, logStatsScanLimit)
+	if err != nil || !empty.Exists || empty.Lines != 0 || empty.Oldest != nil || empty.Newest != nil {
+		t.Errorf("empty file stats = %+v, err = %v", empty, err)
+	}
+	missing, err := scanLogStats(filepath.Join(dir, "rotated.log"), output.HeaderFormat{}, now, logStatsScanLimit)
+	if err != nil || missing.Exists {
+		t.Errorf("missing file stats = %+v, err = %v", missing, err)
+	}
+}
+
+func TestHandlePortLogStats(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := newTestManagerWithPorts()
+	manager.Config().Logging.BasePath = t.TempDir()
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, manager, "/var/log", logger, "1.0.0")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/ports/", server.handlePortAction)
+
+	logPath := filepath.Join(manager.Config().Logging.BasePath, "3100000000-A1.log")
+	if err := os.WriteFile(logPath, []byte("[3100000000][A1][2025-12-03 08:00:00.000] CALL 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/api/ports/ttyS1/logs/stats", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var stats LogFileStats
+	if err := json.NewDecoder(rr.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Port != "ttyS1" || stats.Path != logPath || stats.Lines != 1 || stats.Oldest == nil {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{"unknown port", "GET", "/api/ports/ttyS9/logs/stats", http.StatusNotFound},
+		{"no log yet", "GET", "/api/ports/%2Fcdr/logs/stats", http.StatusOK},
+		{"wrong method", "POST", "/api/ports/ttyS1/logs/stats", http.StatusMethodNotAllowed},
+		{"unknown logs action", "GET", "/api/ports/ttyS1/logs/tail", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, nil))
+			if rr.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleSystemGoMemStats(t *testing.T) {
+	cfg := &config.MonitoringConfig{Port: 8080}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+
+	// Make sure at least one GC has run so NumGC is meaningful
+	runtime.GC()
+
+	req := httptest.NewRequest("GET", "/api/system", nil)
+	w := httptest.NewRecorder()
+	server.handleSystem(w, req)
+
+	var raw struct {
+		GoMemStats map[string]json.RawMessage `json:"go_mem_stats"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for _, field := range []string{"heap_alloc_bytes", "heap_inuse_bytes", "num_gc", "pause_total_ns"} {
+		if _, ok := raw.GoMemStats[field]; !ok {
+			t.Errorf("go_mem_stats missing %q: %s", field, w.Body.String())
+		}
+	}
+
+	var info SystemInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode SystemInfo: %v", err)
+	}
+	mem := info.GoMemStats
+	if mem.HeapAlloc == 0 || mem.HeapInuse == 0 {
+		t.Errorf("heap = %+v, want non-zero", mem)
+	}
+	if mem.HeapAlloc > 1<<40 {
+		t.Errorf("HeapAlloc = %d, implausibly large", mem.HeapAlloc)
+	}
+	if mem.NumGC < 1 {
+		t.Errorf("NumGC = %d, want >= 1 after runtime.GC", mem.NumGC)
+	}
+}
+
+func TestNewHTTPServerTimeouts(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	// Unset values fall back to the defaults
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+	srv := server.newHTTPServer(":8080", http.NotFoundHandler())
+	if srv.ReadHeaderTimeout != config.DefaultReadHeaderTimeoutSec*time.Second ||
+		srv.ReadTimeout != config.DefaultReadTimeoutSec*time.Second ||
+		srv.WriteTimeout != config.DefaultWriteTimeoutSec*time.Second ||
+		srv.IdleTimeout != config.DefaultIdleTimeoutSec*time.Second {
+		t.Errorf("default timeouts = %v/%v/%v/%v", srv.ReadHeaderTimeout, srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+
+	cfg := &config.MonitoringConfig{Port: 8080, ReadHeaderTimeoutSec: 5, ReadTimeoutSec: 30, WriteTimeoutSec: 45, IdleTimeoutSec: 90}
+	server = NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+	srv = server.newHTTPServer(":8080", http.NotFoundHandler())
+	if srv.ReadHeaderTimeout != 5*time.Second || srv.ReadTimeout != 30*time.Second ||
+		srv.WriteTimeout != 45*time.Second || srv.IdleTimeout != 90*time.Second {
+		t.Errorf("configured timeouts = %v/%v/%v/%v", srv.ReadHeaderTimeout, srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+}
+
+func TestHandleSSEOutlivesServerTimeouts(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	cfg := &config.MonitoringConfig{Port: 8080, ReadTimeoutSec: 1, WriteTimeoutSec: 1, SSEKeepaliveSec: 1}
+	server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(server.handleSSE))
+	ts.Config = server.newHTTPServer("", http.HandlerFunc(server.handleSSE))
+	ts.Start()
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "?channel=A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// A keepalive arriving well after both timeouts means the stream survived
+	lines := make(chan string)
+	go func() {
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	start := time.Now()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("stream closed after %v", time.Since(start))
+			}
+			if strings.HasPrefix(line, ": keepalive") && time.Since(start) > 2*time.Second {
+				server.cancel()
+				return
+			}
+		case <-deadline:
+			t.Fatal("no keepalive after the server timeouts elapsed")
+		}
+	}
+}
+
+func TestHTTPChannelLogTailedToSSE(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := capture.NewManager(cfg, filepath.Join(t.TempDir(), "config.json"), logger)
+	if err := manager.AddPort(config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		SideDesignation: "A3",
+		Enabled:         true,
+	}, "test"); err != nil {
+		t.Fatalf("AddPort() error = %v", err)
+	}
+	defer manager.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, manager, cfg.Logging.BasePath, logger, "1.0.0")
+	defer server.cancel()
+
+	client := server.newSSEClient("all")
+	if !server.broker.Register(client) {
+		t.Fatal("broker stopped")
+	}
+
+	// Repeated calls don't start a second tail
+	server.startLogWatchers(server.ctx)
+	server.startLogWatchers(server.ctx)
+	server.watchersMu.Lock()
+	_, watching := server.watchers["1429010002-A3"]
+	count := len(server.watchers)
+	server.watchersMu.Unlock()
+	if !watching || count != 1 {
+		t.Fatalf("watchers = %d (A3 tailed: %v), want just the HTTP channel", count, watching)
+	}
+
+	// The tail starts at the end of the log, so post until the body shows up
+	// (the channel logs the request line and headers too)
+	ch := manager.GetHTTPChannels()[0]
+	deadline := time.After(5 * time.Second)
+	post := time.NewTicker(50 * time.Millisecond)
+	defer post.Stop()
+	for {
+		select {
+		case line := <-client.send:
+			if line == "CALL 001 IN" {
+				return
+			}
+		case <-post.C:
+			rr := httptest.NewRecorder()
+			ch.ServeHTTP(rr, httptest.NewRequest("POST", "/cdr", strings.NewReader("CALL 001 IN")))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("POST status = %d", rr.Code)
+			}
+		case <-deadline:
+			t.Fatal("HTTP channel log line never reached the broker")
+		}
+	}
+}
+
+func TestLogWatchersFollowRuntimePorts(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := capture.NewManager(cfg, filepath.Join(t.TempDir(), "config.json"), logger)
+	defer manager.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, manager, cfg.Logging.BasePath, logger, "1.0.0")
+	defer server.cancel()
+
+	watchers := func() map[string]bool {
+		server.watchersMu.Lock()
+		defer server.watchersMu.Unlock()
+		ids := make(map[string]bool)
+		for id := range server.watchers {
+			ids[id] = true
+		}
+		return ids
+	}
+
+	// Repeated add/delete must not accumulate tails
+	for i := 0; i < 3; i++ {
+		if err := manager.AddPort(config.PortConfig{
+			Type:            config.PortTypeHTTP,
+			Path:            "/cdr",
+			SideDesignation: "A3",
+			Enabled:         true,
+		}, "test"); err != nil {
+			t.Fatalf("AddPort() error = %v", err)
+		}
+		if got := watchers(); len(got) != 1 || !got["1429010002-A3"] {
+			t.Fatalf("after AddPort watchers = %v, want exactly the new port", got)
+		}
+
+		if err := manager.DeletePort("/cdr", "test"); err != nil {
+			t.Fatalf("DeletePort() error = %v", err)
+		}
+		if got := watchers(); len(got) != 0 {
+			t.Fatalf("after DeletePort watchers = %v, want none", got)
+		}
+	}
+
+	// Startup discovery doesn't duplicate a tail the callback already started
+	if err := manager.AddPort(config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		SideDesignation: "A3",
+		Enabled:         true,
+	}, "test"); err != nil {
+		t.Fatalf("AddPort() error = %v", err)
+	}
+	server.startLogWatchers(server.ctx)
+	if got := watchers(); len(got) != 1 {
+		t.Errorf("watchers = %v, want one", got)
+	}
+}
+
+func TestWatchLogFilesWaitsForChannels(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := capture.NewManager(cfg, filepath.Join(t.TempDir(), "config.json"), logger)
+	defer manager.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, manager, cfg.Logging.BasePath, logger, "1.0.0")
+	defer server.cancel()
+
+	watcherCount := func() int {
+		server.watchersMu.Lock()
+		defer server.watchersMu.Unlock()
+		return len(server.watchers)
+	}
+
+	// Stand in for startup: channels appear without runtime notifications
+	manager.SetChannelChangeCallback(nil)
+	ready := make(chan struct{})
+	go server.watchLogFiles(server.ctx, ready)
+
+	if err := manager.AddPort(config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		SideDesignation: "A3",
+		Enabled:         true,
+	}, "test"); err != nil {
+		t.Fatalf("AddPort() error = %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if n := watcherCount(); n != 0 {
+		t.Fatalf("%d watchers started before channels were ready", n)
+	}
+
+	close(ready)
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for watcherCount() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("watchers = %d 500ms after ready, want 1", watcherCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHandlePortGetLastRecord(t *testing.T) {
+	cfg := &config.Config{
+		App:     config.AppConfig{Name: "Test", InstanceID: "test-01", FIPSCode: "1429010002"},
+		Logging: config.LoggingConfig{BasePath: t.TempDir(), MaxSizeMB: 1},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	manager := capture.NewManager(cfg, filepath.Join(t.TempDir(), "config.json"), logger)
+	if err := manager.AddPort(config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		SideDesignation: "A3",
+		RecordPreview:   true,
+		Enabled:         true,
+	}, "test"); err != nil {
+		t.Fatalf("AddPort() error = %v", err)
+	}
+	defer manager.Stop()
+
+	ch := manager.GetHTTPChannels()[0]
+	ch.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/cdr", strings.NewReader("CALL 001 IN")))
+
+	getPort := func(mcfg *config.MonitoringConfig) capture.PortInfo {
+		t.Helper()
+		server := NewServer(mcfg, manager, cfg.Logging.BasePath, logger, "1.0.0")
+		defer server.cancel()
+		rr := httptest.NewRecorder()
+		server.handlePortConfigAction(rr, httptest.NewRequest(http.MethodGet, "/api/ports/config/%2Fcdr", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		var info capture.PortInfo
+		if err := json.NewDecoder(rr.Body).Decode(&info); err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+
+	if got := getPort(&config.MonitoringConfig{Port: 8080, Username: "admin", Password: "secret"}).LastRecord; got != "CALL 001 IN" {
+		t.Errorf("last_record behind auth = %q, want %q", got, "CALL 001 IN")
+	}
+	// Without auth the preview is withheld
+	if got := getPort(&config.MonitoringConfig{Port: 8080}).LastRecord; got != "" {
+		t.Errorf("last_record without auth = %q, want empty", got)
+	}
+}
+
+func TestHTTPCaptureServerBindConflict(t *testing.T) {
+	logDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	// Another process holds the capture port
+	held, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := held.Addr().(*net.TCPAddr).Port
+
+	oldMin := captureBindRetryMin
+	captureBindRetryMin = 20 * time.Millisecond
+	defer func() { captureBindRetryMin = oldMin }()
+
+	portCfg := config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr",
+		ListenPort:      port,
+		SideDesignation: "A1",
+		FIPSCode:        "1429010002",
+		Enabled:         true,
+	}
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       portCfg.Path,
+		Identifier:   "1429010002-A1",
+		LogBasePath:  logDir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := capture.NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+	defer ch.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), logDir, logger, "1.0.0")
+	if err := server.startHTTPCaptureServer(port, []*capture.HTTPChannel{ch}); err == nil {
+		t.Fatal("startHTTPCaptureServer() on a held port should fail")
+	}
+	defer server.Stop(context.Background())
+
+	if got := ch.State(); got != capture.HTTPStateBindFailed {
+		t.Errorf("State() = %q, want %q", got, capture.HTTPStateBindFailed)
+	}
+	if got := ch.StateCode(); got != capture.StateError.StateCode() {
+		t.Errorf("StateCode() = %d, want %d", got, capture.StateError.StateCode())
+	}
+	if ch.GetStats().BindError == "" {
+		t.Error("BindError should carry the listen error")
+	}
+
+	// Once the port is released the retry binds and the endpoint answers
+	held.Close()
+	client := &http.Client{Timeout: 2 * time.Second}
+	var resp *http.Response
+	for i := 0; i < 100; i++ {
+		resp, err = client.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, config.CaptureHealthPath))
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("capture server never bound after the port was released: %v", err)
+	}
+	resp.Body.Close()
+	if got := ch.State(); got != capture.HTTPStateRunning {
+		t.Errorf("State() after retry = %q, want %q", got, capture.HTTPStateRunning)
+	}
+}
+
+func TestHTTPCapturePrefixPath(t *testing.T) {
+	logDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	portCfg := config.PortConfig{
+		Type:            config.PortTypeHTTP,
+		Path:            "/cdr/",
+		ListenPort:      port,
+		SideDesignation: "A1",
+		FIPSCode:        "1429010002",
+		Enabled:         true,
+	}
+	writer, err := output.NewDualWriter(&output.DualWriterConfig{
+		Device:       portCfg.Path,
+		Identifier:   "1429010002-A1",
+		LogBasePath:  logDir,
+		LogMaxSizeMB: 1,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := capture.NewHTTPChannel(portCfg, config.AppConfig{}, writer, logger)
+	defer ch.Stop()
+
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), logDir, logger, "1.0.0")
+	if err := server.startHTTPCaptureServer(port, []*capture.HTTPChannel{ch}); err != nil {
+		t.Fatalf("startHTTPCaptureServer() error = %v", err)
+	}
+	defer server.Stop(context.Background())
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	for _, session := range []string{"session-1", "session-2?seq=7"} {
+		resp, err := client.Post(fmt.Sprintf("http://127.0.0.1:%d/cdr/%s", port, session), "text/plain", strings.NewReader("CALL 001"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST /cdr/%s status = %d, want %d", session, resp.StatusCode, http.StatusOK)
+		}
+	}
+
+	// The full request URI is recorded so sessions can be told apart
+	data, err := os.ReadFile(filepath.Join(logDir, "1429010002-A1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"POST /cdr/session-1 HTTP/1.1", "POST /cdr/session-2?seq=7 HTTP/1.1"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("log missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestSelectiveAuthPrefixPath(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	ch := capture.NewHTTPChannel(config.PortConfig{Type: config.PortTypeHTTP, Path: "/cdr/"}, config.AppConfig{}, nil, logger)
+	server := NewServer(&config.MonitoringConfig{Port: 8080, Username: "admin", Password: "secret"}, newTestManager(), t.TempDir(), logger, "1.0.0")
+	defer server.cancel()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := server.selectiveAuth(ok, []*capture.HTTPChannel{ch})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/cdr/abc123", http.StatusOK},
+		{"/cdr/", http.StatusOK},
+		{"/cdrx", http.StatusUnauthorized},
+		{"/api/stats", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, tt.path, nil))
+		if rr.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.path, rr.Code, tt.want)
+		}
+	}
+}
+
+func TestHandlePortsLabels(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	getPorts := func(cfg *config.MonitoringConfig) []PortStatus {
+		t.Helper()
+		server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+		defer server.cancel()
+		rr := httptest.NewRecorder()
+		server.handlePorts(rr, httptest.NewRequest(http.MethodGet, "/api/ports", nil))
+		var resp struct {
+			Ports []PortStatus `json:"ports"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Ports
+	}
+
+	custom := getPorts(&config.MonitoringConfig{Port: 8080, PortLabels: map[string]string{
+		"/dev/ttyNECTAR1": "COM1",
+		"/dev/ttyNECTAR0": "Front desk",
+	}})
+	want := []PortStatus{
+		{Device: "/dev/ttyNECTAR0", COM: "Front desk"},
+		{Device: "/dev/ttyNECTAR1", COM: "COM1"},
+	}
+	if len(custom) != len(want) {
+		t.Fatalf("ports = %+v, want %+v", custom, want)
+	}
+	for i := range want {
+		if custom[i].Device != want[i].Device || custom[i].COM != want[i].COM {
+			t.Errorf("ports[%d] = %s/%s, want %s/%s", i, custom[i].Device, custom[i].COM, want[i].Device, want[i].COM)
+		}
+	}
+
+	// Unset keeps the stock ttyS1-5 -> COM2-6 mapping
+	stock := getPorts(&config.MonitoringConfig{Port: 8080})
+	if len(stock) != 5 || stock[0].Device != "/dev/ttyS1" || stock[0].COM != "COM2" {
+		t.Errorf("default ports = %+v, want ttyS1-5 as COM2-6", stock)
+	}
+}
+
+func TestHandleEventsTimeRange(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+	server.events = newMockEventSource(25)
+
+	at := func(seq int) string {
+		return mockEventEpoch.Add(time.Duration(seq-1) * time.Minute).Format(time.RFC3339)
+	}
+
+	// Events 5-9: until is exclusive
+	resp := getEvents(t, server, "since="+at(5)+"&until="+at(10))
+	if got := messages(resp); len(got) != 5 || got[0] != "event 5" || got[4] != "event 9" {
+		t.Errorf("since/until = %v, want events 5-9", got)
+	}
+	if resp.NextSince != "" {
+		t.Errorf("next_since = %q, want none once the range is exhausted", resp.NextSince)
+	}
+
+	// A full page pages forward with next_since
+	resp = getEvents(t, server, "count=4&since="+at(20))
+	if got := messages(resp); len(got) != 4 || got[0] != "event 20" || got[3] != "event 23" {
+		t.Errorf("first page = %v, want events 20-23", got)
+	}
+	resp = getEvents(t, server, "count=4&since="+resp.NextSince)
+	if got := messages(resp); len(got) != 2 || got[0] != "event 24" || got[1] != "event 25" {
+		t.Errorf("second page = %v, want events 24-25", got)
+	}
+
+	// Type filter within the range
+	resp = getEvents(t, server, "type=error&since="+at(1)+"&until="+at(16))
+	if got := messages(resp); len(got) != 3 || got[0] != "event 5" || got[2] != "event 15" {
+		t.Errorf("errors in range = %v, want events 5, 10, 15", got)
+	}
+}
+
+func TestHandleEventsTimeRangeScanBound(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080, EventScanWindows: 2}, newTestManager(), "/var/log", logger, "1.0.0")
+	server.events = newMockEventSource(50)
+
+	// Errors are every fifth event: two windows of 3 see only event 5
+	resp := getEvents(t, server, "count=3&type=error&since="+mockEventEpoch.Format(time.RFC3339))
+	if got := messages(resp); len(got) != 1 || got[0] != "event 5" {
+		t.Errorf("bounded scan = %v, want just event 5", got)
+	}
+	// The next page resumes after the last event scanned (event 6)
+	want := mockEventEpoch.Add(5*time.Minute + time.Nanosecond).Format(time.RFC3339Nano)
+	if resp.NextSince != want {
+		t.Errorf("next_since = %q, want %q", resp.NextSince, want)
+	}
+}
+
+func TestHandleEventsTimeRangeInvalid(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(&config.MonitoringConfig{Port: 8080}, newTestManager(), "/var/log", logger, "1.0.0")
+	server.events = newMockEventSource(5)
+
+	for _, query := range []string{
+		"since=yesterday",
+		"until=2025-12-03T15:00:00Z",
+		"since=2025-12-03T16:00:00Z&until=2025-12-03T15:00:00Z",
+		"since=2025-12-03T15:00:00Z&before_seq=3",
+	} {
+		rr := httptest.NewRecorder()
+		server.handleEvents(rr, httptest.NewRequest("GET", "/api/events?"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+// stubEventStream records PullSubscribe calls instead of reaching a server
+type stubEventStream struct {
+	subjects []string
+	opts     int
+}
+
+func (s *stubEventStream) StreamInfo(string, ...nats.JSOpt) (*nats.StreamInfo, error) {
+	return &nats.StreamInfo{}, nil
+}
+
+func (s *stubEventStream) PullSubscribe(subj, _ string, opts ...nats.SubOpt) (*nats.Subscription, error) {
+	s.subjects = append(s.subjects, subj)
+	s.opts = len(opts)
+	return nil, fmt.Errorf("stub")
+}
+
+func TestJetStreamEventSourceStartOptions(t *testing.T) {
+	var gotTime time.Time
+	var gotSeq uint64
+	origTime, origSeq := eventsStartTime, eventsStartSequence
+	eventsStartTime = func(t time.Time) nats.SubOpt { gotTime = t; return origTime(t) }
+	eventsStartSequence = func(seq uint64) nats.SubOpt { gotSeq = seq; return origSeq(seq) }
+	defer func() { eventsStartTime, eventsStartSequence = origTime, origSeq }()
+
+	js := &stubEventStream{}
+	source := &jetStreamEventSource{js: js, subject: "events.test-01", logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	since := time.Date(2025, 12, 3, 15, 0, 0, 0, time.UTC)
+	if _, err := source.FetchSince(since, 10); err == nil {
+		t.Error("FetchSince() should surface the subscribe error")
+	}
+	if !gotTime.Equal(since) || gotSeq != 0 {
+		t.Errorf("FetchSince started at time %v / seq %d, want time %v", gotTime, gotSeq, since)
+	}
+
+	gotTime = time.Time{}
+	source.FetchFrom(42, 10)
+	if gotSeq != 42 || !gotTime.IsZero() {
+		t.Errorf("FetchFrom started at seq %d / time %v, want seq 42", gotSeq, gotTime)
+	}
+
+	// Both bind the events stream on this instance's subject
+	if len(js.subjects) != 2 || js.subjects[0] != "events.test-01" || js.opts != 2 {
+		t.Errorf("PullSubscribe calls = %v with %d options, want 2 on events.test-01 with start + bind", js.subjects, js.opts)
+	}
+}
+
+func TestHandleSchema(t *testing.T) {
+	cfg := &config.MonitoringConfig{Port: 8080}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	server := NewServer(cfg, newTestManager(), "/var/log", logger, "1.0.0")
+
+	req := httptest.NewRequest("GET", "/api/schema", nil)
+	w := httptest.NewRecorder()
+	server.handleSchema(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var schema config.Schema
+	if err := json.NewDecoder(w.Body).Decode(&schema); err != nil {
+		t.Fatalf("failed to decode schema: %v", err)
+	}
+	if !slices.Contains(schema.BaudRates, 9600) || !slices.Contains(schema.BaudRates, 115200) {
+		t.Errorf("BaudRates = %v, want 9600 and 115200", schema.BaudRates)
+	}
+	if !slices.Contains(schema.Parities, "even") || !slices.Contains(schema.Parities, "space") {
+		t.Errorf("Parities = %v, want even and space", schema.Parities)
+	}
+	if !slices.Contains(schema.PortTypes, config.PortTypeSerial) || !slices.Contains(schema.PortTypes, config.PortTypeHTTP) {
+		t.Errorf("PortTypes = %v, want serial and http", schema.PortTypes)
+	}
+	if schema.SideDesignationPattern == "" {
+		t.Error("SideDesignationPattern is empty")
+	}
+
+	// Every advertised value must pass the port update validation
+	for _, baud := range schema.BaudRates {
+		if err := validatePortUpdates(map[string]interface{}{"baud_rate": float64(baud)}, false); err != nil {
+			t.Errorf("baud_rate %d from schema rejected: %v", baud, err)
+		}
+	}
+	for _, parity := range schema.Parities {
+		if err := validatePortUpdates(map[string]interface{}{"parity": parity}, false); err != nil {
+			t.Errorf("parity %q from schema rejected: %v", parity, err)
+		}
+	}
+
+	req = httptest.NewRequest("POST", "/api/schema", nil)
+	w = httptest.NewRecorder()
+	server.handleSchema(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHandlePortPauseErrors(t *testing.T) {
+	cfg := &config.MonitoringConfig{Port: 8080}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewServer(cfg, newTestManagerWithPorts(), "/var/log", logger, "1.0.0")
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"POST", "/api/ports/config/ttyS9/pause", http.StatusNotFound},
+		{"POST", "/api/ports/config/ttyS1/pause", http.StatusConflict}, // not running
+		{"POST", "/api/ports/config/%2Fcdr/resume", http.StatusConflict},
+		{"GET", "/api/ports/config/ttyS1/pause", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		server.handlePortConfigAction(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %s status = %d, want %d (%s)", tt.method, tt.path, w.Code, tt.want, w.Body.String())
+		}
+	}
+}
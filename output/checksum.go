@@ -0,0 +1,41 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+)
+
+// Record checksum algorithms
+const (
+	ChecksumCRC32  = "crc32"  // IEEE CRC-32, 8 hex digits
+	ChecksumSHA256 = "sha256" // SHA-256 truncated to the first 16 hex digits
+)
+
+// sha256HexDigits is how much of the SHA-256 digest is kept; 64 bits is plenty
+// to show a record was altered without doubling its length
+const sha256HexDigits = 16
+
+// Checksum returns the hex digest of data, or "" for an unknown algorithm
+func Checksum(algorithm string, data []byte) string {
+	switch algorithm {
+	case ChecksumCRC32:
+		return fmt.Sprintf("%08x", crc32.ChecksumIEEE(data))
+	case ChecksumSHA256:
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])[:sha256HexDigits]
+	}
+	return ""
+}
+
+// AppendChecksum appends " [algorithm:digest]" to record, with the digest
+// computed over data (the device's bytes, not the header). With no algorithm
+// the record is returned unchanged.
+func AppendChecksum(record, algorithm string, data []byte) string {
+	sum := Checksum(algorithm, data)
+	if sum == "" {
+		return record
+	}
+	return record + " [" + algorithm + ":" + sum + "]"
+}
@@ -0,0 +1,38 @@
+package output
+
+import "testing"
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		data      string
+		want      string
+	}{
+		{ChecksumCRC32, "123456789", "cbf43926"},
+		{ChecksumCRC32, "", "00000000"},
+		{ChecksumSHA256, "abc", "ba7816bf8f01cfea"},
+		{"", "abc", ""},
+		{"md5", "abc", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Checksum(tt.algorithm, []byte(tt.data)); got != tt.want {
+			t.Errorf("Checksum(%q, %q) = %q, want %q", tt.algorithm, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestAppendChecksum(t *testing.T) {
+	header := "[1429010002][A1][2025-12-03 15:04:05.123] "
+
+	got := AppendChecksum(header+"123456789", ChecksumCRC32, []byte("123456789"))
+	want := header + "123456789 [crc32:cbf43926]"
+	if got != want {
+		t.Errorf("AppendChecksum() = %q, want %q", got, want)
+	}
+
+	// Off: record unchanged
+	if got := AppendChecksum(header+"abc", "", []byte("abc")); got != header+"abc" {
+		t.Errorf("AppendChecksum() with no algorithm = %q", got)
+	}
+}
@@ -1,12 +1,19 @@
 package output
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -15,12 +22,28 @@ import (
 // DualWriter writes data to both a rotating log file and NATS JetStream
 type DualWriter struct {
 	device      string
-	logWriter   *lumberjack.Logger
+	logWriter   io.WriteCloser // lumberjack rotating log, swappable in tests
+	logPath     string
 	natsConn    *NATSConnection
 	natsSubject string
+	compress    bool                      // gzip NATS payloads
+	publish     func(msg *nats.Msg) error // NATS publish, swappable in tests
 	logger      *slog.Logger
 	natsEnabled bool
 	mu          sync.Mutex
+
+	extraSubjects []string     // Fan-out subjects published after natsSubject
+	extraErrors   atomic.Int64 // Failed fan-out publishes
+	shortWrites   atomic.Int64 // Records only partly written to the log
+
+	logBuf     *bufio.Writer // Buffers log writes in front of logWriter (nil = unbuffered)
+	bufRecords int           // Records at least partly held in logBuf
+	// Buffered log data discarded after a failed write or flush
+	droppedRecords atomic.Int64
+	droppedBytes   atomic.Int64
+	stopFlush      chan struct{} // Closed by Close to stop the flush loop
+	flushDone      chan struct{} // Closed when the flush loop exits
+	closeOnce      sync.Once
 }
 
 // DualWriterConfig contains configuration for DualWriter
@@ -28,19 +51,46 @@ type DualWriterConfig struct {
 	Device        string
 	Identifier    string // FIPS-A format (e.g., "1429010002-A1")
 	LogBasePath   string
+	LogFilename   string // Relative to LogBasePath, may include subdirectories (default: Identifier + ".log")
 	LogMaxSizeMB  int
 	LogMaxBackups int
 	LogCompress   bool
 	NATSConn      *NATSConnection
 	NATSSubject   string
-	Logger        *slog.Logger
+	// CompressPayload gzips the NATS payload and sets Content-Encoding: gzip.
+	// The log file is always written uncompressed.
+	CompressPayload bool
+	// ExtraSubjects also receive every record published to NATSSubject. A
+	// failed extra publish is logged and counted but doesn't fail the write.
+	ExtraSubjects []string
+	// Publish replaces NATSConn for publishing records (e.g., a mock in
+	// tests). NATS output is enabled when either is set.
+	Publish func(msg *nats.Msg) error
+	// LogBufferSize buffers log writes (bytes, 0 = unbuffered). The buffer
+	// is flushed when full, every LogFlushInterval (default 1s) and on Close; records are
+	// still published to NATS as they're written.
+	LogBufferSize    int
+	LogFlushInterval time.Duration
+	Logger           *slog.Logger
 }
 
+// PayloadEncodingGzip is the Content-Encoding header value on compressed NATS payloads
+const PayloadEncodingGzip = "gzip"
+
 // NewDualWriter creates a new DualWriter
 func NewDualWriter(cfg *DualWriterConfig) (*DualWriter, error) {
 	// Create log file path from identifier
 	// e.g., 1429010002-A1 -> /var/log/nectarcollector/1429010002-A1.log
-	logPath := filepath.Join(cfg.LogBasePath, cfg.Identifier+".log")
+	logFilename := cfg.LogFilename
+	if logFilename == "" {
+		logFilename = cfg.Identifier + ".log"
+	}
+	logPath := filepath.Join(cfg.LogBasePath, logFilename)
+
+	// Templates may group logs into subdirectories (e.g., by county)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
 
 	// Create rotating log writer
 	logWriter := &lumberjack.Logger{
@@ -52,18 +102,37 @@ func NewDualWriter(cfg *DualWriterConfig) (*DualWriter, error) {
 
 	dw := &DualWriter{
 		device:      cfg.Device,
+		logPath:     logPath,
 		logWriter:   logWriter,
 		natsConn:    cfg.NATSConn,
 		natsSubject: cfg.NATSSubject,
+		compress:    cfg.CompressPayload,
 		logger:      cfg.Logger,
-		natsEnabled: cfg.NATSConn != nil,
+		natsEnabled: cfg.NATSConn != nil || cfg.Publish != nil,
+
+		extraSubjects: cfg.ExtraSubjects,
+	}
+	switch {
+	case cfg.Publish != nil:
+		dw.publish = cfg.Publish
+	case cfg.NATSConn != nil:
+		dw.publish = cfg.NATSConn.PublishMsg
+	}
+	if cfg.LogBufferSize > 0 {
+		dw.logBuf = bufio.NewWriterSize(logWriter, cfg.LogBufferSize)
+		dw.stopFlush = make(chan struct{})
+		dw.flushDone = make(chan struct{})
+		go dw.flushLoop(cfg.LogFlushInterval)
 	}
 
 	cfg.Logger.Info("Initialized dual writer",
 		"device", cfg.Device,
 		"log_path", logPath,
 		"nats_subject", cfg.NATSSubject,
-		"nats_enabled", dw.natsEnabled)
+		"nats_enabled", dw.natsEnabled,
+		"extra_subjects", cfg.ExtraSubjects,
+		"compress_payload", cfg.CompressPayload,
+		"log_buffer_size", cfg.LogBufferSize)
 
 	return dw, nil
 }
@@ -76,16 +145,41 @@ func (dw *DualWriter) Write(data string) error {
 	var lastErr error
 
 	// Write to log file (primary output)
-	if _, err := io.WriteString(dw.logWriter, data); err != nil {
+	var n int
+	var err error
+	if dw.logBuf != nil {
+		n, err = dw.logBuf.WriteString(data)
+		if err != nil {
+			// bufio errors are sticky: drop what's buffered so later
+			// records can be written once the disk recovers
+			if n > 0 {
+				dw.bufRecords++
+			}
+			dw.dropBufferLocked()
+		} else {
+			dw.countBufferedLocked(n)
+		}
+	} else {
+		n, err = io.WriteString(dw.logWriter, data)
+	}
+	partial := n > 0 && n < len(data)
+	if partial {
+		// e.g., disk full mid-write: the log now holds a truncated record
+		dw.shortWrites.Add(1)
+		err = fmt.Errorf("%w: %d of %d bytes written to log", io.ErrShortWrite, n, len(data))
+	}
+	if err != nil {
 		dw.logger.Error("Failed to write to log file",
 			"device", dw.device,
 			"error", err)
 		lastErr = err
 	}
 
-	// Write to NATS (secondary output - continue on failure)
-	if dw.natsEnabled {
-		if err := dw.natsConn.Publish(dw.natsSubject, []byte(data)); err != nil {
+	// Write to NATS (secondary output - continue on failure). A record the log
+	// only partly holds isn't published, so consumers never see a CDR that
+	// differs from the log of record.
+	if dw.natsEnabled && !partial {
+		if err := dw.publishNATS([]byte(data)); err != nil {
 			dw.logger.Warn("Failed to publish to NATS",
 				"device", dw.device,
 				"subject", dw.natsSubject,
@@ -108,16 +202,189 @@ func (dw *DualWriter) WriteLine(line string) error {
 	return dw.Write(line)
 }
 
-// Close closes the log writer
+// PublishTo publishes a line to subject only: it isn't logged or fanned out
+// to extra subjects. Used for restricted copies of a record, e.g. the
+// unredacted original. A no-op when NATS is disabled.
+func (dw *DualWriter) PublishTo(subject, line string) error {
+	if !dw.natsEnabled {
+		return nil
+	}
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+
+	msg, err := encodeNATSMsg(subject, []byte(line), dw.compress)
+	if err != nil {
+		return err
+	}
+
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	return dw.publish(msg)
+}
+
+// publishNATS publishes one record, gzipped if compression is enabled, then
+// fans it out to any extra subjects. Only the primary publish's error is
+// returned.
+func (dw *DualWriter) publishNATS(data []byte) error {
+	msg, err := encodeNATSMsg(dw.natsSubject, data, dw.compress)
+	if err != nil {
+		return err
+	}
+	err = dw.publish(msg)
+
+	for _, subject := range dw.extraSubjects {
+		extra := &nats.Msg{Subject: subject, Header: msg.Header, Data: msg.Data}
+		if extraErr := dw.publish(extra); extraErr != nil {
+			dw.extraErrors.Add(1)
+			dw.logger.Warn("Failed to publish to extra NATS subject",
+				"device", dw.device,
+				"subject", subject,
+				"error", extraErr)
+		}
+	}
+
+	return err
+}
+
+// ShortWrites returns how many records were only partly written to the log
+// (and so not published)
+func (dw *DualWriter) ShortWrites() int64 {
+	return dw.shortWrites.Load()
+}
+
+// DroppedLogRecords returns how many buffered records were discarded because
+// the log write or flush failed. Such records were still published to NATS.
+func (dw *DualWriter) DroppedLogRecords() int64 {
+	return dw.droppedRecords.Load()
+}
+
+// DroppedLogBytes returns how many buffered log bytes were discarded
+func (dw *DualWriter) DroppedLogBytes() int64 {
+	return dw.droppedBytes.Load()
+}
+
+// ExtraPublishErrors returns how many publishes to extra subjects failed
+func (dw *DualWriter) ExtraPublishErrors() int64 {
+	return dw.extraErrors.Load()
+}
+
+// encodeNATSMsg builds the NATS message for a record. Compressed payloads
+// carry a Content-Encoding header so consumers know to gunzip.
+func encodeNATSMsg(subject string, data []byte, compress bool) (*nats.Msg, error) {
+	msg := nats.NewMsg(subject)
+	if !compress {
+		msg.Data = data
+		return msg, nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+
+	msg.Data = buf.Bytes()
+	msg.Header.Set("Content-Encoding", PayloadEncodingGzip)
+	return msg, nil
+}
+
+// LogPath returns the path of the log file being written
+func (dw *DualWriter) LogPath() string {
+	return dw.logPath
+}
+
+// Flush writes any buffered log data to the file. A no-op when unbuffered.
+func (dw *DualWriter) Flush() error {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	return dw.flushLocked()
+}
+
+// flushLocked flushes the log buffer. Caller must hold dw.mu.
+func (dw *DualWriter) flushLocked() error {
+	if dw.logBuf == nil || dw.logBuf.Buffered() == 0 {
+		return nil
+	}
+	if err := dw.logBuf.Flush(); err != nil {
+		lost := dw.dropBufferLocked()
+		return fmt.Errorf("log flush failed, %d buffered bytes dropped: %w", lost, err)
+	}
+	dw.bufRecords = 0
+	return nil
+}
+
+// countBufferedLocked updates bufRecords after n bytes were accepted by
+// logBuf. bufio flushes a full buffer mid-write, so if no more than n bytes
+// remain buffered only this record is left in it. Caller must hold dw.mu.
+func (dw *DualWriter) countBufferedLocked(n int) {
+	switch buffered := dw.logBuf.Buffered(); {
+	case buffered == 0:
+		dw.bufRecords = 0
+	case buffered <= n:
+		dw.bufRecords = 1
+	default:
+		dw.bufRecords++
+	}
+}
+
+// dropBufferLocked discards the buffered log data, counting it as dropped,
+// and returns how many bytes were lost. Caller must hold dw.mu.
+func (dw *DualWriter) dropBufferLocked() int {
+	lost := dw.logBuf.Buffered()
+	if lost > 0 {
+		dw.droppedRecords.Add(int64(dw.bufRecords))
+		dw.droppedBytes.Add(int64(lost))
+	}
+	dw.bufRecords = 0
+	dw.logBuf.Reset(dw.logWriter)
+	return lost
+}
+
+// flushLoop flushes the log buffer every interval until Close
+func (dw *DualWriter) flushLoop(interval time.Duration) {
+	defer close(dw.flushDone)
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-dw.stopFlush:
+			return
+		case <-ticker.C:
+			if err := dw.Flush(); err != nil {
+				dw.logger.Error("Failed to flush log buffer", "device", dw.device, "error", err)
+			}
+		}
+	}
+}
+
+// Close flushes any buffered log data and closes the log writer
 func (dw *DualWriter) Close() error {
+	if dw.stopFlush != nil {
+		dw.closeOnce.Do(func() {
+			close(dw.stopFlush)
+			<-dw.flushDone
+		})
+	}
+
 	dw.mu.Lock()
 	defer dw.mu.Unlock()
 
+	flushErr := dw.flushLocked()
 	if dw.logWriter != nil {
-		return dw.logWriter.Close()
+		if err := dw.logWriter.Close(); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return flushErr
 }
 
 // NATSConnection manages NATS connection
@@ -126,12 +393,69 @@ type NATSConnection struct {
 	url    string
 	logger *slog.Logger
 	mu     sync.RWMutex
+
+	// Cached by the RTT monitor so Stats never waits on the network
+	lastRTT   time.Duration
+	lastRTTAt time.Time
+	rttMu     sync.RWMutex
+
+	// Reused by JetStreamBacked so status polls don't query the server
+	backed backedCache
+}
+
+// NATSRTTInterval is how often the RTT monitor pings the server
+const NATSRTTInterval = 30 * time.Second
+
+// rttMeasurer is the part of *nats.Conn needed for round-trip measurement
+type rttMeasurer interface {
+	RTT() (time.Duration, error)
+}
+
+// NATSConnectionConfig contains configuration for NewNATSConnection
+type NATSConnectionConfig struct {
+	URL           string
+	Name          string // Client name shown by `nats server report connz` (e.g., the instance ID)
+	MaxReconnects int
+	ReconnectWait time.Duration // 0 = client default
+	// ReconnectJitter adds up to this much random delay to each reconnect
+	// wait (0 = client default)
+	ReconnectJitter time.Duration
+	// ConnectWait keeps retrying an unreachable server at startup for this
+	// long before giving up. 0 here fails on the first attempt, but the
+	// manager passes NATSConfig.ConnectWait, which turns an unset
+	// connect_wait_sec into the 30s default.
+	ConnectWait time.Duration
+	Logger      *slog.Logger
 }
 
 // NewNATSConnection creates a new NATS connection
-func NewNATSConnection(url string, maxReconnects int, logger *slog.Logger) (*NATSConnection, error) {
+func NewNATSConnection(cfg *NATSConnectionConfig) (*NATSConnection, error) {
+	conn, err := nats.Connect(cfg.URL, natsOptions(cfg)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.URL, err)
+	}
+
+	// With retry on, Connect returns before the first connection is made
+	if cfg.ConnectWait > 0 && !waitConnected(conn, cfg.ConnectWait) {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to NATS at %s within %s", cfg.URL, cfg.ConnectWait)
+	}
+
+	cfg.Logger.Info("Connected to NATS", "url", conn.ConnectedUrl(), "name", cfg.Name)
+
+	return &NATSConnection{
+		conn:   conn,
+		url:    cfg.URL,
+		logger: cfg.Logger,
+	}, nil
+}
+
+// natsOptions builds the client options for NewNATSConnection
+func natsOptions(cfg *NATSConnectionConfig) []nats.Option {
+	logger := cfg.Logger
 	opts := []nats.Option{
-		nats.MaxReconnects(maxReconnects),
+		nats.Name(cfg.Name),
+		nats.MaxReconnects(cfg.MaxReconnects),
 		nats.ReconnectHandler(func(nc *nats.Conn) {
 			logger.Info("Reconnected to NATS", "url", nc.ConnectedUrl())
 		}),
@@ -143,20 +467,37 @@ func NewNATSConnection(url string, maxReconnects int, logger *slog.Logger) (*NAT
 		nats.ClosedHandler(func(nc *nats.Conn) {
 			logger.Info("NATS connection closed")
 		}),
+		// A server going into lame duck mode (e.g., a rolling upgrade) will
+		// close us shortly; the client reconnects to another cluster member
+		nats.LameDuckModeHandler(func(nc *nats.Conn) {
+			logger.Warn("NATS server entering lame duck mode, expect a reconnect", "url", nc.ConnectedUrl())
+		}),
+		nats.DiscoveredServersHandler(func(nc *nats.Conn) {
+			logger.Info("Discovered NATS servers", "servers", nc.DiscoveredServers())
+		}),
 	}
-
-	conn, err := nats.Connect(url, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", url, err)
+	if cfg.ReconnectWait > 0 {
+		opts = append(opts, nats.ReconnectWait(cfg.ReconnectWait))
 	}
+	if cfg.ReconnectJitter > 0 {
+		opts = append(opts, nats.ReconnectJitter(cfg.ReconnectJitter, cfg.ReconnectJitter))
+	}
+	if cfg.ConnectWait > 0 {
+		opts = append(opts, nats.RetryOnFailedConnect(true))
+	}
+	return opts
+}
 
-	logger.Info("Connected to NATS", "url", url)
-
-	return &NATSConnection{
-		conn:   conn,
-		url:    url,
-		logger: logger,
-	}, nil
+// waitConnected polls until conn is connected or timeout passes
+func waitConnected(conn *nats.Conn, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for !conn.IsConnected() {
+		if conn.IsClosed() || time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
 }
 
 // Close closes the NATS connection
@@ -207,13 +548,63 @@ func (nc *NATSConnection) Publish(subject string, data []byte) error {
 	return conn.Publish(subject, data)
 }
 
+// PublishMsg sends a message (with any headers) to NATS
+func (nc *NATSConnection) PublishMsg(msg *nats.Msg) error {
+	nc.mu.RLock()
+	conn := nc.conn
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return fmt.Errorf("NATS connection is nil")
+	}
+	return conn.PublishMsg(msg)
+}
+
+// StartRTTMonitor measures round-trip time to the server immediately and
+// then every interval until ctx is cancelled
+func (nc *NATSConnection) StartRTTMonitor(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if conn := nc.Conn(); conn != nil && conn.IsConnected() {
+				nc.recordRTT(conn)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// recordRTT pings the server and caches the result. A failed ping keeps the
+// previous value; its age (LastRTTAt) shows how stale it is.
+func (nc *NATSConnection) recordRTT(m rttMeasurer) {
+	rtt, err := m.RTT()
+	if err != nil {
+		nc.logger.Debug("NATS RTT measurement failed", "error", err)
+		return
+	}
+
+	nc.rttMu.Lock()
+	nc.lastRTT = rtt
+	nc.lastRTTAt = time.Now()
+	nc.rttMu.Unlock()
+}
+
 // NATSStats contains NATS connection statistics
 type NATSStats struct {
-	Connected    bool   `json:"connected"`
-	URL          string `json:"url"`
-	ConnectedURL string `json:"connected_url,omitempty"`
-	ServerID     string `json:"server_id,omitempty"`
-	Reconnects   uint64 `json:"reconnects"`
+	Connected    bool       `json:"connected"`
+	URL          string     `json:"url"`
+	ConnectedURL string     `json:"connected_url,omitempty"`
+	ServerID     string     `json:"server_id,omitempty"`
+	Reconnects   uint64     `json:"reconnects"`
+	LastRTTMs    float64    `json:"last_rtt_ms,omitempty"` // Most recent round trip to the server
+	LastRTTAt    *time.Time `json:"last_rtt_at,omitempty"` // When LastRTTMs was measured (nil = never)
 	// Stream stats (from JetStream)
 	Streams map[string]StreamStats `json:"streams,omitempty"`
 }
@@ -233,6 +624,14 @@ func (nc *NATSConnection) Stats() NATSStats {
 		URL: nc.url,
 	}
 
+	nc.rttMu.RLock()
+	if !nc.lastRTTAt.IsZero() {
+		at := nc.lastRTTAt
+		stats.LastRTTMs = float64(nc.lastRTT.Microseconds()) / 1000
+		stats.LastRTTAt = &at
+	}
+	nc.rttMu.RUnlock()
+
 	if nc.conn == nil {
 		return stats
 	}
@@ -250,6 +649,61 @@ func (nc *NATSConnection) Stats() NATSStats {
 	return stats
 }
 
+// ConsumerRef names a durable consumer on a stream
+type ConsumerRef struct {
+	Stream string
+	Name   string
+}
+
+// ConsumerStats contains lag for a single JetStream consumer
+type ConsumerStats struct {
+	Stream         string `json:"stream"`
+	Name           string `json:"name"`
+	NumPending     uint64 `json:"num_pending"`     // Messages not yet delivered
+	NumAckPending  int    `json:"num_ack_pending"` // Delivered but not yet acked
+	NumRedelivered int    `json:"num_redelivered"`
+	Error          string `json:"error,omitempty"` // e.g., consumer not found
+}
+
+// consumerInfoer is the part of nats.JetStreamContext needed for consumer lag
+type consumerInfoer interface {
+	ConsumerInfo(stream, name string, opts ...nats.JSOpt) (*nats.ConsumerInfo, error)
+}
+
+// ConsumerStats returns pending/ack-pending counts for the named consumers,
+// or nil if not connected
+func (nc *NATSConnection) ConsumerStats(consumers []ConsumerRef) []ConsumerStats {
+	if !nc.IsConnected() || len(consumers) == 0 {
+		return nil
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil
+	}
+
+	return consumerStats(js, consumers)
+}
+
+// consumerStats queries each consumer. A missing consumer is reported with
+// an error rather than dropped, so a stalled or deleted consumer is visible.
+func consumerStats(js consumerInfoer, consumers []ConsumerRef) []ConsumerStats {
+	result := make([]ConsumerStats, 0, len(consumers))
+	for _, ref := range consumers {
+		stats := ConsumerStats{Stream: ref.Stream, Name: ref.Name}
+		info, err := js.ConsumerInfo(ref.Stream, ref.Name)
+		if err != nil {
+			stats.Error = err.Error()
+		} else {
+			stats.NumPending = info.NumPending
+			stats.NumAckPending = info.NumAckPending
+			stats.NumRedelivered = info.NumRedelivered
+		}
+		result = append(result, stats)
+	}
+	return result
+}
+
 // StatsWithStreams returns NATS stats including JetStream stream info
 func (nc *NATSConnection) StatsWithStreams(streamNames []string) NATSStats {
 	stats := nc.Stats()
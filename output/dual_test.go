@@ -1,11 +1,22 @@
 package output
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
 )
 
 func TestNewDualWriter(t *testing.T) {
@@ -224,6 +235,38 @@ func TestDualWriterLogPath(t *testing.T) {
 	}
 }
 
+func TestDualWriterLogFilenameSubdirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	dw, err := NewDualWriter(&DualWriterConfig{
+		Device:       "/dev/ttyS1",
+		Identifier:   "1234567890-A1",
+		LogBasePath:  tmpDir,
+		LogFilename:  filepath.Join("lancaster", "2025", "1234567890-A1.log"),
+		LogMaxSizeMB: 10,
+		Logger:       logger,
+	})
+	if err != nil {
+		t.Fatalf("NewDualWriter() error = %v", err)
+	}
+
+	expectedPath := filepath.Join(tmpDir, "lancaster", "2025", "1234567890-A1.log")
+	if dw.LogPath() != expectedPath {
+		t.Errorf("LogPath() = %q, want %q", dw.LogPath(), expectedPath)
+	}
+	if info, err := os.Stat(filepath.Dir(expectedPath)); err != nil || !info.IsDir() {
+		t.Fatalf("log directory not created: %v", err)
+	}
+
+	dw.WriteLine("test")
+	dw.Close()
+
+	if _, err := os.Stat(expectedPath); err != nil {
+		t.Errorf("Expected log file %s to exist: %v", expectedPath, err)
+	}
+}
+
 func TestNATSConnectionIsConnected(t *testing.T) {
 	// Test with nil connection
 	nc := &NATSConnection{
@@ -252,28 +295,550 @@ func TestNATSConnectionClose(t *testing.T) {
 }
 
 func BenchmarkDualWriterWriteLine(b *testing.B) {
-	tmpDir := b.TempDir()
+	for _, bc := range []struct {
+		name       string
+		bufferSize int
+	}{
+		{"unbuffered", 0},
+		{"buffered", 64 << 10},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			tmpDir := b.TempDir()
+			logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+			cfg := &DualWriterConfig{
+				Device:        "/dev/ttyS1",
+				Identifier:    "bench-test",
+				LogBasePath:   tmpDir,
+				LogMaxSizeMB:  100,
+				LogMaxBackups: 1,
+				LogCompress:   false,
+				NATSConn:      nil,
+				NATSSubject:   "test.cdr",
+				LogBufferSize: bc.bufferSize,
+				Logger:        logger,
+			}
+
+			dw, _ := NewDualWriter(cfg)
+			defer dw.Close()
+
+			testLine := "[1234567890][A1][2025-01-01 00:00:00.000] Sample CDR data line for benchmarking"
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dw.WriteLine(testLine)
+			}
+		})
+	}
+}
+
+func TestDualWriterBufferedClose(t *testing.T) {
+	var published atomic.Int64
+	dw, err := NewDualWriter(&DualWriterConfig{
+		Device:           "/dev/ttyS1",
+		Identifier:       "1234567890-A1",
+		LogBasePath:      t.TempDir(),
+		LogMaxSizeMB:     10,
+		NATSSubject:      "test.cdr",
+		Publish:          func(*nats.Msg) error { published.Add(1); return nil },
+		LogBufferSize:    64 << 10,
+		LogFlushInterval: time.Hour, // Only Close flushes
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatalf("NewDualWriter() error = %v", err)
+	}
+
+	const lines = 1000
+	for i := range lines {
+		if err := dw.WriteLine(fmt.Sprintf("CALL %04d", i)); err != nil {
+			t.Fatalf("WriteLine() error = %v", err)
+		}
+	}
+
+	// NATS isn't held back by the log buffer
+	if got := published.Load(); got != lines {
+		t.Errorf("published %d records before Close, want %d", got, lines)
+	}
+
+	if err := dw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// A second Close (e.g., restart after stop) is harmless
+	dw.Close()
+
+	data, err := os.ReadFile(dw.LogPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(got) != lines {
+		t.Fatalf("log has %d lines after Close, want %d", len(got), lines)
+	}
+	for i, line := range got {
+		if want := fmt.Sprintf("CALL %04d", i); line != want {
+			t.Fatalf("line %d = %q, want %q", i, line, want)
+		}
+	}
+}
+
+func TestDualWriterBufferedFlushInterval(t *testing.T) {
+	dw, err := NewDualWriter(&DualWriterConfig{
+		Device:           "/dev/ttyS1",
+		Identifier:       "1234567890-A1",
+		LogBasePath:      t.TempDir(),
+		LogMaxSizeMB:     10,
+		LogBufferSize:    64 << 10,
+		LogFlushInterval: 10 * time.Millisecond,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatalf("NewDualWriter() error = %v", err)
+	}
+	defer dw.Close()
+
+	if err := dw.WriteLine("CALL 001"); err != nil {
+		t.Fatalf("WriteLine() error = %v", err)
+	}
+
+	// The periodic flush lands the record without waiting for Close
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		data, _ := os.ReadFile(dw.LogPath())
+		if string(data) == "CALL 001\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("log = %q after flush interval, want the buffered record", data)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEncodeNATSMsgCompressed(t *testing.T) {
+	record := "[1429010002][A1][2025-12-03 15:04:05.123] <CDR><ANI>4025551212</ANI></CDR>\n"
+
+	msg, err := encodeNATSMsg("ne.cdr.1429010002", []byte(record), true)
+	if err != nil {
+		t.Fatalf("encodeNATSMsg() error = %v", err)
+	}
+
+	if got := msg.Header.Get("Content-Encoding"); got != PayloadEncodingGzip {
+		t.Errorf("Content-Encoding = %q, want %q", got, PayloadEncodingGzip)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(msg.Data))
+	if err != nil {
+		t.Fatalf("payload is not gzip: %v", err)
+	}
+	decoded, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to gunzip payload: %v", err)
+	}
+	if string(decoded) != record {
+		t.Errorf("gunzipped payload = %q, want %q", decoded, record)
+	}
+}
+
+func TestEncodeNATSMsgUncompressed(t *testing.T) {
+	msg, err := encodeNATSMsg("ne.cdr.1429010002", []byte("plain"), false)
+	if err != nil {
+		t.Fatalf("encodeNATSMsg() error = %v", err)
+	}
+	if string(msg.Data) != "plain" {
+		t.Errorf("Data = %q, want %q", msg.Data, "plain")
+	}
+	if msg.Header.Get("Content-Encoding") != "" {
+		t.Error("uncompressed message should not set Content-Encoding")
+	}
+}
+
+func TestDualWriterCompressPayload(t *testing.T) {
+	tmpDir := t.TempDir()
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
-	cfg := &DualWriterConfig{
+	dw, err := NewDualWriter(&DualWriterConfig{
+		Device:          "/dev/ttyS1",
+		Identifier:      "1234567890-A1",
+		LogBasePath:     tmpDir,
+		LogMaxSizeMB:    10,
+		NATSSubject:     "test.cdr",
+		CompressPayload: true,
+		Logger:          logger,
+	})
+	if err != nil {
+		t.Fatalf("NewDualWriter() error = %v", err)
+	}
+
+	// Capture publishes instead of sending to a server
+	var published []*nats.Msg
+	dw.natsEnabled = true
+	dw.publish = func(msg *nats.Msg) error {
+		published = append(published, msg)
+		return nil
+	}
+
+	if err := dw.WriteLine("CALL 001 IN"); err != nil {
+		t.Fatalf("WriteLine() error = %v", err)
+	}
+	dw.Close()
+
+	if len(published) != 1 {
+		t.Fatalf("published %d messages, want 1", len(published))
+	}
+	if published[0].Subject != "test.cdr" {
+		t.Errorf("Subject = %q, want %q", published[0].Subject, "test.cdr")
+	}
+	if published[0].Header.Get("Content-Encoding") != PayloadEncodingGzip {
+		t.Error("published message missing Content-Encoding: gzip")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(published[0].Data))
+	if err != nil {
+		t.Fatalf("payload is not gzip: %v", err)
+	}
+	decoded, _ := io.ReadAll(gz)
+	if string(decoded) != "CALL 001 IN\n" {
+		t.Errorf("gunzipped payload = %q, want %q", decoded, "CALL 001 IN\n")
+	}
+
+	// Log file stays human-readable
+	content, err := os.ReadFile(filepath.Join(tmpDir, "1234567890-A1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "CALL 001 IN\n" {
+		t.Errorf("log content = %q, want uncompressed record", content)
+	}
+}
+
+func TestDualWriterExtraSubjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	// A mock connection that rejects the archive subject
+	var published []*nats.Msg
+	dw, err := NewDualWriter(&DualWriterConfig{
 		Device:        "/dev/ttyS1",
-		Identifier:    "bench-test",
+		Identifier:    "1234567890-A1",
 		LogBasePath:   tmpDir,
-		LogMaxSizeMB:  100,
-		LogMaxBackups: 1,
-		LogCompress:   false,
-		NATSConn:      nil,
+		LogMaxSizeMB:  10,
 		NATSSubject:   "test.cdr",
-		Logger:        logger,
+		ExtraSubjects: []string{"realtime.cdr", "archive.cdr"},
+		Publish: func(msg *nats.Msg) error {
+			if msg.Subject == "archive.cdr" {
+				return fmt.Errorf("no responders")
+			}
+			published = append(published, msg)
+			return nil
+		},
+		Logger: logger,
+	})
+	if err != nil {
+		t.Fatalf("NewDualWriter() error = %v", err)
+	}
+	defer dw.Close()
+
+	// The failed extra doesn't fail the write
+	if err := dw.WriteLine("CALL 001 IN"); err != nil {
+		t.Fatalf("WriteLine() error = %v, want nil despite the failed extra subject", err)
+	}
+
+	if len(published) != 2 {
+		t.Fatalf("published %d messages, want 2", len(published))
+	}
+	for i, subject := range []string{"test.cdr", "realtime.cdr"} {
+		if published[i].Subject != subject {
+			t.Errorf("published[%d].Subject = %q, want %q", i, published[i].Subject, subject)
+		}
+		if string(published[i].Data) != "CALL 001 IN\n" {
+			t.Errorf("published[%d].Data = %q, want the record", i, published[i].Data)
+		}
+	}
+	if got := dw.ExtraPublishErrors(); got != 1 {
+		t.Errorf("ExtraPublishErrors() = %d, want 1", got)
+	}
+}
+
+func TestNATSOptions(t *testing.T) {
+	cfg := &NATSConnectionConfig{
+		URL:           "nats://localhost:4222",
+		Name:          "psap-01",
+		MaxReconnects: 10,
+		ReconnectWait: 5 * time.Second,
+		ConnectWait:   30 * time.Second,
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+
+		ReconnectJitter: time.Second,
+	}
+
+	opts := nats.GetDefaultOptions()
+	for _, opt := range natsOptions(cfg) {
+		if err := opt(&opts); err != nil {
+			t.Fatalf("option error: %v", err)
+		}
+	}
+	if opts.Name != "psap-01" {
+		t.Errorf("Name = %q, want %q", opts.Name, "psap-01")
+	}
+	if !opts.RetryOnFailedConnect {
+		t.Error("RetryOnFailedConnect not set with a connect wait")
+	}
+	if opts.MaxReconnect != 10 || opts.ReconnectWait != 5*time.Second {
+		t.Errorf("MaxReconnect/ReconnectWait = %d/%s, want 10/5s", opts.MaxReconnect, opts.ReconnectWait)
+	}
+	if opts.ReconnectJitter != time.Second || opts.ReconnectJitterTLS != time.Second {
+		t.Errorf("ReconnectJitter/TLS = %s/%s, want 1s/1s", opts.ReconnectJitter, opts.ReconnectJitterTLS)
 	}
+	if opts.LameDuckModeHandler == nil || opts.DiscoveredServersCB == nil {
+		t.Error("lame duck and discovered servers handlers should be set")
+	}
+
+	// Without a connect wait the first failure is final
+	cfg.ConnectWait = 0
+	opts = nats.GetDefaultOptions()
+	for _, opt := range natsOptions(cfg) {
+		opt(&opts)
+	}
+	if opts.RetryOnFailedConnect {
+		t.Error("RetryOnFailedConnect set without a connect wait")
+	}
+}
+
+func TestNewNATSConnectionBoundedWait(t *testing.T) {
+	// Nothing listens on this port
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "nats://" + ln.Addr().String()
+	ln.Close()
+
+	start := time.Now()
+	_, err = NewNATSConnection(&NATSConnectionConfig{
+		URL:           url,
+		Name:          "psap-01",
+		MaxReconnects: -1,
+		ReconnectWait: 50 * time.Millisecond,
+		ConnectWait:   300 * time.Millisecond,
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err == nil {
+		t.Fatal("NewNATSConnection() should fail once the connect wait passes")
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("gave up after %s, want about the 300ms connect wait", elapsed)
+	}
+}
+
+// shortWriter accepts only the first limit bytes of each write, as a full
+// disk does mid-record
+type shortWriter struct {
+	buf   bytes.Buffer
+	limit int
+	err   error
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.limit {
+		return w.buf.Write(p)
+	}
+	w.buf.Write(p[:w.limit])
+	return w.limit, w.err
+}
+
+func (w *shortWriter) Close() error { return nil }
+
+func TestDualWriterShortWrite(t *testing.T) {
+	for _, writeErr := range []error{nil, fmt.Errorf("no space left on device")} {
+		dw, err := NewDualWriter(&DualWriterConfig{
+			Device:       "/dev/ttyS1",
+			Identifier:   "1234567890-A1",
+			LogBasePath:  t.TempDir(),
+			LogMaxSizeMB: 10,
+			NATSSubject:  "test.cdr",
+			Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+		if err != nil {
+			t.Fatalf("NewDualWriter() error = %v", err)
+		}
+		var published []*nats.Msg
+		dw.natsEnabled = true
+		dw.publish = func(msg *nats.Msg) error {
+			published = append(published, msg)
+			return nil
+		}
+		log := &shortWriter{limit: 5, err: writeErr}
+		dw.logWriter = log
+
+		err = dw.WriteLine("CALL 001 IN")
+		if !errors.Is(err, io.ErrShortWrite) {
+			t.Errorf("WriteLine() error = %v, want io.ErrShortWrite (writer error %v)", err, writeErr)
+		}
+		if len(published) != 0 {
+			t.Errorf("published %d messages, want none for a truncated log record", len(published))
+		}
+		if got := dw.ShortWrites(); got != 1 {
+			t.Errorf("ShortWrites() = %d, want 1", got)
+		}
 
-	dw, _ := NewDualWriter(cfg)
+		// Complete writes still go to both outputs
+		if err := dw.WriteLine("OK"); err != nil {
+			t.Errorf("WriteLine() error = %v for a complete write", err)
+		}
+		if len(published) != 1 || dw.ShortWrites() != 1 {
+			t.Errorf("after complete write: published %d, ShortWrites %d, want 1 and 1", len(published), dw.ShortWrites())
+		}
+	}
+}
+
+func TestDualWriterBufferedDropsCounted(t *testing.T) {
+	dw, err := NewDualWriter(&DualWriterConfig{
+		Device:           "/dev/ttyS1",
+		Identifier:       "1234567890-A1",
+		LogBasePath:      t.TempDir(),
+		LogMaxSizeMB:     10,
+		LogBufferSize:    16,
+		LogFlushInterval: time.Hour, // Only explicit flushes
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatalf("NewDualWriter() error = %v", err)
+	}
 	defer dw.Close()
+	log := &shortWriter{err: fmt.Errorf("no space left on device")}
+	dw.logWriter = log
+	dw.logBuf = bufio.NewWriterSize(log, 16)
+
+	// Two records buffered, then the flush fails
+	dw.WriteLine("CALL 1")
+	dw.WriteLine("CALL 2")
+	if err := dw.Flush(); err == nil {
+		t.Fatal("Flush() error = nil, want the write error")
+	}
+	if r, b := dw.DroppedLogRecords(), dw.DroppedLogBytes(); r != 2 || b != 14 {
+		t.Errorf("after failed flush dropped %d records, %d bytes, want 2 and 14", r, b)
+	}
+
+	// A write that overflows the buffer fails mid-record: the buffered
+	// record and the part of this one that fit are both dropped
+	dw.WriteLine("CALL 3")
+	if err := dw.WriteLine("CALL 4 LONGER"); err == nil {
+		t.Fatal("WriteLine() error = nil, want the write error")
+	}
+	if r, b := dw.DroppedLogRecords(), dw.DroppedLogBytes(); r != 4 || b != 30 {
+		t.Errorf("after failed write dropped %d records, %d bytes, want 4 and 30", r, b)
+	}
+
+	// Once the disk recovers nothing more is dropped
+	log.limit = 1 << 20
+	for i := range 5 {
+		if err := dw.WriteLine(fmt.Sprintf("CALL %d", 5+i)); err != nil {
+			t.Fatalf("WriteLine() error = %v after recovery", err)
+		}
+	}
+	if err := dw.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v after recovery", err)
+	}
+	if r, b := dw.DroppedLogRecords(), dw.DroppedLogBytes(); r != 4 || b != 30 {
+		t.Errorf("after recovery dropped %d records, %d bytes, want unchanged 4 and 30", r, b)
+	}
+	if got := log.buf.String(); got != "CALL 5\nCALL 6\nCALL 7\nCALL 8\nCALL 9\n" {
+		t.Errorf("log = %q, want the records written after recovery", got)
+	}
+}
+
+// stubConsumerInfoer returns canned consumer info keyed by "stream/name"
+type stubConsumerInfoer struct {
+	infos map[string]*nats.ConsumerInfo
+}
+
+func (s *stubConsumerInfoer) ConsumerInfo(stream, name string, opts ...nats.JSOpt) (*nats.ConsumerInfo, error) {
+	if info, ok := s.infos[stream+"/"+name]; ok {
+		return info, nil
+	}
+	return nil, nats.ErrConsumerNotFound
+}
 
-	testLine := "[1234567890][A1][2025-01-01 00:00:00.000] Sample CDR data line for benchmarking"
+func TestConsumerStats(t *testing.T) {
+	js := &stubConsumerInfoer{infos: map[string]*nats.ConsumerInfo{
+		"cdr/test-01-forwarder": {NumPending: 1500, NumAckPending: 1, NumRedelivered: 3},
+		"events/archiver":       {NumPending: 0, NumAckPending: 0},
+	}}
+
+	stats := consumerStats(js, []ConsumerRef{
+		{Stream: "cdr", Name: "test-01-forwarder"},
+		{Stream: "events", Name: "archiver"},
+		{Stream: "cdr", Name: "missing"},
+	})
+
+	if len(stats) != 3 {
+		t.Fatalf("got %d consumer stats, want 3", len(stats))
+	}
+
+	fwd := stats[0]
+	if fwd.Stream != "cdr" || fwd.Name != "test-01-forwarder" {
+		t.Errorf("stats[0] = %s/%s, want cdr/test-01-forwarder", fwd.Stream, fwd.Name)
+	}
+	if fwd.NumPending != 1500 || fwd.NumAckPending != 1 || fwd.NumRedelivered != 3 {
+		t.Errorf("forwarder lag = %+v, want pending 1500, ack pending 1, redelivered 3", fwd)
+	}
+	if fwd.Error != "" {
+		t.Errorf("forwarder Error = %q, want empty", fwd.Error)
+	}
+
+	if stats[1].NumPending != 0 || stats[1].Error != "" {
+		t.Errorf("archiver = %+v, want caught up", stats[1])
+	}
+
+	if stats[2].Error == "" {
+		t.Error("missing consumer should report an error")
+	}
+}
+
+func TestNATSConnectionConsumerStatsDisconnected(t *testing.T) {
+	nc := &NATSConnection{
+		url:    "nats://localhost:4222",
+		logger: slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	}
+	if stats := nc.ConsumerStats([]ConsumerRef{{Stream: "cdr", Name: "x"}}); stats != nil {
+		t.Errorf("ConsumerStats() = %v, want nil when disconnected", stats)
+	}
+}
+
+type stubRTT struct {
+	rtt time.Duration
+	err error
+}
+
+func (s *stubRTT) RTT() (time.Duration, error) {
+	return s.rtt, s.err
+}
+
+func TestNATSConnectionRTT(t *testing.T) {
+	nc := &NATSConnection{
+		url:    "nats://localhost:4222",
+		logger: slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	}
+
+	if stats := nc.Stats(); stats.LastRTTAt != nil || stats.LastRTTMs != 0 {
+		t.Errorf("before measurement: LastRTTMs = %v, LastRTTAt = %v, want unset", stats.LastRTTMs, stats.LastRTTAt)
+	}
+
+	before := time.Now()
+	nc.recordRTT(&stubRTT{rtt: 2500 * time.Microsecond})
+
+	stats := nc.Stats()
+	if stats.LastRTTMs != 2.5 {
+		t.Errorf("LastRTTMs = %v, want 2.5", stats.LastRTTMs)
+	}
+	if stats.LastRTTAt == nil || stats.LastRTTAt.Before(before) || time.Since(*stats.LastRTTAt) > time.Second {
+		t.Errorf("LastRTTAt = %v, want recent", stats.LastRTTAt)
+	}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		dw.WriteLine(testLine)
+	// A failed ping keeps the last good measurement
+	nc.recordRTT(&stubRTT{err: nats.ErrTimeout})
+	if got := nc.Stats().LastRTTMs; got != 2.5 {
+		t.Errorf("LastRTTMs after failed ping = %v, want 2.5", got)
 	}
 }
@@ -17,7 +17,13 @@ const (
 	EventSignalLost      = "signal_lost"
 	EventSignalDetected  = "signal_detected"
 	EventReconnect       = "reconnect"
+	EventRecovered       = "recovered" // Channel stable again after a run of reconnects
 	EventBaudDetected    = "baud_detected"
+	EventDeviceRemoved   = "device_removed" // Device node disappeared (USB adapter unplugged)
+	EventDeviceAdded     = "device_added"   // Device node reappeared, possibly under a new name
+	EventConfigChange    = "config_change"  // Port added/updated/deleted/enabled/disabled via API
+	EventRateLimited     = "rate_limited"   // Channel exceeded max_lines_per_sec and is dropping lines
+	EventStalled         = "stalled"        // Channel has had no lines for stall_after_sec
 	EventError           = "error"
 )
 
@@ -111,11 +117,17 @@ func (e *EventPublisher) PublishServiceStart(version string) {
 
 // PublishServiceStop publishes a service stop event
 func (e *EventPublisher) PublishServiceStop(reason string) {
-	e.Publish(Event{
+	e.Publish(ServiceStopEvent(reason))
+}
+
+// ServiceStopEvent builds the service_stop event; reason says what triggered
+// the shutdown (e.g. "SIGTERM")
+func ServiceStopEvent(reason string) Event {
+	return Event{
 		Type:    EventServiceStop,
 		Message: "NectarCollector service stopping",
 		Details: map[string]any{"reason": reason},
-	})
+	}
 }
 
 // PublishStateChange publishes a channel state change event
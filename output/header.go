@@ -2,19 +2,129 @@ package output
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// headerTimeLayout is the timestamp layout inside the third header bracket
+const headerTimeLayout = "2006-01-02 15:04:05.000"
+
+// headerParseLayout parses a header timestamp of any precision; Go accepts
+// a fractional second after the seconds field when parsing
+const headerParseLayout = "2006-01-02 15:04:05"
+
+// Header timestamp precisions
+const (
+	PrecisionMillis = "ms" // Default: 2025-12-03 15:04:05.123
+	PrecisionMicros = "us" // 2025-12-03 15:04:05.123456
+	PrecisionNanos  = "ns" // 2025-12-03 15:04:05.123456789
+)
+
+// precisionLayouts maps each precision to its timestamp layout
+var precisionLayouts = map[string]string{
+	PrecisionMillis: headerTimeLayout,
+	PrecisionMicros: "2006-01-02 15:04:05.000000",
+	PrecisionNanos:  "2006-01-02 15:04:05.000000000",
+}
+
 // BuildHeader constructs a header in the format: [FIPSCODE][A1-16][YYYY-MM-DD HH:MM:SS.mmm]
 func BuildHeader(fipsCode, aDesignation string, timestamp time.Time) string {
 	// Format: [1429010002][A5][2025-12-03 15:04:05.123]
 	return fmt.Sprintf("[%s][%s][%s] ",
 		fipsCode,
 		aDesignation,
-		timestamp.Format("2006-01-02 15:04:05.000"))
+		timestamp.Format(headerTimeLayout))
+}
+
+// ParseHeaderTime extracts the timestamp from a line that starts with a
+// BuildHeader header written in UTC, at any precision. Returns false if the
+// line has no parseable header.
+func ParseHeaderTime(line string) (time.Time, bool) {
+	return parseHeaderTime(line, time.UTC)
+}
+
+// parseHeaderTime extracts a header timestamp written in loc
+func parseHeaderTime(line string, loc *time.Location) (time.Time, bool) {
+	// Skip [FIPS][A] to reach [timestamp]
+	rest := line
+	for i := 0; i < 2; i++ {
+		end := strings.Index(rest, "]")
+		if !strings.HasPrefix(rest, "[") || end < 0 {
+			return time.Time{}, false
+		}
+		rest = rest[end+1:]
+	}
+
+	end := strings.Index(rest, "]")
+	if !strings.HasPrefix(rest, "[") || end < 0 {
+		return time.Time{}, false
+	}
+	ts, err := time.ParseInLocation(headerParseLayout, rest[1:end], loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
 }
 
 // FormatTimestamp formats a timestamp in the required format with milliseconds
 func FormatTimestamp(t time.Time) string {
-	return t.Format("2006-01-02 15:04:05.000")
+	return t.Format(headerTimeLayout)
+}
+
+// HeaderFormat controls how record header timestamps are rendered and parsed.
+// The zero value is UTC with milliseconds, as written by BuildHeader.
+type HeaderFormat struct {
+	Location  *time.Location // Zone header times are written in (nil = UTC)
+	Precision string         // PrecisionMillis (default), PrecisionMicros or PrecisionNanos
+}
+
+// NewHeaderFormat builds a HeaderFormat for an IANA zone name ("" = UTC) and
+// a precision ("" = milliseconds)
+func NewHeaderFormat(timeZone, precision string) (HeaderFormat, error) {
+	if _, ok := precisionLayouts[precision]; !ok && precision != "" {
+		return HeaderFormat{}, fmt.Errorf("invalid timestamp precision %q, must be %q, %q or %q",
+			precision, PrecisionMillis, PrecisionMicros, PrecisionNanos)
+	}
+	format := HeaderFormat{Precision: precision}
+	if timeZone == "" {
+		return format, nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return HeaderFormat{}, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+	}
+	format.Location = loc
+	return format, nil
+}
+
+// layout returns the timestamp layout for f's precision
+func (f HeaderFormat) layout() string {
+	if layout, ok := precisionLayouts[f.Precision]; ok {
+		return layout
+	}
+	return headerTimeLayout
+}
+
+// location returns the header zone, defaulting to UTC
+func (f HeaderFormat) location() *time.Location {
+	if f.Location == nil {
+		return time.UTC
+	}
+	return f.Location
+}
+
+// Build constructs a header like BuildHeader with the timestamp in f's zone
+// and precision
+func (f HeaderFormat) Build(fipsCode, aDesignation string, timestamp time.Time) string {
+	return fmt.Sprintf("[%s][%s][%s] ", fipsCode, aDesignation, f.FormatTimestamp(timestamp))
+}
+
+// FormatTimestamp formats t like FormatTimestamp, in f's zone and precision
+func (f HeaderFormat) FormatTimestamp(t time.Time) string {
+	return t.In(f.location()).Format(f.layout())
+}
+
+// ParseTime is ParseHeaderTime for headers written in f's zone
+func (f HeaderFormat) ParseTime(line string) (time.Time, bool) {
+	return parseHeaderTime(line, f.location())
 }
@@ -135,3 +135,109 @@ func BenchmarkBuildHeader(b *testing.B) {
 		BuildHeader("1429010002", "A5", ts)
 	}
 }
+
+func TestParseHeaderTime(t *testing.T) {
+	ts := time.Date(2025, 12, 3, 15, 4, 5, 123000000, time.UTC)
+
+	tests := []struct {
+		name   string
+		line   string
+		want   time.Time
+		wantOK bool
+	}{
+		{"round trip", BuildHeader("1429010002", "A5", ts) + "CALL 001", ts, true},
+		{"no header", "CALL 001", time.Time{}, false},
+		{"two brackets only", "[1429010002][A5] CALL", time.Time{}, false},
+		{"bad timestamp", "[1429010002][A5][yesterday] CALL", time.Time{}, false},
+		{"empty", "", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseHeaderTime(tt.line)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("ParseHeaderTime(%q) = %v, %v, want %v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHeaderFormatTimeZone(t *testing.T) {
+	instant := time.Date(2025, 12, 3, 15, 4, 5, 123000000, time.UTC)
+
+	utc, err := NewHeaderFormat("", "")
+	if err != nil {
+		t.Fatalf("NewHeaderFormat(\"\", \"\") error: %v", err)
+	}
+	chicago, err := NewHeaderFormat("America/Chicago", "")
+	if err != nil {
+		t.Fatalf("NewHeaderFormat(America/Chicago) error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format HeaderFormat
+		want   string
+	}{
+		{"default UTC", utc, "[1429010002][A5][2025-12-03 15:04:05.123] "},
+		{"named zone", chicago, "[1429010002][A5][2025-12-03 09:04:05.123] "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A non-UTC input renders the same as its UTC equivalent
+			got := tt.format.Build("1429010002", "A5", instant.In(time.FixedZone("X", 3600)))
+			if got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+			parsed, ok := tt.format.ParseTime(got + "CALL 001")
+			if !ok || !parsed.Equal(instant) {
+				t.Errorf("ParseTime() = %v, %v, want %v", parsed, ok, instant)
+			}
+		})
+	}
+
+	if _, err := NewHeaderFormat("Mars/Olympus_Mons", ""); err == nil {
+		t.Error("NewHeaderFormat() accepted an unknown zone")
+	}
+}
+
+func TestHeaderFormatPrecision(t *testing.T) {
+	ts := time.Date(2025, 12, 3, 15, 4, 5, 123456789, time.UTC)
+
+	tests := []struct {
+		precision string
+		want      string
+	}{
+		{"", "2025-12-03 15:04:05.123"},
+		{PrecisionMillis, "2025-12-03 15:04:05.123"},
+		{PrecisionMicros, "2025-12-03 15:04:05.123456"},
+		{PrecisionNanos, "2025-12-03 15:04:05.123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run("precision "+tt.precision, func(t *testing.T) {
+			format, err := NewHeaderFormat("", tt.precision)
+			if err != nil {
+				t.Fatalf("NewHeaderFormat() error: %v", err)
+			}
+			if got := format.FormatTimestamp(ts); got != tt.want {
+				t.Errorf("FormatTimestamp() = %q, want %q", got, tt.want)
+			}
+			header := format.Build("1429010002", "A5", ts)
+			if want := "[1429010002][A5][" + tt.want + "] "; header != want {
+				t.Errorf("Build() = %q, want %q", header, want)
+			}
+
+			// Parsing keeps every digit that was written
+			parsed, ok := ParseHeaderTime(header + "CALL 001")
+			if !ok || parsed.Format("2006-01-02 15:04:05.999999999") != tt.want {
+				t.Errorf("ParseHeaderTime() = %v, %v, want %s", parsed, ok, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewHeaderFormat("", "s"); err == nil {
+		t.Error("NewHeaderFormat() accepted an unknown precision")
+	}
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"encoding/json"
 	"log/slog"
+	"os"
 	"sync"
 	"time"
 )
@@ -14,6 +15,8 @@ type HealthPublisher struct {
 	subject    string
 	instanceID string
 	fipsCode   string
+	appVersion string
+	hostname   string
 	startTime  time.Time
 	interval   time.Duration
 	logger     *slog.Logger
@@ -36,11 +39,17 @@ type ChannelHealth struct {
 	Device          string `json:"device"`
 	SideDesignation string `json:"a"`
 	State           string `json:"state"`
-	BaudRate        int    `json:"baud"`       // Current detected baud rate
-	Reconnects      int64  `json:"reconnects"` // Number of reconnection attempts
+	StateCode       int    `json:"state_code"`            // Numeric state, stable across versions
+	BaudRate        int    `json:"baud"`                  // Current detected baud rate
+	BaudSource      string `json:"baud_source,omitempty"` // "configured" or "detected"
+	Reconnects      int64  `json:"reconnects"`            // Number of reconnection attempts
 	BytesRead       int64  `json:"bytes"`
 	LinesRead       int64  `json:"lines"`
 	Errors          int64  `json:"errors"`
+	ParityErrors    int64  `json:"parity_errors,omitempty"` // UART error counts this session (serial only)
+	FramingErrors   int64  `json:"framing_errors,omitempty"`
+	OverrunErrors   int64  `json:"overrun_errors,omitempty"`
+	Breaks          int64  `json:"breaks,omitempty"`
 	LastLineAgo     int64  `json:"last_line_ago_sec"` // Seconds since last line, -1 if never
 }
 
@@ -50,6 +59,8 @@ type HealthMessage struct {
 	Timestamp     string          `json:"ts"`
 	InstanceID    string          `json:"instance_id"`
 	FIPSCode      string          `json:"fips_code"`
+	AppVersion    string          `json:"app_version,omitempty"` // Build running on the collector, to spot stragglers
+	Hostname      string          `json:"hostname,omitempty"`
 	UptimeSec     int64           `json:"uptime_sec"`
 	NATSConnected bool            `json:"nats_connected"`
 	Channels      []ChannelHealth `json:"channels"`
@@ -61,6 +72,7 @@ type HealthPublisherConfig struct {
 	Subject    string        // e.g., "ne.health.psna-ne-kearney-01"
 	InstanceID string        // e.g., "psna-ne-kearney-01"
 	FIPSCode   string        // e.g., "1314010001"
+	AppVersion string        // e.g., "1.4.2"
 	Interval   time.Duration // How often to publish (default 60s)
 	Logger     *slog.Logger
 	StatsFunc  func() HealthStats // Callback to get current stats
@@ -73,11 +85,16 @@ func NewHealthPublisher(cfg *HealthPublisherConfig) *HealthPublisher {
 		interval = 60 * time.Second
 	}
 
+	// The hostname doesn't change under a running process, so look it up once
+	hostname, _ := os.Hostname()
+
 	return &HealthPublisher{
 		conn:       cfg.Conn,
 		subject:    cfg.Subject,
 		instanceID: cfg.InstanceID,
 		fipsCode:   cfg.FIPSCode,
+		appVersion: cfg.AppVersion,
+		hostname:   hostname,
 		startTime:  time.Now(),
 		interval:   interval,
 		logger:     cfg.Logger,
@@ -129,17 +146,7 @@ func (h *HealthPublisher) publish() {
 		return
 	}
 
-	stats := h.statsFunc()
-
-	msg := HealthMessage{
-		Version:       1,
-		Timestamp:     time.Now().UTC().Format(time.RFC3339),
-		InstanceID:    h.instanceID,
-		FIPSCode:      h.fipsCode,
-		UptimeSec:     int64(time.Since(h.startTime).Seconds()),
-		NATSConnected: stats.NATSConnected,
-		Channels:      stats.Channels,
-	}
+	msg := h.message(h.statsFunc())
 
 	data, err := json.Marshal(msg)
 	if err != nil {
@@ -158,6 +165,21 @@ func (h *HealthPublisher) publish() {
 		"channels", len(msg.Channels))
 }
 
+// message builds the heartbeat payload from the current stats
+func (h *HealthPublisher) message(stats HealthStats) HealthMessage {
+	return HealthMessage{
+		Version:       1,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		InstanceID:    h.instanceID,
+		FIPSCode:      h.fipsCode,
+		AppVersion:    h.appVersion,
+		Hostname:      h.hostname,
+		UptimeSec:     int64(time.Since(h.startTime).Seconds()),
+		NATSConnected: stats.NATSConnected,
+		Channels:      stats.Channels,
+	}
+}
+
 // BuildHealthSubject constructs the health subject from state prefix and hostname
 // Format: {state}.health.{hostname}
 func BuildHealthSubject(subjectPrefix, instanceID string) string {
@@ -2,6 +2,7 @@ package output
 
 import (
 	"encoding/json"
+	"os"
 	"testing"
 	"time"
 )
@@ -61,6 +62,8 @@ func TestHealthMessageSize(t *testing.T) {
 		Timestamp:     "2025-12-05T18:30:00Z",
 		InstanceID:    "psna-ne-southcentralpan-kearney-01",
 		FIPSCode:      "1314010001",
+		AppVersion:    "1.10.12-rc3",
+		Hostname:      "psna-ne-southcentralpan-kearney-01",
 		UptimeSec:     2592000, // 30 days
 		NATSConnected: true,
 		Channels: []ChannelHealth{
@@ -68,6 +71,7 @@ func TestHealthMessageSize(t *testing.T) {
 				Device:          "/dev/ttyS1",
 				SideDesignation: "A1",
 				State:           "running",
+				StateCode:       1,
 				BaudRate:        115200,
 				Reconnects:      99,
 				BytesRead:       999999999,
@@ -79,6 +83,7 @@ func TestHealthMessageSize(t *testing.T) {
 				Device:          "/dev/ttyS2",
 				SideDesignation: "A2",
 				State:           "running",
+				StateCode:       1,
 				BaudRate:        115200,
 				Reconnects:      99,
 				BytesRead:       999999999,
@@ -112,6 +117,34 @@ func TestHealthMessageSize(t *testing.T) {
 	}
 }
 
+func TestHealthMessageMetadata(t *testing.T) {
+	h := NewHealthPublisher(&HealthPublisherConfig{
+		InstanceID: "psna-ne-kearney-01",
+		FIPSCode:   "1314010001",
+		AppVersion: "1.4.2",
+	})
+
+	data, err := json.Marshal(h.message(HealthStats{NATSConnected: true}))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if raw["app_version"] != "1.4.2" {
+		t.Errorf("app_version = %v, want 1.4.2", raw["app_version"])
+	}
+	wantHost, _ := os.Hostname()
+	if raw["hostname"] != wantHost {
+		t.Errorf("hostname = %v, want %q", raw["hostname"], wantHost)
+	}
+	if raw["instance_id"] != "psna-ne-kearney-01" {
+		t.Errorf("instance_id = %v", raw["instance_id"])
+	}
+}
+
 func TestBuildHealthSubject(t *testing.T) {
 	tests := []struct {
 		prefix     string
@@ -0,0 +1,61 @@
+package output
+
+import (
+	"context"
+	"log/slog"
+)
+
+// MultiHandler is an slog.Handler that sends each record to every underlying
+// handler that accepts its level, e.g. a log file, stdout and syslog at once
+type MultiHandler []slog.Handler
+
+// NewMultiHandler fans out to handlers. A single handler is returned as-is.
+func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return MultiHandler(handlers)
+}
+
+// Enabled reports whether any underlying handler accepts level
+func (m MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes a copy of r to each handler that accepts its level. Every
+// handler is tried; the first error is returned.
+func (m MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range m {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WithAttrs returns a MultiHandler whose handlers all carry attrs
+func (m MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(MultiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+// WithGroup returns a MultiHandler whose handlers all open group name
+func (m MultiHandler) WithGroup(name string) slog.Handler {
+	out := make(MultiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
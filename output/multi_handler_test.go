@@ -0,0 +1,56 @@
+package output
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMultiHandlerFansOut(t *testing.T) {
+	var file, stdout, errorOnly bytes.Buffer
+	handler := NewMultiHandler(
+		slog.NewJSONHandler(&file, nil),
+		slog.NewTextHandler(&stdout, nil),
+		slog.NewTextHandler(&errorOnly, &slog.HandlerOptions{Level: slog.LevelError}),
+	)
+	logger := slog.New(handler).With("component", "test").WithGroup("req")
+
+	logger.Info("channel started", "device", "/dev/ttyS1")
+
+	for name, buf := range map[string]*bytes.Buffer{"json": &file, "text": &stdout} {
+		out := buf.String()
+		if !strings.Contains(out, "channel started") || !strings.Contains(out, "component") || !strings.Contains(out, "req") {
+			t.Errorf("%s sink = %q, want the record with attrs and group", name, out)
+		}
+	}
+	if errorOnly.Len() != 0 {
+		t.Errorf("error-level sink got an info record: %q", errorOnly.String())
+	}
+
+	logger.Error("write failed")
+	if !strings.Contains(errorOnly.String(), "write failed") {
+		t.Errorf("error-level sink = %q, want the error record", errorOnly.String())
+	}
+}
+
+func TestMultiHandlerEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewMultiHandler(
+		slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}),
+		slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}),
+	)
+	if handler.Enabled(t.Context(), slog.LevelInfo) {
+		t.Error("Enabled(info) = true, no sink accepts info")
+	}
+	if !handler.Enabled(t.Context(), slog.LevelWarn) {
+		t.Error("Enabled(warn) = false, one sink accepts warn")
+	}
+}
+
+func TestNewMultiHandlerSingle(t *testing.T) {
+	h := slog.NewTextHandler(&bytes.Buffer{}, nil)
+	if got := NewMultiHandler(h); got != slog.Handler(h) {
+		t.Errorf("NewMultiHandler(h) = %T, want h unwrapped", got)
+	}
+}
@@ -0,0 +1,213 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// StreamSpec describes a JetStream stream the collector should ensure exists
+type StreamSpec struct {
+	Name      string
+	Subjects  []string
+	Retention string // "limits" (default), "interest", or "workqueue"
+	MaxAge    time.Duration
+	MaxBytes  int64 // 0 = unlimited
+}
+
+// streamManager is the part of nats.JetStreamContext needed to provision streams
+type streamManager interface {
+	StreamInfo(stream string, opts ...nats.JSOpt) (*nats.StreamInfo, error)
+	AddStream(cfg *nats.StreamConfig, opts ...nats.JSOpt) (*nats.StreamInfo, error)
+}
+
+// EnsureStreams creates missing streams. Existing streams are never modified -
+// lowering their limits or changing retention could discard stored CDR - so
+// any difference from spec is only logged for an operator to reconcile.
+func (nc *NATSConnection) EnsureStreams(specs []StreamSpec) error {
+	js, err := nc.JetStream()
+	if err != nil {
+		return err
+	}
+	return ensureStreams(js, specs, nc.logger)
+}
+
+// ensureStreams provisions each stream, continuing past failures so one bad
+// stream doesn't block the others
+func ensureStreams(js streamManager, specs []StreamSpec, logger *slog.Logger) error {
+	var errs []error
+	for _, spec := range specs {
+		if err := ensureStream(js, spec, logger); err != nil {
+			errs = append(errs, fmt.Errorf("stream %s: %w", spec.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func ensureStream(js streamManager, spec StreamSpec, logger *slog.Logger) error {
+	info, err := js.StreamInfo(spec.Name)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		if _, err := js.AddStream(buildStreamConfig(spec)); err != nil {
+			return fmt.Errorf("create failed: %w", err)
+		}
+		logger.Info("Created JetStream stream", "stream", spec.Name, "subjects", spec.Subjects)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if diff := streamDiff(info.Config, spec); len(diff) > 0 {
+		logger.Warn("JetStream stream differs from config, leaving it unchanged",
+			"stream", spec.Name, "differences", diff)
+		return nil
+	}
+	logger.Debug("JetStream stream up to date", "stream", spec.Name)
+	return nil
+}
+
+// buildStreamConfig returns the JetStream config for a new stream
+func buildStreamConfig(spec StreamSpec) *nats.StreamConfig {
+	return &nats.StreamConfig{
+		Name:      spec.Name,
+		Subjects:  spec.Subjects,
+		Retention: streamRetention(spec.Retention),
+		MaxAge:    spec.MaxAge,
+		MaxBytes:  streamMaxBytes(spec.MaxBytes),
+		Storage:   nats.FileStorage,
+		Discard:   nats.DiscardOld,
+		Replicas:  1,
+	}
+}
+
+// streamDiff describes each setting where an existing stream differs from
+// spec, e.g. "max_bytes: 1073741824 (want 5368709120)". Empty if they match.
+func streamDiff(cfg nats.StreamConfig, spec StreamSpec) []string {
+	var diff []string
+	if !slices.Equal(cfg.Subjects, spec.Subjects) {
+		diff = append(diff, fmt.Sprintf("subjects: %v (want %v)", cfg.Subjects, spec.Subjects))
+	}
+	if want := streamRetention(spec.Retention); cfg.Retention != want {
+		diff = append(diff, fmt.Sprintf("retention: %s (want %s)", cfg.Retention, want))
+	}
+	if cfg.MaxAge != spec.MaxAge {
+		diff = append(diff, fmt.Sprintf("max_age: %s (want %s)", cfg.MaxAge, spec.MaxAge))
+	}
+	if want := streamMaxBytes(spec.MaxBytes); cfg.MaxBytes != want {
+		diff = append(diff, fmt.Sprintf("max_bytes: %d (want %d)", cfg.MaxBytes, want))
+	}
+	return diff
+}
+
+func streamRetention(retention string) nats.RetentionPolicy {
+	switch retention {
+	case "interest":
+		return nats.InterestPolicy
+	case "workqueue":
+		return nats.WorkQueuePolicy
+	default:
+		return nats.LimitsPolicy
+	}
+}
+
+// streamMaxBytes maps 0 (unlimited) to -1, which is how the server reports it
+func streamMaxBytes(maxBytes int64) int64 {
+	if maxBytes <= 0 {
+		return -1
+	}
+	return maxBytes
+}
+
+// streamLookup is the part of nats.JetStreamContext needed to find which
+// stream captures a subject
+type streamLookup interface {
+	StreamNameBySubject(subject string, opts ...nats.JSOpt) (string, error)
+}
+
+// JetStreamBacked reports, for each subject, whether a JetStream stream
+// captures it. The server accepts publishes to an uncovered subject and
+// silently drops them, so this is the only sign of a misconfigured stream.
+// Subjects whose lookup failed for another reason are omitted; nil if not
+// connected. Answers are cached for JetStreamBackedTTL, or until the client
+// reconnects to a different server.
+func (nc *NATSConnection) JetStreamBacked(subjects []string) map[string]bool {
+	conn := nc.Conn()
+	if conn == nil || !conn.IsConnected() || len(subjects) == 0 {
+		return nil
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil
+	}
+
+	return nc.backed.lookup(js, conn.ConnectedUrl(), subjects, time.Now())
+}
+
+// JetStreamBackedTTL is how long a JetStreamBacked answer is reused. The
+// status endpoints ask on every poll, and stream subjects rarely change.
+const JetStreamBackedTTL = time.Minute
+
+type backedEntry struct {
+	backed bool
+	at     time.Time
+}
+
+// backedCache remembers StreamNameBySubject answers per subject. Entries
+// belong to the server they were looked up on and are dropped when the
+// connected server changes.
+type backedCache struct {
+	mu      sync.Mutex
+	server  string
+	entries map[string]backedEntry
+}
+
+// lookup answers from fresh entries and asks js only for the rest. Failed
+// lookups aren't cached, so they are retried on the next call.
+func (c *backedCache) lookup(js streamLookup, server string, subjects []string, now time.Time) map[string]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil || server != c.server {
+		c.entries = make(map[string]backedEntry)
+		c.server = server
+	}
+
+	backed := make(map[string]bool, len(subjects))
+	var stale []string
+	for _, subject := range subjects {
+		if e, ok := c.entries[subject]; ok && now.Sub(e.at) < JetStreamBackedTTL {
+			backed[subject] = e.backed
+			continue
+		}
+		stale = append(stale, subject)
+	}
+
+	for subject, b := range jetStreamBacked(js, stale) {
+		c.entries[subject] = backedEntry{backed: b, at: now}
+		backed[subject] = b
+	}
+	return backed
+}
+
+func jetStreamBacked(js streamLookup, subjects []string) map[string]bool {
+	backed := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		if _, ok := backed[subject]; ok {
+			continue
+		}
+		_, err := js.StreamNameBySubject(subject)
+		switch {
+		case err == nil:
+			backed[subject] = true
+		case errors.Is(err, nats.ErrNoMatchingStream):
+			backed[subject] = false
+		}
+	}
+	return backed
+}
@@ -0,0 +1,234 @@
+package output
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type stubStreamManager struct {
+	streams map[string]*nats.StreamInfo
+	added   []string
+	addErr  error
+}
+
+func (s *stubStreamManager) StreamInfo(stream string, opts ...nats.JSOpt) (*nats.StreamInfo, error) {
+	if info, ok := s.streams[stream]; ok {
+		return info, nil
+	}
+	return nil, nats.ErrStreamNotFound
+}
+
+func (s *stubStreamManager) AddStream(cfg *nats.StreamConfig, opts ...nats.JSOpt) (*nats.StreamInfo, error) {
+	if s.addErr != nil {
+		return nil, s.addErr
+	}
+	s.added = append(s.added, cfg.Name)
+	info := &nats.StreamInfo{Config: *cfg}
+	s.streams[cfg.Name] = info
+	return info, nil
+}
+
+func TestBuildStreamConfig(t *testing.T) {
+	cfg := buildStreamConfig(StreamSpec{
+		Name:      "health",
+		Subjects:  []string{"*.health.>"},
+		Retention: "workqueue",
+		MaxAge:    720 * time.Hour,
+		MaxBytes:  1 << 30,
+	})
+
+	if cfg.Name != "health" || len(cfg.Subjects) != 1 || cfg.Subjects[0] != "*.health.>" {
+		t.Errorf("name/subjects = %s %v", cfg.Name, cfg.Subjects)
+	}
+	if cfg.Retention != nats.WorkQueuePolicy {
+		t.Errorf("Retention = %v, want WorkQueuePolicy", cfg.Retention)
+	}
+	if cfg.MaxAge != 720*time.Hour || cfg.MaxBytes != 1<<30 {
+		t.Errorf("MaxAge = %v, MaxBytes = %d", cfg.MaxAge, cfg.MaxBytes)
+	}
+	if cfg.Storage != nats.FileStorage || cfg.Discard != nats.DiscardOld {
+		t.Errorf("Storage = %v, Discard = %v, want file/old", cfg.Storage, cfg.Discard)
+	}
+
+	// Defaults: limits retention, unlimited bytes
+	cfg = buildStreamConfig(StreamSpec{Name: "cdr", Subjects: []string{"*.cdr.>"}})
+	if cfg.Retention != nats.LimitsPolicy || cfg.MaxBytes != -1 {
+		t.Errorf("defaults: Retention = %v, MaxBytes = %d", cfg.Retention, cfg.MaxBytes)
+	}
+}
+
+func TestStreamDiff(t *testing.T) {
+	spec := StreamSpec{Name: "cdr", Subjects: []string{"*.cdr.>"}, MaxBytes: 5 << 30}
+	if diff := streamDiff(*buildStreamConfig(spec), spec); len(diff) != 0 {
+		t.Errorf("streamDiff() of a matching stream = %v, want none", diff)
+	}
+
+	existing := *buildStreamConfig(spec)
+	existing.MaxBytes = 1 << 30
+	existing.MaxAge = time.Hour
+	diff := streamDiff(existing, spec)
+	want := []string{"max_age: 1h0m0s (want 0s)", "max_bytes: 1073741824 (want 5368709120)"}
+	if !slices.Equal(diff, want) {
+		t.Errorf("streamDiff() = %q, want %q", diff, want)
+	}
+}
+
+func TestEnsureStreams(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	specs := []StreamSpec{
+		{Name: "cdr", Subjects: []string{"*.cdr.>"}, MaxBytes: 5 << 30},
+		{Name: "events", Subjects: []string{"*.events.>"}, MaxBytes: 500 << 20},
+	}
+
+	t.Run("creates missing streams", func(t *testing.T) {
+		js := &stubStreamManager{streams: map[string]*nats.StreamInfo{}}
+		if err := ensureStreams(js, specs, logger); err != nil {
+			t.Fatalf("ensureStreams() error = %v", err)
+		}
+		if len(js.added) != 2 {
+			t.Errorf("added = %v, want both added", js.added)
+		}
+
+		// Second run is a no-op
+		js.added = nil
+		if err := ensureStreams(js, specs, logger); err != nil {
+			t.Fatalf("second ensureStreams() error = %v", err)
+		}
+		if len(js.added) != 0 {
+			t.Errorf("second run added = %v, want no-op", js.added)
+		}
+	})
+
+	t.Run("existing matching streams untouched", func(t *testing.T) {
+		js := &stubStreamManager{streams: map[string]*nats.StreamInfo{
+			"cdr":    {Config: *buildStreamConfig(specs[0])},
+			"events": {Config: *buildStreamConfig(specs[1])},
+		}}
+		if err := ensureStreams(js, specs, logger); err != nil {
+			t.Fatalf("ensureStreams() error = %v", err)
+		}
+		if len(js.added) != 0 {
+			t.Errorf("added = %v, want no-op", js.added)
+		}
+	})
+
+	t.Run("drifted stream left unchanged", func(t *testing.T) {
+		existing := *buildStreamConfig(specs[0])
+		existing.MaxBytes = 10 << 30
+		existing.Retention = nats.InterestPolicy
+		js := &stubStreamManager{streams: map[string]*nats.StreamInfo{
+			"cdr":    {Config: existing},
+			"events": {Config: *buildStreamConfig(specs[1])},
+		}}
+		if err := ensureStreams(js, specs, logger); err != nil {
+			t.Fatalf("ensureStreams() error = %v", err)
+		}
+		if len(js.added) != 0 {
+			t.Errorf("added = %v, want none", js.added)
+		}
+		got := js.streams["cdr"].Config
+		if got.MaxBytes != 10<<30 || got.Retention != nats.InterestPolicy {
+			t.Errorf("MaxBytes = %d, Retention = %v, want the existing limits kept", got.MaxBytes, got.Retention)
+		}
+	})
+
+	t.Run("failure on one stream reported, others still created", func(t *testing.T) {
+		addErr := errors.New("insufficient resources")
+		js := &stubStreamManager{streams: map[string]*nats.StreamInfo{
+			"events": {Config: *buildStreamConfig(specs[1])},
+		}, addErr: addErr}
+		err := ensureStreams(js, specs, logger)
+		if !errors.Is(err, addErr) {
+			t.Errorf("ensureStreams() error = %v, want %v", err, addErr)
+		}
+	})
+}
+
+type stubStreamLookup struct {
+	bySubject map[string]string // subject -> stream
+	failing   map[string]bool   // subjects whose lookup times out
+	lookups   int
+}
+
+func (s *stubStreamLookup) StreamNameBySubject(subject string, opts ...nats.JSOpt) (string, error) {
+	s.lookups++
+	if s.failing[subject] {
+		return "", nats.ErrTimeout
+	}
+	if name, ok := s.bySubject[subject]; ok {
+		return name, nil
+	}
+	return "", nats.ErrNoMatchingStream
+}
+
+func TestJetStreamBacked(t *testing.T) {
+	js := &stubStreamLookup{
+		bySubject: map[string]string{"ne.cdr.1429010002": "cdr"},
+		failing:   map[string]bool{"ne.cdr.1429010003": true},
+	}
+
+	backed := jetStreamBacked(js, []string{
+		"ne.cdr.1429010002",
+		"nx.cdr.1429010002", // Typo'd prefix: no stream captures it
+		"ne.cdr.1429010003",
+		"ne.cdr.1429010002", // Shared subject is looked up once
+	})
+
+	if !backed["ne.cdr.1429010002"] {
+		t.Error("subject captured by the cdr stream: want backed")
+	}
+	if b, ok := backed["nx.cdr.1429010002"]; !ok || b {
+		t.Errorf("uncovered subject = %v (present %v), want false", b, ok)
+	}
+	if _, ok := backed["ne.cdr.1429010003"]; ok {
+		t.Error("failed lookup should be omitted, not reported unbacked")
+	}
+	if js.lookups != 3 {
+		t.Errorf("lookups = %d, want 3", js.lookups)
+	}
+}
+
+func TestBackedCache(t *testing.T) {
+	js := &stubStreamLookup{
+		bySubject: map[string]string{"ne.cdr.1429010002": "cdr"},
+		failing:   map[string]bool{"ne.cdr.1429010003": true},
+	}
+	subjects := []string{"ne.cdr.1429010002", "nx.cdr.1429010002", "ne.cdr.1429010003"}
+	start := time.Now()
+
+	var c backedCache
+	c.lookup(js, "nats://a:4222", subjects, start)
+	if js.lookups != 3 {
+		t.Fatalf("first poll lookups = %d, want 3", js.lookups)
+	}
+
+	// Within the TTL only the failed subject is asked again
+	backed := c.lookup(js, "nats://a:4222", subjects, start.Add(JetStreamBackedTTL/2))
+	if js.lookups != 4 {
+		t.Errorf("cached poll lookups = %d, want 4", js.lookups)
+	}
+	if !backed["ne.cdr.1429010002"] {
+		t.Error("cached backed subject: want true")
+	}
+	if b, ok := backed["nx.cdr.1429010002"]; !ok || b {
+		t.Errorf("cached uncovered subject = %v (present %v), want false", b, ok)
+	}
+
+	// Expired entries are looked up again
+	c.lookup(js, "nats://a:4222", subjects, start.Add(JetStreamBackedTTL))
+	if js.lookups != 7 {
+		t.Errorf("expired poll lookups = %d, want 7", js.lookups)
+	}
+
+	// A reconnect to another server drops the cache
+	c.lookup(js, "nats://b:4222", subjects, start.Add(JetStreamBackedTTL))
+	if js.lookups != 10 {
+		t.Errorf("lookups after server change = %d, want 10", js.lookups)
+	}
+}
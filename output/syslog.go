@@ -0,0 +1,287 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// syslogFacilities maps RFC 5424 facility names to their codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3,
+	"auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogTimeFormat is RFC 3339 with the microsecond precision RFC 5424 allows
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+const (
+	syslogQueueSize    = 1024             // Messages buffered for the sender before new ones are dropped
+	syslogDialTimeout  = 5 * time.Second  // Per connection attempt
+	syslogWriteTimeout = 5 * time.Second  // Per message, so a stalled TCP server can't wedge the sender
+	syslogMinRedial    = time.Second      // First wait after a failed connection attempt
+	syslogMaxRedial    = 30 * time.Second // Cap on the doubling wait between attempts
+)
+
+var (
+	errSyslogClosed    = errors.New("syslog handler closed")
+	errSyslogQueueFull = errors.New("syslog queue full, record dropped")
+)
+
+// SyslogHandlerConfig contains configuration for SyslogHandler
+type SyslogHandlerConfig struct {
+	Network  string // "udp" or "tcp"
+	Address  string // host:port
+	Facility string // RFC 5424 facility name, e.g., "local0"
+	AppName  string // APP-NAME field, e.g., "nectarcollector"
+	Level    slog.Leveler
+}
+
+// SyslogHandler is an slog.Handler that sends each record to a syslog server
+// as an RFC 5424 message. The message text is the record in slog's text
+// format (msg="..." key=value ...). TCP uses octet-counting framing (RFC 6587).
+// Records are queued and sent by a background goroutine so logging never
+// blocks on the network; they are dropped while the queue is full or the
+// server is unreachable.
+type SyslogHandler struct {
+	out  *syslogConn
+	ops  []func(slog.Handler) slog.Handler // WithAttrs/WithGroup calls, replayed per record
+	opts slog.HandlerOptions
+}
+
+// syslogConn is the connection shared by a SyslogHandler and its derivatives
+type syslogConn struct {
+	network  string
+	address  string
+	facility int
+	appName  string
+	hostname string
+	pid      string
+
+	queue     chan []byte
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// Owned by the sender goroutine
+	conn    net.Conn
+	backoff time.Duration // Wait before the next connection attempt (0 = connect now)
+	retryAt time.Time
+}
+
+// NewSyslogHandler connects to the syslog server and returns a handler.
+// Returns an error if the facility is unknown or the server is unreachable,
+// so callers can fall back to local logging.
+func NewSyslogHandler(cfg SyslogHandlerConfig) (*SyslogHandler, error) {
+	facility, ok := syslogFacilities[cfg.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility: %q", cfg.Facility)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	appName := cfg.AppName
+	if appName == "" {
+		appName = "-"
+	}
+
+	out := &syslogConn{
+		network:  cfg.Network,
+		address:  cfg.Address,
+		facility: facility,
+		appName:  appName,
+		hostname: hostname,
+		pid:      strconv.Itoa(os.Getpid()),
+		queue:    make(chan []byte, syslogQueueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := out.dial(); err != nil {
+		return nil, err
+	}
+	go out.run()
+
+	return &SyslogHandler{
+		out: out,
+		opts: slog.HandlerOptions{
+			Level: cfg.Level,
+			// Time and level are carried in the syslog header
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+					return slog.Attr{}
+				}
+				return a
+			},
+		},
+	}, nil
+}
+
+// Enabled reports whether the handler handles records at the given level
+func (h *SyslogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle formats the record as an RFC 5424 message and queues it for sending
+func (h *SyslogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	var text slog.Handler = slog.NewTextHandler(&buf, &h.opts)
+	for _, op := range h.ops {
+		text = op(text)
+	}
+	if err := text.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	return h.out.enqueue(syslogSeverity(r.Level), r.Time, bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *SyslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+// WithGroup returns a handler that nests subsequent attrs under name
+func (h *SyslogHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *SyslogHandler) with(op func(slog.Handler) slog.Handler) *SyslogHandler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &SyslogHandler{out: h.out, ops: append(ops, op), opts: h.opts}
+}
+
+// Close sends whatever is still queued, then closes the connection to the
+// syslog server. Records logged afterwards are dropped.
+func (h *SyslogHandler) Close() error {
+	h.out.closeOnce.Do(func() { close(h.out.stop) })
+	<-h.out.done
+	return nil
+}
+
+// syslogSeverity maps slog levels to RFC 5424 severities
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+func (s *syslogConn) dial() error {
+	conn, err := net.DialTimeout(s.network, s.address, syslogDialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog server %s: %w", s.address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// format builds an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *syslogConn) format(severity int, t time.Time, msg []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s - - ",
+		s.facility*8+severity,
+		t.Format(syslogTimeFormat),
+		s.hostname,
+		s.appName,
+		s.pid)
+	buf.Write(msg)
+	return buf.Bytes()
+}
+
+// enqueue frames one message for the sender goroutine, dropping it if the
+// queue is full or the handler has been closed
+func (s *syslogConn) enqueue(severity int, t time.Time, msg []byte) error {
+	frame := s.format(severity, t, msg)
+	if s.network == "tcp" {
+		frame = append([]byte(strconv.Itoa(len(frame))+" "), frame...)
+	}
+
+	select {
+	case <-s.stop:
+		return errSyslogClosed
+	default:
+	}
+	select {
+	case s.queue <- frame:
+		return nil
+	default:
+		return errSyslogQueueFull
+	}
+}
+
+// run sends queued messages until Close, then drains the queue
+func (s *syslogConn) run() {
+	defer close(s.done)
+	for {
+		select {
+		case frame := <-s.queue:
+			s.send(frame)
+		case <-s.stop:
+			for {
+				select {
+				case frame := <-s.queue:
+					s.send(frame)
+				default:
+					if s.conn != nil {
+						s.conn.Close()
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// send writes one message, redialing once if the connection has dropped
+// (e.g., the syslog server restarted while we were connected over TCP).
+// After a failed connection attempt, messages are dropped until the backoff
+// expires rather than each one waiting out another dial timeout.
+func (s *syslogConn) send(frame []byte) {
+	if s.conn != nil {
+		s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+		if _, err := s.conn.Write(frame); err == nil {
+			return
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	if time.Now().Before(s.retryAt) {
+		return
+	}
+	if err := s.dial(); err != nil {
+		s.backoff = min(max(s.backoff*2, syslogMinRedial), syslogMaxRedial)
+		s.retryAt = time.Now().Add(s.backoff)
+		return
+	}
+	s.backoff = 0
+	s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+	if _, err := s.conn.Write(frame); err != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
@@ -0,0 +1,200 @@
+package output
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogHandlerUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	h, err := NewSyslogHandler(SyslogHandlerConfig{
+		Network:  "udp",
+		Address:  pc.LocalAddr().String(),
+		Facility: "local0",
+		AppName:  "nectarcollector",
+		Level:    slog.LevelInfo,
+	})
+	if err != nil {
+		t.Fatalf("NewSyslogHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	logger := slog.New(h).With("device", "/dev/ttyS1")
+	logger.Debug("filtered out")
+	logger.Warn("Signal lost", "channel", "A1")
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 2048)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	msg := string(buf[:n])
+
+	// local0 (16) * 8 + warning (4) = 132
+	hostname, _ := os.Hostname()
+	pattern := `^<132>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(Z|[+-]\d{2}:\d{2}) ` +
+		regexp.QuoteMeta(hostname) + ` nectarcollector ` + strconv.Itoa(os.Getpid()) + ` - - ` +
+		`msg="Signal lost" device=/dev/ttyS1 channel=A1$`
+	if !regexp.MustCompile(pattern).MatchString(msg) {
+		t.Errorf("message = %q, want match for %q", msg, pattern)
+	}
+}
+
+func TestSyslogHandlerTCPFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		lenStr, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(lenStr))
+		frame := make([]byte, n)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return
+		}
+		received <- string(frame)
+	}()
+
+	h, err := NewSyslogHandler(SyslogHandlerConfig{
+		Network:  "tcp",
+		Address:  ln.Addr().String(),
+		Facility: "daemon",
+		Level:    slog.LevelDebug,
+	})
+	if err != nil {
+		t.Fatalf("NewSyslogHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	slog.New(h).WithGroup("nats").Error("Publish failed", "subject", "ne.cdr")
+
+	select {
+	case msg := <-received:
+		// daemon (3) * 8 + err (3) = 27
+		if !strings.HasPrefix(msg, "<27>1 ") {
+			t.Errorf("message = %q, want <27>1 prefix", msg)
+		}
+		if !strings.HasSuffix(msg, ` - - msg="Publish failed" nats.subject=ne.cdr`) {
+			t.Errorf("message = %q, want grouped attrs", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestSyslogHandlerCloseDrainsQueue(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	h, err := NewSyslogHandler(SyslogHandlerConfig{
+		Network:  "udp",
+		Address:  pc.LocalAddr().String(),
+		Facility: "local0",
+	})
+	if err != nil {
+		t.Fatalf("NewSyslogHandler() error = %v", err)
+	}
+
+	logger := slog.New(h)
+	for i := range 3 {
+		logger.Info("queued", "n", i)
+	}
+	h.Close()
+
+	// Everything queued before Close was sent
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 2048)
+	for i := range 3 {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("message %d: ReadFrom() error = %v", i, err)
+		}
+		if want := "n=" + strconv.Itoa(i); !strings.HasSuffix(string(buf[:n]), want) {
+			t.Errorf("message %d = %q, want suffix %q", i, buf[:n], want)
+		}
+	}
+
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0)); !errors.Is(err, errSyslogClosed) {
+		t.Errorf("Handle() after Close error = %v, want %v", err, errSyslogClosed)
+	}
+}
+
+func TestSyslogSendBacksOffAfterFailedDial(t *testing.T) {
+	// Grab a free port and close it so nothing is listening
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	s := &syslogConn{network: "tcp", address: addr}
+	s.send([]byte("first"))
+	if s.backoff != syslogMinRedial || s.retryAt.IsZero() {
+		t.Fatalf("after failed dial: backoff = %v, retryAt = %v, want %v and set", s.backoff, s.retryAt, syslogMinRedial)
+	}
+
+	// Within the backoff the message is dropped without another attempt
+	retryAt := s.retryAt
+	s.send([]byte("second"))
+	if s.retryAt != retryAt || s.backoff != syslogMinRedial {
+		t.Errorf("send within backoff redialed: backoff = %v", s.backoff)
+	}
+
+	// Each further failure doubles the wait, up to the cap
+	for range 10 {
+		s.retryAt = time.Time{}
+		s.send([]byte("again"))
+	}
+	if s.backoff != syslogMaxRedial {
+		t.Errorf("backoff = %v, want capped at %v", s.backoff, syslogMaxRedial)
+	}
+}
+
+func TestNewSyslogHandlerErrors(t *testing.T) {
+	// Grab a free port and close it so nothing is listening
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := NewSyslogHandler(SyslogHandlerConfig{Network: "tcp", Address: addr, Facility: "local0"}); err == nil {
+		t.Error("expected error for unreachable TCP server")
+	}
+	if _, err := NewSyslogHandler(SyslogHandlerConfig{Network: "udp", Address: addr, Facility: "bogus"}); err == nil {
+		t.Error("expected error for unknown facility")
+	}
+}
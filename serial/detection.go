@@ -1,9 +1,11 @@
 package serial
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -33,17 +35,56 @@ const (
 // DetectionResult contains the results of autobaud and pinout detection
 type DetectionResult struct {
 	BaudRate       int
+	DataBits       int    // Detected data bits (8 unless framing detection is enabled)
+	Parity         string // Detected parity ("none" unless framing detection is enabled)
 	UseFlowControl bool
 	ValidityRatio  float64
 	BytesRead      int
 }
 
+// Framing is a data bits/parity combination tried during framing detection
+type Framing struct {
+	DataBits int
+	Parity   string
+}
+
+// String returns the conventional short form (e.g., "8N1", "7E1")
+func (f Framing) String() string {
+	parity := "N"
+	if f.Parity != "" {
+		parity = strings.ToUpper(f.Parity[:1])
+	}
+	return fmt.Sprintf("%d%s1", f.DataBits, parity)
+}
+
+// DefaultFramings is the framing matrix swept when framing detection is enabled.
+// 8N1 comes first so it wins ties - 8N1 text read as 7E1 still looks like valid
+// ASCII, but 7E1 text read as 8N1 does not (the parity bit lands in bit 7).
+var DefaultFramings = []Framing{
+	{DataBits: 8, Parity: "none"},
+	{DataBits: 7, Parity: "even"},
+	{DataBits: 7, Parity: "odd"},
+}
+
+// ReaderOpener opens a Reader with the given configuration.
+// Detection uses this so tests can substitute a mock port.
+type ReaderOpener func(device string, config SerialConfig) (Reader, error)
+
+// openRealReader is the default ReaderOpener
+func openRealReader(device string, config SerialConfig) (Reader, error) {
+	return NewRealReaderWithConfig(device, config)
+}
+
 // Detector handles autobaud and pinout detection
 type Detector struct {
 	device           string
 	baudRates        []int
 	detectionTimeout time.Duration
 	minBytesForValid int
+	framings         []Framing // nil = baud-only detection (8N1)
+	assertDTR        *bool     // Passed to every open (nil = assert)
+	assertRTS        *bool
+	openReader       ReaderOpener
 	logger           *slog.Logger
 }
 
@@ -54,31 +95,72 @@ func NewDetector(device string, baudRates []int, detectionTimeout time.Duration,
 		baudRates:        baudRates,
 		detectionTimeout: detectionTimeout,
 		minBytesForValid: minBytesForValid,
+		openReader:       openRealReader,
 		logger:           logger,
 	}
 }
 
+// SetFramingDetection enables or disables the data bits/parity sweep.
+// When enabled, Detect tries every framing in DefaultFramings at each baud rate,
+// which multiplies detection time by the number of framings.
+func (d *Detector) SetFramingDetection(enabled bool) {
+	if enabled {
+		d.SetFramings(DefaultFramings)
+	} else {
+		d.SetFramings(nil)
+	}
+}
+
+// SetFramings sets the data bits/parity combinations Detect sweeps, e.g. a
+// subset of DefaultFramings consistent with a port's configured settings.
+// nil disables framing detection.
+func (d *Detector) SetFramings(framings []Framing) {
+	d.framings = framings
+}
+
+// SetModemOutputs sets whether detection's opens assert DTR and RTS (nil =
+// assert), for devices that reset when DTR toggles
+func (d *Detector) SetModemOutputs(assertDTR, assertRTS *bool) {
+	d.assertDTR = assertDTR
+	d.assertRTS = assertRTS
+}
+
+// serialConfig returns the 8N1 config detection opens the port with
+func (d *Detector) serialConfig(baudRate int, useFlowControl bool) SerialConfig {
+	config := DefaultSerialConfig(baudRate, useFlowControl)
+	config.AssertDTR = d.assertDTR
+	config.AssertRTS = d.assertRTS
+	return config
+}
+
 // DetectBaudRate attempts to detect the correct baud rate
-// Returns the detected baud rate or an error
-func (d *Detector) DetectBaudRate() (int, error) {
+// Returns the detected baud rate or an error. Cancelling ctx abandons the
+// sweep and returns ctx.Err().
+func (d *Detector) DetectBaudRate(ctx context.Context) (int, error) {
 	d.logger.Info("Starting autobaud detection", "device", d.device, "rates", d.baudRates)
 
 	// Open port once at first baud rate, then use SetMode for subsequent rates
 	// This is much faster than close/reopen cycles (avoids 100ms settling delays)
-	var reader *RealReader
+	var reader Reader
 	var err error
 
 	for i, baudRate := range d.baudRates {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		d.logger.Debug("Trying baud rate", "device", d.device, "baud", baudRate)
 
 		if i == 0 {
 			// First iteration: open the port
-			reader, err = NewRealReader(d.device, baudRate, false)
+			reader, err = d.openReader(d.device, d.serialConfig(baudRate, false))
 			if err != nil {
 				d.logger.Warn("Failed to open port", "device", d.device, "baud", baudRate, "error", err)
 				return 0, fmt.Errorf("failed to open port for detection: %w", err)
 			}
 			defer reader.Close()
+			// Aborting the port unblocks a Read waiting out its timeout
+			stop := context.AfterFunc(ctx, func() { abortReader(reader) })
+			defer stop()
 		} else {
 			// Subsequent iterations: just change baud rate (fast path)
 			if err := reader.SetBaudRate(baudRate); err != nil {
@@ -94,7 +176,10 @@ func (d *Detector) DetectBaudRate() (int, error) {
 			// Non-fatal - continue with detection
 		}
 
-		validityRatio, bytesRead := d.testBaudRate(reader)
+		validityRatio, bytesRead := d.testBaudRate(ctx, reader)
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 
 		d.logger.Debug("Baud rate test result",
 			"device", d.device,
@@ -116,6 +201,89 @@ func (d *Detector) DetectBaudRate() (int, error) {
 	return 0, fmt.Errorf("failed to detect baud rate for %s after trying all rates", d.device)
 }
 
+// DetectFraming sweeps baud rates and data bits/parity combinations, scoring
+// each by its valid ASCII ratio. Baud rates are tried in priority order; at the
+// first baud rate where any framing passes, the best-scoring framing is returned.
+// Cancelling ctx abandons the sweep and returns ctx.Err().
+func (d *Detector) DetectFraming(ctx context.Context) (*DetectionResult, error) {
+	framings := d.framings
+	if len(framings) == 0 {
+		framings = DefaultFramings[:1]
+	}
+
+	d.logger.Info("Starting autobaud and framing detection",
+		"device", d.device, "rates", d.baudRates, "framings", framings)
+
+	var best *DetectionResult
+
+	for _, baudRate := range d.baudRates {
+		for _, framing := range framings {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			config := d.serialConfig(baudRate, false)
+			config.DataBits = framing.DataBits
+			config.Parity = framing.Parity
+
+			reader, err := d.openReader(d.device, config)
+			if err != nil {
+				d.logger.Warn("Failed to open port", "device", d.device, "baud", baudRate,
+					"framing", framing.String(), "error", err)
+				return nil, fmt.Errorf("failed to open port for detection: %w", err)
+			}
+
+			if err := reader.ResetInputBuffer(); err != nil {
+				d.logger.Debug("Failed to reset input buffer", "device", d.device, "error", err)
+			}
+
+			stop := context.AfterFunc(ctx, func() { abortReader(reader) })
+			validityRatio, bytesRead := d.testBaudRate(ctx, reader)
+			stop()
+			reader.Close()
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			d.logger.Debug("Framing test result",
+				"device", d.device,
+				"baud", baudRate,
+				"framing", framing.String(),
+				"validity", fmt.Sprintf("%.2f", validityRatio),
+				"bytes", bytesRead)
+
+			if validityRatio >= ValidityThreshold && bytesRead >= d.minBytesForValid &&
+				(best == nil || validityRatio > best.ValidityRatio) {
+				best = &DetectionResult{
+					BaudRate:      baudRate,
+					DataBits:      framing.DataBits,
+					Parity:        framing.Parity,
+					ValidityRatio: validityRatio,
+					BytesRead:     bytesRead,
+				}
+			}
+
+			// Let the adapter settle before reopening with different framing
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(DetectionSettlingTime):
+			}
+		}
+
+		if best != nil {
+			d.logger.Info("Detected baud rate and framing",
+				"device", d.device,
+				"baud", best.BaudRate,
+				"framing", Framing{DataBits: best.DataBits, Parity: best.Parity}.String(),
+				"validity", fmt.Sprintf("%.2f", best.ValidityRatio),
+				"bytes", best.BytesRead)
+			return best, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to detect baud rate and framing for %s after trying all combinations", d.device)
+}
+
 // DetectPinout attempts to detect the correct pinout (flow control settings)
 // Returns true if flow control should be used
 func (d *Detector) DetectPinout(baudRate int) (bool, error) {
@@ -138,14 +306,15 @@ func (d *Detector) DetectPinout(baudRate int) (bool, error) {
 	return false, fmt.Errorf("failed to detect pinout for %s - no data received", d.device)
 }
 
-// testBaudRate tests a specific baud rate and returns validity ratio and bytes read
-func (d *Detector) testBaudRate(reader Reader) (float64, int) {
+// testBaudRate tests a specific baud rate and returns validity ratio and bytes read.
+// It stops early if ctx is cancelled; callers check ctx.Err() afterwards.
+func (d *Detector) testBaudRate(ctx context.Context, reader Reader) (float64, int) {
 	buf := make([]byte, DetectionBufferSize)
 	totalBytes := 0
 	validChars := 0
 	deadline := time.Now().Add(d.detectionTimeout)
 
-	for time.Now().Before(deadline) {
+	for time.Now().Before(deadline) && ctx.Err() == nil {
 		n, err := reader.Read(buf)
 		if err != nil && err != io.EOF {
 			// Read error, stop testing
@@ -176,7 +345,7 @@ func (d *Detector) testBaudRate(reader Reader) (float64, int) {
 
 // testFlowControl tests if data can be received with the given flow control setting
 func (d *Detector) testFlowControl(baudRate int, useFlowControl bool) bool {
-	reader, err := NewRealReader(d.device, baudRate, useFlowControl)
+	reader, err := NewRealReaderWithConfig(d.device, d.serialConfig(baudRate, useFlowControl))
 	if err != nil {
 		d.logger.Warn("Failed to open port for pinout test",
 			"device", d.device,
@@ -228,10 +397,27 @@ func countValidASCII(data []byte) int {
 	return count
 }
 
-// Detect runs full detection (baud rate only, no pinout)
-func (d *Detector) Detect() (*DetectionResult, error) {
+// abortReader interrupts a Read blocked on reader when detection is cancelled.
+// RealReader.Close waits for an in-flight Read, so its Abort is used instead;
+// the caller still closes the reader afterwards.
+func abortReader(reader Reader) {
+	if a, ok := reader.(interface{ Abort() error }); ok {
+		a.Abort()
+		return
+	}
+	reader.Close()
+}
+
+// Detect runs full detection (baud rate, plus framing if enabled; no pinout).
+// It returns ctx.Err() promptly if ctx is cancelled mid-sweep.
+func (d *Detector) Detect(ctx context.Context) (*DetectionResult, error) {
+	if len(d.framings) > 0 {
+		// Always use no flow control (null modem default)
+		return d.DetectFraming(ctx)
+	}
+
 	// Detect baud rate only
-	baudRate, err := d.DetectBaudRate()
+	baudRate, err := d.DetectBaudRate(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +425,8 @@ func (d *Detector) Detect() (*DetectionResult, error) {
 	// Always use no flow control (null modem default)
 	return &DetectionResult{
 		BaudRate:       baudRate,
+		DataBits:       8,
+		Parity:         "none",
 		UseFlowControl: false,
 	}, nil
 }
@@ -1,7 +1,15 @@
 package serial
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/bits"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestCountValidASCII(t *testing.T) {
@@ -110,6 +118,97 @@ func TestDetectorConstants(t *testing.T) {
 	}
 }
 
+// framingMockReader simulates a port wired to a 7E1 device at 9600 baud.
+// Reads only produce clean ASCII when opened with matching settings; read as
+// 8N1 the parity bit lands in bit 7, and any other baud rate yields noise.
+type framingMockReader struct {
+	*MockReader
+	config SerialConfig
+}
+
+func (m *framingMockReader) SetBaudRate(baudRate int) error {
+	m.config.BaudRate = baudRate
+	return nil
+}
+
+func (m *framingMockReader) Read(p []byte) (int, error) {
+	text := []byte("CALL_START,PSAP-01,5551234567,5559876543,ANSWERED,120\r\n")
+	out := make([]byte, len(text))
+	for i, c := range text {
+		switch {
+		case m.config.BaudRate != 9600:
+			out[i] = c ^ 0xA5 // Wrong baud - bit soup
+		case m.config.DataBits == 7 && m.config.Parity == "even":
+			out[i] = c
+		default:
+			// Wrong framing - the even parity bit shows up as bit 7
+			if bits.OnesCount8(c)%2 == 1 {
+				out[i] = c | 0x80
+			} else {
+				out[i] = c
+			}
+			if m.config.Parity == "odd" {
+				out[i] ^= 0x80
+			}
+		}
+	}
+	return copy(p, out), nil
+}
+
+func newFramingTestDetector() *Detector {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	d := NewDetector("/dev/ttyS1", []int{19200, 9600}, 50*time.Millisecond, 50, logger)
+	d.openReader = func(device string, config SerialConfig) (Reader, error) {
+		return &framingMockReader{MockReader: NewMockReader(device, nil), config: config}, nil
+	}
+	return d
+}
+
+func TestDetectFraming7E1(t *testing.T) {
+	d := newFramingTestDetector()
+	d.SetFramingDetection(true)
+
+	result, err := d.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if result.BaudRate != 9600 {
+		t.Errorf("BaudRate = %d, want 9600", result.BaudRate)
+	}
+	if result.DataBits != 7 || result.Parity != "even" {
+		t.Errorf("framing = %d/%s, want 7/even", result.DataBits, result.Parity)
+	}
+	if result.ValidityRatio < ValidityThreshold {
+		t.Errorf("ValidityRatio = %.2f, want >= %.2f", result.ValidityRatio, ValidityThreshold)
+	}
+}
+
+func TestDetectWithoutFramingMisses7E1(t *testing.T) {
+	d := newFramingTestDetector()
+
+	if _, err := d.Detect(context.Background()); err == nil {
+		t.Error("Detect() should fail on 7E1 data when framing detection is disabled")
+	}
+}
+
+func TestFramingString(t *testing.T) {
+	tests := []struct {
+		framing Framing
+		want    string
+	}{
+		{Framing{DataBits: 8, Parity: "none"}, "8N1"},
+		{Framing{DataBits: 7, Parity: "even"}, "7E1"},
+		{Framing{DataBits: 7, Parity: "odd"}, "7O1"},
+		{Framing{DataBits: 8}, "8N1"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.framing.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
 func BenchmarkCountValidASCII(b *testing.B) {
 	// Typical CDR line
 	data := []byte("[1234567890][A1][2025-01-01 12:00:00.000] CALL_START,PSAP-01,5551234567,5559876543,2025-01-01T12:00:00Z,ANSWERED,120")
@@ -132,3 +231,86 @@ func BenchmarkCountValidASCIILarge(b *testing.B) {
 		countValidASCII(data)
 	}
 }
+
+func TestDetectCancelled(t *testing.T) {
+	for _, framing := range []bool{false, true} {
+		t.Run(fmt.Sprintf("framing=%v", framing), func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			// Long per-rate timeout and a silent port: an uncancelled sweep would take minutes
+			d := NewDetector("/dev/ttyS1", []int{9600, 19200, 4800, 38400}, time.Minute, 50, logger)
+			d.openReader = func(device string, config SerialConfig) (Reader, error) {
+				return NewMockReader(device, nil), nil
+			}
+			d.SetFramingDetection(framing)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			_, err := d.Detect(ctx)
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Detect() error = %v, want context.Canceled", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Detect() took %v after cancel, want prompt return", elapsed)
+			}
+		})
+	}
+}
+
+// blockingDetectReader mimics RealReader: Read blocks until Abort, and Close
+// waits for an in-flight Read to return
+type blockingDetectReader struct {
+	*MockReader
+	reading   sync.Mutex
+	aborted   chan struct{}
+	abortOnce sync.Once
+}
+
+func (b *blockingDetectReader) Read(p []byte) (int, error) {
+	b.reading.Lock()
+	defer b.reading.Unlock()
+	<-b.aborted
+	return 0, errors.New("port closed")
+}
+
+func (b *blockingDetectReader) Close() error {
+	b.reading.Lock()
+	defer b.reading.Unlock()
+	return nil
+}
+
+func (b *blockingDetectReader) Abort() error {
+	b.abortOnce.Do(func() { close(b.aborted) })
+	return nil
+}
+
+func TestDetectCancelAbortsBlockedRead(t *testing.T) {
+	for _, framing := range []bool{false, true} {
+		t.Run(fmt.Sprintf("framing=%v", framing), func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			d := NewDetector("/dev/ttyS1", []int{9600}, time.Minute, 50, logger)
+			d.openReader = func(device string, config SerialConfig) (Reader, error) {
+				return &blockingDetectReader{MockReader: NewMockReader(device, nil), aborted: make(chan struct{})}, nil
+			}
+			d.SetFramingDetection(framing)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := d.Detect(ctx)
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("Detect() error = %v, want context.Canceled", err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Detect() still blocked in Read after cancel")
+			}
+		})
+	}
+}
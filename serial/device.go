@@ -0,0 +1,33 @@
+package serial
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ResolveDevicePath returns the device node to open for a port.
+// If byID is set (typically a /dev/serial/by-id/... symlink), it's resolved to
+// the node it currently points at, so a USB adapter that re-enumerates under a
+// new name after being unplugged (ttyUSB0 -> ttyUSB1) is still found.
+// Otherwise device is returned as-is if the node exists.
+func ResolveDevicePath(device, byID string) (string, error) {
+	if byID != "" {
+		resolved, err := filepath.EvalSymlinks(byID)
+		if err != nil {
+			return "", fmt.Errorf("device %s not present: %w", byID, err)
+		}
+		return resolved, nil
+	}
+
+	if !DevicePresent(device) {
+		return "", fmt.Errorf("device %s not present", device)
+	}
+	return device, nil
+}
+
+// DevicePresent returns true if the device node exists
+func DevicePresent(device string) bool {
+	_, err := os.Stat(device)
+	return err == nil
+}
@@ -0,0 +1,92 @@
+package serial
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveDevicePathPlain(t *testing.T) {
+	dir := t.TempDir()
+	device := filepath.Join(dir, "ttyS1")
+	if err := os.WriteFile(device, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ResolveDevicePath(device, "")
+	if err != nil {
+		t.Fatalf("ResolveDevicePath() error = %v", err)
+	}
+	if got != device {
+		t.Errorf("ResolveDevicePath() = %q, want %q", got, device)
+	}
+
+	if _, err := ResolveDevicePath(filepath.Join(dir, "ttyS2"), ""); err == nil {
+		t.Error("ResolveDevicePath() should fail for a missing device")
+	}
+}
+
+func TestResolveDevicePathByID(t *testing.T) {
+	dir := t.TempDir()
+	byIDDir := filepath.Join(dir, "serial", "by-id")
+	if err := os.MkdirAll(byIDDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	usb0 := filepath.Join(dir, "ttyUSB0")
+	usb1 := filepath.Join(dir, "ttyUSB1")
+	for _, p := range []string{usb0, usb1} {
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	byID := filepath.Join(byIDDir, "usb-FTDI_FT232R_USB_UART_A1B2C3-if00-port0")
+	if err := os.Symlink("../../ttyUSB0", byID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Configured device is ignored in favor of the by-id link
+	got, err := ResolveDevicePath(usb1, byID)
+	if err != nil {
+		t.Fatalf("ResolveDevicePath() error = %v", err)
+	}
+	if got != usb0 {
+		t.Errorf("ResolveDevicePath() = %q, want %q", got, usb0)
+	}
+
+	// Adapter unplugged: udev removes the link
+	if err := os.Remove(byID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResolveDevicePath(usb0, byID); err == nil {
+		t.Error("ResolveDevicePath() should fail when the by-id link is gone")
+	}
+
+	// Adapter replugged and re-enumerated under a new name
+	if err := os.Symlink("../../ttyUSB1", byID); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ResolveDevicePath(usb0, byID)
+	if err != nil {
+		t.Fatalf("ResolveDevicePath() error = %v", err)
+	}
+	if got != usb1 {
+		t.Errorf("ResolveDevicePath() after replug = %q, want %q", got, usb1)
+	}
+}
+
+func TestDevicePresent(t *testing.T) {
+	dir := t.TempDir()
+	device := filepath.Join(dir, "ttyACM0")
+
+	if DevicePresent(device) {
+		t.Error("DevicePresent() should be false before the node exists")
+	}
+	if err := os.WriteFile(device, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !DevicePresent(device) {
+		t.Error("DevicePresent() should be true once the node exists")
+	}
+}
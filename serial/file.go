@@ -0,0 +1,92 @@
+package serial
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileReader reads a captured log file for replay. It implements Reader so
+// replayed lines flow through the capture pipeline and stats like a live
+// port; Read returns io.EOF at the end of the file and baud and modem
+// operations are unsupported.
+type FileReader struct {
+	path   string
+	file   *os.File
+	isOpen bool
+	mu     sync.RWMutex
+}
+
+// NewFileReader opens path for reading
+func NewFileReader(path string) (*FileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file %s: %w", path, err)
+	}
+	return &FileReader{
+		path:   path,
+		file:   file,
+		isOpen: true,
+	}, nil
+}
+
+// Read implements io.Reader
+func (r *FileReader) Read(p []byte) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.isOpen {
+		return 0, fmt.Errorf("file not open")
+	}
+	return r.file.Read(p)
+}
+
+// Close implements io.Closer
+func (r *FileReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.isOpen {
+		return nil
+	}
+	r.isOpen = false
+	return r.file.Close()
+}
+
+// Device returns the replay file path
+func (r *FileReader) Device() string {
+	return r.path
+}
+
+// IsOpen returns true if the file is open
+func (r *FileReader) IsOpen() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.isOpen
+}
+
+// Reconfigure is unsupported - a file has no line parameters
+func (r *FileReader) Reconfigure(baudRate int, useFlowControl bool) error {
+	return ErrNotSerial
+}
+
+// SetBaudRate is unsupported - a file has no line parameters
+func (r *FileReader) SetBaudRate(baudRate int) error {
+	return ErrNotSerial
+}
+
+// SetReadTimeout is a no-op: file reads never wait for data
+func (r *FileReader) SetReadTimeout(timeout time.Duration) error {
+	return nil
+}
+
+// ResetInputBuffer is a no-op
+func (r *FileReader) ResetInputBuffer() error {
+	return nil
+}
+
+// GetModemStatus is unsupported - a file has no modem lines
+func (r *FileReader) GetModemStatus() (*ModemStatus, error) {
+	return nil, ErrNotSerial
+}
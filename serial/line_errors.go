@@ -0,0 +1,33 @@
+package serial
+
+import "errors"
+
+// LineErrors counts characters the UART flagged as bad. Parity and framing
+// errors point at mismatched settings (parity/data bits, baud/stop bits),
+// overruns at data arriving faster than it is read, and breaks at a line
+// held low - often a cable pulled mid-frame. A disconnected cable shows up
+// in modem signals instead.
+type LineErrors struct {
+	Parity  int64
+	Framing int64
+	Overrun int64
+	Break   int64
+}
+
+// Sub returns the counts accumulated since base
+func (e LineErrors) Sub(base LineErrors) LineErrors {
+	return LineErrors{
+		Parity:  e.Parity - base.Parity,
+		Framing: e.Framing - base.Framing,
+		Overrun: e.Overrun - base.Overrun,
+		Break:   e.Break - base.Break,
+	}
+}
+
+// errLineErrorsUnsupported is returned where the platform or driver doesn't
+// expose UART error counters
+var errLineErrorsUnsupported = errors.New("line error counters not supported")
+
+// readLineErrors reads a port's cumulative UART error counters; a variable
+// so tests can stand in for the ioctl
+var readLineErrors = portLineErrors
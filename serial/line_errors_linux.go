@@ -0,0 +1,57 @@
+package serial
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"go.bug.st/serial"
+	"golang.org/x/sys/unix"
+)
+
+// serialICounter mirrors the kernel's struct serial_icounter_struct
+type serialICounter struct {
+	CTS, DSR, RNG, DCD          int32
+	RX, TX                      int32
+	Frame, Overrun, Parity, Brk int32
+	BufOverrun                  int32
+	Reserved                    [9]int32
+}
+
+// portLineErrors reads the UART's error counters with TIOCGICOUNT. The
+// counters run from when the driver bound the port, not from our open.
+// Overrun includes tty buffer overruns as well as UART FIFO overruns.
+func portLineErrors(port serial.Port) (LineErrors, error) {
+	fd, ok := portFD(port)
+	if !ok {
+		return LineErrors{}, errLineErrorsUnsupported
+	}
+
+	var ic serialICounter
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.TIOCGICOUNT, uintptr(unsafe.Pointer(&ic)))
+	if errno != 0 {
+		return LineErrors{}, fmt.Errorf("TIOCGICOUNT: %w", errno)
+	}
+
+	return LineErrors{
+		Parity:  int64(ic.Parity),
+		Framing: int64(ic.Frame),
+		Overrun: int64(ic.Overrun) + int64(ic.BufOverrun),
+		Break:   int64(ic.Brk),
+	}, nil
+}
+
+// portFD returns the file descriptor behind a go.bug.st/serial port. The
+// library doesn't export it, and opening the device again to ask fails
+// because ports are opened exclusive (TIOCEXCL).
+func portFD(port serial.Port) (int, bool) {
+	v := reflect.ValueOf(port)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return 0, false
+	}
+	handle := v.Elem().FieldByName("handle")
+	if !handle.IsValid() || handle.Kind() != reflect.Int {
+		return 0, false
+	}
+	return int(handle.Int()), true
+}
@@ -0,0 +1,41 @@
+package serial
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"go.bug.st/serial"
+	"golang.org/x/sys/unix"
+)
+
+func TestPortFD(t *testing.T) {
+	if _, ok := portFD(&fakePort{}); ok {
+		t.Error("portFD() ok = true for a port without a handle")
+	}
+
+	// A pty stands in for a serial device, so a library upgrade that renames
+	// the unexported handle is caught here rather than as missing stats
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pty available: %v", err)
+	}
+	defer ptmx.Close()
+	if err := unix.IoctlSetPointerInt(int(ptmx.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		t.Skipf("unlock pty: %v", err)
+	}
+	n, err := unix.IoctlGetInt(int(ptmx.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Skipf("pty number: %v", err)
+	}
+
+	port, err := serial.Open("/dev/pts/"+strconv.Itoa(n), &serial.Mode{BaudRate: 9600})
+	if err != nil {
+		t.Skipf("open pty: %v", err)
+	}
+	defer port.Close()
+
+	if fd, ok := portFD(port); !ok || fd <= 0 {
+		t.Errorf("portFD() = %d, %v; want the open descriptor", fd, ok)
+	}
+}
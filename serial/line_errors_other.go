@@ -0,0 +1,10 @@
+//go:build !linux
+
+package serial
+
+import "go.bug.st/serial"
+
+// portLineErrors is Linux-only (TIOCGICOUNT)
+func portLineErrors(serial.Port) (LineErrors, error) {
+	return LineErrors{}, errLineErrorsUnsupported
+}
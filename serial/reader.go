@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.bug.st/serial"
@@ -42,6 +43,21 @@ type Reader interface {
 	GetModemStatus() (*ModemStatus, error)
 }
 
+// Flow control modes
+const (
+	FlowControlNone     = "none"     // No handshaking
+	FlowControlHardware = "hardware" // RTS/CTS handshaking
+	FlowControlSoftware = "software" // XON sent at open, XON/XOFF stripped from input
+)
+
+// Software flow control characters (ASCII DC1/DC3). The collector only
+// receives and never pauses the sender, so it doesn't send XOFF; the
+// characters are dropped from input rather than captured as data.
+const (
+	XON  byte = 0x11
+	XOFF byte = 0x13
+)
+
 // SerialConfig holds all serial port configuration parameters
 type SerialConfig struct {
 	BaudRate       int
@@ -49,6 +65,35 @@ type SerialConfig struct {
 	Parity         string  // "none", "odd", "even", "mark", "space"
 	StopBits       float64 // 1, 1.5, or 2
 	UseFlowControl bool
+	FlowControl    string // "none", "hardware", "software" (empty = derive from UseFlowControl)
+	// AssertDTR and AssertRTS control whether open raises DTR and RTS
+	// (nil = raise). Some devices reset when DTR toggles, dropping the first
+	// records after every reconnect.
+	AssertDTR *bool
+	AssertRTS *bool
+}
+
+// dtr reports whether DTR should be asserted on open
+func (c SerialConfig) dtr() bool {
+	return c.AssertDTR == nil || *c.AssertDTR
+}
+
+// rts reports whether RTS should be asserted on open
+func (c SerialConfig) rts() bool {
+	return c.AssertRTS == nil || *c.AssertRTS
+}
+
+// FlowControlMode returns the effective flow control mode.
+// An explicit FlowControl setting wins; otherwise UseFlowControl selects hardware.
+func (c SerialConfig) FlowControlMode() string {
+	switch c.FlowControl {
+	case FlowControlHardware, FlowControlSoftware, FlowControlNone:
+		return c.FlowControl
+	}
+	if c.UseFlowControl {
+		return FlowControlHardware
+	}
+	return FlowControlNone
 }
 
 // DefaultSerialConfig returns the standard 8N1 configuration
@@ -90,6 +135,42 @@ func stopBitsFromFloat(s float64) serial.StopBits {
 	}
 }
 
+// buildMode converts a SerialConfig into a go.bug.st/serial Mode.
+// InitialStatusBits reflects the flow control mode:
+//   - hardware: RTS and DTR asserted (we're ready to receive, we're online)
+//   - software: DTR asserted, RTS released (flow control is in-band, see FlowControlSoftware)
+//   - none: left nil so the driver default applies (DTR and RTS asserted)
+//
+// AssertDTR/AssertRTS set to false override these, so the line is held low
+// from open instead of being raised by the driver.
+func buildMode(config SerialConfig) *serial.Mode {
+	// Apply defaults for zero values
+	dataBits := config.DataBits
+	if dataBits == 0 {
+		dataBits = 8
+	}
+
+	mode := &serial.Mode{
+		BaudRate: config.BaudRate,
+		DataBits: dataBits,
+		Parity:   parityFromString(config.Parity),
+		StopBits: stopBitsFromFloat(config.StopBits),
+	}
+
+	switch config.FlowControlMode() {
+	case FlowControlHardware:
+		mode.InitialStatusBits = &serial.ModemOutputBits{RTS: config.rts(), DTR: config.dtr()}
+	case FlowControlSoftware:
+		mode.InitialStatusBits = &serial.ModemOutputBits{RTS: false, DTR: config.dtr()}
+	default:
+		if !config.dtr() || !config.rts() {
+			mode.InitialStatusBits = &serial.ModemOutputBits{RTS: config.rts(), DTR: config.dtr()}
+		}
+	}
+
+	return mode
+}
+
 // RealReader implements Reader using go.bug.st/serial
 type RealReader struct {
 	device string
@@ -97,6 +178,11 @@ type RealReader struct {
 	config SerialConfig
 	isOpen bool
 	mu     sync.RWMutex // RWMutex allows concurrent reads while blocking on close
+
+	// UART error counters at open, so LineErrors reports this session only
+	// (lineErrorsErr is set if the driver doesn't count them)
+	lineBase      LineErrors
+	lineErrorsErr error
 }
 
 // NewRealReader creates a new RealReader with basic 8N1 configuration
@@ -120,7 +206,7 @@ func NewRealReaderWithConfig(device string, config SerialConfig) (*RealReader, e
 }
 
 // formatPortError provides user-friendly error messages based on PortError codes
-func formatPortError(device string, err error) error {
+func formatPortError(device string, baudRate int, err error) error {
 	portErr, ok := err.(*serial.PortError)
 	if !ok {
 		return fmt.Errorf("failed to open %s: %w", device, err)
@@ -136,7 +222,7 @@ func formatPortError(device string, err error) error {
 	case serial.PermissionDenied:
 		return fmt.Errorf("permission denied for %s (try: sudo usermod -a -G dialout $USER)", device)
 	case serial.InvalidSpeed:
-		return fmt.Errorf("invalid baud rate for %s: %s", device, portErr.EncodedErrorString())
+		return fmt.Errorf("baud rate %d not supported by %s driver: %s", baudRate, device, portErr.EncodedErrorString())
 	case serial.InvalidDataBits:
 		return fmt.Errorf("invalid data bits for %s (must be 5, 6, 7, or 8)", device)
 	case serial.InvalidParity:
@@ -148,6 +234,24 @@ func formatPortError(device string, err error) error {
 	}
 }
 
+// openPort opens a serial device; a variable so tests can substitute a fake port
+var openPort = serial.Open
+
+// openHandles counts serial port handles currently open across all readers
+var openHandles atomic.Int64
+
+// OpenHandles returns how many serial port handles are open. A count that
+// keeps climbing while ports reconnect points to a leaked descriptor.
+func OpenHandles() int64 {
+	return openHandles.Load()
+}
+
+// closePort closes a handle from openPort and releases its count
+func closePort(port serial.Port) error {
+	openHandles.Add(-1)
+	return port.Close()
+}
+
 // open opens the serial port with current configuration
 func (r *RealReader) open() error {
 	r.mu.Lock()
@@ -157,55 +261,63 @@ func (r *RealReader) open() error {
 		return fmt.Errorf("port already open")
 	}
 
-	// Apply defaults for zero values
-	dataBits := r.config.DataBits
-	if dataBits == 0 {
-		dataBits = 8
-	}
-
-	mode := &serial.Mode{
-		BaudRate: r.config.BaudRate,
-		DataBits: dataBits,
-		Parity:   parityFromString(r.config.Parity),
-		StopBits: stopBitsFromFloat(r.config.StopBits),
-	}
-
-	port, err := serial.Open(r.device, mode)
+	port, err := openPort(r.device, buildMode(r.config))
 	if err != nil {
-		return formatPortError(r.device, err)
+		return formatPortError(r.device, r.config.BaudRate, err)
 	}
+	openHandles.Add(1)
 
 	// Set read timeout - use detection timeout initially, can be changed later
 	// for production reads via SetReadTimeout()
 	if err := port.SetReadTimeout(DetectionReadTimeout); err != nil {
-		port.Close()
+		closePort(port)
 		return fmt.Errorf("failed to set read timeout: %w", err)
 	}
 
 	// Configure modem control signals
 	// For receive-only capture, we assert RTS and DTR to signal we're ready
 	// This is critical for devices that use hardware flow control
-	if r.config.UseFlowControl {
+	switch r.config.FlowControlMode() {
+	case FlowControlHardware:
 		// Assert RTS (Request To Send) - tells sender we're ready to receive
-		if err := port.SetRTS(true); err != nil {
-			port.Close()
-			return fmt.Errorf("failed to set RTS: %w", err)
+		if r.config.rts() {
+			if err := port.SetRTS(true); err != nil {
+				closePort(port)
+				return fmt.Errorf("failed to set RTS: %w", err)
+			}
 		}
 		// Assert DTR (Data Terminal Ready) - tells DCE we're online
-		if err := port.SetDTR(true); err != nil {
-			port.Close()
-			return fmt.Errorf("failed to set DTR: %w", err)
+		if r.config.dtr() {
+			if err := port.SetDTR(true); err != nil {
+				closePort(port)
+				return fmt.Errorf("failed to set DTR: %w", err)
+			}
+		}
+	case FlowControlSoftware:
+		// Assert DTR like the no-flow-control case, then send XON so a sender
+		// that was left paused by a previous session resumes transmitting
+		if r.config.dtr() {
+			if err := port.SetDTR(true); err != nil {
+				// Non-fatal - some ports don't support DTR control
+			}
+		}
+		if _, err := port.Write([]byte{XON}); err != nil {
+			closePort(port)
+			return fmt.Errorf("failed to send XON: %w", err)
 		}
-	} else {
+	default:
 		// Even without flow control, some devices need DTR asserted to send data
 		// This mimics Scannex behavior - always ready to receive
-		if err := port.SetDTR(true); err != nil {
-			// Non-fatal - some ports don't support DTR control
+		if r.config.dtr() {
+			if err := port.SetDTR(true); err != nil {
+				// Non-fatal - some ports don't support DTR control
+			}
 		}
 	}
 
 	r.port = port
 	r.isOpen = true
+	r.lineBase, r.lineErrorsErr = readLineErrors(port)
 
 	return nil
 }
@@ -220,7 +332,24 @@ func (r *RealReader) Read(p []byte) (n int, err error) {
 		return 0, fmt.Errorf("port not open")
 	}
 
-	return r.port.Read(p)
+	n, err = r.port.Read(p)
+	if r.config.FlowControlMode() == FlowControlSoftware {
+		n = stripFlowControl(p[:n])
+	}
+	return n, err
+}
+
+// stripFlowControl removes XON/XOFF characters from buf in place and returns
+// the new length
+func stripFlowControl(buf []byte) int {
+	n := 0
+	for _, b := range buf {
+		if b != XON && b != XOFF {
+			buf[n] = b
+			n++
+		}
+	}
+	return n
 }
 
 // Close implements io.Closer
@@ -243,13 +372,27 @@ func (r *RealReader) Close() error {
 	// Clear input buffer to prevent stale data on reconnect
 	_ = r.port.ResetInputBuffer()
 
-	err := r.port.Close()
+	err := closePort(r.port)
 	r.port = nil
 	r.isOpen = false
 
 	return err
 }
 
+// Abort closes the underlying port without waiting for an in-flight Read,
+// which then returns an error. Close must still be called afterwards to
+// release the reader.
+func (r *RealReader) Abort() error {
+	r.mu.RLock()
+	port := r.port
+	r.mu.RUnlock()
+
+	if port == nil {
+		return nil
+	}
+	return port.Close()
+}
+
 // Device returns the device path
 func (r *RealReader) Device() string {
 	return r.device
@@ -314,6 +457,26 @@ func (r *RealReader) GetModemStatus() (*ModemStatus, error) {
 	}, nil
 }
 
+// LineErrors returns the parity, framing, overrun and break counts since the
+// port was opened
+func (r *RealReader) LineErrors() (LineErrors, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.isOpen || r.port == nil {
+		return LineErrors{}, fmt.Errorf("port not open")
+	}
+	if r.lineErrorsErr != nil {
+		return LineErrors{}, r.lineErrorsErr
+	}
+
+	counts, err := readLineErrors(r.port)
+	if err != nil {
+		return LineErrors{}, err
+	}
+	return counts.Sub(r.lineBase), nil
+}
+
 // SetBaudRate changes the baud rate without closing/reopening the port.
 // This uses SetMode() which is faster and avoids USB adapter settling delays.
 // Ideal for autobaud detection where we need to test multiple baud rates quickly.
@@ -325,20 +488,10 @@ func (r *RealReader) SetBaudRate(baudRate int) error {
 		return fmt.Errorf("port not open")
 	}
 
-	// Apply defaults for zero values
-	dataBits := r.config.DataBits
-	if dataBits == 0 {
-		dataBits = 8
-	}
-
-	mode := &serial.Mode{
-		BaudRate: baudRate,
-		DataBits: dataBits,
-		Parity:   parityFromString(r.config.Parity),
-		StopBits: stopBitsFromFloat(r.config.StopBits),
-	}
+	config := r.config
+	config.BaudRate = baudRate
 
-	if err := r.port.SetMode(mode); err != nil {
+	if err := r.port.SetMode(buildMode(config)); err != nil {
 		return fmt.Errorf("failed to set baud rate %d: %w", baudRate, err)
 	}
 
@@ -438,6 +591,16 @@ func (r *ReaderWithStats) Close() error {
 	return r.reader.Close()
 }
 
+// Abort interrupts a blocked Read. Readers whose Close would wait for the
+// Read (RealReader) provide Abort; for the rest Close is safe to call
+// concurrently.
+func (r *ReaderWithStats) Abort() error {
+	if a, ok := r.reader.(interface{ Abort() error }); ok {
+		return a.Abort()
+	}
+	return r.reader.Close()
+}
+
 // Device returns the device path
 func (r *ReaderWithStats) Device() string {
 	return r.reader.Device()
@@ -473,6 +636,18 @@ func (r *ReaderWithStats) GetModemStatus() (*ModemStatus, error) {
 	return r.reader.GetModemStatus()
 }
 
+// LineErrors returns the underlying reader's UART error counts. ok is false
+// for sources without a UART (TCP, file replay) or a driver that doesn't
+// count them.
+func (r *ReaderWithStats) LineErrors() (counts LineErrors, ok bool) {
+	lr, ok := r.reader.(interface{ LineErrors() (LineErrors, error) })
+	if !ok {
+		return LineErrors{}, false
+	}
+	counts, err := lr.LineErrors()
+	return counts, err == nil
+}
+
 // LineRead increments the line counter and resets stall detection
 func (r *ReaderWithStats) LineRead() {
 	r.mu.Lock()
@@ -1,10 +1,14 @@
 package serial
 
 import (
+	"fmt"
 	"io"
+	"slices"
 	"sync"
 	"testing"
 	"time"
+
+	"go.bug.st/serial"
 )
 
 // MockReader implements Reader for testing
@@ -248,6 +252,76 @@ func TestSerialConfigWithFlowControl(t *testing.T) {
 	}
 }
 
+func TestSerialConfigFlowControlMode(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SerialConfig
+		want string
+	}{
+		{"default", SerialConfig{}, FlowControlNone},
+		{"legacy bool", SerialConfig{UseFlowControl: true}, FlowControlHardware},
+		{"explicit none overrides bool", SerialConfig{UseFlowControl: true, FlowControl: "none"}, FlowControlNone},
+		{"explicit hardware", SerialConfig{FlowControl: "hardware"}, FlowControlHardware},
+		{"explicit software", SerialConfig{FlowControl: "software"}, FlowControlSoftware},
+		{"unknown falls back to bool", SerialConfig{FlowControl: "bogus"}, FlowControlNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.FlowControlMode(); got != tt.want {
+				t.Errorf("FlowControlMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildModeFlowControl(t *testing.T) {
+	tests := []struct {
+		flowControl string
+		wantBits    bool
+		wantRTS     bool
+		wantDTR     bool
+	}{
+		{FlowControlNone, false, false, false},
+		{FlowControlHardware, true, true, true},
+		{FlowControlSoftware, true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flowControl, func(t *testing.T) {
+			cfg := DefaultSerialConfig(9600, false)
+			cfg.FlowControl = tt.flowControl
+			mode := buildMode(cfg)
+
+			if mode.BaudRate != 9600 {
+				t.Errorf("BaudRate = %d, want 9600", mode.BaudRate)
+			}
+			if mode.DataBits != 8 {
+				t.Errorf("DataBits = %d, want 8", mode.DataBits)
+			}
+			if (mode.InitialStatusBits != nil) != tt.wantBits {
+				t.Fatalf("InitialStatusBits set = %v, want %v", mode.InitialStatusBits != nil, tt.wantBits)
+			}
+			if mode.InitialStatusBits == nil {
+				return
+			}
+			if mode.InitialStatusBits.RTS != tt.wantRTS {
+				t.Errorf("RTS = %v, want %v", mode.InitialStatusBits.RTS, tt.wantRTS)
+			}
+			if mode.InitialStatusBits.DTR != tt.wantDTR {
+				t.Errorf("DTR = %v, want %v", mode.InitialStatusBits.DTR, tt.wantDTR)
+			}
+		})
+	}
+}
+
+func TestBuildModeDataBitsDefault(t *testing.T) {
+	mode := buildMode(SerialConfig{BaudRate: 4800})
+	if mode.DataBits != 8 {
+		t.Errorf("DataBits = %d, want 8 when unset", mode.DataBits)
+	}
+}
+
 func TestModemStatus(t *testing.T) {
 	status := ModemStatus{
 		CTS: true,
@@ -356,3 +430,258 @@ func BenchmarkReaderWithStatsRead(b *testing.B) {
 		reader.Read(buf)
 	}
 }
+
+// fakePort implements serial.Port for exercising RealReader without hardware
+type fakePort struct {
+	readTimeoutErr error
+	closed         int
+	dtrSet         []bool // Values passed to SetDTR, in order
+	rtsSet         []bool
+	data           []byte // Returned by the next Read
+}
+
+func (p *fakePort) SetMode(*serial.Mode) error { return nil }
+func (p *fakePort) Read(b []byte) (int, error) {
+	n := copy(b, p.data)
+	p.data = p.data[n:]
+	return n, nil
+}
+func (p *fakePort) Write(b []byte) (int, error) { return len(b), nil }
+func (p *fakePort) Drain() error                { return nil }
+func (p *fakePort) ResetInputBuffer() error     { return nil }
+func (p *fakePort) ResetOutputBuffer() error    { return nil }
+func (p *fakePort) SetDTR(v bool) error         { p.dtrSet = append(p.dtrSet, v); return nil }
+func (p *fakePort) SetRTS(v bool) error         { p.rtsSet = append(p.rtsSet, v); return nil }
+func (p *fakePort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{}, nil
+}
+func (p *fakePort) SetReadTimeout(time.Duration) error { return p.readTimeoutErr }
+func (p *fakePort) Close() error                       { p.closed++; return nil }
+func (p *fakePort) Break(time.Duration) error          { return nil }
+
+func TestOpenHandlesBalanced(t *testing.T) {
+	var ports []*fakePort
+	var failSetup bool
+	origOpen := openPort
+	openPort = func(string, *serial.Mode) (serial.Port, error) {
+		p := &fakePort{}
+		if failSetup {
+			p.readTimeoutErr = fmt.Errorf("ioctl failed")
+		}
+		ports = append(ports, p)
+		return p, nil
+	}
+	defer func() { openPort = origOpen }()
+
+	base := OpenHandles()
+
+	// Reconnect churn: every open is paired with a close
+	for i := 0; i < 50; i++ {
+		r, err := NewRealReader("/dev/ttyFAKE", 9600, false)
+		if err != nil {
+			t.Fatalf("NewRealReader() error: %v", err)
+		}
+		if got := OpenHandles(); got != base+1 {
+			t.Fatalf("OpenHandles() while open = %d, want %d", got, base+1)
+		}
+		r.Close()
+		r.Close() // A second Close must not release the handle again
+	}
+	if got := OpenHandles(); got != base {
+		t.Errorf("OpenHandles() after open/close pairs = %d, want %d", got, base)
+	}
+
+	// A full reconfigure closes and reopens without drifting
+	r, err := NewRealReader("/dev/ttyFAKE", 9600, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconfigure(19200, true); err != nil {
+		t.Fatalf("Reconfigure() error: %v", err)
+	}
+	r.Close()
+	if got := OpenHandles(); got != base {
+		t.Errorf("OpenHandles() after reconfigure = %d, want %d", got, base)
+	}
+
+	// A setup failure after the device opened closes it and releases the count
+	failSetup = true
+	if _, err := NewRealReader("/dev/ttyFAKE", 9600, false); err == nil {
+		t.Fatal("NewRealReader() should fail when setup fails")
+	}
+	if got := OpenHandles(); got != base {
+		t.Errorf("OpenHandles() after failed setup = %d, want %d", got, base)
+	}
+	for i, p := range ports {
+		if p.closed != 1 {
+			t.Errorf("port %d closed %d times, want 1", i, p.closed)
+		}
+	}
+}
+
+func TestReadStripsSoftwareFlowControl(t *testing.T) {
+	tests := []struct {
+		flowControl string
+		want        string
+	}{
+		{FlowControlSoftware, "CALL 001\r\n"},
+		{FlowControlNone, "\x13CALL\x11 001\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flowControl, func(t *testing.T) {
+			port := &fakePort{data: []byte("\x13CALL\x11 001\r\n")}
+			origOpen := openPort
+			openPort = func(string, *serial.Mode) (serial.Port, error) { return port, nil }
+			defer func() { openPort = origOpen }()
+
+			r, err := NewRealReaderWithConfig("/dev/ttyFAKE", SerialConfig{BaudRate: 9600, FlowControl: tt.flowControl})
+			if err != nil {
+				t.Fatalf("NewRealReaderWithConfig() error: %v", err)
+			}
+			defer r.Close()
+
+			buf := make([]byte, 64)
+			n, err := r.Read(buf)
+			if err != nil {
+				t.Fatalf("Read() error: %v", err)
+			}
+			if got := string(buf[:n]); got != tt.want {
+				t.Errorf("Read() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealReaderLineErrors(t *testing.T) {
+	origOpen, origRead := openPort, readLineErrors
+	defer func() { openPort, readLineErrors = origOpen, origRead }()
+	openPort = func(string, *serial.Mode) (serial.Port, error) { return &fakePort{}, nil }
+
+	// The kernel counters run from boot: a session reports only what
+	// accumulated after it opened the port
+	counts := LineErrors{Parity: 7, Framing: 3, Overrun: 1, Break: 2}
+	readLineErrors = func(serial.Port) (LineErrors, error) { return counts, nil }
+
+	r, err := NewRealReaderWithConfig("/dev/ttyFAKE", SerialConfig{BaudRate: 9600})
+	if err != nil {
+		t.Fatalf("NewRealReaderWithConfig() error: %v", err)
+	}
+	defer r.Close()
+	stats := NewReaderWithStats(r)
+
+	counts.Parity += 4
+	counts.Framing += 2
+	counts.Overrun += 1
+	got, ok := stats.LineErrors()
+	if want := (LineErrors{Parity: 4, Framing: 2, Overrun: 1}); !ok || got != want {
+		t.Errorf("LineErrors() = %+v, %v; want %+v, true", got, ok, want)
+	}
+
+	// A failed ioctl reports nothing rather than zeros
+	readLineErrors = func(serial.Port) (LineErrors, error) { return LineErrors{}, errLineErrorsUnsupported }
+	if _, ok := stats.LineErrors(); ok {
+		t.Error("LineErrors() ok = true after the ioctl failed")
+	}
+}
+
+func TestLineErrorsUnavailable(t *testing.T) {
+	origOpen, origRead := openPort, readLineErrors
+	defer func() { openPort, readLineErrors = origOpen, origRead }()
+	openPort = func(string, *serial.Mode) (serial.Port, error) { return &fakePort{}, nil }
+	readLineErrors = func(serial.Port) (LineErrors, error) { return LineErrors{}, errLineErrorsUnsupported }
+
+	// Driver without counters: later reads aren't trusted either
+	r, err := NewRealReaderWithConfig("/dev/ttyFAKE", SerialConfig{BaudRate: 9600})
+	if err != nil {
+		t.Fatalf("NewRealReaderWithConfig() error: %v", err)
+	}
+	defer r.Close()
+	readLineErrors = func(serial.Port) (LineErrors, error) { return LineErrors{Parity: 5}, nil }
+	if _, ok := NewReaderWithStats(r).LineErrors(); ok {
+		t.Error("LineErrors() ok = true for a port without counters at open")
+	}
+
+	// Sources without a UART have no line errors
+	if _, ok := NewReaderWithStats(NewMockReader("/dev/ttyS1", nil)).LineErrors(); ok {
+		t.Error("LineErrors() ok = true for a reader without a UART")
+	}
+}
+
+func TestOpenModemOutputs(t *testing.T) {
+	off := false
+	tests := []struct {
+		name     string
+		config   SerialConfig
+		wantDTR  int // SetDTR(true) calls
+		wantRTS  int
+		wantBits *serial.ModemOutputBits // nil = driver default
+	}{
+		{
+			name:    "default asserts DTR",
+			config:  DefaultSerialConfig(9600, false),
+			wantDTR: 1,
+		},
+		{
+			name:     "assert_dtr false",
+			config:   SerialConfig{BaudRate: 9600, AssertDTR: &off},
+			wantBits: &serial.ModemOutputBits{RTS: true, DTR: false},
+		},
+		{
+			name:     "assert_rts false",
+			config:   SerialConfig{BaudRate: 9600, AssertRTS: &off},
+			wantDTR:  1,
+			wantBits: &serial.ModemOutputBits{RTS: false, DTR: true},
+		},
+		{
+			name:     "hardware flow control",
+			config:   SerialConfig{BaudRate: 9600, FlowControl: FlowControlHardware},
+			wantDTR:  1,
+			wantRTS:  1,
+			wantBits: &serial.ModemOutputBits{RTS: true, DTR: true},
+		},
+		{
+			name:     "hardware flow control, assert_dtr false",
+			config:   SerialConfig{BaudRate: 9600, FlowControl: FlowControlHardware, AssertDTR: &off},
+			wantRTS:  1,
+			wantBits: &serial.ModemOutputBits{RTS: true, DTR: false},
+		},
+		{
+			name:     "software flow control, assert_dtr false",
+			config:   SerialConfig{BaudRate: 9600, FlowControl: FlowControlSoftware, AssertDTR: &off},
+			wantBits: &serial.ModemOutputBits{RTS: false, DTR: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := &fakePort{}
+			var mode *serial.Mode
+			origOpen := openPort
+			openPort = func(_ string, m *serial.Mode) (serial.Port, error) {
+				mode = m
+				return port, nil
+			}
+			defer func() { openPort = origOpen }()
+
+			r, err := NewRealReaderWithConfig("/dev/ttyFAKE", tt.config)
+			if err != nil {
+				t.Fatalf("NewRealReaderWithConfig() error: %v", err)
+			}
+			defer r.Close()
+
+			if len(port.dtrSet) != tt.wantDTR || slices.Contains(port.dtrSet, false) {
+				t.Errorf("SetDTR calls = %v, want %d x true", port.dtrSet, tt.wantDTR)
+			}
+			if len(port.rtsSet) != tt.wantRTS || slices.Contains(port.rtsSet, false) {
+				t.Errorf("SetRTS calls = %v, want %d x true", port.rtsSet, tt.wantRTS)
+			}
+			switch {
+			case tt.wantBits == nil && mode.InitialStatusBits != nil:
+				t.Errorf("InitialStatusBits = %+v, want driver default", *mode.InitialStatusBits)
+			case tt.wantBits != nil && (mode.InitialStatusBits == nil || *mode.InitialStatusBits != *tt.wantBits):
+				t.Errorf("InitialStatusBits = %+v, want %+v", mode.InitialStatusBits, *tt.wantBits)
+			}
+		})
+	}
+}
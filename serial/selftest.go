@@ -0,0 +1,72 @@
+package serial
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// SelfTestDuration is how long a self-test listens for data
+const SelfTestDuration = 3 * time.Second
+
+// SelfTestResult reports what a self-test saw on a port
+type SelfTestResult struct {
+	Signals       *ModemStatus // nil if the driver doesn't report modem lines
+	BytesRead     int
+	ValidityRatio float64 // Fraction of BytesRead that is printable ASCII
+}
+
+// DataReceived reports whether any bytes arrived during the test
+func (r *SelfTestResult) DataReceived() bool {
+	return r.BytesRead > 0
+}
+
+// RunSelfTest opens device with cfg, runs SelfTest for duration and closes it
+func RunSelfTest(device string, cfg SerialConfig, duration time.Duration) (*SelfTestResult, error) {
+	reader, err := NewRealReaderWithConfig(device, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Short reads so the test ends close to its deadline
+	if err := reader.SetReadTimeout(DefaultReadTimeout); err != nil {
+		return nil, fmt.Errorf("failed to set read timeout: %w", err)
+	}
+	return SelfTest(reader, duration)
+}
+
+// SelfTest samples modem signals and listens on an open reader until duration
+// elapses or a detection buffer's worth of data has arrived. The ASCII
+// validity ratio uses the same measure as autobaud detection, so a low ratio
+// points at a baud or framing mismatch rather than a dead cable.
+func SelfTest(reader Reader, duration time.Duration) (*SelfTestResult, error) {
+	result := &SelfTestResult{}
+	if modem, err := reader.GetModemStatus(); err == nil {
+		result.Signals = modem
+	}
+
+	buf := make([]byte, DetectionBufferSize)
+	validChars := 0
+	deadline := time.Now().Add(duration)
+
+	for time.Now().Before(deadline) && result.BytesRead < len(buf) {
+		n, err := reader.Read(buf[result.BytesRead:])
+		if n > 0 {
+			validChars += countValidASCII(buf[result.BytesRead : result.BytesRead+n])
+			result.BytesRead += n
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("read failed: %w", err)
+		}
+		if n == 0 {
+			// Brief pause to avoid busy loop
+			time.Sleep(DetectionPollInterval)
+		}
+	}
+
+	if result.BytesRead > 0 {
+		result.ValidityRatio = float64(validChars) / float64(result.BytesRead)
+	}
+	return result, nil
+}
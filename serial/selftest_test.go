@@ -0,0 +1,53 @@
+package serial
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSelfTest(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		wantBytes int
+		wantRatio float64
+	}{
+		{"clean text", []byte("CALL 001 IN 5551234\r\n"), 21, 1.0},
+		{"garbled", []byte{'A', 'B', 0x80, 0xFF}, 4, 0.5},
+		{"silent", nil, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockReader("/dev/ttyS1", tt.data)
+
+			result, err := SelfTest(mock, 50*time.Millisecond)
+			if err != nil {
+				t.Fatalf("SelfTest() error = %v", err)
+			}
+
+			if result.BytesRead != tt.wantBytes {
+				t.Errorf("BytesRead = %d, want %d", result.BytesRead, tt.wantBytes)
+			}
+			if result.DataReceived() != (tt.wantBytes > 0) {
+				t.Errorf("DataReceived() = %v, want %v", result.DataReceived(), tt.wantBytes > 0)
+			}
+			if result.ValidityRatio != tt.wantRatio {
+				t.Errorf("ValidityRatio = %v, want %v", result.ValidityRatio, tt.wantRatio)
+			}
+			if result.Signals == nil || !result.Signals.DCD {
+				t.Errorf("Signals = %+v, want mock modem status", result.Signals)
+			}
+		})
+	}
+}
+
+func TestSelfTestReadError(t *testing.T) {
+	mock := NewMockReader("/dev/ttyS1", nil)
+	mock.readErr = errors.New("device unplugged")
+
+	if _, err := SelfTest(mock, 50*time.Millisecond); err == nil {
+		t.Error("SelfTest() should fail on a read error")
+	}
+}
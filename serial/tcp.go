@@ -0,0 +1,126 @@
+package serial
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// TCPDialTimeout bounds how long connecting to a terminal server may take
+const TCPDialTimeout = 10 * time.Second
+
+// ErrNotSerial is returned by TCPReader and FileReader for operations that
+// only make sense on a real serial line (baud changes, modem signals)
+var ErrNotSerial = fmt.Errorf("not supported on a non-serial source")
+
+// TCPReader reads a raw TCP stream from a terminal server (e.g., a Moxa NPort
+// in TCP server mode) that fronts a serial port. It implements Reader so the
+// capture read loop handles it like a local port; the terminal server owns
+// the line settings, so baud and modem operations are unsupported.
+type TCPReader struct {
+	address     string
+	conn        net.Conn
+	readTimeout time.Duration // 0 = block until data arrives
+	isOpen      bool
+	mu          sync.RWMutex
+	closeOnce   sync.Once
+	closeErr    error
+}
+
+// NewTCPReader connects to address (host:port)
+func NewTCPReader(address string, dialTimeout time.Duration) (*TCPReader, error) {
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	return &TCPReader{
+		address: address,
+		conn:    conn,
+		isOpen:  true,
+	}, nil
+}
+
+// Read implements io.Reader. A read timeout returns (0, nil) like a serial
+// port with nothing to say; the remote closing the connection is an error,
+// not io.EOF, so the channel reconnects with backoff.
+func (r *TCPReader) Read(p []byte) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.isOpen {
+		return 0, fmt.Errorf("connection not open")
+	}
+
+	if r.readTimeout > 0 {
+		r.conn.SetReadDeadline(time.Now().Add(r.readTimeout))
+	}
+	n, err := r.conn.Read(p)
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return n, nil
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return n, nil
+	case errors.Is(err, io.EOF):
+		return n, fmt.Errorf("connection to %s closed by remote", r.address)
+	default:
+		return n, err
+	}
+}
+
+// Close implements io.Closer. The socket is closed before taking the lock so
+// a Read blocked without a timeout returns instead of deadlocking Close.
+func (r *TCPReader) Close() error {
+	r.closeOnce.Do(func() {
+		r.closeErr = r.conn.Close()
+	})
+
+	r.mu.Lock()
+	r.isOpen = false
+	r.mu.Unlock()
+	return r.closeErr
+}
+
+// Device returns the remote address
+func (r *TCPReader) Device() string {
+	return r.address
+}
+
+// IsOpen returns true if the connection is open
+func (r *TCPReader) IsOpen() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.isOpen
+}
+
+// Reconfigure is unsupported - the terminal server sets the line parameters
+func (r *TCPReader) Reconfigure(baudRate int, useFlowControl bool) error {
+	return ErrNotSerial
+}
+
+// SetBaudRate is unsupported - the terminal server sets the line parameters
+func (r *TCPReader) SetBaudRate(baudRate int) error {
+	return ErrNotSerial
+}
+
+// SetReadTimeout sets how long each Read waits for data before returning empty
+func (r *TCPReader) SetReadTimeout(timeout time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readTimeout = timeout
+	return nil
+}
+
+// ResetInputBuffer is a no-op: there's no driver buffer holding stale data
+// from a wrong baud rate
+func (r *TCPReader) ResetInputBuffer() error {
+	return nil
+}
+
+// GetModemStatus is unsupported - modem lines aren't visible over raw TCP
+func (r *TCPReader) GetModemStatus() (*ModemStatus, error) {
+	return nil, ErrNotSerial
+}
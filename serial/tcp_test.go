@@ -0,0 +1,119 @@
+package serial
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTCPSource listens on a loopback port and hands the first accepted
+// connection to serve
+func startTCPSource(t *testing.T, serve func(net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		serve(conn)
+	}()
+	return ln.Addr().String()
+}
+
+func TestTCPReader(t *testing.T) {
+	release := make(chan struct{})
+	addr := startTCPSource(t, func(conn net.Conn) {
+		conn.Write([]byte("CALL 001 IN\r\n"))
+		<-release
+		conn.Close()
+	})
+
+	r, err := NewTCPReader(addr, time.Second)
+	if err != nil {
+		t.Fatalf("NewTCPReader() error = %v", err)
+	}
+	defer r.Close()
+	r.SetReadTimeout(50 * time.Millisecond)
+
+	if r.Device() != addr || !r.IsOpen() {
+		t.Errorf("Device() = %q, IsOpen() = %v", r.Device(), r.IsOpen())
+	}
+
+	buf := make([]byte, 64)
+	n, err := io.ReadAtLeast(r, buf, len("CALL 001 IN\r\n"))
+	if err != nil || string(buf[:n]) != "CALL 001 IN\r\n" {
+		t.Fatalf("Read() = %q, %v", buf[:n], err)
+	}
+
+	// Silence is a timeout, reported like an idle serial port
+	if n, err := r.Read(buf); n != 0 || err != nil {
+		t.Errorf("idle Read() = %d, %v, want 0, nil", n, err)
+	}
+
+	// The remote hanging up is an error, not a clean EOF
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := r.Read(buf)
+		if err != nil {
+			if errors.Is(err, io.EOF) || !strings.Contains(err.Error(), "closed by remote") {
+				t.Errorf("Read() after remote close = %v, want closed-by-remote error", err)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Read() never reported the remote close")
+		}
+	}
+}
+
+func TestTCPReaderSerialOnlyOperations(t *testing.T) {
+	addr := startTCPSource(t, func(conn net.Conn) {})
+	r, err := NewTCPReader(addr, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := r.GetModemStatus(); !errors.Is(err, ErrNotSerial) {
+		t.Errorf("GetModemStatus() error = %v, want ErrNotSerial", err)
+	}
+	if err := r.SetBaudRate(9600); !errors.Is(err, ErrNotSerial) {
+		t.Errorf("SetBaudRate() error = %v, want ErrNotSerial", err)
+	}
+	if err := r.ResetInputBuffer(); err != nil {
+		t.Errorf("ResetInputBuffer() error = %v, want nil", err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if r.IsOpen() {
+		t.Error("IsOpen() = true after Close()")
+	}
+	if _, err := r.Read(make([]byte, 8)); err == nil {
+		t.Error("Read() after Close() should fail")
+	}
+}
+
+func TestNewTCPReaderRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := NewTCPReader(addr, time.Second); err == nil {
+		t.Error("NewTCPReader() should fail with nothing listening")
+	}
+}
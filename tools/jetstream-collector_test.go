@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Trimmed /jsz?accounts=true&streams=true&consumers=true from a site server
+const sampleJSZ = `{
+  "server_id": "NDDPGM2FQCEJLCJKKKJOEBMPKUNWQ2ZAM6HU5BVLXMGLLZZAZUODFGPX",
+  "now": "2025-12-03T15:04:05.123Z",
+  "memory": 0,
+  "storage": 1048576,
+  "streams": 2,
+  "consumers": 1,
+  "messages": 1290,
+  "bytes": 1048576,
+  "account_details": [
+    {
+      "name": "$G",
+      "id": "$G",
+      "stream_detail": [
+        {
+          "name": "cdr",
+          "created": "2025-11-01T00:00:00Z",
+          "state": {"messages": 1200, "bytes": 1000000, "first_seq": 1, "last_seq": 1200, "consumer_count": 1},
+          "consumer_detail": [
+            {
+              "stream_name": "cdr",
+              "name": "forwarder-psna-ne-metro-omaha-01",
+              "num_pending": 37,
+              "num_ack_pending": 2,
+              "num_redelivered": 5,
+              "num_waiting": 1
+            }
+          ]
+        },
+        {
+          "name": "health",
+          "created": "2025-11-01T00:00:00Z",
+          "state": {"messages": 90, "bytes": 48576, "first_seq": 11, "last_seq": 100, "consumer_count": 0}
+        }
+      ]
+    }
+  ]
+}`
+
+// collectMetrics gathers a collector's metrics and returns each gauge's value
+// keyed by name{label=value,...}
+func collectMetrics(t *testing.T, c prometheus.Collector) map[string]float64 {
+	t.Helper()
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	got := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%s", l.GetName(), l.GetValue()))
+			}
+			got[family.GetName()+"{"+strings.Join(labels, ",")+"}"] = m.GetGauge().GetValue()
+		}
+	}
+	return got
+}
+
+func TestJetStreamCollector(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(sampleJSZ))
+	}))
+	defer srv.Close()
+
+	got := collectMetrics(t, NewJetStreamCollector(srv.URL))
+
+	if !strings.Contains(query, "streams=true") || !strings.Contains(query, "consumers=true") {
+		t.Errorf("jsz query = %q, want stream and consumer detail", query)
+	}
+
+	want := map[string]float64{
+		"nats_jetstream_up{}":                                                                            1,
+		"nats_stream_messages{account=$G,stream=cdr}":                                                    1200,
+		"nats_stream_bytes{account=$G,stream=cdr}":                                                       1000000,
+		"nats_stream_last_seq{account=$G,stream=cdr}":                                                    1200,
+		"nats_stream_messages{account=$G,stream=health}":                                                 90,
+		"nats_stream_bytes{account=$G,stream=health}":                                                    48576,
+		"nats_stream_last_seq{account=$G,stream=health}":                                                 100,
+		"nats_consumer_num_pending{account=$G,consumer=forwarder-psna-ne-metro-omaha-01,stream=cdr}":     37,
+		"nats_consumer_num_ack_pending{account=$G,consumer=forwarder-psna-ne-metro-omaha-01,stream=cdr}": 2,
+		"nats_consumer_num_redelivered{account=$G,consumer=forwarder-psna-ne-metro-omaha-01,stream=cdr}": 5,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d metrics, want %d: %v", len(got), len(want), got)
+	}
+	for key, value := range want {
+		if v, ok := got[key]; !ok || v != value {
+			t.Errorf("%s = %v (present %v), want %v", key, v, ok, value)
+		}
+	}
+}
+
+func TestJetStreamCollectorScrapeFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "jetstream not enabled", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	got := collectMetrics(t, NewJetStreamCollector(srv.URL))
+
+	// Only up=0: missing stream gauges must not read as empty streams
+	if len(got) != 1 || got["nats_jetstream_up{}"] != 0 {
+		t.Errorf("metrics = %v, want only nats_jetstream_up 0", got)
+	}
+	if _, ok := got["nats_jetstream_up{}"]; !ok {
+		t.Error("nats_jetstream_up missing on scrape failure")
+	}
+}
@@ -15,6 +15,7 @@ import (
 var (
 	natsURL    = flag.String("nats-url", "http://localhost:8222", "NATS monitoring URL")
 	listenAddr = flag.String("listen", ":9100", "Address to listen on for metrics")
+	jetstream  = flag.Bool("jetstream", true, "Also export JetStream stream and consumer metrics from /jsz")
 )
 
 type NATSConnection struct {
@@ -148,6 +149,9 @@ func main() {
 
 	collector := NewNATSCollector()
 	prometheus.MustRegister(collector)
+	if *jetstream {
+		prometheus.MustRegister(NewJetStreamCollector(*natsURL))
+	}
 
 	http.Handle("/metrics", promhttp.Handler())
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {